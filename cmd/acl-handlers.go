@@ -1,5 +1,5 @@
 /*
- * MinIO Cloud Storage, (C) 2018 MinIO, Inc.
+ * MinIO Cloud Storage, (C) 2018, 2019 MinIO, Inc.
  *
  * Licensed under the Apache License, Version 2.0 (the "License");
  * you may not use this file except in compliance with the License.
@@ -18,36 +18,132 @@ package cmd
 
 import (
 	"encoding/xml"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
+	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/policy"
 )
 
-// Data types used for returning dummy access control
-// policy XML, these variables shouldn't be used elsewhere
-// they are only defined to be used in this file alone.
-type grantee struct {
-	XMLNS       string `xml:"xmlns:xsi,attr"`
-	XMLXSI      string `xml:"xsi:type,attr"`
-	Type        string `xml:"Type"`
-	ID          string `xml:"ID,omitempty"`
-	DisplayName string `xml:"DisplayName,omitempty"`
-	URI         string `xml:"URI,omitempty"`
+// Maximum size of an access control policy document.
+const maxACLConfigSize = 512 * 1024
+
+// getCannedACLFromHeader - returns the canned ACL requested by the
+// x-amz-acl header, explicit x-amz-grant-* headers are not supported.
+// The boolean return value reports if an ACL other than the default
+// private ACL was requested.
+func getCannedACLFromHeader(header http.Header) (acl.CannedACL, bool, APIErrorCode) {
+	for k := range header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(k), xhttp.AmzGrantPrefix) {
+			return "", true, ErrNotImplemented
+		}
+	}
+
+	value, ok := header[xhttp.AmzACL]
+	if !ok {
+		return acl.Private, false, ErrNone
+	}
+
+	cannedACL, err := acl.Parse(strings.Join(value, ""))
+	if err != nil {
+		return "", true, ErrInvalidCannedACL
+	}
+	return cannedACL, cannedACL != acl.Private, ErrNone
 }
 
-type grant struct {
-	Grantee    grantee `xml:"Grantee"`
-	Permission string  `xml:"Permission"`
+// getCannedACLFromRequest - returns the canned ACL requested either by
+// the x-amz-acl header or by the access control policy in the body.
+func getCannedACLFromRequest(r *http.Request) (acl.CannedACL, APIErrorCode) {
+	cannedACL, _, s3Error := getCannedACLFromHeader(r.Header)
+	if s3Error != ErrNone {
+		return cannedACL, s3Error
+	}
+	if _, ok := r.Header[xhttp.AmzACL]; ok {
+		return cannedACL, ErrNone
+	}
+
+	if r.ContentLength <= 0 {
+		return "", ErrMissingRequestBodyError
+	}
+
+	acp, err := acl.ParseAccessControlPolicy(io.LimitReader(r.Body, maxACLConfigSize))
+	if err != nil {
+		return "", ErrMalformedACLError
+	}
+
+	if cannedACL, err = acp.CannedACL(); err != nil {
+		return "", ErrNotImplemented
+	}
+	return cannedACL, ErrNone
 }
 
-type accessControlPolicy struct {
-	XMLName           xml.Name `xml:"AccessControlPolicy"`
-	Owner             Owner    `xml:"Owner"`
-	AccessControlList struct {
-		Grants []grant `xml:"Grant"`
-	} `xml:"AccessControlList"`
+// writeAccessControlPolicy - writes the access control policy of
+// the canned ACL as XML response.
+func writeAccessControlPolicy(w http.ResponseWriter, cannedACL acl.CannedACL) error {
+	if err := xml.NewEncoder(w).Encode(cannedACL.ToPolicy(getACLOwner())); err != nil {
+		return err
+	}
+
+	w.(http.Flusher).Flush()
+	return nil
+}
+
+// PutBucketACLHandler - PUT Bucket ACL
+// -----------------
+// This operation uses the ACL subresource to set
+// the canned ACL of a specified bucket.
+func (api objectAPIHandlers) PutBucketACLHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutBucketACL")
+
+	defer logger.AuditLog(w, r, "PutBucketACL", mustGetClaimsFromToken(r))
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// ACLs are not supported in gateway mode.
+	if globalIsGateway {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNotImplemented), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutBucketACLAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Before proceeding validate if bucket exists.
+	_, err := objAPI.GetBucketInfo(ctx, bucket)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	cannedACL, s3Error := getCannedACLFromRequest(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if err = saveBucketACLConfig(ctx, objAPI, bucket, cannedACL); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	globalACLSys.Set(bucket, cannedACL)
+	globalNotificationSys.SetBucketACL(ctx, bucket, cannedACL)
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
 }
 
 // GetBucketACLHandler - GET Bucket ACL
@@ -68,9 +164,7 @@ func (api objectAPIHandlers) GetBucketACLHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Allow getBucketACL if policy action is set, since this is a dummy call
-	// we are simply re-purposing the bucketPolicyAction.
-	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketPolicyAction, bucket, ""); s3Error != ErrNone {
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketACLAction, bucket, ""); s3Error != ErrNone {
 		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
 		return
 	}
@@ -82,21 +176,69 @@ func (api objectAPIHandlers) GetBucketACLHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	acl := &accessControlPolicy{}
-	acl.AccessControlList.Grants = append(acl.AccessControlList.Grants, grant{
-		Grantee: grantee{
-			XMLNS:  "http://www.w3.org/2001/XMLSchema-instance",
-			XMLXSI: "CanonicalUser",
-			Type:   "CanonicalUser",
-		},
-		Permission: "FULL_CONTROL",
-	})
-	if err := xml.NewEncoder(w).Encode(acl); err != nil {
+	if err = writeAccessControlPolicy(w, globalACLSys.Get(bucket)); err != nil {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+}
 
-	w.(http.Flusher).Flush()
+// PutObjectACLHandler - PUT Object ACL
+// -----------------
+// This operation uses the ACL subresource to set
+// the canned ACL of a specified object.
+func (api objectAPIHandlers) PutObjectACLHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutObjectACL")
+
+	defer logger.AuditLog(w, r, "PutObjectACL", mustGetClaimsFromToken(r))
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// ACLs are not supported in gateway mode.
+	if globalIsGateway {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNotImplemented), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutObjectACLAction, bucket, object); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	cannedACL, s3Error := getCannedACLFromRequest(r)
+	if s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	setObjectACL(objInfo.UserDefined, cannedACL)
+	// "expires" is parsed out of the metadata, restore it so
+	// the metadata only update does not drop it.
+	if !objInfo.Expires.IsZero() {
+		objInfo.UserDefined["expires"] = objInfo.Expires.Format(http.TimeFormat)
+	}
+	objInfo.metadataOnly = true
+
+	if _, err = objAPI.CopyObject(ctx, bucket, object, bucket, object, objInfo, ObjectOptions{}, ObjectOptions{}); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
 }
 
 // GetObjectACLHandler - GET Object ACL
@@ -118,33 +260,20 @@ func (api objectAPIHandlers) GetObjectACLHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	// Allow getObjectACL if policy action is set, since this is a dummy call
-	// we are simply re-purposing the bucketPolicyAction.
-	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketPolicyAction, bucket, ""); s3Error != ErrNone {
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetObjectACLAction, bucket, object); s3Error != ErrNone {
 		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
 		return
 	}
 
 	// Before proceeding validate if object exists.
-	_, err := objAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
 	if err != nil {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
 
-	acl := &accessControlPolicy{}
-	acl.AccessControlList.Grants = append(acl.AccessControlList.Grants, grant{
-		Grantee: grantee{
-			XMLNS:  "http://www.w3.org/2001/XMLSchema-instance",
-			XMLXSI: "CanonicalUser",
-			Type:   "CanonicalUser",
-		},
-		Permission: "FULL_CONTROL",
-	})
-	if err := xml.NewEncoder(w).Encode(acl); err != nil {
+	if err = writeAccessControlPolicy(w, getObjectACL(objInfo)); err != nil {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
-
-	w.(http.Flusher).Flush()
 }
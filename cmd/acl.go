@@ -0,0 +1,253 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/acl"
+	"github.com/minio/minio/pkg/policy"
+)
+
+const (
+	// ACL configuration file.
+	bucketACLConfig = "acl.json"
+
+	// Internal metadata key holding the canned ACL of an object.
+	objectACLMetadataKey = ReservedMetadataPrefix + "acl"
+)
+
+// bucketACL - on disk format of the bucket ACL configuration.
+type bucketACL struct {
+	Version int           `json:"version"`
+	ACL     acl.CannedACL `json:"acl"`
+}
+
+// ACLSys - Bucket and object canned ACL subsystem.
+type ACLSys struct {
+	sync.RWMutex
+	bucketACLMap map[string]acl.CannedACL
+}
+
+// Set - sets canned ACL of the given bucket name.
+func (sys *ACLSys) Set(bucketName string, cannedACL acl.CannedACL) {
+	if globalIsGateway {
+		// no-op
+		return
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	if cannedACL == acl.Private {
+		delete(sys.bucketACLMap, bucketName)
+		return
+	}
+	sys.bucketACLMap[bucketName] = cannedACL
+}
+
+// Get - returns canned ACL of the given bucket name, private
+// if none is set.
+func (sys *ACLSys) Get(bucketName string) acl.CannedACL {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if cannedACL, ok := sys.bucketACLMap[bucketName]; ok {
+		return cannedACL
+	}
+	return acl.Private
+}
+
+// Remove - removes canned ACL of the given bucket name.
+func (sys *ACLSys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketACLMap, bucketName)
+}
+
+// IsAllowed - checks if the bucket ACL, or for object reads the
+// object ACL, grants the action to a requester not allowed by
+// any policy.
+func (sys *ACLSys) IsAllowed(ctx context.Context, action policy.Action, bucketName, objectName string, isAuthenticated bool) bool {
+	if globalIsGateway || bucketName == "" {
+		return false
+	}
+
+	if sys.Get(bucketName).IsBucketAllowed(action, isAuthenticated) {
+		return true
+	}
+
+	// Object ACLs are only looked up for object reads.
+	if objectName == "" || action != policy.GetObjectAction {
+		return false
+	}
+
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return false
+	}
+
+	objInfo, err := objAPI.GetObjectInfo(ctx, bucketName, objectName, ObjectOptions{})
+	if err != nil {
+		return false
+	}
+	return getObjectACL(objInfo).IsObjectAllowed(action, isAuthenticated)
+}
+
+// getObjectACL - returns the canned ACL of the object, private
+// if none is set.
+func getObjectACL(objInfo ObjectInfo) acl.CannedACL {
+	if v, ok := objInfo.UserDefined[objectACLMetadataKey]; ok {
+		return acl.CannedACL(v)
+	}
+	return acl.Private
+}
+
+// setObjectACL - records the canned ACL of the object in its metadata.
+func setObjectACL(metadata map[string]string, cannedACL acl.CannedACL) {
+	if cannedACL == acl.Private || cannedACL == "" {
+		delete(metadata, objectACLMetadataKey)
+		return
+	}
+	metadata[objectACLMetadataKey] = string(cannedACL)
+}
+
+// getACLOwner - returns the owner of all buckets and objects.
+func getACLOwner() acl.Owner {
+	return acl.Owner{ID: globalMinioDefaultOwnerID}
+}
+
+// setBucketACL - saves the canned ACL of the bucket and propagates
+// it to all peers, private ACL of new buckets is not saved.
+func setBucketACL(ctx context.Context, objAPI ObjectLayer, bucketName string, cannedACL acl.CannedACL) error {
+	if globalIsGateway || cannedACL == acl.Private {
+		return nil
+	}
+	if err := saveBucketACLConfig(ctx, objAPI, bucketName, cannedACL); err != nil {
+		return err
+	}
+	globalACLSys.Set(bucketName, cannedACL)
+	globalNotificationSys.SetBucketACL(ctx, bucketName, cannedACL)
+	return nil
+}
+
+func saveBucketACLConfig(ctx context.Context, objAPI ObjectLayer, bucketName string, cannedACL acl.CannedACL) error {
+	// Construct path to acl.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketACLConfig)
+	if cannedACL == acl.Private {
+		if err := objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil {
+			if _, ok := err.(ObjectNotFound); !ok {
+				return err
+			}
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(bucketACL{Version: 1, ACL: cannedACL})
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// getBucketACLConfig - get canned ACL config for given bucket name.
+func getBucketACLConfig(objAPI ObjectLayer, bucketName string) (acl.CannedACL, error) {
+	// Construct path to acl.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketACLConfig)
+	configData, err := readConfig(context.Background(), objAPI, configFile)
+	if err != nil {
+		if err == errConfigNotFound {
+			return acl.Private, nil
+		}
+		return "", err
+	}
+
+	var config bucketACL
+	if err = json.Unmarshal(configData, &config); err != nil {
+		return "", err
+	}
+	return acl.Parse(string(config.ACL))
+}
+
+func removeBucketACLConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	return saveBucketACLConfig(ctx, objAPI, bucketName, acl.Private)
+}
+
+// NewACLSys - creates new ACL system.
+func NewACLSys() *ACLSys {
+	return &ACLSys{
+		bucketACLMap: make(map[string]acl.CannedACL),
+	}
+}
+
+// Init - initializes ACL system from acl.json of all buckets.
+func (sys *ACLSys) Init(buckets []BucketInfo, objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	// ACLs are not supported in gateway mode.
+	if globalIsGateway {
+		return nil
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Initializing ACLs needs a retry mechanism for
+	// the following reasons:
+	//  - Read quorum is lost just after the initialization
+	//    of the object layer.
+	retryTimerCh := newRetryTimerSimple(doneCh)
+	for {
+		select {
+		case <-retryTimerCh:
+			// Load ACLSys once during boot.
+			if err := sys.load(buckets, objAPI); err != nil {
+				if err == errDiskNotFound ||
+					strings.Contains(err.Error(), InsufficientReadQuorum{}.Error()) ||
+					strings.Contains(err.Error(), InsufficientWriteQuorum{}.Error()) {
+					logger.Info("Waiting for ACL subsystem to be initialized..")
+					continue
+				}
+				return err
+			}
+			return nil
+		case <-globalOSSignalCh:
+			return fmt.Errorf("Initializing ACL sub-system gracefully stopped")
+		}
+	}
+}
+
+// Loads canned ACLs of all buckets into ACLSys.
+func (sys *ACLSys) load(buckets []BucketInfo, objAPI ObjectLayer) error {
+	for _, bucket := range buckets {
+		cannedACL, err := getBucketACLConfig(objAPI, bucket.Name)
+		if err != nil {
+			return err
+		}
+		sys.Set(bucket.Name, cannedACL)
+	}
+	return nil
+}
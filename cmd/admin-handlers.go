@@ -40,13 +40,13 @@ import (
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/cpu"
+	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/handlers"
 	iampolicy "github.com/minio/minio/pkg/iam/policy"
 	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/minio/pkg/mem"
 	xnet "github.com/minio/minio/pkg/net"
 	"github.com/minio/minio/pkg/quick"
-	trace "github.com/minio/minio/pkg/trace"
 )
 
 const (
@@ -901,6 +901,10 @@ func (a adminAPIHandlers) BackgroundHealStatusHandler(w http.ResponseWriter, r *
 	var aggregatedHealStateResult = madmin.BgHealState{}
 	for _, state := range bgHealStates {
 		aggregatedHealStateResult.ScannedItemsCount += state.ScannedItemsCount
+		aggregatedHealStateResult.MRFQueueDepth += state.MRFQueueDepth
+		aggregatedHealStateResult.MRFHealedCount += state.MRFHealedCount
+		aggregatedHealStateResult.MRFFailedCount += state.MRFFailedCount
+		aggregatedHealStateResult.MRFDroppedCount += state.MRFDroppedCount
 		if aggregatedHealStateResult.LastHealActivity.Before(state.LastHealActivity) {
 			aggregatedHealStateResult.LastHealActivity = state.LastHealActivity
 		}
@@ -915,6 +919,273 @@ func (a adminAPIHandlers) BackgroundHealStatusHandler(w http.ResponseWriter, r *
 	w.(http.Flusher).Flush()
 }
 
+// DrivesHealStatusHandler - POST /minio/admin/v1/background-heal/drives
+// ----------
+// Returns the healing progress of all replaced drives of the cluster.
+func (a adminAPIHandlers) DrivesHealStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "DrivesHealStatus")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Check if this setup has an erasure coded backend.
+	if !globalIsXL {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrHealNotImplemented), r.URL)
+		return
+	}
+
+	drives := getLocalDrivesHealStatus()
+	if globalIsDistXL {
+		// Get the replaced drives of other peers
+		drives = append(drives, globalNotificationSys.DrivesHealStatus()...)
+	}
+
+	if err := json.NewEncoder(w).Encode(drives); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	w.(http.Flusher).Flush()
+}
+
+// StartDecommissionHandler - POST /minio/admin/v1/decommission/start?zone={zone}&set={set}
+// ----------
+// Starts moving all objects off a zone, or off a single erasure set
+// of the zone when set is not negative, to the remaining zones.
+func (a adminAPIHandlers) StartDecommissionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "StartDecommission")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	zone, err := strconv.Atoi(r.URL.Query().Get("zone"))
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+		return
+	}
+	set := -1
+	if v := r.URL.Query().Get("set"); v != "" {
+		if set, err = strconv.Atoi(v); err != nil {
+			writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+			return
+		}
+	}
+
+	if err = globalDecommissionSys.Start(ctx, objectAPI, zone, set); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// DecommissionStatusHandler - GET /minio/admin/v1/decommission/status
+// ----------
+// Returns the progress of the current or the last decommission.
+func (a adminAPIHandlers) DecommissionStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "DecommissionStatus")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	// Only the server moving objects knows the latest progress,
+	// other servers report the last saved progress.
+	info := globalDecommissionSys.Info()
+	if !globalDecommissionSys.IsActive() {
+		var err error
+		if info, err = readDecommissionConfig(ctx, objectAPI); err != nil {
+			writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+			return
+		}
+	}
+
+	if err := json.NewEncoder(w).Encode(info); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	w.(http.Flusher).Flush()
+}
+
+// CancelDecommissionHandler - POST /minio/admin/v1/decommission/cancel
+// ----------
+// Stops a running decommission, the zone or set receives new
+// objects again.
+func (a adminAPIHandlers) CancelDecommissionHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "CancelDecommission")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	if err := globalDecommissionSys.Cancel(ctx, objectAPI); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// SetBucketStorageClassHandler - PUT /minio/admin/v1/bucket-storage-class?bucket={bucket}
+// ----------
+// Sets the storage classes of new objects in the bucket uploaded
+// without x-amz-storage-class, an empty configuration removes them.
+func (a adminAPIHandlers) SetBucketStorageClassHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "SetBucketStorageClass")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	// Error out if Content-Length is missing.
+	if r.ContentLength <= 0 {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrMissingContentLength), r.URL)
+		return
+	}
+
+	// Error out if Content-Length is beyond allowed size.
+	if r.ContentLength > maxBucketPolicySize {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrEntityTooLarge), r.URL)
+		return
+	}
+
+	var sc madmin.BucketStorageClass
+	if err := json.NewDecoder(io.LimitReader(r.Body, r.ContentLength)).Decode(&sc); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminConfigBadJSON), r.URL)
+		return
+	}
+	if err := validateBucketStorageClass(sc); err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrInvalidStorageClass), r.URL)
+		return
+	}
+
+	if err := saveBucketStorageClassConfig(ctx, objectAPI, bucket, sc); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	globalBucketStorageClassSys.Set(bucket, sc)
+	globalNotificationSys.SetBucketStorageClass(ctx, bucket, sc)
+
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketStorageClassHandler - GET /minio/admin/v1/bucket-storage-class?bucket={bucket}
+// ----------
+// Returns the storage classes of new objects in the bucket uploaded
+// without x-amz-storage-class.
+func (a adminAPIHandlers) GetBucketStorageClassHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketStorageClass")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	bucket := mux.Vars(r)["bucket"]
+	if _, err := objectAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(globalBucketStorageClassSys.Get(bucket)); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	w.(http.Flusher).Flush()
+}
+
+// Default and maximum number of events returned by a peek at the queue
+// of a notification target.
+const (
+	defaultNotificationQueuePeek = 10
+	maxNotificationQueuePeek     = 1000
+)
+
+// NotificationTargetsHandler - GET /minio/admin/v1/notification/targets
+// ----------
+// Returns the online state, queue length and oldest queued event of
+// the notification targets of all servers.
+func (a adminAPIHandlers) NotificationTargetsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "NotificationTargets")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	infos := localNotificationTargets()
+	infos = append(infos, globalNotificationSys.NotificationTargets(ctx)...)
+	if infos == nil {
+		infos = []madmin.NotificationTargetInfo{}
+	}
+
+	if err := json.NewEncoder(w).Encode(infos); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	w.(http.Flusher).Flush()
+}
+
+// NotificationQueueHandler - GET /minio/admin/v1/notification/queue?arn={arn}&count={count}
+// POST /minio/admin/v1/notification/queue/{action}?arn={arn}
+// ----------
+// Returns the oldest queued events of a notification target of all
+// servers, or purges or replays them depending on action.
+func (a adminAPIHandlers) NotificationQueueHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "NotificationQueue")
+
+	objectAPI := validateAdminReq(ctx, w, r)
+	if objectAPI == nil {
+		return
+	}
+
+	arn, err := event.ParseARN(r.URL.Query().Get("arn"))
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+		return
+	}
+
+	action := notificationQueuePeek
+	if r.Method == http.MethodPost {
+		action = mux.Vars(r)["action"]
+	}
+
+	count := defaultNotificationQueuePeek
+	if v := r.URL.Query().Get("count"); v != "" {
+		if count, err = strconv.Atoi(v); err != nil || count <= 0 || count > maxNotificationQueuePeek {
+			writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrAdminInvalidArgument), r.URL)
+			return
+		}
+	}
+
+	infos := []madmin.NotificationQueueInfo{localNotificationQueue(arn, action, count)}
+	infos = append(infos, globalNotificationSys.NotificationQueue(arn.String(), action, count)...)
+
+	if err = json.NewEncoder(w).Encode(infos); err != nil {
+		writeErrorResponseJSON(ctx, w, toAdminAPIErr(ctx, err), r.URL)
+		return
+	}
+
+	w.(http.Flusher).Flush()
+}
+
 // GetConfigHandler - GET /minio/admin/v1/config
 // Get config.json of this minio setup.
 func (a adminAPIHandlers) GetConfigHandler(w http.ResponseWriter, r *http.Request) {
@@ -1565,30 +1836,11 @@ func (a adminAPIHandlers) SetConfigHandler(w http.ResponseWriter, r *http.Reques
 	writeSuccessResponseHeadersOnly(w)
 }
 
-// Returns true if the trace.Info should be traced,
-// false if certain conditions are not met.
-// - input entry is not of the type *trace.Info*
-// - errOnly entries are to be traced, not status code 2xx, 3xx.
-// - all entries to be traced, if not trace only S3 API requests.
-func mustTrace(entry interface{}, trcAll, errOnly bool) bool {
-	trcInfo, ok := entry.(trace.Info)
-	if !ok {
-		return false
-	}
-	trace := trcAll || !hasPrefix(trcInfo.ReqInfo.Path, minioReservedBucketPath+SlashSeparator)
-	if errOnly {
-		return trace && trcInfo.RespInfo.StatusCode >= http.StatusBadRequest
-	}
-	return trace
-}
-
 // TraceHandler - POST /minio/admin/v1/trace
 // ----------
 // The handler sends http trace to the connected HTTP client.
 func (a adminAPIHandlers) TraceHandler(w http.ResponseWriter, r *http.Request) {
 	ctx := newContext(r, w, "HTTPTrace")
-	trcAll := r.URL.Query().Get("all") == "true"
-	trcErr := r.URL.Query().Get("err") == "true"
 
 	// Validate request signature.
 	adminAPIErr := checkAdminRequestAuthType(ctx, r, "")
@@ -1597,6 +1849,12 @@ func (a adminAPIHandlers) TraceHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	opts, err := getTraceOptions(r.URL.Query())
+	if err != nil {
+		writeErrorResponseJSON(ctx, w, errorCodes.ToAPIErr(ErrInvalidRequest), r.URL)
+		return
+	}
+
 	w.Header().Set(xhttp.ContentType, "text/event-stream")
 
 	doneCh := make(chan struct{})
@@ -1611,12 +1869,10 @@ func (a adminAPIHandlers) TraceHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	globalHTTPTrace.Subscribe(traceCh, doneCh, func(entry interface{}) bool {
-		return mustTrace(entry, trcAll, trcErr)
-	})
+	subscribeTrace(traceCh, doneCh, opts)
 
 	for _, peer := range peers {
-		peer.Trace(traceCh, doneCh, trcAll, trcErr)
+		peer.Trace(traceCh, doneCh, opts)
 	}
 
 	keepAliveTicker := time.NewTicker(500 * time.Millisecond)
@@ -61,6 +61,18 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 		adminV1Router.Methods(http.MethodPost).Path("/heal/{bucket}/{prefix:.*}").HandlerFunc(httpTraceAll(adminAPI.HealHandler))
 
 		adminV1Router.Methods(http.MethodPost).Path("/background-heal/status").HandlerFunc(httpTraceAll(adminAPI.BackgroundHealStatusHandler))
+		adminV1Router.Methods(http.MethodPost).Path("/background-heal/drives").HandlerFunc(httpTraceAll(adminAPI.DrivesHealStatusHandler))
+
+		/// Decommission operations
+
+		adminV1Router.Methods(http.MethodPost).Path("/decommission/start").HandlerFunc(httpTraceAll(adminAPI.StartDecommissionHandler))
+		adminV1Router.Methods(http.MethodGet).Path("/decommission/status").HandlerFunc(httpTraceAll(adminAPI.DecommissionStatusHandler))
+		adminV1Router.Methods(http.MethodPost).Path("/decommission/cancel").HandlerFunc(httpTraceAll(adminAPI.CancelDecommissionHandler))
+
+		/// Bucket storage class operations
+
+		adminV1Router.Methods(http.MethodPut).Path("/bucket-storage-class").HandlerFunc(httpTraceHdrs(adminAPI.SetBucketStorageClassHandler)).Queries("bucket", "{bucket:.*}")
+		adminV1Router.Methods(http.MethodGet).Path("/bucket-storage-class").HandlerFunc(httpTraceAll(adminAPI.GetBucketStorageClassHandler)).Queries("bucket", "{bucket:.*}")
 
 		/// Health operations
 
@@ -68,6 +80,11 @@ func registerAdminRouter(router *mux.Router, enableConfigOps, enableIAMOps bool)
 	// Performance command - return performance details based on input type
 	adminV1Router.Methods(http.MethodGet).Path("/performance").HandlerFunc(httpTraceAll(adminAPI.PerfInfoHandler)).Queries("perfType", "{perfType:.*}")
 
+	// Notification target operations
+	adminV1Router.Methods(http.MethodGet).Path("/notification/targets").HandlerFunc(httpTraceAll(adminAPI.NotificationTargetsHandler))
+	adminV1Router.Methods(http.MethodGet).Path("/notification/queue").HandlerFunc(httpTraceAll(adminAPI.NotificationQueueHandler)).Queries("arn", "{arn:.*}")
+	adminV1Router.Methods(http.MethodPost).Path("/notification/queue/{action:purge|replay}").HandlerFunc(httpTraceAll(adminAPI.NotificationQueueHandler)).Queries("arn", "{arn:.*}")
+
 	// Profiling operations
 	adminV1Router.Methods(http.MethodPost).Path("/profiling/start").HandlerFunc(httpTraceAll(adminAPI.StartProfilingHandler)).
 		Queries("profilerType", "{profilerType:.*}")
@@ -87,6 +87,9 @@ const (
 	ErrInvalidPolicyDocument
 	ErrInvalidObjectState
 	ErrMalformedXML
+	ErrMalformedACLError
+	ErrInvalidCannedACL
+	ErrInvalidTargetBucketForLogging
 	ErrMissingContentLength
 	ErrMissingContentMD5
 	ErrMissingRequestBodyError
@@ -174,6 +177,7 @@ const (
 	ErrFilterNamePrefix
 	ErrFilterNameSuffix
 	ErrFilterValueInvalid
+	ErrObjectFilterInvalid
 	ErrOverlappingConfigs
 	ErrUnsupportedNotification
 
@@ -224,6 +228,9 @@ const (
 	ErrHealMissingBucket
 	ErrHealAlreadyRunning
 	ErrHealOverlappingPaths
+	ErrDecommissionNotImplemented
+	ErrDecommissionAlreadyRunning
+	ErrDecommissionNotRunning
 	ErrIncorrectContinuationToken
 
 	// S3 Select Errors
@@ -447,6 +454,21 @@ var errorCodes = errorCodeMap{
 		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrMalformedACLError: {
+		Code:           "MalformedACLError",
+		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidCannedACL: {
+		Code:           "InvalidArgument",
+		Description:    "The canned ACL you provided is not valid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrInvalidTargetBucketForLogging: {
+		Code:           "InvalidTargetBucketForLogging",
+		Description:    "The target bucket for logging does not exist.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrMissingContentLength: {
 		Code:           "MissingContentLength",
 		Description:    "You must provide the Content-Length HTTP header.",
@@ -763,6 +785,11 @@ var errorCodes = errorCodeMap{
 		Description:    "Size of filter rule value cannot exceed 1024 bytes in UTF-8 representation",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrObjectFilterInvalid: {
+		Code:           "InvalidArgument",
+		Description:    "The object filter of a notification configuration is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrOverlappingConfigs: {
 		Code:           "InvalidArgument",
 		Description:    "Configurations overlap. Configurations on the same bucket cannot share a common event type.",
@@ -1057,6 +1084,21 @@ var errorCodes = errorCodeMap{
 		Description:    "",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrDecommissionNotImplemented: {
+		Code:           "XMinioDecommissionNotImplemented",
+		Description:    "Decommission is only supported on servers with more than one zone.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ErrDecommissionAlreadyRunning: {
+		Code:           "XMinioDecommissionAlreadyRunning",
+		Description:    "A decommission is already running.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	ErrDecommissionNotRunning: {
+		Code:           "XMinioDecommissionNotRunning",
+		Description:    "No decommission is running.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrBackendDown: {
 		Code:           "XMinioBackendDown",
 		Description:    "Object storage backend is unreachable",
@@ -1530,6 +1572,12 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrAdminGroupNotEmpty
 	case errNoSuchPolicy:
 		apiErr = ErrAdminNoSuchPolicy
+	case errDecommissionNotSupported:
+		apiErr = ErrDecommissionNotImplemented
+	case errDecommissionRunning:
+		apiErr = ErrDecommissionAlreadyRunning
+	case errDecommissionNotRunning:
+		apiErr = ErrDecommissionNotRunning
 	case errSignatureMismatch:
 		apiErr = ErrSignatureDoesNotMatch
 	case errInvalidRange:
@@ -1681,6 +1729,8 @@ func toAPIErrorCode(ctx context.Context, err error) (apiErr APIErrorCode) {
 		apiErr = ErrFilterNameSuffix
 	case *event.ErrInvalidFilterValue:
 		apiErr = ErrFilterValueInvalid
+	case *event.ErrInvalidObjectFilter:
+		apiErr = ErrObjectFilterInvalid
 	case *event.ErrDuplicateEventName:
 		apiErr = ErrOverlappingConfigs
 	case *event.ErrDuplicateQueueConfiguration:
@@ -687,10 +687,20 @@ func writeErrorResponse(ctx context.Context, w http.ResponseWriter, err APIError
 	errorResponse := getAPIErrorResponse(ctx, err, reqURL.Path,
 		w.Header().Get(xhttp.AmzRequestID), globalDeploymentID)
 	encodedErrorResponse := encodeResponse(errorResponse)
+	setErrorCode(w, err.Code)
 	writeResponse(w, err.HTTPStatusCode, encodedErrorResponse, mimeXML)
 }
 
+// setErrorCode - records the S3 error code of a failed request for
+// audit logs, metrics and traces.
+func setErrorCode(w http.ResponseWriter, code string) {
+	if lrw, ok := w.(*logger.ResponseWriter); ok {
+		lrw.ErrorCode = code
+	}
+}
+
 func writeErrorResponseHeadersOnly(w http.ResponseWriter, err APIError) {
+	setErrorCode(w, err.Code)
 	writeResponse(w, err.HTTPStatusCode, nil, mimeNone)
 }
 
@@ -766,5 +776,6 @@ func writeCustomErrorResponseXML(ctx context.Context, w http.ResponseWriter, err
 	}
 
 	encodedErrorResponse := encodeResponse(errorResponse)
+	setErrorCode(w, err.Code)
 	writeResponse(w, err.HTTPStatusCode, encodedErrorResponse, mimeXML)
 }
@@ -0,0 +1,193 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+)
+
+// Upper bounds in seconds of the S3 API latency histogram buckets.
+var apiDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// APIRequestStats - requests of an S3 API to a bucket.
+type APIRequestStats struct {
+	API    string
+	Bucket string
+	Count  uint64
+	// Total duration of all requests in seconds.
+	Duration float64
+	// Number of requests per latency bucket, the last
+	// bucket counts requests slower than all bounds.
+	Buckets []uint64
+}
+
+// APIErrorStats - failed requests of an S3 API to a bucket
+// by S3 error code.
+type APIErrorStats struct {
+	API    string
+	Bucket string
+	Code   string
+	Count  uint64
+}
+
+// APIStats - S3 API statistics of a server, or of all
+// servers of a cluster.
+type APIStats struct {
+	Requests []APIRequestStats
+	Errors   []APIErrorStats
+}
+
+type apiStatsKey struct {
+	api, bucket string
+}
+
+type apiErrorKey struct {
+	api, bucket, code string
+}
+
+// apiStatsSys - collects the S3 API statistics of this server.
+type apiStatsSys struct {
+	mu       sync.Mutex
+	requests map[apiStatsKey]*APIRequestStats
+	errors   map[apiErrorKey]uint64
+	// Buckets known to exist, i.e. with successful requests.
+	buckets map[string]struct{}
+}
+
+func newAPIStatsSys() *apiStatsSys {
+	return &apiStatsSys{
+		requests: make(map[apiStatsKey]*APIRequestStats),
+		errors:   make(map[apiErrorKey]uint64),
+		buckets:  make(map[string]struct{}),
+	}
+}
+
+// record - records a request, code is the S3 error code of
+// failed requests. Failed requests to buckets not known to exist,
+// e.g. anonymous requests to random bucket names, are recorded
+// without bucket to bound the number of statistics.
+func (sys *apiStatsSys) record(api, bucket, code string, duration time.Duration) {
+	secs := duration.Seconds()
+	bucketIndex := sort.SearchFloat64s(apiDurationBuckets, secs)
+
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	switch {
+	case bucket == "":
+	case code == "" && api != "DeleteBucket":
+		sys.buckets[bucket] = struct{}{}
+	default:
+		if _, ok := sys.buckets[bucket]; !ok || code == "NoSuchBucket" {
+			bucket = ""
+		}
+	}
+
+	key := apiStatsKey{api, bucket}
+	stats, ok := sys.requests[key]
+	if !ok {
+		stats = &APIRequestStats{
+			API:     api,
+			Bucket:  bucket,
+			Buckets: make([]uint64, len(apiDurationBuckets)+1),
+		}
+		sys.requests[key] = stats
+	}
+	stats.Count++
+	stats.Duration += secs
+	stats.Buckets[bucketIndex]++
+
+	if code != "" {
+		sys.errors[apiErrorKey{api, bucket, code}]++
+	}
+}
+
+// removeBucket - drops the statistics of a deleted bucket.
+func (sys *apiStatsSys) removeBucket(bucket string) {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	delete(sys.buckets, bucket)
+	for key := range sys.requests {
+		if key.bucket == bucket {
+			delete(sys.requests, key)
+		}
+	}
+	for key := range sys.errors {
+		if key.bucket == bucket {
+			delete(sys.errors, key)
+		}
+	}
+}
+
+// stats - returns a copy of the statistics.
+func (sys *apiStatsSys) stats() APIStats {
+	sys.mu.Lock()
+	defer sys.mu.Unlock()
+
+	var stats APIStats
+	for _, requests := range sys.requests {
+		r := *requests
+		r.Buckets = append([]uint64{}, requests.Buckets...)
+		stats.Requests = append(stats.Requests, r)
+	}
+	for key, count := range sys.errors {
+		stats.Errors = append(stats.Errors, APIErrorStats{
+			API:    key.api,
+			Bucket: key.bucket,
+			Code:   key.code,
+			Count:  count,
+		})
+	}
+	return stats
+}
+
+// collectAPIStats - records the requests of an S3 API by bucket,
+// other APIs are not recorded.
+func collectAPIStats(api string, f http.HandlerFunc) http.HandlerFunc {
+	if !strings.HasPrefix(api, "s3.") {
+		return f
+	}
+	api = strings.TrimPrefix(api, "s3.")
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		lrw, ok := w.(*logger.ResponseWriter)
+		if !ok {
+			f.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		f.ServeHTTP(w, r)
+
+		var code string
+		if lrw.StatusCode >= http.StatusBadRequest {
+			if code = lrw.ErrorCode; code == "" {
+				code = strconv.Itoa(lrw.StatusCode)
+			}
+		}
+		globalAPIStats.record(api, mux.Vars(r)["bucket"], code, time.Since(start))
+	}
+}
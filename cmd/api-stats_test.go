@@ -0,0 +1,118 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCollectAPIStats(t *testing.T) {
+	savedStats := globalAPIStats
+	defer func() { globalAPIStats = savedStats }()
+	globalAPIStats = newAPIStatsSys()
+
+	handler := collectAPIStats("s3.HeadObject", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			writeErrorResponseHeadersOnly(w, errorCodes.ToAPIErr(ErrNoSuchKey))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	for _, req := range []struct{ bucket, url string }{
+		{"bucket", "/bucket/object"},
+		{"bucket", "/bucket/object?fail=true"},
+		{"bucket", "/bucket/object"},
+		// Failed requests to unknown buckets are recorded without bucket.
+		{"random", "/random/object?fail=true"},
+	} {
+		r := mux.SetURLVars(httptest.NewRequest(http.MethodHead, req.url, nil), map[string]string{"bucket": req.bucket})
+		w := logger.NewResponseWriter(httptest.NewRecorder())
+		handler(w, r)
+		if w.Header().Get("x-minio-error-code") != "" {
+			t.Fatal("Expected the error code not to be sent to the client")
+		}
+	}
+
+	// Non S3 APIs are not recorded.
+	internal := func(w http.ResponseWriter, r *http.Request) {}
+	if h := collectAPIStats("internal.DiskInfo", internal); reflect.ValueOf(h).Pointer() != reflect.ValueOf(internal).Pointer() {
+		t.Fatal("Expected internal APIs not to be recorded")
+	}
+
+	stats := globalAPIStats.stats()
+	sort.Slice(stats.Requests, func(i, j int) bool { return stats.Requests[i].Bucket < stats.Requests[j].Bucket })
+	sort.Slice(stats.Errors, func(i, j int) bool { return stats.Errors[i].Bucket < stats.Errors[j].Bucket })
+	if len(stats.Requests) != 2 {
+		t.Fatalf("Expected two buckets, got %v", stats.Requests)
+	}
+	if r := stats.Requests[0]; r.API != "HeadObject" || r.Bucket != "" || r.Count != 1 {
+		t.Fatalf("Expected 1 HeadObject request to an unknown bucket, got %v", r)
+	}
+	if r := stats.Requests[1]; r.API != "HeadObject" || r.Bucket != "bucket" || r.Count != 3 {
+		t.Fatalf("Expected 3 HeadObject requests to bucket, got %v", r)
+	}
+	expectedErrors := []APIErrorStats{
+		{API: "HeadObject", Bucket: "", Code: "NoSuchKey", Count: 1},
+		{API: "HeadObject", Bucket: "bucket", Code: "NoSuchKey", Count: 1},
+	}
+	if !reflect.DeepEqual(stats.Errors, expectedErrors) {
+		t.Fatalf("Expected %v, got %v", expectedErrors, stats.Errors)
+	}
+
+	globalAPIStats.removeBucket("bucket")
+	if stats = globalAPIStats.stats(); len(stats.Requests) != 1 || len(stats.Errors) != 1 {
+		t.Fatalf("Expected no stats for a deleted bucket, got %v", stats)
+	}
+	// The deleted bucket is no longer known.
+	globalAPIStats.record("HeadObject", "bucket", "NoSuchBucket", time.Millisecond)
+	if stats = globalAPIStats.stats(); len(stats.Requests) != 1 {
+		t.Fatalf("Expected no stats for a deleted bucket, got %v", stats)
+	}
+}
+
+func TestCollectAPIMetrics(t *testing.T) {
+	node := newAPIStatsSys()
+	node.record("GetObject", "bucket", "", 2*time.Millisecond)
+	node.record("GetObject", "bucket", "NoSuchKey", 20*time.Second)
+
+	// Cluster metrics are labeled with the server they were counted by.
+	ch := make(chan prometheus.Metric, 10)
+	collectAPIMetrics(ch, node.stats(), prometheus.Labels{"node": "server1:9000"})
+	close(ch)
+
+	var n int
+	for metric := range ch {
+		n++
+		if desc := metric.Desc().String(); !strings.Contains(desc, `node="server1:9000"`) {
+			t.Fatalf("Expected node label, got %s", desc)
+		}
+	}
+	// Requests, latencies and errors of GetObject.
+	if n != 3 {
+		t.Fatalf("Expected 3 metrics, got %d", n)
+	}
+}
@@ -165,11 +165,16 @@ func mustGetClaimsFromToken(r *http.Request) map[string]interface{} {
 
 // Fetch claims in the security token returned by the client.
 func getClaimsFromToken(r *http.Request) (map[string]interface{}, error) {
-	claims := make(map[string]interface{})
 	token := getSessionToken(r)
 	if token == "" {
-		return claims, nil
+		return make(map[string]interface{}), nil
 	}
+	return getClaimsFromSessionToken(token)
+}
+
+// Fetch claims from the given session token.
+func getClaimsFromSessionToken(token string) (map[string]interface{}, error) {
+	claims := make(map[string]interface{})
 	stsTokenCallback := func(jwtToken *jwtgo.Token) (interface{}, error) {
 		// JWT token for x-amz-security-token is signed with admin
 		// secret key, temporary credentials become invalid if
@@ -329,6 +334,9 @@ func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, actio
 		r.Body = ioutil.NopCloser(bytes.NewReader(payload))
 	}
 
+	// Record the policy decision in the audit log.
+	defer func() { logger.SetPolicyDecision(ctx, s3Err == ErrNone) }()
+
 	if cred.AccessKey == "" {
 		if globalPolicySys.IsAllowed(policy.Args{
 			AccountName:     cred.AccessKey,
@@ -341,6 +349,10 @@ func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, actio
 			// Request is allowed return the appropriate access key.
 			return cred.AccessKey, owner, ErrNone
 		}
+		if isACLAllowed(ctx, r, action, bucketName, objectName, locationConstraint, cred, owner, claims) {
+			// Request is allowed by the bucket or object ACL.
+			return cred.AccessKey, owner, ErrNone
+		}
 		return accessKey, owner, ErrAccessDenied
 	}
 
@@ -356,9 +368,44 @@ func checkRequestAuthTypeToAccessKey(ctx context.Context, r *http.Request, actio
 		// Request is allowed return the appropriate access key.
 		return cred.AccessKey, owner, ErrNone
 	}
+	if isACLAllowed(ctx, r, action, bucketName, objectName, locationConstraint, cred, owner, claims) {
+		// Request is allowed by the bucket or object ACL.
+		return cred.AccessKey, owner, ErrNone
+	}
 	return accessKey, owner, ErrAccessDenied
 }
 
+// isACLAllowed - checks whether the bucket or object ACL grants the
+// request. ACLs only grant requests which neither the bucket policy
+// nor the IAM policies of the account explicitly deny.
+func isACLAllowed(ctx context.Context, r *http.Request, action policy.Action, bucketName, objectName, locationConstraint string, cred auth.Credentials, owner bool, claims map[string]interface{}) bool {
+	if globalPolicySys.IsDenied(policy.Args{
+		AccountName:     cred.AccessKey,
+		Action:          action,
+		BucketName:      bucketName,
+		ConditionValues: getConditionValues(r, locationConstraint, cred.AccessKey),
+		IsOwner:         owner,
+		ObjectName:      objectName,
+	}) {
+		return false
+	}
+
+	authenticated := cred.AccessKey != ""
+	if authenticated && globalIAMSys.IsDenied(iampolicy.Args{
+		AccountName:     cred.AccessKey,
+		Action:          iampolicy.Action(action),
+		BucketName:      bucketName,
+		ConditionValues: getConditionValues(r, "", cred.AccessKey),
+		ObjectName:      objectName,
+		IsOwner:         owner,
+		Claims:          claims,
+	}) {
+		return false
+	}
+
+	return globalACLSys.IsAllowed(ctx, action, bucketName, objectName, authenticated)
+}
+
 // Verify if request has valid AWS Signature Version '2'.
 func isReqAuthenticatedV2(r *http.Request) (s3Error APIErrorCode) {
 	if isRequestSignatureV2(r) {
@@ -476,7 +523,14 @@ func (a authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 // isPutAllowed - check if PUT operation is allowed on the resource, this
 // call verifies bucket policies and IAM policies, supports multi user
 // checks etc.
-func isPutAllowed(atype authType, bucketName, objectName string, r *http.Request) (s3Err APIErrorCode) {
+func isPutAllowed(ctx context.Context, atype authType, bucketName, objectName string, r *http.Request) (s3Err APIErrorCode) {
+	return isPutActionAllowed(ctx, atype, bucketName, objectName, r, policy.PutObjectAction)
+}
+
+// isPutActionAllowed - check if the action of a PUT operation is allowed
+// on the resource, unlike checkRequestAuthType the signature is verified
+// later while reading the request body.
+func isPutActionAllowed(ctx context.Context, atype authType, bucketName, objectName string, r *http.Request, action policy.Action) (s3Err APIErrorCode) {
 	var cred auth.Credentials
 	var owner bool
 	switch atype {
@@ -497,10 +551,13 @@ func isPutAllowed(atype authType, bucketName, objectName string, r *http.Request
 		return s3Err
 	}
 
+	// Record the policy decision in the audit log.
+	defer func() { logger.SetPolicyDecision(ctx, s3Err == ErrNone) }()
+
 	if cred.AccessKey == "" {
 		if globalPolicySys.IsAllowed(policy.Args{
 			AccountName:     cred.AccessKey,
-			Action:          policy.PutObjectAction,
+			Action:          action,
 			BucketName:      bucketName,
 			ConditionValues: getConditionValues(r, "", ""),
 			IsOwner:         false,
@@ -508,12 +565,16 @@ func isPutAllowed(atype authType, bucketName, objectName string, r *http.Request
 		}) {
 			return ErrNone
 		}
+		if isACLAllowed(ctx, r, action, bucketName, objectName, "", cred, owner, claims) {
+			// Request is allowed by the bucket ACL.
+			return ErrNone
+		}
 		return ErrAccessDenied
 	}
 
 	if globalIAMSys.IsAllowed(iampolicy.Args{
 		AccountName:     cred.AccessKey,
-		Action:          policy.PutObjectAction,
+		Action:          iampolicy.Action(action),
 		BucketName:      bucketName,
 		ConditionValues: getConditionValues(r, "", cred.AccessKey),
 		ObjectName:      objectName,
@@ -522,5 +583,9 @@ func isPutAllowed(atype authType, bucketName, objectName string, r *http.Request
 	}) {
 		return ErrNone
 	}
+	if isACLAllowed(ctx, r, action, bucketName, objectName, "", cred, owner, claims) {
+		// Request is allowed by the bucket ACL.
+		return ErrNone
+	}
 	return ErrAccessDenied
 }
@@ -18,6 +18,8 @@ package cmd
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
@@ -41,10 +43,31 @@ type healResult struct {
 	err    error
 }
 
+// Maximum number of objects waiting in the MRF queue, objects
+// reported while the queue is full are healed by the daily sweep.
+const mrfQueueSize = 10000
+
+// mrfOperation - object which was found degraded on read or only
+// partially written, queued to be healed in the background.
+type mrfOperation struct {
+	bucket   string
+	object   string
+	scanMode madmin.HealScanMode
+}
+
 // healRoutine receives heal tasks, to heal buckets, objects and format.json
 type healRoutine struct {
 	tasks  chan healTask
 	doneCh chan struct{}
+
+	// Most recently failed (MRF) objects.
+	mrfCh      chan mrfOperation
+	mrfMu      sync.Mutex
+	mrfPending map[string]struct{}
+
+	mrfHealed  int64
+	mrfFailed  int64
+	mrfDropped int64
 }
 
 // Add a new task in the tasks queue
@@ -52,6 +75,59 @@ func (h *healRoutine) queueHealTask(task healTask) {
 	h.tasks <- task
 }
 
+// Add an object to the MRF queue, never blocks. Objects already
+// waiting in the queue are not added twice.
+func (h *healRoutine) queueMRFHeal(op mrfOperation) {
+	key := pathJoin(op.bucket, op.object)
+
+	h.mrfMu.Lock()
+	defer h.mrfMu.Unlock()
+	if _, ok := h.mrfPending[key]; ok {
+		return
+	}
+	select {
+	case h.mrfCh <- op:
+		h.mrfPending[key] = struct{}{}
+	default:
+		atomic.AddInt64(&h.mrfDropped, 1)
+	}
+}
+
+// Heal an object taken from the MRF queue.
+func (h *healRoutine) healMRF(ctx context.Context, op mrfOperation) {
+	h.mrfMu.Lock()
+	delete(h.mrfPending, pathJoin(op.bucket, op.object))
+	h.mrfMu.Unlock()
+
+	_, err := bgHealObject(ctx, op.bucket, op.object, madmin.HealOpts{ScanMode: op.scanMode})
+	if err != nil && !isErrObjectNotFound(err) {
+		atomic.AddInt64(&h.mrfFailed, 1)
+		logger.LogIf(ctx, err)
+		return
+	}
+	atomic.AddInt64(&h.mrfHealed, 1)
+}
+
+// Returns the MRF queue depth and heal counters.
+func (h *healRoutine) mrfStatus() (queued, healed, failed, dropped int64) {
+	return int64(len(h.mrfCh)), atomic.LoadInt64(&h.mrfHealed),
+		atomic.LoadInt64(&h.mrfFailed), atomic.LoadInt64(&h.mrfDropped)
+}
+
+// Wait at max 10 minute for an inprogress request before proceeding to heal
+func waitForLowHTTPReq() {
+	if globalHTTPServer == nil {
+		return
+	}
+	waitCount := 600
+	// Any requests in progress, delay the heal.
+	for (globalHTTPServer.GetRequestCount() >= int32(globalXLSetCount*globalXLSetDriveCount)) &&
+		waitCount > 0 {
+		waitCount--
+		time.Sleep(1 * time.Second)
+	}
+}
+
 // Wait for heal requests and process them
 func (h *healRoutine) run() {
 	ctx := context.Background()
@@ -61,16 +137,7 @@ func (h *healRoutine) run() {
 			if !ok {
 				break
 			}
-			if globalHTTPServer != nil {
-				// Wait at max 10 minute for an inprogress request before proceeding to heal
-				waitCount := 600
-				// Any requests in progress, delay the heal.
-				for (globalHTTPServer.GetRequestCount() >= int32(globalXLSetCount*globalXLSetDriveCount)) &&
-					waitCount > 0 {
-					waitCount--
-					time.Sleep(1 * time.Second)
-				}
-			}
+			waitForLowHTTPReq()
 
 			var res madmin.HealResultItem
 			var err error
@@ -92,17 +159,50 @@ func (h *healRoutine) run() {
 	}
 }
 
+// Heal the objects of the MRF queue, separately from the heal
+// requests which must not wait for the MRF queue to drain.
+func (h *healRoutine) runMRF() {
+	ctx := context.Background()
+	for {
+		select {
+		case op := <-h.mrfCh:
+			waitForLowHTTPReq()
+			h.healMRF(ctx, op)
+		case <-h.doneCh:
+			return
+		case <-GlobalServiceDoneCh:
+			return
+		}
+	}
+}
+
 func initHealRoutine() *healRoutine {
 	return &healRoutine{
-		tasks:  make(chan healTask),
-		doneCh: make(chan struct{}),
+		tasks:      make(chan healTask),
+		doneCh:     make(chan struct{}),
+		mrfCh:      make(chan mrfOperation, mrfQueueSize),
+		mrfPending: make(map[string]struct{}),
 	}
 
 }
 
+// queueMRFHeal - queues an object which was found degraded on read,
+// or only written to some of the disks, to be healed in the background.
+func queueMRFHeal(bucket, object string, scanMode madmin.HealScanMode) {
+	if globalBackgroundHealing == nil {
+		return
+	}
+	// Temporary and multipart data is never healed.
+	if bucket == minioMetaTmpBucket || bucket == minioMetaMultipartBucket {
+		return
+	}
+	globalBackgroundHealing.queueMRFHeal(mrfOperation{bucket: bucket, object: object, scanMode: scanMode})
+}
+
 func initBackgroundHealing() {
 	healBg := initHealRoutine()
 	go healBg.run()
+	go healBg.runMRF()
 
 	globalBackgroundHealing = healBg
 }
@@ -156,5 +256,11 @@ func bgHealObject(ctx context.Context, bucket, object string, opts madmin.HealOp
 	if objectAPI == nil {
 		return madmin.HealResultItem{}, errServerNotInitialized
 	}
-	return objectAPI.HealObject(ctx, bucket, object, opts.DryRun, opts.Remove, opts.ScanMode)
+	res, err := objectAPI.HealObject(ctx, bucket, object, opts.DryRun, opts.Remove, opts.ScanMode)
+
+	// Notify the outcome, a dry run does not heal anything.
+	if !opts.DryRun {
+		sendHealEvent(bucket, object, res, err)
+	}
+	return res, err
 }
@@ -0,0 +1,537 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Interval at which local drives are checked for replacements.
+	defaultMonitorNewDiskInterval = time.Minute
+
+	// Number of healed objects after which the ETA is estimated
+	// again and the progress is saved.
+	driveHealETAInterval = 100
+
+	// Healing progress kept on a replaced drive until it is healed.
+	healingTrackerFile = "healing.json"
+)
+
+// Objects of the meta bucket healed along with a replaced drive.
+var driveHealMetaPrefixes = []string{bucketConfigPrefix + SlashSeparator, minioConfigPrefix + SlashSeparator}
+
+// driveHealTracker - records the healing progress of the replaced
+// drives of this server.
+type driveHealTracker struct {
+	sync.RWMutex
+	drives map[string]*madmin.DriveHealInfo
+}
+
+func newDriveHealTracker() *driveHealTracker {
+	return &driveHealTracker{
+		drives: make(map[string]*madmin.DriveHealInfo),
+	}
+}
+
+var globalDriveHealTracker = newDriveHealTracker()
+
+// Starts tracking the given drive with the progress saved on the
+// drive, returns false if the drive is already being healed.
+func (t *driveHealTracker) start(info madmin.DriveHealInfo) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	if current, ok := t.drives[info.Endpoint]; ok && current.Finished.IsZero() {
+		return false
+	}
+	t.drives[info.Endpoint] = &info
+	return true
+}
+
+// Returns the healing progress of the drive.
+func (t *driveHealTracker) get(endpoint string) (madmin.DriveHealInfo, bool) {
+	t.RLock()
+	defer t.RUnlock()
+
+	info, ok := t.drives[endpoint]
+	if !ok {
+		return madmin.DriveHealInfo{}, false
+	}
+	return *info, true
+}
+
+// Updates the progress of all drives healed with the set.
+func (t *driveHealTracker) update(endpoints []string, fn func(info *madmin.DriveHealInfo)) {
+	t.Lock()
+	defer t.Unlock()
+
+	for _, endpoint := range endpoints {
+		if info, ok := t.drives[endpoint]; ok {
+			fn(info)
+		}
+	}
+}
+
+// Returns the healing progress of all drives of this server.
+func (t *driveHealTracker) list() []madmin.DriveHealInfo {
+	t.RLock()
+	defer t.RUnlock()
+
+	drives := make([]madmin.DriveHealInfo, 0, len(t.drives))
+	for _, info := range t.drives {
+		drives = append(drives, *info)
+	}
+	sort.Slice(drives, func(i, j int) bool {
+		return drives[i].Endpoint < drives[j].Endpoint
+	})
+	return drives
+}
+
+// healingTracker - on disk format of the healing progress of a
+// replaced drive. It is written before the drive is formatted and
+// removed once the drive is healed, a drive which still has it is
+// healed again after a restart.
+type healingTracker struct {
+	Version int                  `json:"version"`
+	Info    madmin.DriveHealInfo `json:"info"`
+	// Bucket and object healed last, buckets are healed in
+	// lexical order after the meta bucket.
+	Bucket string `json:"bucket,omitempty"`
+	Object string `json:"object,omitempty"`
+}
+
+func loadHealingTracker(disk StorageAPI) (healingTracker, error) {
+	var t healingTracker
+	data, err := disk.ReadAll(minioMetaBucket, healingTrackerFile)
+	if err != nil {
+		return t, err
+	}
+	err = json.Unmarshal(data, &t)
+	return t, err
+}
+
+// saveHealingTracker - replaces the healing progress of the drive,
+// the meta bucket is created on drives which are not formatted yet.
+func saveHealingTracker(disk StorageAPI, t healingTracker) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if err = disk.MakeVol(minioMetaBucket); err != nil && err != errVolumeExists {
+		return err
+	}
+
+	tmpFile := mustGetUUID() + ".json"
+	defer disk.DeleteFile(minioMetaBucket, tmpFile)
+
+	if err = disk.WriteAll(minioMetaBucket, tmpFile, bytes.NewReader(data)); err != nil {
+		return err
+	}
+	return disk.RenameFile(minioMetaBucket, tmpFile, minioMetaBucket, healingTrackerFile)
+}
+
+// initLocalDisksAutoHeal creates a go-routine which detects replaced
+// local drives and heals their erasure sets.
+func initLocalDisksAutoHeal() {
+	go monitorLocalDisksAndHeal()
+}
+
+// Checks the local drives at a regular interval, a drive without
+// format.json was replaced and gets formatted and healed right away
+// instead of waiting for the daily sweep.
+func monitorLocalDisksAndHeal() {
+	var objAPI ObjectLayer
+	ctx := context.Background()
+
+	// Wait until the object layer is ready
+	for {
+		objAPI = newObjectLayerFn()
+		if objAPI == nil {
+			time.Sleep(time.Second)
+			continue
+		}
+		break
+	}
+
+	// Resume healing drives which were healed before a restart.
+	healReplacedDisks(ctx, objAPI)
+
+	ticker := time.NewTicker(defaultMonitorNewDiskInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-GlobalServiceDoneCh:
+			return
+		case <-ticker.C:
+			healReplacedDisks(ctx, objAPI)
+		}
+	}
+}
+
+// Formats and heals all replaced local drives, along with the drives
+// whose healing was interrupted by a restart.
+func healReplacedDisks(ctx context.Context, objAPI ObjectLayer) {
+	zones := getZoneSets(objAPI)
+
+	var formatNeeded, found bool
+	for zoneIdx, s := range zones {
+		for _, index := range s.unformattedLocalEndpoints() {
+			// Healing is resumed after a restart once the
+			// drive is formatted.
+			endpoint := s.endpoints[index]
+			t := healingTracker{
+				Version: 1,
+				Info: madmin.DriveHealInfo{
+					Endpoint: endpoint.String(),
+					Zone:     zoneIdx,
+					Set:      index / s.drivesPerSet,
+					Started:  UTCNow(),
+				},
+			}
+			if err := saveLocalHealingTracker(endpoint, t); err != nil {
+				logger.LogIf(ctx, err)
+				continue
+			}
+			formatNeeded = true
+		}
+	}
+
+	if formatNeeded {
+		// Format the new drives, peers reload the new format.
+		if _, err := bgHealDiskFormat(ctx, madmin.HealOpts{}); err != nil {
+			logger.LogIf(ctx, err)
+			return
+		}
+	}
+
+	healing := make([]map[int]healingTracker, len(zones))
+	for zoneIdx, s := range zones {
+		healing[zoneIdx] = s.healingLocalEndpoints()
+		if len(healing[zoneIdx]) > 0 {
+			found = true
+		}
+	}
+	if !found {
+		return
+	}
+
+	for zoneIdx, s := range zones {
+		// Drives of the same set are healed together.
+		setEndpoints := make(map[int][]Endpoint)
+		setTrackers := make(map[int][]healingTracker)
+		for index, t := range healing[zoneIdx] {
+			setIndex := index / s.drivesPerSet
+			endpoint := s.endpoints[index]
+			t.Info.Endpoint, t.Info.Zone, t.Info.Set = endpoint.String(), zoneIdx, setIndex
+			if globalDriveHealTracker.start(t.Info) {
+				setEndpoints[setIndex] = append(setEndpoints[setIndex], endpoint)
+				setTrackers[setIndex] = append(setTrackers[setIndex], t)
+			}
+		}
+		// Drives are healed in the background, the tracker
+		// does not start a drive again while it is healed.
+		for setIndex, endpoints := range setEndpoints {
+			go s.healReplacedDrives(ctx, setIndex, endpoints, setTrackers[setIndex])
+		}
+	}
+}
+
+// Returns the erasure coded sets of all zones of the object layer.
+func getZoneSets(objAPI ObjectLayer) []*xlSets {
+	switch z := objAPI.(type) {
+	case *xlSets:
+		return []*xlSets{z}
+	case *xlZones:
+		return z.zones
+	}
+	return nil
+}
+
+// Returns the indexes of local endpoints whose drives are not
+// formatted, a local drive stays connected when it is swapped
+// hence format.json is always checked.
+func (s *xlSets) unformattedLocalEndpoints() []int {
+	var indexes []int
+	for index, endpoint := range s.endpoints {
+		if !endpoint.IsLocal {
+			continue
+		}
+		disk, err := newStorageAPI(endpoint)
+		if err != nil {
+			continue
+		}
+		_, err = loadFormatXL(disk)
+		disk.Close()
+		if err == errUnformattedDisk {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+// Returns the healing progress of the local formatted drives which
+// are being healed, by the index of their endpoint.
+func (s *xlSets) healingLocalEndpoints() map[int]healingTracker {
+	trackers := make(map[int]healingTracker)
+	for index, endpoint := range s.endpoints {
+		if !endpoint.IsLocal {
+			continue
+		}
+		disk, err := newStorageAPI(endpoint)
+		if err != nil {
+			continue
+		}
+		if _, err = loadFormatXL(disk); err == nil {
+			var t healingTracker
+			if t, err = loadHealingTracker(disk); err == nil {
+				trackers[index] = t
+			}
+		}
+		disk.Close()
+	}
+	return trackers
+}
+
+// Saves the healing progress on the local drive of the endpoint.
+func saveLocalHealingTracker(endpoint Endpoint, t healingTracker) error {
+	disk, err := newStorageAPI(endpoint)
+	if err != nil {
+		return err
+	}
+	defer disk.Close()
+	return saveHealingTracker(disk, t)
+}
+
+// Removes the healing progress from the local drive of the endpoint.
+func removeLocalHealingTracker(endpoint Endpoint) error {
+	disk, err := newStorageAPI(endpoint)
+	if err != nil {
+		return err
+	}
+	defer disk.Close()
+	if err = disk.DeleteFile(minioMetaBucket, healingTrackerFile); err != nil && err != errFileNotFound {
+		return err
+	}
+	return nil
+}
+
+// Heals all buckets and objects of the set, the heal is throttled
+// when the server is busy serving requests. Healing resumes after
+// the object healed last if all drives were healed up to it, the
+// progress is saved when the server shuts down.
+func (s *xlSets) healReplacedDrives(ctx context.Context, setIndex int, endpoints []Endpoint, trackers []healingTracker) {
+	set := s.sets[setIndex]
+
+	drives := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		drives[i] = endpoint.String()
+	}
+
+	resumeBucket, resumeObject := trackers[0].Bucket, trackers[0].Object
+	for _, t := range trackers[1:] {
+		if t.Bucket != resumeBucket || t.Object != resumeObject {
+			resumeBucket, resumeObject = "", ""
+			break
+		}
+	}
+	if resumeBucket == minioMetaBucket {
+		resumeBucket, resumeObject = "", ""
+	}
+
+	buckets, err := s.ListBucketsHeal(ctx)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		buckets = nil
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Name < buckets[j].Name
+	})
+
+	// Saves the progress on the drives.
+	var lastBucket, lastObject string
+	saveProgress := func() {
+		for _, endpoint := range endpoints {
+			info, ok := globalDriveHealTracker.get(endpoint.String())
+			if !ok {
+				continue
+			}
+			t := healingTracker{Version: 1, Info: info, Bucket: lastBucket, Object: lastObject}
+			logger.LogIf(ctx, saveLocalHealingTracker(endpoint, t))
+		}
+	}
+
+	var count int
+	healObject := func(bucket, object string) error {
+		select {
+		case <-GlobalServiceDoneCh:
+			return errHealStopSignalled
+		default:
+		}
+		waitForLowHTTPReq()
+
+		res, err := set.HealObject(ctx, bucket, object, false, false, madmin.HealNormalScan)
+		globalDriveHealTracker.update(drives, func(info *madmin.DriveHealInfo) {
+			if err != nil && !isErrObjectNotFound(err) {
+				info.ObjectsFailed++
+				return
+			}
+			info.ObjectsHealed++
+			info.BytesHealed += res.ObjectSize
+		})
+		if err != nil && !isErrObjectNotFound(err) {
+			logger.LogIf(ctx, err)
+		}
+
+		lastBucket, lastObject = bucket, object
+		count++
+		if count%driveHealETAInterval == 0 {
+			s.estimateDriveHealETA(setIndex, endpoints)
+			saveProgress()
+		}
+		// Failures are recorded, healing goes on with the next object.
+		return nil
+	}
+
+	// Returns true once the server shuts down, after saving
+	// the progress to resume from on the next start.
+	stopped := func(err error) bool {
+		if err == nil {
+			return false
+		}
+		if err == errHealStopSignalled {
+			saveProgress()
+			return true
+		}
+		logger.LogIf(ctx, err)
+		return false
+	}
+
+	if resumeBucket == "" {
+		for _, prefix := range driveHealMetaPrefixes {
+			if stopped(s.healSetObjects(ctx, setIndex, minioMetaBucket, prefix, "", healObject)) {
+				return
+			}
+		}
+	}
+	for _, bucket := range buckets {
+		if bucket.Name < resumeBucket {
+			// Healed before the restart.
+			continue
+		}
+		if _, err = set.HealBucket(ctx, bucket.Name, false, false); err != nil {
+			logger.LogIf(ctx, err)
+			continue
+		}
+		marker := ""
+		if bucket.Name == resumeBucket {
+			marker = resumeObject
+		}
+		if stopped(s.healSetObjects(ctx, setIndex, bucket.Name, "", marker, healObject)) {
+			return
+		}
+	}
+
+	globalDriveHealTracker.update(drives, func(info *madmin.DriveHealInfo) {
+		info.Finished = UTCNow()
+		info.ETA = time.Time{}
+	})
+	for _, endpoint := range endpoints {
+		logger.LogIf(ctx, removeLocalHealingTracker(endpoint))
+	}
+}
+
+// Calls healObjectFn for all objects of the bucket after the marker
+// found on any disk of the set.
+func (s *xlSets) healSetObjects(ctx context.Context, setIndex int, bucket, prefix, marker string, healObjectFn func(string, string) error) error {
+	endWalkCh := make(chan struct{})
+	defer close(endWalkCh)
+
+	listDir := listDirSetsFactory(ctx, s.sets[setIndex])
+	walkResultCh := startTreeWalk(ctx, bucket, prefix, marker, true, listDir, endWalkCh)
+	for {
+		walkResult, ok := <-walkResultCh
+		if !ok {
+			break
+		}
+		if err := healObjectFn(bucket, walkResult.entry); err != nil {
+			return toObjectErr(err, bucket, walkResult.entry)
+		}
+		if walkResult.end {
+			break
+		}
+	}
+	return nil
+}
+
+// Estimates when healing of the drives completes by comparing their
+// used space with the used space of the other drives of the set.
+func (s *xlSets) estimateDriveHealETA(setIndex int, endpoints []Endpoint) {
+	// Local drives are identified by their path.
+	healing := make(map[string]bool, len(endpoints))
+	drives := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		healing[endpoint.Path] = true
+		drives[i] = endpoint.String()
+	}
+
+	var healedUsed, otherUsed uint64
+	var healedCount, otherCount uint64
+	for _, disk := range s.GetAllDisks(setIndex)() {
+		if disk == nil {
+			continue
+		}
+		di, err := disk.DiskInfo()
+		if err != nil {
+			continue
+		}
+		if healing[disk.String()] {
+			healedUsed += di.Used
+			healedCount++
+		} else {
+			otherUsed += di.Used
+			otherCount++
+		}
+	}
+	if healedCount == 0 || otherCount == 0 || healedUsed == 0 {
+		return
+	}
+	progress := float64(healedUsed/healedCount) / float64(otherUsed/otherCount)
+	if progress <= 0 || progress >= 1 {
+		return
+	}
+
+	globalDriveHealTracker.update(drives, func(info *madmin.DriveHealInfo) {
+		elapsed := time.Since(info.Started)
+		info.ETA = info.Started.Add(time.Duration(float64(elapsed) / progress))
+	})
+}
+
+// getLocalDrivesHealStatus - returns the healing progress of the
+// replaced drives of this server.
+func getLocalDrivesHealStatus() []madmin.DriveHealInfo {
+	return globalDriveHealTracker.list()
+}
@@ -0,0 +1,167 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Waits until the drives healed in the background are healed.
+func waitForDriveHeals(t *testing.T, count int) []madmin.DriveHealInfo {
+	t.Helper()
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		drives := getLocalDrivesHealStatus()
+		finished := 0
+		for _, drive := range drives {
+			if !drive.Finished.IsZero() {
+				finished++
+			}
+		}
+		if finished == count {
+			return drives
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d healed drives, got %v", count, drives)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHealReplacedDisks(t *testing.T) {
+	objLayer, fsDirs, err := prepareXLSets32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = objLayer
+	globalObjLayerMutex.Unlock()
+	// Formatting the replaced drive reloads the format on all peers.
+	notificationSys := globalNotificationSys
+	globalNotificationSys = &NotificationSys{}
+	defer func() {
+		globalNotificationSys = notificationSys
+	}()
+	globalDriveHealTracker = newDriveHealTracker()
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = objLayer.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello")
+	var objects []string
+	for i := 0; i < 20; i++ {
+		object := fmt.Sprintf("object%d", i)
+		if _, err = objLayer.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, object)
+	}
+
+	// Nothing to heal while all drives are formatted.
+	healReplacedDisks(ctx, objLayer)
+	if drives := getLocalDrivesHealStatus(); len(drives) != 0 {
+		t.Fatalf("Expected no healed drives, got %v", drives)
+	}
+
+	// Replace the first drive of the second set.
+	replaced := fsDirs[16]
+	if err = os.RemoveAll(replaced); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.MkdirAll(replaced, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	healReplacedDisks(ctx, objLayer)
+
+	drives := waitForDriveHeals(t, 1)
+	if len(drives) != 1 {
+		t.Fatalf("Expected one healed drive, got %v", drives)
+	}
+	drive := drives[0]
+	if drive.Set != 1 || drive.Finished.IsZero() || drive.ObjectsFailed != 0 {
+		t.Fatalf("Unexpected drive heal status %v", drive)
+	}
+
+	s := objLayer.(*xlSets)
+	var setObjects int64
+	for _, object := range objects {
+		if s.getHashedSetIndex(object) != 1 {
+			continue
+		}
+		setObjects++
+		if _, err = os.Stat(path.Join(replaced, bucket, object, xlMetaJSONFile)); err != nil {
+			t.Fatalf("Expected %s to be healed, %s", object, err)
+		}
+	}
+	if drive.ObjectsHealed < setObjects {
+		t.Fatalf("Expected at least %d healed objects, got %d", setObjects, drive.ObjectsHealed)
+	}
+	if _, err = os.Stat(path.Join(replaced, minioMetaBucket, healingTrackerFile)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the healing progress to be removed, got %v", err)
+	}
+
+	// A formatted drive whose healing was interrupted by a restart
+	// is healed again.
+	var object string
+	for _, object = range objects {
+		if s.getHashedSetIndex(object) == 0 {
+			break
+		}
+	}
+	interrupted := fsDirs[0]
+	if err = os.RemoveAll(path.Join(interrupted, bucket, object)); err != nil {
+		t.Fatal(err)
+	}
+	disk, err := newPosix(interrupted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = saveHealingTracker(disk, healingTracker{Version: 1, Info: madmin.DriveHealInfo{Started: UTCNow()}}); err != nil {
+		t.Fatal(err)
+	}
+
+	healReplacedDisks(ctx, objLayer)
+
+	drives = waitForDriveHeals(t, 2)
+	if len(drives) != 2 {
+		t.Fatalf("Expected two healed drives, got %v", drives)
+	}
+	for _, drive = range drives {
+		if drive.Finished.IsZero() || (drive.Endpoint == interrupted && drive.Set != 0) {
+			t.Fatalf("Unexpected drive heal status %v", drive)
+		}
+	}
+	if _, err = os.Stat(path.Join(interrupted, bucket, object, xlMetaJSONFile)); err != nil {
+		t.Fatalf("Expected %s to be healed, %s", object, err)
+	}
+	if _, err = loadHealingTracker(disk); err != errFileNotFound {
+		t.Fatalf("Expected the healing progress to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,325 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/cmd/config/scrubber"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Scrubber progress file.
+	scrubberConfigFile = "scrubber.json"
+
+	// Lock of the meta bucket held by the scrubbing server.
+	scrubberLockPath = "scrubber.lock"
+
+	// Number of verified objects after which the progress is saved.
+	scrubberSaveInterval = 100
+)
+
+var scrubberTimeout = newDynamicTimeout(60*time.Second, time.Second)
+
+// scrubberStats - counters of the bitrot scrubber of this server.
+type scrubberStats struct {
+	objectsScanned  uint64
+	bytesVerified   uint64
+	corruptedShards uint64
+	repairedShards  uint64
+}
+
+var globalScrubberStats scrubberStats
+
+// scrubberState - on disk format of the scrubber progress, the
+// current cycle resumes from Zone, Bucket and Marker.
+type scrubberState struct {
+	Version    int       `json:"version"`
+	CycleStart time.Time `json:"cycleStart"`
+	CycleEnd   time.Time `json:"cycleEnd,omitempty"`
+	Zone       int       `json:"zone"`
+	Bucket     string    `json:"bucket,omitempty"`
+	Marker     string    `json:"marker,omitempty"`
+}
+
+// scrubThrottle - limits the rate at which data is read from disks.
+type scrubThrottle struct {
+	rate  uint64
+	start time.Time
+	bytes uint64
+}
+
+func newScrubThrottle(rate uint64) *scrubThrottle {
+	return &scrubThrottle{rate: rate, start: time.Now()}
+}
+
+// Waits until reading n more bytes stays within the rate limit.
+func (t *scrubThrottle) wait(n int64) {
+	if t.rate == 0 {
+		return
+	}
+	t.bytes += uint64(n)
+	expected := time.Duration(float64(t.bytes) / float64(t.rate) * float64(time.Second))
+	if d := expected - time.Since(t.start); d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// initBackgroundScrubber creates a go-routine which verifies the
+// bitrot checksums of all objects once every scrubber cycle.
+func initBackgroundScrubber() {
+	go backgroundScrubber(globalScrubberConfig)
+}
+
+func backgroundScrubber(config scrubber.Config) {
+	var objAPI ObjectLayer
+	ctx := context.Background()
+
+	// Wait until the object layer is ready
+	for {
+		objAPI = newObjectLayerFn()
+		if objAPI == nil {
+			time.Sleep(time.Second)
+			continue
+		}
+		break
+	}
+
+	for {
+		wait, err := scrubCycle(ctx, objAPI, config)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			wait = time.Minute
+		}
+		select {
+		case <-GlobalServiceDoneCh:
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// scrubCycle - verifies all objects not verified in the current cycle,
+// returns the time to wait for the next cycle.
+func scrubCycle(ctx context.Context, objAPI ObjectLayer, config scrubber.Config) (time.Duration, error) {
+	// Check again in a minute while the scrubber has to pause,
+	// e.g. during peak hours.
+	if config.IsPaused(time.Now()) {
+		return time.Minute, nil
+	}
+
+	// Only one server scrubs the cluster, disks verify
+	// their data locally.
+	scrubLock := globalNSMutex.NewNSLock(ctx, minioMetaBucket, scrubberLockPath)
+	if err := scrubLock.GetLock(scrubberTimeout); err != nil {
+		return time.Hour, nil
+	}
+	defer scrubLock.Unlock()
+
+	state, err := readScrubberConfig(ctx, objAPI)
+	if err != nil {
+		return 0, err
+	}
+	if !state.CycleEnd.IsZero() {
+		if next := state.CycleStart.Add(config.Cycle); time.Now().Before(next) {
+			return time.Until(next), nil
+		}
+		state = scrubberState{Version: 1, CycleStart: UTCNow()}
+	}
+	if state.CycleStart.IsZero() {
+		state = scrubberState{Version: 1, CycleStart: UTCNow()}
+	}
+
+	throttle := newScrubThrottle(config.RateLimit)
+	var scanned int
+	zones := getZoneSets(objAPI)
+	for zoneIdx := state.Zone; zoneIdx < len(zones); zoneIdx++ {
+		s := zones[zoneIdx]
+		if zoneIdx != state.Zone {
+			state.Zone, state.Bucket, state.Marker = zoneIdx, "", ""
+		}
+
+		buckets, err := s.ListBuckets(ctx)
+		if err != nil {
+			return 0, err
+		}
+		sort.Slice(buckets, func(i, j int) bool {
+			return buckets[i].Name < buckets[j].Name
+		})
+		for _, bucket := range buckets {
+			if bucket.Name < state.Bucket {
+				// Already verified in this cycle.
+				continue
+			}
+			if bucket.Name != state.Bucket {
+				state.Bucket, state.Marker = bucket.Name, ""
+			}
+			for {
+				loi, err := s.ListObjects(ctx, bucket.Name, "", state.Marker, "", maxObjectList)
+				if err != nil {
+					return 0, err
+				}
+				for _, obj := range loi.Objects {
+					// Save the progress and release the lock while
+					// paused or shutting down, the cycle resumes
+					// from the last verified object.
+					select {
+					case <-GlobalServiceDoneCh:
+						return 0, saveScrubberConfig(ctx, objAPI, state)
+					default:
+					}
+					if config.IsPaused(time.Now()) {
+						return time.Minute, saveScrubberConfig(ctx, objAPI, state)
+					}
+					n, err := s.getHashedSet(obj.Name).scrubObject(ctx, bucket.Name, obj.Name)
+					if err != nil && !isErrObjectNotFound(err) {
+						logger.LogIf(ctx, err)
+					}
+					throttle.wait(n)
+
+					state.Marker = obj.Name
+					scanned++
+					if scanned%scrubberSaveInterval == 0 {
+						if err = saveScrubberConfig(ctx, objAPI, state); err != nil {
+							return 0, err
+						}
+					}
+				}
+				if !loi.IsTruncated {
+					break
+				}
+			}
+		}
+	}
+
+	state.CycleEnd = UTCNow()
+	state.Zone, state.Bucket, state.Marker = 0, "", ""
+	if err = saveScrubberConfig(ctx, objAPI, state); err != nil {
+		return 0, err
+	}
+	return time.Until(state.CycleStart.Add(config.Cycle)), nil
+}
+
+// scrubObject - verifies the bitrot checksums of all shards of the
+// object and heals corrupted or missing shards, returns the number
+// of bytes read.
+func (xl xlObjects) scrubObject(ctx context.Context, bucket, object string) (int64, error) {
+	// Directories have no data.
+	if hasSuffix(object, SlashSeparator) {
+		return 0, nil
+	}
+
+	n, corrupted, err := xl.verifyObjectShards(ctx, bucket, object)
+	atomic.AddUint64(&globalScrubberStats.objectsScanned, 1)
+	atomic.AddUint64(&globalScrubberStats.bytesVerified, uint64(n))
+	if err != nil || corrupted == 0 {
+		return n, err
+	}
+	atomic.AddUint64(&globalScrubberStats.corruptedShards, uint64(corrupted))
+
+	res, err := xl.HealObject(ctx, bucket, object, false, false, madmin.HealDeepScan)
+	sendHealEvent(bucket, object, res, err)
+	if err != nil {
+		return n, err
+	}
+
+	// Verify again to count the repaired shards.
+	m, remaining, err := xl.verifyObjectShards(ctx, bucket, object)
+	n += m
+	if err != nil {
+		return n, err
+	}
+	if remaining < corrupted {
+		atomic.AddUint64(&globalScrubberStats.repairedShards, uint64(corrupted-remaining))
+	}
+	return n, nil
+}
+
+// Returns the number of bytes verified and the number of corrupted
+// or missing shards on the disks holding the latest object version.
+func (xl xlObjects) verifyObjectShards(ctx context.Context, bucket, object string) (int64, int, error) {
+	// Lock the object so that writes are not reported as corruption.
+	objectLock := xl.nsMutex.NewNSLock(ctx, bucket, object)
+	if err := objectLock.GetRLock(globalObjectTimeout); err != nil {
+		return 0, 0, err
+	}
+	defer objectLock.RUnlock()
+
+	storageDisks := xl.getDisks()
+	partsMetadata, errs := readAllXLMetadata(ctx, storageDisks, bucket, object)
+	readQuorum, _, err := objectQuorumFromMeta(ctx, xl, partsMetadata, errs)
+	if err != nil {
+		return 0, 0, toObjectErr(err, bucket, object)
+	}
+	if reducedErr := reduceReadQuorumErrs(ctx, errs, objectOpIgnoredErrs, readQuorum); reducedErr != nil {
+		return 0, 0, toObjectErr(reducedErr, bucket, object)
+	}
+
+	latestDisks, _ := listOnlineDisks(storageDisks, partsMetadata, errs)
+	_, dataErrs := disksWithAllParts(ctx, latestDisks, partsMetadata, errs, bucket, object, madmin.HealDeepScan)
+
+	var n int64
+	var corrupted int
+	for i, disk := range latestDisks {
+		// Offline and outdated disks are healed by the daily sweep.
+		if disk == nil {
+			continue
+		}
+		erasureInfo := partsMetadata[i].Erasure
+		erasure, err := NewErasure(ctx, erasureInfo.DataBlocks, erasureInfo.ParityBlocks, erasureInfo.BlockSize)
+		if err != nil {
+			return n, corrupted, err
+		}
+		for _, part := range partsMetadata[i].Parts {
+			n += erasure.ShardFileSize(part.Size)
+		}
+		if dataErrs[i] == errFileCorrupt || dataErrs[i] == errFileNotFound {
+			corrupted++
+		}
+	}
+	return n, corrupted, nil
+}
+
+func saveScrubberConfig(ctx context.Context, objAPI ObjectLayer, state scrubberState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, path.Join(minioConfigPrefix, scrubberConfigFile), data)
+}
+
+// readScrubberConfig - returns the scrubber progress, an empty state
+// if the scrubber never ran.
+func readScrubberConfig(ctx context.Context, objAPI ObjectLayer) (scrubberState, error) {
+	var state scrubberState
+	configData, err := readConfig(ctx, objAPI, path.Join(minioConfigPrefix, scrubberConfigFile))
+	if err != nil {
+		if err == errConfigNotFound {
+			return state, nil
+		}
+		return state, err
+	}
+	err = json.Unmarshal(configData, &state)
+	return state, err
+}
@@ -0,0 +1,128 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/cmd/config/scrubber"
+)
+
+func TestScrubCycle(t *testing.T) {
+	objLayer, fsDirs, err := prepareXLSets32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	// The scrubber holds a cluster wide lock.
+	initNSLock(false)
+	globalScrubberStats = scrubberStats{}
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = objLayer.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("a"), humanize.MiByte)
+	for i := 0; i < 10; i++ {
+		object := fmt.Sprintf("object%d", i)
+		if _, err = objLayer.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Corrupt the data of the object on one disk.
+	object := "object0"
+	disk := objLayer.(*xlSets).getHashedSet(object).getDisks()[0]
+	partPath := filepath.Join(object, "part.1")
+	fi, err := disk.StatFile(bucket, partPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = disk.DeleteFile(bucket, partPath); err != nil {
+		t.Fatal(err)
+	}
+	if err = disk.WriteAll(bucket, partPath, bytes.NewReader(bytes.Repeat([]byte("z"), int(fi.Size)))); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is verified while the scrubber is paused.
+	paused := scrubber.Config{Enabled: true, Cycle: time.Hour, PauseStart: time.Nanosecond}
+	wait, err := scrubCycle(ctx, objLayer, paused)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait != time.Minute {
+		t.Fatalf("Expected to check the pause again in a minute, got %v", wait)
+	}
+	if scanned := atomic.LoadUint64(&globalScrubberStats.objectsScanned); scanned != 0 {
+		t.Fatalf("Expected no scanned objects while paused, got %d", scanned)
+	}
+
+	config := scrubber.Config{Enabled: true, Cycle: time.Hour}
+	wait, err = scrubCycle(ctx, objLayer, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait <= 0 || wait > time.Hour {
+		t.Fatalf("Unexpected wait for the next cycle %v", wait)
+	}
+
+	if scanned := atomic.LoadUint64(&globalScrubberStats.objectsScanned); scanned != 10 {
+		t.Fatalf("Expected 10 scanned objects, got %d", scanned)
+	}
+	if corrupted := atomic.LoadUint64(&globalScrubberStats.corruptedShards); corrupted != 1 {
+		t.Fatalf("Expected 1 corrupted shard, got %d", corrupted)
+	}
+	if repaired := atomic.LoadUint64(&globalScrubberStats.repairedShards); repaired != 1 {
+		t.Fatalf("Expected 1 repaired shard, got %d", repaired)
+	}
+	if verified := atomic.LoadUint64(&globalScrubberStats.bytesVerified); verified < 10*humanize.MiByte {
+		t.Fatalf("Expected at least %d verified bytes, got %d", 10*humanize.MiByte, verified)
+	}
+
+	_, corrupted, err := objLayer.(*xlSets).getHashedSet(object).verifyObjectShards(ctx, bucket, object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrupted != 0 {
+		t.Fatalf("Expected the object to be repaired, got %d corrupted shards", corrupted)
+	}
+
+	// The completed cycle is not repeated before the next one is due.
+	state, err := readScrubberConfig(ctx, objLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.CycleEnd.IsZero() || state.Bucket != "" {
+		t.Fatalf("Unexpected scrubber state %v", state)
+	}
+	if _, err = scrubCycle(ctx, objLayer, config); err != nil {
+		t.Fatal(err)
+	}
+	if scanned := atomic.LoadUint64(&globalScrubberStats.objectsScanned); scanned != 10 {
+		t.Fatalf("Expected no objects to be scanned again, got %d", scanned)
+	}
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"hash"
 	"io"
+	"io/ioutil"
 
 	"github.com/minio/minio/cmd/logger"
 )
@@ -91,6 +92,7 @@ type streamingBitrotReader struct {
 	h          hash.Hash
 	shardSize  int64
 	hashBytes  []byte
+	data       []byte // Stream of objects stored inline in `xl.json`.
 }
 
 func (b *streamingBitrotReader) Close() error {
@@ -111,9 +113,16 @@ func (b *streamingBitrotReader) ReadAt(buf []byte, offset int64) (int, error) {
 		// For the first ReadAt() call we need to open the stream for reading.
 		b.currOffset = offset
 		streamOffset := (offset/b.shardSize)*int64(b.h.Size()) + offset
-		b.rc, err = b.disk.ReadFileStream(b.volume, b.filePath, streamOffset, b.tillOffset-streamOffset)
-		if err != nil {
-			return 0, err
+		if b.data != nil {
+			if b.tillOffset > int64(len(b.data)) {
+				return 0, errFileCorrupt
+			}
+			b.rc = ioutil.NopCloser(bytes.NewReader(b.data[streamOffset:b.tillOffset]))
+		} else {
+			b.rc, err = b.disk.ReadFileStream(b.volume, b.filePath, streamOffset, b.tillOffset-streamOffset)
+			if err != nil {
+				return 0, err
+			}
 		}
 	}
 	if offset != b.currOffset {
@@ -154,5 +163,65 @@ func newStreamingBitrotReader(disk StorageAPI, volume, filePath string, tillOffs
 		h,
 		shardSize,
 		make([]byte, h.Size()),
+		nil,
+	}
+}
+
+// Calculates bitrot in chunks like streamingBitrotWriter but keeps
+// the stream in memory, used for objects stored inline in `xl.json`.
+type inlineBitrotWriter struct {
+	buf bytes.Buffer
+	h   hash.Hash
+}
+
+func (b *inlineBitrotWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	b.h.Reset()
+	b.h.Write(p)
+	b.buf.Write(b.h.Sum(nil))
+	return b.buf.Write(p)
+}
+
+// Bytes returns the stream to be stored in `xl.json`.
+func (b *inlineBitrotWriter) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+// Returns inline bitrot writer implementation.
+func newInlineBitrotWriter(algo BitrotAlgorithm) *inlineBitrotWriter {
+	return &inlineBitrotWriter{h: algo.New()}
+}
+
+// Returns streaming bitrot reader implementation which reads the
+// stream of an object stored inline in `xl.json`.
+func newInlineBitrotReader(data []byte, tillOffset int64, algo BitrotAlgorithm, shardSize int64) *streamingBitrotReader {
+	b := newStreamingBitrotReader(nil, "", "", tillOffset, algo, shardSize)
+	b.data = data
+	return b
+}
+
+// Verifies the stream of an object stored inline in `xl.json`,
+// equivalent of StorageAPI.VerifyFile() for part files.
+func verifyInlineBitrot(data []byte, size int64, algo BitrotAlgorithm, shardSize int64) error {
+	if int64(len(data)) != bitrotShardFileSize(size, shardSize, algo) {
+		return errFileCorrupt
+	}
+	h := algo.New()
+	for len(data) > 0 {
+		hashBytes := data[:h.Size()]
+		data = data[h.Size():]
+		n := shardSize
+		if int64(len(data)) < n {
+			n = int64(len(data))
+		}
+		h.Reset()
+		h.Write(data[:n])
+		if !bytes.Equal(h.Sum(nil), hashBytes) {
+			return errFileCorrupt
+		}
+		data = data[n:]
 	}
+	return nil
 }
@@ -82,3 +82,44 @@ func TestAllBitrotAlgorithms(t *testing.T) {
 		testBitrotReaderWriterAlgo(t, bitrotAlgo)
 	}
 }
+
+func TestInlineBitrotReaderWriter(t *testing.T) {
+	writer := newInlineBitrotWriter(DefaultBitrotAlgorithm)
+	for _, p := range []string{"aaaaaaaaaa", "bbbbbbbbbb", "ccccc"} {
+		if _, err := writer.Write([]byte(p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := writer.Bytes()
+	if err := verifyInlineBitrot(data, 25, DefaultBitrotAlgorithm, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := newInlineBitrotReader(data, 25, DefaultBitrotAlgorithm, 10)
+	b := make([]byte, 10)
+	if _, err := reader.ReadAt(b, 10); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "bbbbbbbbbb" {
+		t.Fatalf("Unexpected data %s", b)
+	}
+	if _, err := reader.ReadAt(b[:5], 20); err != nil {
+		t.Fatal(err)
+	}
+	if string(b[:5]) != "ccccc" {
+		t.Fatalf("Unexpected data %s", b[:5])
+	}
+
+	// Corrupted and truncated data is detected.
+	data[len(data)-1] = 'z'
+	if err := verifyInlineBitrot(data, 25, DefaultBitrotAlgorithm, 10); err != errFileCorrupt {
+		t.Fatalf("Expected %v, got %v", errFileCorrupt, err)
+	}
+	if err := verifyInlineBitrot(data[:20], 25, DefaultBitrotAlgorithm, 10); err != errFileCorrupt {
+		t.Fatalf("Expected %v, got %v", errFileCorrupt, err)
+	}
+	reader = newInlineBitrotReader(data, 25, DefaultBitrotAlgorithm, 10)
+	if _, err := reader.ReadAt(b[:5], 20); err == nil {
+		t.Fatal("Expected bitrot verification to fail")
+	}
+}
@@ -496,6 +496,15 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	cannedACL, aclRequested, s3Error := getCannedACLFromHeader(r.Header)
+	if s3Error == ErrNone && aclRequested {
+		s3Error = checkRequestAuthType(ctx, r, policy.PutBucketACLAction, bucket, "")
+	}
+	if s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
 	if globalDNSConfig != nil {
 		if _, err := globalDNSConfig.Get(bucket); err != nil {
 			if err == dns.ErrNoEntriesFound {
@@ -509,12 +518,19 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 					writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 					return
 				}
+				if err = setBucketACL(ctx, objectAPI, bucket, cannedACL); err != nil {
+					globalDNSConfig.Delete(bucket)
+					objectAPI.DeleteBucket(ctx, bucket)
+					writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+					return
+				}
 
 				// Make sure to add Location information here only for bucket
 				w.Header().Set(xhttp.Location,
 					getObjectLocation(r, globalDomainNames, bucket, ""))
 
 				writeSuccessResponseHeadersOnly(w)
+				sendBucketEvent(event.BucketCreated, bucket, w, r)
 				return
 			}
 			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
@@ -532,10 +548,34 @@ func (api objectAPIHandlers) PutBucketHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if err = setBucketACL(ctx, objectAPI, bucket, cannedACL); err != nil {
+		// Do not leave a bucket without the requested ACL.
+		objectAPI.DeleteBucket(ctx, bucket)
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
 	// Make sure to add Location information here only for bucket
 	w.Header().Set(xhttp.Location, path.Clean(r.URL.Path)) // Clean any trailing slashes.
 
 	writeSuccessResponseHeadersOnly(w)
+
+	// Notify bucket created event.
+	sendBucketEvent(event.BucketCreated, bucket, w, r)
+}
+
+// sendBucketEvent - notifies a bucket created or removed event, the
+// event has no object. A new bucket has no notification configuration,
+// so bucket created events only reach listeners of all buckets.
+func sendBucketEvent(eventName event.Name, bucket string, w http.ResponseWriter, r *http.Request) {
+	sendEvent(eventArgs{
+		EventName:    eventName,
+		BucketName:   bucket,
+		ReqParams:    extractReqParams(r),
+		RespElements: extractRespElements(w),
+		UserAgent:    r.UserAgent(),
+		Host:         handlers.GetSourceIP(r),
+	})
 }
 
 // PostPolicyBucketHandler - POST policy
@@ -692,6 +732,7 @@ func (api objectAPIHandlers) PostPolicyBucketHandler(w http.ResponseWriter, r *h
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	setBucketStorageClass(metadata, bucket, object)
 
 	hashReader, err := hash.NewReader(fileBody, fileSize, "", "", fileSize, globalCLIContext.StrictS3Compat)
 	if err != nil {
@@ -862,11 +903,19 @@ func (api objectAPIHandlers) DeleteBucketHandler(w http.ResponseWriter, r *http.
 		}
 	}
 
+	// Notify bucket removed event, before the notification
+	// configuration of the bucket is removed.
+	sendBucketEvent(event.BucketRemoved, bucket, w, r)
+
 	globalNotificationSys.RemoveNotification(bucket)
 	globalPolicySys.Remove(bucket)
 	globalNotificationSys.DeleteBucket(ctx, bucket)
 	globalLifecycleSys.Remove(bucket)
 	globalNotificationSys.RemoveBucketLifecycle(ctx, bucket)
+	globalACLSys.Remove(bucket)
+	globalBucketLoggingSys.Remove(bucket)
+	globalBucketStorageClassSys.Remove(bucket)
+	globalAPIStats.removeBucket(bucket)
 
 	// Write success response.
 	writeSuccessNoContent(w)
@@ -0,0 +1,147 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/accesslog"
+	"github.com/minio/minio/pkg/policy"
+)
+
+// Maximum size of a bucket logging status document.
+const maxBucketLoggingConfigSize = 64 * 1024
+
+// PutBucketLoggingHandler - This HTTP handler enables or disables server
+// access logging of a bucket as per
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTlogging.html
+func (api objectAPIHandlers) PutBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "PutBucketLogging")
+
+	defer logger.AuditLog(w, r, "PutBucketLogging", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Server access logging is not supported in gateway mode.
+	if globalIsGateway {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNotImplemented), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.PutBucketLoggingAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if r.ContentLength <= 0 {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrMissingRequestBodyError), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	config, err := accesslog.ParseConfig(io.LimitReader(r.Body, maxBucketLoggingConfigSize))
+	if err != nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrMalformedXML), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Logs can only be delivered to an existing bucket.
+	if config.IsEnabled() {
+		target := config.LoggingEnabled
+		if _, err = objAPI.GetBucketInfo(ctx, target.TargetBucket); err != nil {
+			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidTargetBucketForLogging), r.URL, guessIsBrowserReq(r))
+			return
+		}
+
+		// The server writes the logs on behalf of the caller, who
+		// must be allowed to upload objects to the target prefix.
+		// The request signature was already verified above.
+		if s3Error := isPutActionAllowed(ctx, getRequestAuthType(r), target.TargetBucket, target.TargetPrefix, r, policy.PutObjectAction); s3Error != ErrNone {
+			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+			return
+		}
+	}
+
+	if err = saveBucketLoggingConfig(ctx, objAPI, bucket, config); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	globalBucketLoggingSys.Set(bucket, *config)
+	globalNotificationSys.SetBucketLogging(ctx, bucket, config)
+
+	// Success.
+	writeSuccessResponseHeadersOnly(w)
+}
+
+// GetBucketLoggingHandler - This HTTP handler returns the server
+// access logging status of a bucket.
+func (api objectAPIHandlers) GetBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "GetBucketLogging")
+
+	defer logger.AuditLog(w, r, "GetBucketLogging", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucket := vars["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.GetBucketLoggingAction, bucket, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Check if bucket exists.
+	if _, err := objAPI.GetBucketInfo(ctx, bucket); err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	config := accesslog.Config{XMLNS: "http://doc.s3.amazonaws.com/2006-03-01"}
+	if target, ok := globalBucketLoggingSys.Get(bucket); ok {
+		config.LoggingEnabled = &target
+	}
+
+	configData, err := xml.Marshal(config)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Write logging status to client.
+	writeSuccessResponseXML(w, configData)
+}
@@ -0,0 +1,358 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/cmd/logger/message/audit"
+	"github.com/minio/minio/pkg/accesslog"
+	"github.com/minio/minio/pkg/hash"
+)
+
+const (
+	// Bucket logging configuration file.
+	bucketLoggingConfig = "logging.xml"
+
+	// Interval at which batched access log records are delivered.
+	accessLogFlushInterval = 5 * time.Minute
+
+	// Size of a batch of access log records which triggers
+	// an early delivery.
+	accessLogMaxBatchSize = 4 * humanize.MiByte
+
+	// Number of pending access log records, records are
+	// dropped when delivery cannot keep up.
+	accessLogQueueSize = 10000
+)
+
+// BucketLoggingSys - Bucket server access logging subsystem.
+type BucketLoggingSys struct {
+	sync.RWMutex
+	bucketLoggingMap map[string]accesslog.LoggingEnabled
+}
+
+// Set - sets logging status of the given bucket name.
+func (sys *BucketLoggingSys) Set(bucketName string, config accesslog.Config) {
+	if globalIsGateway {
+		// no-op
+		return
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	if !config.IsEnabled() {
+		delete(sys.bucketLoggingMap, bucketName)
+		return
+	}
+	sys.bucketLoggingMap[bucketName] = *config.LoggingEnabled
+}
+
+// Get - returns the logging target of the given bucket name.
+func (sys *BucketLoggingSys) Get(bucketName string) (target accesslog.LoggingEnabled, ok bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	target, ok = sys.bucketLoggingMap[bucketName]
+	return target, ok
+}
+
+// Remove - removes logging status of the given bucket name.
+func (sys *BucketLoggingSys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketLoggingMap, bucketName)
+}
+
+func saveBucketLoggingConfig(ctx context.Context, objAPI ObjectLayer, bucketName string, config *accesslog.Config) error {
+	// Construct path to logging.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketLoggingConfig)
+	if !config.IsEnabled() {
+		if err := objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil {
+			if _, ok := err.(ObjectNotFound); !ok {
+				return err
+			}
+		}
+		return nil
+	}
+
+	data, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// getBucketLoggingConfig - get logging status for given bucket name,
+// logging is disabled if no configuration is present.
+func getBucketLoggingConfig(objAPI ObjectLayer, bucketName string) (*accesslog.Config, error) {
+	// Construct path to logging.xml for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketLoggingConfig)
+	configData, err := readConfig(context.Background(), objAPI, configFile)
+	if err != nil {
+		if err == errConfigNotFound {
+			return &accesslog.Config{}, nil
+		}
+		return nil, err
+	}
+
+	return accesslog.ParseConfig(bytes.NewReader(configData))
+}
+
+func removeBucketLoggingConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	return saveBucketLoggingConfig(ctx, objAPI, bucketName, &accesslog.Config{})
+}
+
+// NewBucketLoggingSys - creates new bucket logging system.
+func NewBucketLoggingSys() *BucketLoggingSys {
+	return &BucketLoggingSys{
+		bucketLoggingMap: make(map[string]accesslog.LoggingEnabled),
+	}
+}
+
+// Init - initializes bucket logging system from logging.xml of all buckets.
+func (sys *BucketLoggingSys) Init(buckets []BucketInfo, objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	// Server access logging is not supported in gateway mode.
+	if globalIsGateway {
+		return nil
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Initializing bucket logging needs a retry mechanism for
+	// the following reasons:
+	//  - Read quorum is lost just after the initialization
+	//    of the object layer.
+	retryTimerCh := newRetryTimerSimple(doneCh)
+	for {
+		select {
+		case <-retryTimerCh:
+			// Load BucketLoggingSys once during boot.
+			if err := sys.load(buckets, objAPI); err != nil {
+				if err == errDiskNotFound ||
+					strings.Contains(err.Error(), InsufficientReadQuorum{}.Error()) ||
+					strings.Contains(err.Error(), InsufficientWriteQuorum{}.Error()) {
+					logger.Info("Waiting for bucket logging subsystem to be initialized..")
+					continue
+				}
+				return err
+			}
+			return nil
+		case <-globalOSSignalCh:
+			return fmt.Errorf("Initializing bucket logging sub-system gracefully stopped")
+		}
+	}
+}
+
+// Loads logging status of all buckets into BucketLoggingSys.
+func (sys *BucketLoggingSys) load(buckets []BucketInfo, objAPI ObjectLayer) error {
+	for _, bucket := range buckets {
+		config, err := getBucketLoggingConfig(objAPI, bucket.Name)
+		if err != nil {
+			return err
+		}
+		sys.Set(bucket.Name, *config)
+	}
+	return nil
+}
+
+// accessLogTarget - the bucket and prefix access logs are delivered to.
+type accessLogTarget struct {
+	bucket string
+	prefix string
+}
+
+// accessLogRecord - a formatted access log record and its target.
+type accessLogRecord struct {
+	target accessLogTarget
+	line   string
+}
+
+// accessLogger - batches server access log records of buckets
+// with logging enabled and delivers them as objects into the
+// target buckets.
+type accessLogger struct {
+	recordCh chan accessLogRecord
+	// Number of records dropped as the queue was full.
+	dropped uint64
+}
+
+// newAccessLogger - creates a new access logger and starts
+// the delivery of batched records.
+func newAccessLogger() *accessLogger {
+	l := &accessLogger{
+		recordCh: make(chan accessLogRecord, accessLogQueueSize),
+	}
+	go l.run()
+	return l
+}
+
+// SendAccess - builds the access log record of a request against a
+// bucket with logging enabled and queues it for delivery.
+func (l *accessLogger) SendAccess(r *http.Request, w *logger.ResponseWriter, entry audit.Entry) {
+	target, ok := globalBucketLoggingSys.Get(entry.API.Bucket)
+	if !ok {
+		return
+	}
+
+	record := newAccessLogRecord(r, w, entry)
+	select {
+	case l.recordCh <- accessLogRecord{
+		target: accessLogTarget{bucket: target.TargetBucket, prefix: target.TargetPrefix},
+		line:   record.String(),
+	}:
+	default:
+		// Access logs are best effort, drop the record
+		// instead of blocking the request.
+		atomic.AddUint64(&l.dropped, 1)
+		logger.LogOnceIf(context.Background(), errors.New("Access log queue is full, dropping access log records"), "access-log-dropped")
+	}
+}
+
+// Dropped - returns the number of access log records dropped
+// as delivery could not keep up.
+func (l *accessLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// run - batches records per target, batches are delivered
+// periodically or as soon as they grow large enough.
+func (l *accessLogger) run() {
+	batches := make(map[accessLogTarget]*bytes.Buffer)
+	ticker := time.NewTicker(accessLogFlushInterval)
+	defer ticker.Stop()
+
+	flush := func() {
+		for target, batch := range batches {
+			l.deliver(target, batch.Bytes())
+		}
+		batches = make(map[accessLogTarget]*bytes.Buffer)
+	}
+
+	for {
+		select {
+		case record := <-l.recordCh:
+			batch, ok := batches[record.target]
+			if !ok {
+				batch = &bytes.Buffer{}
+				batches[record.target] = batch
+			}
+			batch.WriteString(record.line)
+			batch.WriteByte('\n')
+			if batch.Len() >= accessLogMaxBatchSize {
+				l.deliver(record.target, batch.Bytes())
+				delete(batches, record.target)
+			}
+		case <-ticker.C:
+			flush()
+		case <-GlobalServiceDoneCh:
+			flush()
+			return
+		}
+	}
+}
+
+// deliver - writes a batch of records as a new log object
+// named TargetPrefixYYYY-mm-DD-HH-MM-SS-UniqueString.
+func (l *accessLogger) deliver(target accessLogTarget, data []byte) {
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		return
+	}
+
+	uniqueID := strings.ToUpper(strings.Replace(mustGetUUID(), "-", "", -1))[:16]
+	object := target.prefix + UTCNow().Format("2006-01-02-15-04-05") + "-" + uniqueID
+
+	ctx := logger.SetReqInfo(context.Background(), &logger.ReqInfo{BucketName: target.bucket, ObjectName: object})
+	hashReader, err := hash.NewReader(bytes.NewReader(data), int64(len(data)), "", "", int64(len(data)), globalCLIContext.StrictS3Compat)
+	if err != nil {
+		logger.LogIf(ctx, err)
+		return
+	}
+
+	opts := ObjectOptions{UserDefined: map[string]string{"content-type": "text/plain"}}
+	if _, err = objAPI.PutObject(ctx, target.bucket, object, NewPutObjReader(hashReader, nil, nil), opts); err != nil {
+		logger.LogIf(ctx, err)
+	}
+}
+
+// newAccessLogRecord - builds the access log record of a request
+// from its audit entry and the trapped response.
+func newAccessLogRecord(r *http.Request, w *logger.ResponseWriter, entry audit.Entry) accesslog.Record {
+	record := accesslog.Record{
+		BucketOwner:    globalMinioDefaultOwnerID,
+		Bucket:         entry.API.Bucket,
+		Time:           w.StartTime,
+		RemoteIP:       entry.RemoteHost,
+		RequestID:      entry.RequestID,
+		Operation:      accesslog.Operation(r.Method, r.URL.Query(), entry.API.Object),
+		Key:            entry.API.Object,
+		ObjectSize:     entry.API.ObjectSize,
+		RequestURI:     r.Method + " " + r.RequestURI + " " + r.Proto,
+		HTTPStatus:     w.StatusCode,
+		BytesSent:      int64(w.BodySize()),
+		TotalTime:      time.Since(w.StartTime),
+		TurnAroundTime: w.TimeToFirstByte,
+		Referrer:       r.Referer(),
+		UserAgent:      r.UserAgent(),
+		CipherSuite:    accesslog.CipherSuite(r.TLS),
+		HostHeader:     r.Host,
+		TLSVersion:     accesslog.TLSVersion(r.TLS),
+	}
+
+	switch getRequestAuthType(r) {
+	case authTypeSigned, authTypeStreamingSigned:
+		record.SignatureVersion, record.AuthType = "SigV4", "AuthHeader"
+	case authTypePresigned:
+		record.SignatureVersion, record.AuthType = "SigV4", "QueryString"
+	case authTypeSignedV2:
+		record.SignatureVersion, record.AuthType = "SigV2", "AuthHeader"
+	case authTypePresignedV2:
+		record.SignatureVersion, record.AuthType = "SigV2", "QueryString"
+	}
+	if record.SignatureVersion != "" {
+		record.Requester = getReqAccessCred(r, globalServerConfig.GetRegion()).AccessKey
+	}
+
+	if w.StatusCode >= http.StatusBadRequest {
+		var errResp APIErrorResponse
+		if err := xml.Unmarshal(w.Body(), &errResp); err == nil {
+			record.ErrorCode = errResp.Code
+		}
+	}
+
+	return record
+}
@@ -0,0 +1,90 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/cmd/logger/message/audit"
+	"github.com/minio/minio/pkg/accesslog"
+)
+
+func TestNewAccessLogRecord(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/mybucket/myobject", nil)
+	r.Header.Set("Referer", "http://example.com/")
+
+	w := logger.NewResponseWriter(httptest.NewRecorder())
+	writeErrorResponse(context.Background(), w, errorCodes.ToAPIErr(ErrNoSuchKey), r.URL, false)
+
+	var entry audit.Entry
+	entry.API.Bucket = "mybucket"
+	entry.API.Object = "myobject"
+	entry.RemoteHost = "192.0.2.3"
+	entry.RequestID = "15F0C3B4D2D8E3B6"
+	entry.API.ObjectSize = 1024
+
+	record := newAccessLogRecord(r, w, entry)
+	if record.Operation != "REST.GET.OBJECT" {
+		t.Fatalf("expected operation REST.GET.OBJECT, got %s", record.Operation)
+	}
+	if record.HTTPStatus != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, record.HTTPStatus)
+	}
+	if record.ErrorCode != "NoSuchKey" {
+		t.Fatalf("expected error code NoSuchKey, got %s", record.ErrorCode)
+	}
+	if record.Requester != "" || record.AuthType != "" {
+		t.Fatalf("expected anonymous request, got requester %s with auth type %s", record.Requester, record.AuthType)
+	}
+	if record.BytesSent == 0 {
+		t.Fatal("expected error response to be accounted in bytes sent")
+	}
+	if record.RequestURI != "GET /mybucket/myobject HTTP/1.1" {
+		t.Fatalf("unexpected request URI %s", record.RequestURI)
+	}
+	if record.ObjectSize != 1024 {
+		t.Fatalf("expected object size 1024, got %d", record.ObjectSize)
+	}
+}
+
+func TestAccessLoggerDropped(t *testing.T) {
+	globalBucketLoggingSys = NewBucketLoggingSys()
+	globalBucketLoggingSys.Set("mybucket", accesslog.Config{
+		LoggingEnabled: &accesslog.LoggingEnabled{TargetBucket: "logs"},
+	})
+	defer globalBucketLoggingSys.Remove("mybucket")
+
+	// Nothing is delivering, the records are queued until the queue is full.
+	l := &accessLogger{recordCh: make(chan accessLogRecord, 1)}
+
+	r := httptest.NewRequest(http.MethodGet, "/mybucket/myobject", nil)
+	w := logger.NewResponseWriter(httptest.NewRecorder())
+	var entry audit.Entry
+	entry.API.Bucket = "mybucket"
+	entry.API.Object = "myobject"
+
+	for i := 0; i < 3; i++ {
+		l.SendAccess(r, w, entry)
+	}
+	if dropped := l.Dropped(); dropped != 2 {
+		t.Fatalf("expected 2 dropped records, got %d", dropped)
+	}
+}
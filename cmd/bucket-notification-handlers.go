@@ -202,50 +202,13 @@ func (api objectAPIHandlers) ListenBucketNotificationHandler(w http.ResponseWrit
 		return
 	}
 
-	values := r.URL.Query()
-
-	var prefix string
-	if len(values["prefix"]) > 1 {
-		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrFilterNamePrefix), r.URL, guessIsBrowserReq(r))
-		return
-	}
-
-	if len(values["prefix"]) == 1 {
-		if err := event.ValidateFilterRuleValue(values["prefix"][0]); err != nil {
-			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
-			return
-		}
-
-		prefix = values["prefix"][0]
-	}
-
-	var suffix string
-	if len(values["suffix"]) > 1 {
-		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrFilterNameSuffix), r.URL, guessIsBrowserReq(r))
+	opts, err := getListenOptions(r.URL.Query())
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
-
-	if len(values["suffix"]) == 1 {
-		if err := event.ValidateFilterRuleValue(values["suffix"][0]); err != nil {
-			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
-			return
-		}
-
-		suffix = values["suffix"][0]
-	}
-
-	pattern := event.NewPattern(prefix, suffix)
-
-	eventNames := []event.Name{}
-	for _, s := range values["events"] {
-		eventName, err := event.ParseName(s)
-		if err != nil {
-			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
-			return
-		}
-
-		eventNames = append(eventNames, eventName)
-	}
+	pattern := event.NewPattern(opts.prefix, opts.suffix)
+	eventNames := opts.eventNames
 
 	if _, err := objAPI.GetBucketInfo(ctx, bucketName); err != nil {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
@@ -298,3 +261,85 @@ func (api objectAPIHandlers) ListenBucketNotificationHandler(w http.ResponseWrit
 		return
 	}
 }
+
+// ListenNotificationStreamHandler - This HTTP handler streams the events of a
+// bucket, or of all buckets without a bucket name, of all servers to the client
+// as Server-Sent Events or over a WebSocket. Clients select the stream and the
+// prefix/suffix object name and events to watch as query parameters, and may
+// resume from the cursor of the last event received.
+func (api objectAPIHandlers) ListenNotificationStreamHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "ListenNotificationStream")
+
+	defer logger.AuditLog(w, r, "ListenNotificationStream", mustGetClaimsFromToken(r))
+
+	objAPI := api.ObjectAPI()
+	if objAPI == nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrServerNotInitialized), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if !objAPI.IsNotificationSupported() {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNotImplemented), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if !objAPI.IsListenBucketSupported() {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrNotImplemented), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	// Listening to all buckets requires the permission for all buckets.
+	bucketName := mux.Vars(r)["bucket"]
+
+	if s3Error := checkRequestAuthType(ctx, r, policy.ListenBucketNotificationAction, bucketName, ""); s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	values := r.URL.Query()
+	stream := values.Get(listenStream)
+	if stream != listenStreamSSE && stream != listenStreamWebSocket {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidRequest), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	opts, err := getListenOptions(values)
+	if err != nil {
+		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	opts.bucket = bucketName
+
+	// Browsers send the ID of the last event received when
+	// reconnecting to an event source.
+	cursorValue := values.Get(listenStreamCursor)
+	if cursorValue == "" {
+		cursorValue = r.Header.Get("Last-Event-ID")
+	}
+	cursor, err := parseListenCursor(cursorValue)
+	if err != nil {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(ErrInvalidRequest), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
+	if bucketName != "" {
+		if _, err = objAPI.GetBucketInfo(ctx, bucketName); err != nil {
+			writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
+			return
+		}
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Events of all servers are taken at the pace of the client,
+	// servers keep the events a slow client falls behind with.
+	eventCh := make(chan listenEvent, listenBatchSize)
+	globalNotificationSys.ListenEvents(opts, cursor, eventCh, doneCh)
+
+	if stream == listenStreamWebSocket {
+		serveListenWebSocket(w, r, cursor, eventCh)
+		return
+	}
+	serveListenEvents(newListenSSEWriter(w), cursor, eventCh, r.Context().Done())
+}
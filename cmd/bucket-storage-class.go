@@ -0,0 +1,211 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Bucket storage class configuration file.
+const bucketStorageClassConfig = "storageclass.json"
+
+// BucketStorageClassSys - Bucket default storage class subsystem.
+type BucketStorageClassSys struct {
+	sync.RWMutex
+	bucketStorageClassMap map[string]madmin.BucketStorageClass
+}
+
+// Set - sets the default storage classes of the given bucket name.
+func (sys *BucketStorageClassSys) Set(bucketName string, sc madmin.BucketStorageClass) {
+	if globalIsGateway {
+		// no-op
+		return
+	}
+
+	sys.Lock()
+	defer sys.Unlock()
+
+	if sc.IsEmpty() {
+		delete(sys.bucketStorageClassMap, bucketName)
+		return
+	}
+	sys.bucketStorageClassMap[bucketName] = sc
+}
+
+// Get - returns the default storage classes of the given bucket name.
+func (sys *BucketStorageClassSys) Get(bucketName string) madmin.BucketStorageClass {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	return sys.bucketStorageClassMap[bucketName]
+}
+
+// Remove - removes the default storage classes of the given bucket name.
+func (sys *BucketStorageClassSys) Remove(bucketName string) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	delete(sys.bucketStorageClassMap, bucketName)
+}
+
+// StorageClass - returns the storage class of a new object without
+// x-amz-storage-class, the longest matching prefix wins over the
+// bucket default. Returns "" if none is set.
+func (sys *BucketStorageClassSys) StorageClass(bucketName, objectName string) string {
+	sc := sys.Get(bucketName)
+	storageClass, matched := sc.Default, -1
+	for prefix, prefixClass := range sc.Prefixes {
+		if len(prefix) > matched && strings.HasPrefix(objectName, prefix) {
+			storageClass, matched = prefixClass, len(prefix)
+		}
+	}
+	return storageClass
+}
+
+// setBucketStorageClass - records the default storage class of the
+// bucket in the metadata of a new object, unless the request set one.
+// Storage classes removed from the server configuration are ignored.
+func setBucketStorageClass(metadata map[string]string, bucketName, objectName string) {
+	if _, ok := metadata[amzStorageClass]; ok {
+		return
+	}
+	if sc := globalBucketStorageClassSys.StorageClass(bucketName, objectName); isValidStorageClassMeta(sc) {
+		metadata[amzStorageClass] = sc
+	}
+}
+
+// validateBucketStorageClass - returns an error if the configuration
+// refers to storage classes unknown to this server.
+func validateBucketStorageClass(sc madmin.BucketStorageClass) error {
+	if sc.Default != "" && !isValidStorageClassMeta(sc.Default) {
+		return fmt.Errorf("Unknown storage class %s", sc.Default)
+	}
+	for prefix, prefixClass := range sc.Prefixes {
+		if !isValidStorageClassMeta(prefixClass) {
+			return fmt.Errorf("Unknown storage class %s for prefix %s", prefixClass, prefix)
+		}
+	}
+	return nil
+}
+
+func saveBucketStorageClassConfig(ctx context.Context, objAPI ObjectLayer, bucketName string, sc madmin.BucketStorageClass) error {
+	// Construct path to storageclass.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketStorageClassConfig)
+	if sc.IsEmpty() {
+		if err := objAPI.DeleteObject(ctx, minioMetaBucket, configFile); err != nil {
+			if _, ok := err.(ObjectNotFound); !ok {
+				return err
+			}
+		}
+		return nil
+	}
+
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, configFile, data)
+}
+
+// getBucketStorageClassConfig - get the default storage classes of the
+// given bucket name, none are set if no configuration is present.
+func getBucketStorageClassConfig(objAPI ObjectLayer, bucketName string) (madmin.BucketStorageClass, error) {
+	var sc madmin.BucketStorageClass
+
+	// Construct path to storageclass.json for the given bucket.
+	configFile := path.Join(bucketConfigPrefix, bucketName, bucketStorageClassConfig)
+	configData, err := readConfig(context.Background(), objAPI, configFile)
+	if err != nil {
+		if err == errConfigNotFound {
+			return sc, nil
+		}
+		return sc, err
+	}
+
+	err = json.Unmarshal(configData, &sc)
+	return sc, err
+}
+
+func removeBucketStorageClassConfig(ctx context.Context, objAPI ObjectLayer, bucketName string) error {
+	return saveBucketStorageClassConfig(ctx, objAPI, bucketName, madmin.BucketStorageClass{})
+}
+
+// NewBucketStorageClassSys - creates new bucket storage class system.
+func NewBucketStorageClassSys() *BucketStorageClassSys {
+	return &BucketStorageClassSys{
+		bucketStorageClassMap: make(map[string]madmin.BucketStorageClass),
+	}
+}
+
+// Init - initializes bucket storage class system from storageclass.json of all buckets.
+func (sys *BucketStorageClassSys) Init(buckets []BucketInfo, objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	// Bucket storage classes are not supported in gateway mode.
+	if globalIsGateway {
+		return nil
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Initializing bucket storage classes needs a retry mechanism for
+	// the following reasons:
+	//  - Read quorum is lost just after the initialization
+	//    of the object layer.
+	retryTimerCh := newRetryTimerSimple(doneCh)
+	for {
+		select {
+		case <-retryTimerCh:
+			// Load BucketStorageClassSys once during boot.
+			if err := sys.load(buckets, objAPI); err != nil {
+				if err == errDiskNotFound ||
+					strings.Contains(err.Error(), InsufficientReadQuorum{}.Error()) ||
+					strings.Contains(err.Error(), InsufficientWriteQuorum{}.Error()) {
+					logger.Info("Waiting for bucket storage class subsystem to be initialized..")
+					continue
+				}
+				return err
+			}
+			return nil
+		case <-globalOSSignalCh:
+			return fmt.Errorf("Initializing bucket storage class sub-system gracefully stopped")
+		}
+	}
+}
+
+// Loads the default storage classes of all buckets into BucketStorageClassSys.
+func (sys *BucketStorageClassSys) load(buckets []BucketInfo, objAPI ObjectLayer) error {
+	for _, bucket := range buckets {
+		sc, err := getBucketStorageClassConfig(objAPI, bucket.Name)
+		if err != nil {
+			return err
+		}
+		sys.Set(bucket.Name, sc)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestBucketStorageClass(t *testing.T) {
+	obj, fsDirs, err := prepareXL16()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	resetGlobalStorageEnvs()
+	defer resetGlobalStorageEnvs()
+	globalCustomStorageClasses = map[string]storageClass{
+		"ARCHIVE": {Scheme: "EC", Parity: 8},
+		"SCRATCH": {Scheme: "EC", Parity: 2},
+	}
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := madmin.BucketStorageClass{
+		Default: "ARCHIVE",
+		Prefixes: map[string]string{
+			"tmp/":      "SCRATCH",
+			"tmp/keep/": "STANDARD",
+			"old/":      "REMOVED",
+		},
+	}
+	if err = validateBucketStorageClass(sc); err == nil {
+		t.Fatal("Expected unknown storage class to be rejected")
+	}
+	if err = saveBucketStorageClassConfig(ctx, obj, bucket, sc); err != nil {
+		t.Fatal(err)
+	}
+	savedSC, err := getBucketStorageClassConfig(obj, bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(savedSC, sc) {
+		t.Fatalf("Expected %v, got %v", sc, savedSC)
+	}
+
+	sys := NewBucketStorageClassSys()
+	if err = sys.Init([]BucketInfo{{Name: bucket}}, obj); err != nil {
+		t.Fatal(err)
+	}
+	globalBucketStorageClassSys = sys
+	defer func() {
+		globalBucketStorageClassSys = NewBucketStorageClassSys()
+	}()
+
+	testCases := []struct {
+		object         string
+		metadata       map[string]string
+		expectedClass  string
+		expectedParity int
+	}{
+		{"object", map[string]string{}, "ARCHIVE", 8},
+		{"tmp/object", map[string]string{}, "SCRATCH", 2},
+		{"tmp/keep/object", map[string]string{}, "STANDARD", 8},
+		// The storage class of the request wins.
+		{"tmp/other", map[string]string{amzStorageClass: "ARCHIVE"}, "ARCHIVE", 8},
+		// Storage classes unknown to the server are ignored.
+		{"old/object", map[string]string{}, "", 8},
+	}
+	for i, testCase := range testCases {
+		setBucketStorageClass(testCase.metadata, bucket, testCase.object)
+		if sc := testCase.metadata[amzStorageClass]; sc != testCase.expectedClass {
+			t.Fatalf("Test %d: expected storage class %q, got %q", i+1, testCase.expectedClass, sc)
+		}
+
+		data := []byte("hello")
+		if _, err = obj.PutObject(ctx, bucket, testCase.object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{UserDefined: testCase.metadata}); err != nil {
+			t.Fatal(err)
+		}
+		xlMeta, err := readXLMeta(ctx, obj.(*xlObjects).storageDisks[0], bucket, testCase.object)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if xlMeta.Erasure.ParityBlocks != testCase.expectedParity {
+			t.Fatalf("Test %d: expected parity %d, got %d", i+1, testCase.expectedParity, xlMeta.Erasure.ParityBlocks)
+		}
+	}
+
+	// An empty configuration removes the bucket storage classes.
+	if err = removeBucketStorageClassConfig(ctx, obj, bucket); err != nil {
+		t.Fatal(err)
+	}
+	if savedSC, err = getBucketStorageClassConfig(obj, bucket); err != nil || !savedSC.IsEmpty() {
+		t.Fatalf("Expected no storage classes, got %v, %v", savedSC, err)
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+const (
+	bucketUsageConfigFile = "usage.json"
+
+	// Interval at which the usage of all buckets is computed.
+	bucketUsageInterval = 12 * time.Hour
+
+	// Interval at which servers check for a newer usage.
+	bucketUsageTick = time.Hour
+)
+
+// BucketUsage - number of objects and bytes stored in a bucket.
+type BucketUsage struct {
+	ObjectsCount uint64 `json:"objectsCount"`
+	Size         uint64 `json:"size"`
+}
+
+// bucketUsageInfo - usage of all buckets, as computed by the
+// last crawl of any server.
+type bucketUsageInfo struct {
+	Version    int                    `json:"version"`
+	LastUpdate time.Time              `json:"lastUpdate"`
+	Buckets    map[string]BucketUsage `json:"buckets"`
+}
+
+// Usage of all buckets last loaded or computed by this server.
+var globalBucketUsage = &bucketUsageCache{}
+
+type bucketUsageCache struct {
+	sync.RWMutex
+	info bucketUsageInfo
+}
+
+func (c *bucketUsageCache) set(info bucketUsageInfo) {
+	c.Lock()
+	defer c.Unlock()
+	c.info = info
+}
+
+// get - returns the usage of all buckets, nil if not computed yet.
+func (c *bucketUsageCache) get() map[string]BucketUsage {
+	c.RLock()
+	defer c.RUnlock()
+	return c.info.Buckets
+}
+
+// initBucketUsageCrawler starts the routine which periodically
+// counts the objects and bytes of all buckets.
+func initBucketUsageCrawler() {
+	go startBucketUsageCrawler()
+}
+
+func startBucketUsageCrawler() {
+	var objAPI ObjectLayer
+	ctx := context.Background()
+
+	// Wait until the object layer is ready
+	for {
+		objAPI = newObjectLayerFn()
+		if objAPI == nil {
+			time.Sleep(time.Second)
+			continue
+		}
+		break
+	}
+
+	for {
+		if err := bucketUsageRound(ctx, objAPI); err != nil {
+			logger.LogIf(ctx, err)
+		}
+		select {
+		case <-GlobalServiceDoneCh:
+			return
+		case <-time.After(bucketUsageTick):
+		}
+	}
+}
+
+var bucketUsageTimeout = newDynamicTimeout(60*time.Second, time.Second)
+
+// bucketUsageRound - loads the usage computed by any server, and
+// computes it again if it is outdated.
+func bucketUsageRound(ctx context.Context, objAPI ObjectLayer) error {
+	info, err := readBucketUsageConfig(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	globalBucketUsage.set(info)
+	if time.Since(info.LastUpdate) < bucketUsageInterval {
+		return nil
+	}
+
+	// Only one server crawls the buckets.
+	crawlLock := globalNSMutex.NewNSLock(ctx, "system", "bucket-usage")
+	if err = crawlLock.GetLock(bucketUsageTimeout); err != nil {
+		return nil
+	}
+	defer crawlLock.Unlock()
+
+	info, err = crawlBucketUsage(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	globalBucketUsage.set(info)
+	return saveBucketUsageConfig(ctx, objAPI, info)
+}
+
+// crawlBucketUsage - counts the objects and bytes of all buckets.
+func crawlBucketUsage(ctx context.Context, objAPI ObjectLayer) (bucketUsageInfo, error) {
+	info := bucketUsageInfo{
+		Version: 1,
+		Buckets: make(map[string]BucketUsage),
+	}
+
+	buckets, err := objAPI.ListBuckets(ctx)
+	if err != nil {
+		return info, err
+	}
+	for _, bucket := range buckets {
+		var usage BucketUsage
+		marker := ""
+		for {
+			loi, err := objAPI.ListObjects(ctx, bucket.Name, "", marker, "", maxObjectList)
+			if err != nil {
+				return info, err
+			}
+			for _, obj := range loi.Objects {
+				if obj.IsDir {
+					continue
+				}
+				usage.ObjectsCount++
+				usage.Size += uint64(obj.Size)
+			}
+			if !loi.IsTruncated {
+				break
+			}
+			marker = loi.NextMarker
+		}
+		info.Buckets[bucket.Name] = usage
+	}
+	info.LastUpdate = UTCNow()
+	return info, nil
+}
+
+func saveBucketUsageConfig(ctx context.Context, objAPI ObjectLayer, info bucketUsageInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, path.Join(minioConfigPrefix, bucketUsageConfigFile), data)
+}
+
+// readBucketUsageConfig - returns the usage of all buckets saved by
+// the last crawl, an empty usage if no crawl finished yet.
+func readBucketUsageConfig(ctx context.Context, objAPI ObjectLayer) (bucketUsageInfo, error) {
+	var info bucketUsageInfo
+	configData, err := readConfig(ctx, objAPI, path.Join(minioConfigPrefix, bucketUsageConfigFile))
+	if err != nil {
+		if err == errConfigNotFound {
+			return info, nil
+		}
+		return info, err
+	}
+	err = json.Unmarshal(configData, &info)
+	return info, err
+}
@@ -0,0 +1,70 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestBucketUsageRound(t *testing.T) {
+	obj, fsDirs, err := prepareXL16()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	initNSLock(false)
+
+	ctx := context.Background()
+	for _, bucket := range []string{"bucket", "empty"} {
+		if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, object := range []string{"a", "dir/b"} {
+		data := []byte("hello")
+		if _, err = obj.PutObject(ctx, "bucket", object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	savedUsage := globalBucketUsage
+	defer func() { globalBucketUsage = savedUsage }()
+	globalBucketUsage = &bucketUsageCache{}
+
+	if err = bucketUsageRound(ctx, obj); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]BucketUsage{
+		"bucket": {ObjectsCount: 2, Size: 10},
+		"empty":  {},
+	}
+	if usage := globalBucketUsage.get(); !reflect.DeepEqual(usage, expected) {
+		t.Fatalf("Expected %v, got %v", expected, usage)
+	}
+
+	// The usage is saved for the other servers.
+	info, err := readBucketUsageConfig(ctx, obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(info.Buckets, expected) || info.LastUpdate.IsZero() {
+		t.Fatalf("Expected saved usage %v, got %v", expected, info)
+	}
+}
@@ -17,6 +17,7 @@
 package cmd
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
 	"net"
@@ -30,8 +31,12 @@ import (
 	"github.com/minio/cli"
 	"github.com/minio/minio-go/v6/pkg/set"
 	"github.com/minio/minio/cmd/config"
+	"github.com/minio/minio/cmd/config/audit"
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/cmd/logger/target/file"
 	"github.com/minio/minio/cmd/logger/target/http"
+	"github.com/minio/minio/cmd/logger/target/kafka"
+	"github.com/minio/minio/cmd/logger/target/queue"
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/dns"
 	"github.com/minio/minio/pkg/env"
@@ -76,21 +81,29 @@ func checkUpdate(mode string) {
 func loadLoggers() {
 	loggerUserAgent := getUserAgent(getMinioMode())
 
-	auditEndpoint, ok := env.Lookup("MINIO_AUDIT_LOGGER_HTTP_ENDPOINT")
-	if ok {
-		// Enable audit HTTP logging through ENV.
-		logger.AddAuditTarget(http.New(auditEndpoint, loggerUserAgent, NewCustomHTTPTransport()))
+	auditConfig, err := audit.Lookup()
+	logger.FatalIf(err, "Unable to parse audit logger configuration from env")
+	loadAuditLoggers(auditConfig, loggerUserAgent)
+
+	newHTTPLogger := func(endpoint string) logger.Target {
+		target, err := http.New(http.Args{
+			Endpoint:  endpoint,
+			UserAgent: loggerUserAgent,
+			Transport: NewCustomHTTPTransport(),
+		})
+		logger.FatalIf(err, "Unable to initialize the HTTP logger %s", endpoint)
+		return target
 	}
 
 	loggerEndpoint, ok := env.Lookup("MINIO_LOGGER_HTTP_ENDPOINT")
 	if ok {
 		// Enable HTTP logging through ENV.
-		logger.AddTarget(http.New(loggerEndpoint, loggerUserAgent, NewCustomHTTPTransport()))
+		logger.AddTarget(newHTTPLogger(loggerEndpoint))
 	} else {
 		for _, l := range globalServerConfig.Logger.HTTP {
 			if l.Enabled {
 				// Enable http logging
-				logger.AddTarget(http.New(l.Endpoint, loggerUserAgent, NewCustomHTTPTransport()))
+				logger.AddTarget(newHTTPLogger(l.Endpoint))
 			}
 		}
 	}
@@ -102,6 +115,69 @@ func loadLoggers() {
 
 }
 
+// Load the audit logger targets, audit logs are queued until they are
+// delivered and failed deliveries are retried.
+func loadAuditLoggers(c audit.Config, loggerUserAgent string) {
+	logger.SetAuditHeaderFilter(c.HeadersAllow, c.HeadersDeny)
+
+	queueConfig := func(name string) queue.Config {
+		qc := queue.Config{Limit: c.QueueLimit, BatchSize: c.BatchSize}
+		if c.QueueDir != "" {
+			qc.Dir = filepath.Join(c.QueueDir, name)
+		}
+		return qc
+	}
+	onError := func(name string) func(error) {
+		return func(err error) {
+			logger.LogOnceIf(context.Background(), err, "audit-"+name)
+		}
+	}
+
+	if c.HTTP.Enabled {
+		target, err := http.New(http.Args{
+			Endpoint:  c.HTTP.Endpoint,
+			AuthToken: c.HTTP.AuthToken,
+			UserAgent: loggerUserAgent,
+			Transport: NewCustomHTTPTransport(),
+			Queue:     queueConfig("http"),
+			OnError:   onError("http"),
+		})
+		logger.FatalIf(err, "Unable to initialize the HTTP audit logger %s", c.HTTP.Endpoint)
+		logger.AddAuditTarget(target)
+	}
+
+	if c.Kafka.Enabled {
+		args := kafka.Args{
+			Brokers: c.Kafka.Brokers,
+			Topic:   c.Kafka.Topic,
+			Queue:   queueConfig("kafka"),
+			OnError: onError("kafka"),
+		}
+		args.TLS.Enable = c.Kafka.TLS
+		args.TLS.SkipVerify = c.Kafka.TLSSkipVerify
+		args.TLS.RootCAs = globalRootCAs
+		args.SASL.Enable = c.Kafka.SASLUsername != ""
+		args.SASL.User = c.Kafka.SASLUsername
+		args.SASL.Password = c.Kafka.SASLPassword
+		target, err := kafka.New(args)
+		logger.FatalIf(err, "Unable to initialize the Kafka audit logger")
+		logger.AddAuditTarget(target)
+	}
+
+	if c.File.Enabled {
+		target, err := file.New(file.Args{
+			Dir:      c.File.Dir,
+			Filename: "audit.log",
+			MaxSize:  int64(c.File.MaxSize),
+			MaxFiles: c.File.MaxFiles,
+			Queue:    queueConfig("file"),
+			OnError:  onError("file"),
+		})
+		logger.FatalIf(err, "Unable to initialize the audit log file in %s", c.File.Dir)
+		logger.AddAuditTarget(target)
+	}
+}
+
 func newConfigDirFromCtx(ctx *cli.Context, option string, getDefaultDir func() string) (*ConfigDir, bool) {
 	var dir string
 	var dirSet bool
@@ -333,19 +409,29 @@ func handleCommonEnvVars() {
 			logger.FatalIf(err, "Invalid value set in environment variable %s", reducedRedundancyStorageClassEnv)
 		}
 
+		if csc := os.Getenv(customStorageClassEnv); csc != "" {
+			globalCustomStorageClasses, err = parseCustomStorageClasses(csc)
+			logger.FatalIf(err, "Invalid value set in environment variable %s", customStorageClassEnv)
+		}
+
 		// Validation is done after parsing both the storage classes. This is needed because we need one
 		// storage class value to deduce the correct value of the other storage class.
 		if globalRRStorageClass.Scheme != "" {
-			err = validateParity(globalStandardStorageClass.Parity, globalRRStorageClass.Parity)
+			err = validateParity(globalStandardStorageClass.Parity, globalRRStorageClass.Parity, nil)
 			logger.FatalIf(err, "Invalid value set in environment variable %s", reducedRedundancyStorageClassEnv)
 			globalIsStorageClass = true
 		}
 
 		if globalStandardStorageClass.Scheme != "" {
-			err = validateParity(globalStandardStorageClass.Parity, globalRRStorageClass.Parity)
+			err = validateParity(globalStandardStorageClass.Parity, globalRRStorageClass.Parity, nil)
 			logger.FatalIf(err, "Invalid value set in environment variable %s", standardStorageClassEnv)
 			globalIsStorageClass = true
 		}
+
+		if len(globalCustomStorageClasses) > 0 {
+			err = validateParity(globalStandardStorageClass.Parity, globalRRStorageClass.Parity, globalCustomStorageClasses)
+			logger.FatalIf(err, "Invalid value set in environment variable %s", customStorageClassEnv)
+		}
 	}
 
 	// Get WORM environment variable.
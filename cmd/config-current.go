@@ -304,6 +304,18 @@ func (s *serverConfig) loadFromEnvs() {
 		s.OpenID.JWKS.URL = u
 	}
 
+	if configURL, ok := env.Lookup("MINIO_IDENTITY_OPENID_CONFIG_URL"); ok {
+		u, err := xnet.ParseURL(configURL)
+		if err != nil {
+			logger.FatalIf(err, "Unable to parse MINIO_IDENTITY_OPENID_CONFIG_URL %s", configURL)
+		}
+		s.OpenID.JWKS.ConfigURL = u
+	}
+
+	s.OpenID.JWKS.ClientID = env.Get("MINIO_IDENTITY_OPENID_CLIENT_ID", s.OpenID.JWKS.ClientID)
+	s.OpenID.JWKS.ClaimName = env.Get("MINIO_IDENTITY_OPENID_CLAIM_NAME", s.OpenID.JWKS.ClaimName)
+	s.OpenID.JWKS.ClaimPrefix = env.Get("MINIO_IDENTITY_OPENID_CLAIM_PREFIX", s.OpenID.JWKS.ClaimPrefix)
+
 	if opaURL, ok := env.Lookup("MINIO_IAM_OPA_URL"); ok {
 		u, err := xnet.ParseURL(opaURL)
 		if err != nil {
@@ -581,11 +593,20 @@ func (s *serverConfig) loadToCachedConfigs() {
 		globalIsCompressionEnabled = compressionConf.Enabled
 	}
 
-	if s.OpenID.JWKS.URL != nil && s.OpenID.JWKS.URL.String() != "" {
+	if s.OpenID.JWKS.IsEnabled() {
 		logger.FatalIf(s.OpenID.JWKS.PopulatePublicKey(),
 			"Unable to populate public key from JWKS URL %s", s.OpenID.JWKS.URL)
 	}
 
+	for id, args := range s.OpenID.Providers {
+		if !args.IsEnabled() {
+			continue
+		}
+		logger.FatalIf(args.PopulatePublicKey(),
+			"Unable to populate public key for OpenID provider %s", id)
+		s.OpenID.Providers[id] = args
+	}
+
 	globalOpenIDValidators = getOpenIDValidators(s)
 
 	if s.Policy.OPA.URL != nil && s.Policy.OPA.URL.String() != "" {
@@ -660,10 +681,16 @@ func loadConfig(objAPI ObjectLayer) error {
 func getOpenIDValidators(config *serverConfig) *openid.Validators {
 	validators := openid.NewValidators()
 
-	if config.OpenID.JWKS.URL != nil {
+	if config.OpenID.JWKS.IsEnabled() {
 		validators.Add(openid.NewJWT(config.OpenID.JWKS))
 	}
 
+	for id, args := range config.OpenID.Providers {
+		if args.IsEnabled() {
+			validators.Add(openid.NewJWTWithID(openid.ID(id), args))
+		}
+	}
+
 	return validators
 }
 
@@ -812,8 +839,12 @@ func getNotificationTargets(config *serverConfig) *event.TargetList {
 	for id, args := range config.Notify.Webhook {
 		if args.Enable {
 			args.RootCAs = globalRootCAs
-			newTarget := target.NewWebhookTarget(id, args, GlobalServiceDoneCh)
-			if err := targetList.Add(newTarget); err != nil {
+			newTarget, err := target.NewWebhookTarget(id, args, GlobalServiceDoneCh)
+			if err != nil {
+				logger.LogIf(context.Background(), err)
+				continue
+			}
+			if err = targetList.Add(newTarget); err != nil {
 				logger.LogIf(context.Background(), err)
 				continue
 			}
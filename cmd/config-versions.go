@@ -913,6 +913,10 @@ type serverConfigV33 struct {
 	OpenID struct {
 		// JWKS validator config.
 		JWKS openid.JWKSArgs `json:"jwks"`
+
+		// Additional identity providers indexed by provider id,
+		// tokens are matched to a provider by their issuer.
+		Providers map[string]openid.JWKSArgs `json:"providers,omitempty"`
 	} `json:"openid"`
 
 	// External policy enforcements.
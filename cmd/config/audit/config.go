@@ -0,0 +1,170 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"errors"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/env"
+)
+
+const (
+	// Audit log files are rotated at 100MiB by default.
+	defaultFileMaxSize = 100 * humanize.MiByte
+
+	// Rotated audit log files kept by default.
+	defaultFileMaxFiles = 10
+)
+
+// HTTP - audit logs sent to a webhook.
+type HTTP struct {
+	Enabled   bool
+	Endpoint  string
+	AuthToken string
+}
+
+// Kafka - audit logs published to a Kafka topic.
+type Kafka struct {
+	Enabled       bool
+	Brokers       []string
+	Topic         string
+	TLS           bool
+	TLSSkipVerify bool
+	SASLUsername  string
+	SASLPassword  string
+}
+
+// File - audit logs appended to rotating local files.
+type File struct {
+	Enabled  bool
+	Dir      string
+	MaxSize  uint64
+	MaxFiles int
+}
+
+// Config - audit log targets and the delivery shared by them.
+type Config struct {
+	HTTP  HTTP
+	Kafka Kafka
+	File  File
+
+	// Directory in which undelivered audit logs are kept, each
+	// target uses its own sub directory. Audit logs are kept in
+	// memory if empty.
+	QueueDir string
+	// Maximum number of undelivered audit logs per target.
+	QueueLimit uint64
+	// Maximum number of audit logs sent at once.
+	BatchSize int
+
+	// Headers recorded in audit logs, all headers are recorded if
+	// empty. Denied headers are never recorded.
+	HeadersAllow []string
+	HeadersDeny  []string
+}
+
+// Audit logger envs.
+const (
+	EnvAuditHTTPEndpoint       = "MINIO_AUDIT_LOGGER_HTTP_ENDPOINT"
+	EnvAuditHTTPAuthToken      = "MINIO_AUDIT_LOGGER_HTTP_AUTH_TOKEN"
+	EnvAuditKafkaBrokers       = "MINIO_AUDIT_LOGGER_KAFKA_BROKERS"
+	EnvAuditKafkaTopic         = "MINIO_AUDIT_LOGGER_KAFKA_TOPIC"
+	EnvAuditKafkaTLS           = "MINIO_AUDIT_LOGGER_KAFKA_TLS"
+	EnvAuditKafkaTLSSkipVerify = "MINIO_AUDIT_LOGGER_KAFKA_TLS_SKIP_VERIFY"
+	EnvAuditKafkaSASLUsername  = "MINIO_AUDIT_LOGGER_KAFKA_SASL_USERNAME"
+	EnvAuditKafkaSASLPassword  = "MINIO_AUDIT_LOGGER_KAFKA_SASL_PASSWORD"
+	EnvAuditFileDir            = "MINIO_AUDIT_LOGGER_FILE_DIR"
+	EnvAuditFileMaxSize        = "MINIO_AUDIT_LOGGER_FILE_MAX_SIZE"
+	EnvAuditFileMaxFiles       = "MINIO_AUDIT_LOGGER_FILE_MAX_FILES"
+	EnvAuditQueueDir           = "MINIO_AUDIT_LOGGER_QUEUE_DIR"
+	EnvAuditQueueLimit         = "MINIO_AUDIT_LOGGER_QUEUE_LIMIT"
+	EnvAuditBatchSize          = "MINIO_AUDIT_LOGGER_BATCH_SIZE"
+	EnvAuditHeadersAllow       = "MINIO_AUDIT_LOGGER_HEADERS_ALLOW"
+	EnvAuditHeadersDeny        = "MINIO_AUDIT_LOGGER_HEADERS_DENY"
+)
+
+// Lookup - initializes the audit logger config from the environment,
+// a target is enabled if its endpoint, brokers or directory is set.
+func Lookup() (c Config, err error) {
+	if c.HTTP.Endpoint = env.Get(EnvAuditHTTPEndpoint, ""); c.HTTP.Endpoint != "" {
+		c.HTTP.Enabled = true
+		c.HTTP.AuthToken = env.Get(EnvAuditHTTPAuthToken, "")
+	}
+
+	if v := env.Get(EnvAuditKafkaBrokers, ""); v != "" {
+		c.Kafka.Brokers = splitList(v)
+		c.Kafka.Topic = env.Get(EnvAuditKafkaTopic, "")
+		if c.Kafka.Topic == "" {
+			return c, errors.New("Audit Kafka topic has to be set")
+		}
+		c.Kafka.TLS = strings.EqualFold(env.Get(EnvAuditKafkaTLS, "off"), "on")
+		c.Kafka.TLSSkipVerify = strings.EqualFold(env.Get(EnvAuditKafkaTLSSkipVerify, "off"), "on")
+		c.Kafka.SASLUsername = env.Get(EnvAuditKafkaSASLUsername, "")
+		c.Kafka.SASLPassword = env.Get(EnvAuditKafkaSASLPassword, "")
+		c.Kafka.Enabled = true
+	}
+
+	if c.File.Dir = env.Get(EnvAuditFileDir, ""); c.File.Dir != "" {
+		if !filepath.IsAbs(c.File.Dir) {
+			return c, errors.New("Audit log file directory has to be an absolute path")
+		}
+		c.File.MaxSize = defaultFileMaxSize
+		if v := env.Get(EnvAuditFileMaxSize, ""); v != "" {
+			if c.File.MaxSize, err = humanize.ParseBytes(v); err != nil {
+				return c, errors.New("Audit log file max size err:" + err.Error())
+			}
+		}
+		c.File.MaxFiles = defaultFileMaxFiles
+		if v := env.Get(EnvAuditFileMaxFiles, ""); v != "" {
+			if c.File.MaxFiles, err = strconv.Atoi(v); err != nil || c.File.MaxFiles < 0 {
+				return c, errors.New("Audit log file max files has to be a non-negative number")
+			}
+		}
+		c.File.Enabled = true
+	}
+
+	if c.QueueDir = env.Get(EnvAuditQueueDir, ""); c.QueueDir != "" && !filepath.IsAbs(c.QueueDir) {
+		return c, errors.New("Audit queue directory has to be an absolute path")
+	}
+	if v := env.Get(EnvAuditQueueLimit, ""); v != "" {
+		if c.QueueLimit, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return c, errors.New("Audit queue limit err:" + err.Error())
+		}
+	}
+	if v := env.Get(EnvAuditBatchSize, ""); v != "" {
+		if c.BatchSize, err = strconv.Atoi(v); err != nil || c.BatchSize <= 0 {
+			return c, errors.New("Audit batch size has to be a positive number")
+		}
+	}
+	c.HeadersAllow = splitList(env.Get(EnvAuditHeadersAllow, ""))
+	c.HeadersDeny = splitList(env.Get(EnvAuditHeadersDeny, ""))
+	return c, nil
+}
+
+// Returns the non-empty elements of a comma separated list.
+func splitList(v string) (list []string) {
+	for _, elem := range strings.Split(v, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			list = append(list, elem)
+		}
+	}
+	return list
+}
@@ -0,0 +1,79 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	envs := []string{
+		EnvAuditHTTPEndpoint, EnvAuditHTTPAuthToken,
+		EnvAuditKafkaBrokers, EnvAuditKafkaTopic, EnvAuditKafkaTLS, EnvAuditKafkaTLSSkipVerify,
+		EnvAuditKafkaSASLUsername, EnvAuditKafkaSASLPassword,
+		EnvAuditFileDir, EnvAuditFileMaxSize, EnvAuditFileMaxFiles,
+		EnvAuditQueueDir, EnvAuditQueueLimit, EnvAuditBatchSize,
+		EnvAuditHeadersAllow, EnvAuditHeadersDeny,
+	}
+	testCases := []struct {
+		env         map[string]string
+		expected    Config
+		expectedErr bool
+	}{
+		{map[string]string{}, Config{}, false},
+		{map[string]string{EnvAuditHTTPEndpoint: "http://localhost:8080/audit", EnvAuditHTTPAuthToken: "Bearer secret"},
+			Config{HTTP: HTTP{Enabled: true, Endpoint: "http://localhost:8080/audit", AuthToken: "Bearer secret"}}, false},
+		{map[string]string{EnvAuditKafkaBrokers: "kafka1:9092, kafka2:9092", EnvAuditKafkaTopic: "audit", EnvAuditKafkaTLS: "on"},
+			Config{Kafka: Kafka{Enabled: true, Brokers: []string{"kafka1:9092", "kafka2:9092"}, Topic: "audit", TLS: true}}, false},
+		{map[string]string{EnvAuditKafkaBrokers: "kafka1:9092"}, Config{}, true},
+		{map[string]string{EnvAuditFileDir: "/var/log/minio"},
+			Config{File: File{Enabled: true, Dir: "/var/log/minio", MaxSize: defaultFileMaxSize, MaxFiles: defaultFileMaxFiles}}, false},
+		{map[string]string{EnvAuditFileDir: "/var/log/minio", EnvAuditFileMaxSize: "1GiB", EnvAuditFileMaxFiles: "0"},
+			Config{File: File{Enabled: true, Dir: "/var/log/minio", MaxSize: 1 << 30}}, false},
+		{map[string]string{EnvAuditFileDir: "log"}, Config{}, true},
+		{map[string]string{EnvAuditFileDir: "/var/log/minio", EnvAuditFileMaxSize: "large"}, Config{}, true},
+		{map[string]string{EnvAuditFileDir: "/var/log/minio", EnvAuditFileMaxFiles: "-1"}, Config{}, true},
+		{map[string]string{EnvAuditQueueDir: "/var/lib/minio/audit", EnvAuditQueueLimit: "100000", EnvAuditBatchSize: "100"},
+			Config{QueueDir: "/var/lib/minio/audit", QueueLimit: 100000, BatchSize: 100}, false},
+		{map[string]string{EnvAuditQueueDir: "audit"}, Config{}, true},
+		{map[string]string{EnvAuditQueueLimit: "many"}, Config{}, true},
+		{map[string]string{EnvAuditBatchSize: "0"}, Config{}, true},
+		{map[string]string{EnvAuditHeadersAllow: "Content-Type, X-Amz-Meta-*", EnvAuditHeadersDeny: "Cookie,"},
+			Config{HeadersAllow: []string{"Content-Type", "X-Amz-Meta-*"}, HeadersDeny: []string{"Cookie"}}, false},
+	}
+
+	for i, testCase := range testCases {
+		for _, key := range envs {
+			os.Unsetenv(key)
+		}
+		for key, value := range testCase.env {
+			os.Setenv(key, value)
+		}
+		c, err := Lookup()
+		if testCase.expectedErr != (err != nil) {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err == nil && !reflect.DeepEqual(c, testCase.expected) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, c)
+		}
+	}
+	for _, key := range envs {
+		os.Unsetenv(key)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package certidentity
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/minio/minio/pkg/env"
+)
+
+const (
+	defaultCertExpiry = time.Hour * 1
+)
+
+// Config contains the client certificate identity configuration,
+// used to issue STS credentials to callers presenting a client
+// TLS certificate signed by one of the configured CAs.
+type Config struct {
+	Enabled bool
+
+	// PEM encoded CA certificates file used to verify client
+	// certificates.
+	ClientCAFile string
+
+	// STS credentials expiry duration
+	stsExpiryDuration time.Duration
+
+	clientCAs *x509.CertPool
+}
+
+// Certificate identity envs.
+const (
+	EnvClientCAFile = "MINIO_IDENTITY_TLS_CLIENT_CA_FILE"
+	EnvSTSExpiry    = "MINIO_IDENTITY_TLS_STS_EXPIRY"
+)
+
+// Errors returned while verifying client certificates.
+var (
+	ErrNoClientCertificate = errors.New("no client certificate presented")
+	ErrNoCommonName        = errors.New("client certificate has no subject common name")
+)
+
+// GetExpiryDuration - return parsed expiry duration.
+func (c Config) GetExpiryDuration() time.Duration {
+	return c.stsExpiryDuration
+}
+
+// Verify - verifies the client certificate chain presented during
+// the TLS handshake against the configured CAs and returns the
+// client (leaf) certificate.
+func (c Config) Verify(chain []*x509.Certificate) (*x509.Certificate, error) {
+	if len(chain) == 0 {
+		return nil, ErrNoClientCertificate
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         c.clientCAs,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, cert := range chain[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	cert := chain[0]
+	if _, err := cert.Verify(opts); err != nil {
+		return nil, err
+	}
+	if cert.Subject.CommonName == "" {
+		return nil, ErrNoCommonName
+	}
+	return cert, nil
+}
+
+// Lookup - initializes the certificate identity config from the
+// environment, it is disabled when no client CA file is set.
+func Lookup() (c Config, err error) {
+	caFile := env.Get(EnvClientCAFile, "")
+	if caFile == "" {
+		return c, nil
+	}
+
+	pemData, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return c, fmt.Errorf("Unable to read client CA file: %v", err)
+	}
+	c.clientCAs = x509.NewCertPool()
+	if !c.clientCAs.AppendCertsFromPEM(pemData) {
+		return c, fmt.Errorf("No valid PEM encoded certificates found in %s", caFile)
+	}
+
+	c.stsExpiryDuration = defaultCertExpiry
+	if v := env.Get(EnvSTSExpiry, ""); v != "" {
+		expDur, err := time.ParseDuration(v)
+		if err != nil {
+			return c, errors.New("Certificate identity expiry time err:" + err.Error())
+		}
+		if expDur <= 0 {
+			return c, errors.New("Certificate identity expiry time has to be positive")
+		}
+		c.stsExpiryDuration = expDur
+	}
+
+	c.Enabled = true
+	c.ClientCAFile = caFile
+	return c, nil
+}
@@ -0,0 +1,91 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package certidentity
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newTestCert(t *testing.T, cn string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	if isCA {
+		template.KeyUsage = x509.KeyUsageCertSign
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestVerify(t *testing.T) {
+	ca, caKey := newTestCert(t, "ca", true, nil, nil)
+	otherCA, otherCAKey := newTestCert(t, "other-ca", true, nil, nil)
+
+	client, _ := newTestCert(t, "readwrite", false, ca, caKey)
+	noCN, _ := newTestCert(t, "", false, ca, caKey)
+	untrusted, _ := newTestCert(t, "readwrite", false, otherCA, otherCAKey)
+
+	c := Config{Enabled: true, clientCAs: x509.NewCertPool()}
+	c.clientCAs.AddCert(ca)
+
+	testCases := []struct {
+		chain       []*x509.Certificate
+		expectedErr bool
+	}{
+		{[]*x509.Certificate{client}, false},
+		{[]*x509.Certificate{}, true},
+		{[]*x509.Certificate{noCN}, true},
+		{[]*x509.Certificate{untrusted}, true},
+	}
+
+	for i, testCase := range testCases {
+		cert, err := c.Verify(testCase.chain)
+		if testCase.expectedErr != (err != nil) {
+			t.Errorf("Test %d: Expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err == nil && cert.Subject.CommonName != "readwrite" {
+			t.Errorf("Test %d: Unexpected common name %s", i+1, cert.Subject.CommonName)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drivehealth
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/pkg/env"
+)
+
+const (
+	// Drives slower than this on average are excluded.
+	defaultMaxLatency = 5 * time.Second
+
+	// Drives failing more than this fraction of requests are excluded.
+	defaultMaxErrorRate = 0.5
+
+	// Interval at which excluded drives are probed for re-admission.
+	defaultProbeInterval = 30 * time.Second
+)
+
+// Config contains the thresholds above which drives are
+// temporarily excluded from erasure set operations.
+type Config struct {
+	Enabled bool
+
+	// Maximum average latency of drive requests.
+	MaxLatency time.Duration
+
+	// Maximum fraction of failed drive requests, between 0 and 1.
+	MaxErrorRate float64
+
+	// Interval at which excluded drives are probed.
+	ProbeInterval time.Duration
+}
+
+// Drive health envs.
+const (
+	EnvDriveHealth              = "MINIO_DRIVE_HEALTH"
+	EnvDriveHealthMaxLatency    = "MINIO_DRIVE_HEALTH_MAX_LATENCY"
+	EnvDriveHealthMaxErrorRate  = "MINIO_DRIVE_HEALTH_MAX_ERROR_RATE"
+	EnvDriveHealthProbeInterval = "MINIO_DRIVE_HEALTH_PROBE_INTERVAL"
+)
+
+// Lookup - initializes the drive health config from the environment,
+// slow or faulty drives are excluded unless MINIO_DRIVE_HEALTH is
+// set to "off".
+func Lookup() (c Config, err error) {
+	if strings.EqualFold(env.Get(EnvDriveHealth, "on"), "off") {
+		return c, nil
+	}
+
+	c.MaxLatency = defaultMaxLatency
+	if v := env.Get(EnvDriveHealthMaxLatency, ""); v != "" {
+		if c.MaxLatency, err = time.ParseDuration(v); err != nil {
+			return c, errors.New("Drive max latency err:" + err.Error())
+		}
+		if c.MaxLatency <= 0 {
+			return c, errors.New("Drive max latency has to be positive")
+		}
+	}
+
+	c.MaxErrorRate = defaultMaxErrorRate
+	if v := env.Get(EnvDriveHealthMaxErrorRate, ""); v != "" {
+		if c.MaxErrorRate, err = strconv.ParseFloat(v, 64); err != nil {
+			return c, errors.New("Drive max error rate err:" + err.Error())
+		}
+		if c.MaxErrorRate <= 0 || c.MaxErrorRate > 1 {
+			return c, errors.New("Drive max error rate has to be between 0 and 1")
+		}
+	}
+
+	c.ProbeInterval = defaultProbeInterval
+	if v := env.Get(EnvDriveHealthProbeInterval, ""); v != "" {
+		if c.ProbeInterval, err = time.ParseDuration(v); err != nil {
+			return c, errors.New("Drive probe interval err:" + err.Error())
+		}
+		if c.ProbeInterval <= 0 {
+			return c, errors.New("Drive probe interval has to be positive")
+		}
+	}
+
+	c.Enabled = true
+	return c, nil
+}
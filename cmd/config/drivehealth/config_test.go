@@ -0,0 +1,62 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package drivehealth
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLookup(t *testing.T) {
+	envs := []string{EnvDriveHealth, EnvDriveHealthMaxLatency, EnvDriveHealthMaxErrorRate, EnvDriveHealthProbeInterval}
+	defaultConfig := Config{Enabled: true, MaxLatency: defaultMaxLatency, MaxErrorRate: defaultMaxErrorRate, ProbeInterval: defaultProbeInterval}
+	testCases := []struct {
+		env         map[string]string
+		expected    Config
+		expectedErr bool
+	}{
+		{map[string]string{}, defaultConfig, false},
+		{map[string]string{EnvDriveHealth: "off"}, Config{}, false},
+		{map[string]string{EnvDriveHealthMaxLatency: "2s", EnvDriveHealthMaxErrorRate: "0.1", EnvDriveHealthProbeInterval: "1m"},
+			Config{Enabled: true, MaxLatency: 2 * time.Second, MaxErrorRate: 0.1, ProbeInterval: time.Minute}, false},
+		{map[string]string{EnvDriveHealthMaxLatency: "-1s"}, Config{}, true},
+		{map[string]string{EnvDriveHealthMaxLatency: "slow"}, Config{}, true},
+		{map[string]string{EnvDriveHealthMaxErrorRate: "2"}, Config{}, true},
+		{map[string]string{EnvDriveHealthMaxErrorRate: "many"}, Config{}, true},
+		{map[string]string{EnvDriveHealthProbeInterval: "0s"}, Config{}, true},
+	}
+
+	for i, testCase := range testCases {
+		for _, key := range envs {
+			os.Unsetenv(key)
+		}
+		for key, value := range testCase.env {
+			os.Setenv(key, value)
+		}
+		c, err := Lookup()
+		if testCase.expectedErr != (err != nil) {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err == nil && c != testCase.expected {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, c)
+		}
+	}
+	for _, key := range envs {
+		os.Unsetenv(key)
+	}
+}
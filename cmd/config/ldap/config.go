@@ -22,6 +22,8 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/minio/minio/pkg/env"
@@ -48,9 +50,26 @@ type Config struct {
 	// Format string for usernames
 	UsernameFormat string `json:"usernameFormat"`
 
+	// Lookup bind LDAP service account, used to search for the
+	// DN of a user instead of building it from UsernameFormat.
+	LookupBindDN       string `json:"lookupBindDN"`
+	LookupBindPassword string `json:"lookupBindPassword"`
+
+	// User DN search parameters, only used in lookup bind mode.
+	UserDNSearchBaseDN string `json:"userDNSearchBaseDN"`
+	UserDNSearchFilter string `json:"userDNSearchFilter"`
+
+	// Attributes of the user entry whose values are treated as
+	// additional group names for policy mapping.
+	UserGroupAttributes []string `json:"userGroupAttributes"`
+
 	GroupSearchBaseDN  string `json:"groupSearchBaseDN"`
 	GroupSearchFilter  string `json:"groupSearchFilter"`
 	GroupNameAttribute string `json:"groupNameAttribute"`
+
+	// Resolve groups of groups by re-running the group search
+	// with the DN of every group found.
+	GroupSearchNested bool `json:"groupSearchNested"`
 }
 
 // LDAP keys and envs.
@@ -61,6 +80,12 @@ const (
 	GroupSearchFilter  = "group_search_filter"
 	GroupNameAttribute = "group_name_attribute"
 	GroupSearchBaseDN  = "group_search_base_dn"
+	LookupBindDN       = "lookup_bind_dn"
+	LookupBindPassword = "lookup_bind_password"
+	UserDNSearchBaseDN = "user_dn_search_base_dn"
+	UserDNSearchFilter = "user_dn_search_filter"
+	UserGroupAttrs     = "user_group_attributes"
+	GroupSearchNested  = "group_search_nested"
 
 	EnvServerAddr         = "MINIO_IDENTITY_LDAP_SERVER_ADDR"
 	EnvSTSExpiry          = "MINIO_IDENTITY_LDAP_STS_EXPIRY"
@@ -68,8 +93,18 @@ const (
 	EnvGroupSearchFilter  = "MINIO_IDENTITY_LDAP_GROUP_SEARCH_FILTER"
 	EnvGroupNameAttribute = "MINIO_IDENTITY_LDAP_GROUP_NAME_ATTRIBUTE"
 	EnvGroupSearchBaseDN  = "MINIO_IDENTITY_LDAP_GROUP_SEARCH_BASE_DN"
+	EnvLookupBindDN       = "MINIO_IDENTITY_LDAP_LOOKUP_BIND_DN"
+	EnvLookupBindPassword = "MINIO_IDENTITY_LDAP_LOOKUP_BIND_PASSWORD"
+	EnvUserDNSearchBaseDN = "MINIO_IDENTITY_LDAP_USER_DN_SEARCH_BASE_DN"
+	EnvUserDNSearchFilter = "MINIO_IDENTITY_LDAP_USER_DN_SEARCH_FILTER"
+	EnvUserGroupAttrs     = "MINIO_IDENTITY_LDAP_USER_GROUP_ATTRIBUTES"
+	EnvGroupSearchNested  = "MINIO_IDENTITY_LDAP_GROUP_SEARCH_NESTED"
 )
 
+// maxNestedGroupDepth limits how deep nested group membership
+// is resolved, protecting against cyclic group definitions.
+const maxNestedGroupDepth = 10
+
 // Connect connect to ldap server.
 func (l *Config) Connect() (ldapConn *ldap.Conn, err error) {
 	if l == nil {
@@ -79,6 +114,245 @@ func (l *Config) Connect() (ldapConn *ldap.Conn, err error) {
 	return ldap.DialTLS("tcp", l.ServerAddr, &tls.Config{RootCAs: l.RootCAs})
 }
 
+// IsLookupBindEnabled - returns true if a lookup bind service
+// account is configured to search for user DNs.
+func (l Config) IsLookupBindEnabled() bool {
+	return l.LookupBindDN != ""
+}
+
+// lookupBind - binds the connection with the lookup bind
+// service account credentials.
+func (l *Config) lookupBind(conn *ldap.Conn) error {
+	if l.LookupBindPassword == "" {
+		return conn.UnauthenticatedBind(l.LookupBindDN)
+	}
+	return conn.Bind(l.LookupBindDN, l.LookupBindPassword)
+}
+
+// lookupUserDN - searches for the DN of the given username, the
+// connection must already be bound with the lookup bind account.
+func (l *Config) lookupUserDN(conn *ldap.Conn, username string) (string, error) {
+	subs, err := NewSubstituter("username", ldap.EscapeFilter(username))
+	if err != nil {
+		return "", err
+	}
+	// We ignore error below as we already validated the search
+	// filter at startup.
+	filter, _ := subs.Substitute(l.UserDNSearchFilter)
+	searchRequest := ldap.NewSearchRequest(
+		l.UserDNSearchBaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		filter,
+		[]string{}, // only need DN, so no pass no attributes here
+		nil,
+	)
+
+	searchResult, err := conn.Search(searchRequest)
+	if err != nil {
+		return "", err
+	}
+	if len(searchResult.Entries) != 1 {
+		return "", ErrUserDNNotFound{Username: username, Count: len(searchResult.Entries)}
+	}
+	return searchResult.Entries[0].DN, nil
+}
+
+// groupMember - an entry whose groups are searched, the user
+// itself or, for nested groups, a group found before.
+type groupMember struct {
+	name, dn string
+}
+
+// searchForUserGroups - returns the names of all the groups the
+// user is a member of, including nested groups if enabled. Nested
+// levels search with the name and DN of the groups found before in
+// place of the username and user DN.
+func (l *Config) searchForUserGroups(conn *ldap.Conn, username, bindDN string) ([]string, error) {
+	var groups []string
+	if l.GroupSearchFilter == "" {
+		return groups, nil
+	}
+
+	seen := make(map[string]struct{})
+	queue := []groupMember{{name: username, dn: bindDN}}
+	for depth := 0; len(queue) > 0 && depth < maxNestedGroupDepth; depth++ {
+		var next []groupMember
+		for _, member := range queue {
+			entries, err := l.searchGroups(conn, member.name, member.dn)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if _, ok := seen[entry.DN]; ok {
+					continue
+				}
+				seen[entry.DN] = struct{}{}
+				names := entry.GetAttributeValues(l.GroupNameAttribute)
+				groups = append(groups, names...)
+				group := groupMember{dn: entry.DN}
+				if len(names) > 0 {
+					group.name = names[0]
+				}
+				next = append(next, group)
+			}
+		}
+		if !l.GroupSearchNested {
+			break
+		}
+		queue = next
+	}
+	return groups, nil
+}
+
+// searchGroups - runs the group search with `memberName` and
+// `memberDN` substituted for the `username` and `usernamedn`
+// variables.
+func (l *Config) searchGroups(conn *ldap.Conn, memberName, memberDN string) ([]*ldap.Entry, error) {
+	searchSubs, err := NewSubstituter(
+		"username", ldap.EscapeFilter(memberName),
+		"usernamedn", ldap.EscapeFilter(memberDN),
+	)
+	if err != nil {
+		return nil, err
+	}
+	// We ignore error below as we already validated the search
+	// string at startup.
+	groupSearchFilter, _ := searchSubs.Substitute(l.GroupSearchFilter)
+	baseSubs, err := NewSubstituter(
+		"username", memberName,
+		"usernamedn", memberDN,
+	)
+	if err != nil {
+		return nil, err
+	}
+	baseDN, _ := baseSubs.Substitute(l.GroupSearchBaseDN)
+	searchRequest := ldap.NewSearchRequest(
+		baseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		groupSearchFilter,
+		[]string{l.GroupNameAttribute},
+		nil,
+	)
+
+	sr, err := conn.Search(searchRequest)
+	if err != nil {
+		return nil, err
+	}
+	return sr.Entries, nil
+}
+
+// searchUserAttributeGroups - returns the values of the configured
+// user group attributes from the user entry itself.
+func (l *Config) searchUserAttributeGroups(conn *ldap.Conn, bindDN string) ([]string, error) {
+	var groups []string
+	if len(l.UserGroupAttributes) == 0 {
+		return groups, nil
+	}
+	searchRequest := ldap.NewSearchRequest(
+		bindDN,
+		ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false,
+		"(objectClass=*)",
+		l.UserGroupAttributes,
+		nil,
+	)
+	sr, err := conn.Search(searchRequest)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range sr.Entries {
+		for _, attr := range l.UserGroupAttributes {
+			groups = append(groups, entry.GetAttributeValues(attr)...)
+		}
+	}
+	return groups, nil
+}
+
+// Bind - authenticates the user with the given password and
+// returns the user's DN and the names of all the groups the user
+// belongs to.
+func (l *Config) Bind(username, password string) (string, []string, error) {
+	conn, err := l.Connect()
+	if err != nil {
+		return "", nil, err
+	}
+	if conn == nil {
+		return "", nil, errors.New("LDAP server not configured")
+	}
+	defer conn.Close()
+
+	var bindDN string
+	if l.IsLookupBindEnabled() {
+		// Bind to the lookup user account
+		if err = l.lookupBind(conn); err != nil {
+			return "", nil, fmt.Errorf("LDAP lookup bind failure: %v", err)
+		}
+
+		// Lookup user DN
+		bindDN, err = l.lookupUserDN(conn, username)
+		if err != nil {
+			return "", nil, err
+		}
+	} else {
+		usernameSubs, _ := NewSubstituter("username", username)
+		// We ignore error below as we already validated the username
+		// format string at startup.
+		bindDN, _ = usernameSubs.Substitute(l.UsernameFormat)
+	}
+
+	// Authenticate the user credentials.
+	if err = conn.Bind(bindDN, password); err != nil {
+		return "", nil, fmt.Errorf("LDAP authentication failure: %v", err)
+	}
+
+	groups, err := l.searchUserAttributeGroups(conn, bindDN)
+	if err != nil {
+		return "", nil, fmt.Errorf("LDAP search failure: %v", err)
+	}
+
+	// In lookup bind mode group search is done with the service
+	// account, as regular users may not be allowed to search.
+	if l.IsLookupBindEnabled() {
+		if err = l.lookupBind(conn); err != nil {
+			return "", nil, fmt.Errorf("LDAP lookup bind failure: %v", err)
+		}
+	}
+
+	memberOf, err := l.searchForUserGroups(conn, username, bindDN)
+	if err != nil {
+		return "", nil, fmt.Errorf("LDAP search failure: %v", err)
+	}
+	return bindDN, append(groups, memberOf...), nil
+}
+
+// DoesUsernameExist - checks if the given username is still present
+// in the directory, only supported in lookup bind mode.
+func (l *Config) DoesUsernameExist(username string) (bool, error) {
+	if !l.IsLookupBindEnabled() {
+		return false, errors.New("LDAP lookup bind is not configured")
+	}
+
+	conn, err := l.Connect()
+	if err != nil {
+		return false, err
+	}
+	if conn == nil {
+		return false, errors.New("LDAP server not configured")
+	}
+	defer conn.Close()
+
+	if err = l.lookupBind(conn); err != nil {
+		return false, fmt.Errorf("LDAP lookup bind failure: %v", err)
+	}
+
+	if _, err = l.lookupUserDN(conn, username); err != nil {
+		if _, ok := err.(ErrUserDNNotFound); ok {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 // GetExpiryDuration - return parsed expiry duration.
 func (l Config) GetExpiryDuration() time.Duration {
 	return l.stsExpiryDuration
@@ -120,6 +394,36 @@ func Lookup(cfg Config, rootCAs *x509.CertPool) (l Config, err error) {
 		l.UsernameFormat = v
 	}
 
+	lookupBindDN := env.Get(EnvLookupBindDN, cfg.LookupBindDN)
+	userDNSearchBaseDN := env.Get(EnvUserDNSearchBaseDN, cfg.UserDNSearchBaseDN)
+	userDNSearchFilter := env.Get(EnvUserDNSearchFilter, cfg.UserDNSearchFilter)
+	if lookupBindDN != "" {
+		if l.UsernameFormat != "" {
+			return l, errors.New("Username format and lookup bind mode cannot be used together")
+		}
+		if userDNSearchBaseDN == "" || userDNSearchFilter == "" {
+			return l, errors.New("User DN search base DN and filter must be set in lookup bind mode")
+		}
+		subs, err := NewSubstituter("username", "test")
+		if err != nil {
+			return l, err
+		}
+		if _, err := subs.Substitute(userDNSearchFilter); err != nil {
+			return l, fmt.Errorf("Only username may be substituted in the user DN search filter: %s", err)
+		}
+		l.LookupBindDN = lookupBindDN
+		l.LookupBindPassword = env.Get(EnvLookupBindPassword, cfg.LookupBindPassword)
+		l.UserDNSearchBaseDN = userDNSearchBaseDN
+		l.UserDNSearchFilter = userDNSearchFilter
+	} else if userDNSearchBaseDN != "" || userDNSearchFilter != "" {
+		return l, errors.New("User DN search parameters require a lookup bind DN")
+	}
+
+	l.UserGroupAttributes = cfg.UserGroupAttributes
+	if v := env.Get(EnvUserGroupAttrs, ""); v != "" {
+		l.UserGroupAttributes = strings.Split(v, ",")
+	}
+
 	grpSearchFilter := env.Get(EnvGroupSearchFilter, cfg.GroupSearchFilter)
 	grpSearchNameAttr := env.Get(EnvGroupNameAttribute, cfg.GroupNameAttribute)
 	grpSearchBaseDN := env.Get(EnvGroupSearchBaseDN, cfg.GroupSearchBaseDN)
@@ -150,9 +454,39 @@ func Lookup(cfg Config, rootCAs *x509.CertPool) (l Config, err error) {
 		}
 		l.GroupSearchBaseDN = grpSearchBaseDN
 	}
+
+	l.GroupSearchNested = cfg.GroupSearchNested
+	if v := env.Get(EnvGroupSearchNested, ""); v != "" {
+		nested, err := strconv.ParseBool(v)
+		if err != nil {
+			return l, fmt.Errorf("Invalid value for group search nested: %s", err)
+		}
+		l.GroupSearchNested = nested
+	}
+	if l.GroupSearchNested && !allSet {
+		return l, errors.New("Nested group search requires all group related parameters to be set")
+	}
+	if l.GroupSearchNested && !strings.Contains(l.GroupSearchFilter, "${usernamedn}") {
+		// Groups are members of their parent groups by DN.
+		return l, errors.New("Nested group search requires usernamedn to be substituted in the group search filter string")
+	}
 	return
 }
 
+// ErrUserDNNotFound - returned when the user DN search did not find
+// exactly one entry for the given username.
+type ErrUserDNNotFound struct {
+	Username string
+	Count    int
+}
+
+func (e ErrUserDNNotFound) Error() string {
+	if e.Count == 0 {
+		return fmt.Sprintf("User DN for %s not found", e.Username)
+	}
+	return fmt.Sprintf("Multiple DNs for %s found - please fix the search filter", e.Username)
+}
+
 // Substituter - This type is to allow restricted runtime
 // substitutions of variables in LDAP configuration items during
 // runtime.
@@ -63,3 +63,99 @@ func TestSubstituter(t *testing.T) {
 		})
 	}
 }
+
+func TestLookupBindConfig(t *testing.T) {
+	tests := []struct {
+		cfg         Config
+		ErrExpected bool
+	}{
+		{
+			cfg: Config{
+				ServerAddr:         "ldap.minio.io:636",
+				LookupBindDN:       "cn=admin,dc=minio,dc=io",
+				UserDNSearchBaseDN: "dc=minio,dc=io",
+				UserDNSearchFilter: "(uid=${username})",
+			},
+			ErrExpected: false,
+		},
+		{
+			cfg: Config{
+				ServerAddr:         "ldap.minio.io:636",
+				LookupBindDN:       "cn=admin,dc=minio,dc=io",
+				UserDNSearchFilter: "(uid=${username})",
+			},
+			ErrExpected: true,
+		},
+		{
+			cfg: Config{
+				ServerAddr:         "ldap.minio.io:636",
+				LookupBindDN:       "cn=admin,dc=minio,dc=io",
+				UserDNSearchBaseDN: "dc=minio,dc=io",
+				UserDNSearchFilter: "(uid=${usernamedn})",
+			},
+			ErrExpected: true,
+		},
+		{
+			cfg: Config{
+				ServerAddr:         "ldap.minio.io:636",
+				UsernameFormat:     "uid=${username},dc=minio,dc=io",
+				LookupBindDN:       "cn=admin,dc=minio,dc=io",
+				UserDNSearchBaseDN: "dc=minio,dc=io",
+				UserDNSearchFilter: "(uid=${username})",
+			},
+			ErrExpected: true,
+		},
+		{
+			cfg: Config{
+				ServerAddr:         "ldap.minio.io:636",
+				UserDNSearchBaseDN: "dc=minio,dc=io",
+			},
+			ErrExpected: true,
+		},
+		{
+			cfg: Config{
+				ServerAddr:        "ldap.minio.io:636",
+				UsernameFormat:    "uid=${username},dc=minio,dc=io",
+				GroupSearchNested: true,
+			},
+			ErrExpected: true,
+		},
+		{
+			cfg: Config{
+				ServerAddr:         "ldap.minio.io:636",
+				UsernameFormat:     "uid=${username},dc=minio,dc=io",
+				GroupSearchFilter:  "(&(objectclass=groupOfNames)(memberUid=${username}))",
+				GroupNameAttribute: "cn",
+				GroupSearchBaseDN:  "dc=minio,dc=io",
+				GroupSearchNested:  true,
+			},
+			ErrExpected: true,
+		},
+		{
+			cfg: Config{
+				ServerAddr:         "ldap.minio.io:636",
+				UsernameFormat:     "uid=${username},dc=minio,dc=io",
+				GroupSearchFilter:  "(&(objectclass=groupOfNames)(member=${usernamedn}))",
+				GroupNameAttribute: "cn",
+				GroupSearchBaseDN:  "dc=minio,dc=io",
+				GroupSearchNested:  true,
+			},
+		},
+	}
+
+	for i, test := range tests {
+		test := test
+		t.Run(fmt.Sprintf("Test%d", i+1), func(t *testing.T) {
+			l, err := Lookup(test.cfg, nil)
+			if err != nil && !test.ErrExpected {
+				t.Errorf("Unexpected failure %s", err)
+			}
+			if err == nil && test.ErrExpected {
+				t.Errorf("Expected failure, got success")
+			}
+			if err == nil && test.cfg.LookupBindDN != "" && !l.IsLookupBindEnabled() {
+				t.Errorf("Expected lookup bind mode to be enabled")
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listindex
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minio/minio/pkg/env"
+)
+
+// Config contains the buckets which are listed from a
+// persistent metadata index instead of walking all drives.
+//
+// Every server keeps the index in memory and shares its
+// updates with the other servers through journals on the
+// drives, all servers must use the same configuration.
+type Config struct {
+	Enabled bool
+
+	// Indexed buckets, all buckets are indexed if empty.
+	Buckets []string
+}
+
+// List index envs.
+const (
+	EnvListIndex        = "MINIO_LIST_INDEX"
+	EnvListIndexBuckets = "MINIO_LIST_INDEX_BUCKETS"
+)
+
+// IsIndexed - returns true if listings of the bucket are
+// served from the index.
+func (c Config) IsIndexed(bucket string) bool {
+	if !c.Enabled {
+		return false
+	}
+	if len(c.Buckets) == 0 {
+		return true
+	}
+	for _, b := range c.Buckets {
+		if b == bucket {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup - initializes the list index config from the environment.
+func Lookup() (c Config, err error) {
+	if !strings.EqualFold(env.Get(EnvListIndex, "off"), "on") {
+		return c, nil
+	}
+
+	if v := env.Get(EnvListIndexBuckets, ""); v != "" {
+		for _, bucket := range strings.Split(v, ",") {
+			bucket = strings.TrimSpace(bucket)
+			if bucket == "" {
+				return c, fmt.Errorf("Invalid list index buckets %s, expected a comma separated list of buckets", v)
+			}
+			c.Buckets = append(c.Buckets, bucket)
+		}
+	}
+
+	c.Enabled = true
+	return c, nil
+}
@@ -0,0 +1,76 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listindex
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	envs := []string{EnvListIndex, EnvListIndexBuckets}
+	testCases := []struct {
+		env         map[string]string
+		expected    Config
+		expectedErr bool
+	}{
+		{map[string]string{}, Config{}, false},
+		{map[string]string{EnvListIndexBuckets: "photos"}, Config{}, false},
+		{map[string]string{EnvListIndex: "on"}, Config{Enabled: true}, false},
+		{map[string]string{EnvListIndex: "on", EnvListIndexBuckets: "photos, logs"},
+			Config{Enabled: true, Buckets: []string{"photos", "logs"}}, false},
+		{map[string]string{EnvListIndex: "on", EnvListIndexBuckets: "photos,,logs"}, Config{}, true},
+	}
+
+	for i, testCase := range testCases {
+		for _, key := range envs {
+			os.Unsetenv(key)
+		}
+		for key, value := range testCase.env {
+			os.Setenv(key, value)
+		}
+		c, err := Lookup()
+		if testCase.expectedErr != (err != nil) {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err == nil && !reflect.DeepEqual(c, testCase.expected) {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, c)
+		}
+	}
+	for _, key := range envs {
+		os.Unsetenv(key)
+	}
+}
+
+func TestIsIndexed(t *testing.T) {
+	testCases := []struct {
+		config   Config
+		bucket   string
+		expected bool
+	}{
+		{Config{}, "photos", false},
+		{Config{Enabled: true}, "photos", true},
+		{Config{Enabled: true, Buckets: []string{"photos"}}, "photos", true},
+		{Config{Enabled: true, Buckets: []string{"photos"}}, "logs", false},
+	}
+	for i, testCase := range testCases {
+		if indexed := testCase.config.IsIndexed(testCase.bucket); indexed != testCase.expected {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, indexed)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notifyrules
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/minio/minio/pkg/env"
+	"github.com/minio/minio/pkg/event"
+)
+
+// Config contains the notification configuration whose rules
+// apply to the events of all buckets, e.g. to bucket created
+// events sent before a new bucket has a configuration.
+type Config struct {
+	// Path of a notification configuration XML document,
+	// no server-wide rules are set if empty.
+	File string
+}
+
+// Server-wide notification rules envs.
+const (
+	EnvNotifyRulesFile = "MINIO_NOTIFY_RULES_FILE"
+)
+
+// Load - parses the configuration file, the targets of its rules
+// have to exist in targetList.
+func (c Config) Load(region string, targetList *event.TargetList) (event.RulesMap, error) {
+	if c.File == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(c.File)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	config, err := event.ParseConfig(f, region, targetList)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid notification rules in %s: %v", c.File, err)
+	}
+	return config.ToRulesMap(), nil
+}
+
+// Lookup - initializes the server-wide notification rules config
+// from the environment.
+func Lookup() (c Config, err error) {
+	c.File = env.Get(EnvNotifyRulesFile, "")
+	if c.File == "" {
+		return c, nil
+	}
+	if _, err = os.Stat(c.File); err != nil {
+		return c, fmt.Errorf("Unable to read %s: %v", EnvNotifyRulesFile, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,111 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package notifyrules
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/minio/pkg/event"
+)
+
+type testTarget struct {
+	id event.TargetID
+}
+
+func (target testTarget) ID() event.TargetID     { return target.id }
+func (target testTarget) Save(event.Event) error { return nil }
+func (target testTarget) Send(string) error      { return nil }
+func (target testTarget) Close() error           { return nil }
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notifyrules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	targetID := event.TargetID{ID: "1", Name: "webhook"}
+	targetList := event.NewTargetList()
+	if err = targetList.Add(testTarget{targetID}); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	valid := writeFile("valid.xml", `<NotificationConfiguration><QueueConfiguration><Id>1</Id><Queue>arn:minio:sqs:us-east-1:1:webhook</Queue><Event>s3:BucketCreated:*</Event></QueueConfiguration></NotificationConfiguration>`)
+	unknown := writeFile("unknown.xml", `<NotificationConfiguration><QueueConfiguration><Id>1</Id><Queue>arn:minio:sqs:us-east-1:1:amqp</Queue><Event>s3:BucketCreated:*</Event></QueueConfiguration></NotificationConfiguration>`)
+
+	testCases := []struct {
+		config      Config
+		matches     bool
+		expectedErr bool
+	}{
+		{Config{}, false, false},
+		{Config{File: valid}, true, false},
+		{Config{File: unknown}, false, true},
+		{Config{File: filepath.Join(dir, "missing.xml")}, false, true},
+	}
+
+	for i, testCase := range testCases {
+		rulesMap, err := testCase.config.Load("us-east-1", targetList)
+		if testCase.expectedErr != (err != nil) {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		targetIDSet := rulesMap.Match(event.BucketCreated, "", event.ObjectProperties{})
+		if _, ok := targetIDSet[targetID]; ok != testCase.matches {
+			t.Fatalf("Test %d: expected match %v, got %v", i+1, testCase.matches, ok)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	file, err := ioutil.TempFile("", "notifyrules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+
+	testCases := []struct {
+		env         string
+		expectedErr bool
+	}{
+		{"", false},
+		{file.Name(), false},
+		{file.Name() + ".missing", true},
+	}
+
+	for i, testCase := range testCases {
+		os.Setenv(EnvNotifyRulesFile, testCase.env)
+		c, err := Lookup()
+		if testCase.expectedErr != (err != nil) {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err == nil && c.File != testCase.env {
+			t.Fatalf("Test %d: expected %s, got %s", i+1, testCase.env, c.File)
+		}
+	}
+	os.Unsetenv(EnvNotifyRulesFile)
+}
@@ -0,0 +1,124 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scrubber
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/env"
+)
+
+const (
+	// Every object is verified once per cycle.
+	defaultCycle = 30 * 24 * time.Hour
+
+	// Default maximum rate at which data is read from disks.
+	defaultRateLimit = 32 * humanize.MiByte
+)
+
+// Config contains the background bitrot scrubber configuration.
+type Config struct {
+	Enabled bool
+
+	// Time in which all objects are verified once.
+	Cycle time.Duration
+
+	// Maximum bytes per second read for verification, zero
+	// means unlimited.
+	RateLimit uint64
+
+	// Daily window, in local time, in which the scrubber pauses,
+	// the window may span midnight.
+	PauseStart, PauseEnd time.Duration
+}
+
+// Scrubber envs.
+const (
+	EnvScrubber          = "MINIO_SCRUBBER"
+	EnvScrubberCycle     = "MINIO_SCRUBBER_CYCLE"
+	EnvScrubberRateLimit = "MINIO_SCRUBBER_RATE_LIMIT"
+	EnvScrubberPause     = "MINIO_SCRUBBER_PAUSE"
+)
+
+// IsPaused - returns true if the scrubber has to pause at the given
+// time, i.e. during peak hours.
+func (c Config) IsPaused(t time.Time) bool {
+	if c.PauseStart == c.PauseEnd {
+		return false
+	}
+	year, month, day := t.Date()
+	offset := t.Sub(time.Date(year, month, day, 0, 0, 0, 0, t.Location()))
+	if c.PauseStart < c.PauseEnd {
+		return offset >= c.PauseStart && offset < c.PauseEnd
+	}
+	// Window spans midnight.
+	return offset >= c.PauseStart || offset < c.PauseEnd
+}
+
+// Parses a time of day of the form "15:04".
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Lookup - initializes the scrubber config from the environment, the
+// scrubber is disabled unless MINIO_SCRUBBER is set to "on".
+func Lookup() (c Config, err error) {
+	if !strings.EqualFold(env.Get(EnvScrubber, "off"), "on") {
+		return c, nil
+	}
+
+	c.Cycle = defaultCycle
+	if v := env.Get(EnvScrubberCycle, ""); v != "" {
+		if c.Cycle, err = time.ParseDuration(v); err != nil {
+			return c, errors.New("Scrubber cycle err:" + err.Error())
+		}
+		if c.Cycle <= 0 {
+			return c, errors.New("Scrubber cycle has to be positive")
+		}
+	}
+
+	c.RateLimit = defaultRateLimit
+	if v := env.Get(EnvScrubberRateLimit, ""); v != "" {
+		if c.RateLimit, err = humanize.ParseBytes(v); err != nil {
+			return c, errors.New("Scrubber rate limit err:" + err.Error())
+		}
+	}
+
+	if v := env.Get(EnvScrubberPause, ""); v != "" {
+		window := strings.Split(v, "-")
+		if len(window) != 2 {
+			return c, fmt.Errorf("Invalid scrubber pause window %s, expected HH:MM-HH:MM", v)
+		}
+		if c.PauseStart, err = parseTimeOfDay(window[0]); err != nil {
+			return c, fmt.Errorf("Invalid scrubber pause window %s: %v", v, err)
+		}
+		if c.PauseEnd, err = parseTimeOfDay(window[1]); err != nil {
+			return c, fmt.Errorf("Invalid scrubber pause window %s: %v", v, err)
+		}
+	}
+
+	c.Enabled = true
+	return c, nil
+}
@@ -0,0 +1,81 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package scrubber
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLookup(t *testing.T) {
+	testCases := []struct {
+		env         map[string]string
+		expected    Config
+		expectedErr bool
+	}{
+		{map[string]string{}, Config{}, false},
+		{map[string]string{EnvScrubber: "on"}, Config{Enabled: true, Cycle: defaultCycle, RateLimit: defaultRateLimit}, false},
+		{map[string]string{EnvScrubber: "on", EnvScrubberCycle: "24h", EnvScrubberRateLimit: "1MiB", EnvScrubberPause: "22:00-06:30"},
+			Config{Enabled: true, Cycle: 24 * time.Hour, RateLimit: 1 << 20, PauseStart: 22 * time.Hour, PauseEnd: 6*time.Hour + 30*time.Minute}, false},
+		{map[string]string{EnvScrubber: "on", EnvScrubberCycle: "-1h"}, Config{}, true},
+		{map[string]string{EnvScrubber: "on", EnvScrubberRateLimit: "fast"}, Config{}, true},
+		{map[string]string{EnvScrubber: "on", EnvScrubberPause: "22:00"}, Config{}, true},
+		{map[string]string{EnvScrubber: "on", EnvScrubberPause: "22:00-25:00"}, Config{}, true},
+	}
+
+	for i, testCase := range testCases {
+		for _, key := range []string{EnvScrubber, EnvScrubberCycle, EnvScrubberRateLimit, EnvScrubberPause} {
+			os.Unsetenv(key)
+		}
+		for key, value := range testCase.env {
+			os.Setenv(key, value)
+		}
+		c, err := Lookup()
+		if testCase.expectedErr != (err != nil) {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err == nil && c != testCase.expected {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, c)
+		}
+	}
+	for _, key := range []string{EnvScrubber, EnvScrubberCycle, EnvScrubberRateLimit, EnvScrubberPause} {
+		os.Unsetenv(key)
+	}
+}
+
+func TestIsPaused(t *testing.T) {
+	day := time.Date(2019, time.November, 4, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		c        Config
+		t        time.Time
+		expected bool
+	}{
+		{Config{}, day.Add(12 * time.Hour), false},
+		{Config{PauseStart: 9 * time.Hour, PauseEnd: 17 * time.Hour}, day.Add(12 * time.Hour), true},
+		{Config{PauseStart: 9 * time.Hour, PauseEnd: 17 * time.Hour}, day.Add(17 * time.Hour), false},
+		{Config{PauseStart: 22 * time.Hour, PauseEnd: 6 * time.Hour}, day.Add(23 * time.Hour), true},
+		{Config{PauseStart: 22 * time.Hour, PauseEnd: 6 * time.Hour}, day.Add(3 * time.Hour), true},
+		{Config{PauseStart: 22 * time.Hour, PauseEnd: 6 * time.Hour}, day.Add(12 * time.Hour), false},
+	}
+
+	for i, testCase := range testCases {
+		if paused := testCase.c.IsPaused(testCase.t); paused != testCase.expected {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, paused)
+		}
+	}
+}
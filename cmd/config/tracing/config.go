@@ -0,0 +1,83 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio/pkg/env"
+)
+
+const (
+	// OTLP/HTTP endpoint of a collector running on the same host.
+	defaultEndpoint = "http://localhost:4318/v1/traces"
+
+	// All requests are traced by default.
+	defaultSampleRatio = 1.0
+)
+
+// Config - where spans are exported to and which fraction of the
+// S3 requests is traced.
+type Config struct {
+	Enabled bool
+
+	// OTLP/HTTP endpoint of the collector, e.g. Jaeger.
+	Endpoint string
+
+	// Fraction of S3 requests which are traced, between 0 and 1.
+	SampleRatio float64
+}
+
+// Tracing envs.
+const (
+	EnvTracing            = "MINIO_TRACING"
+	EnvTracingEndpoint    = "MINIO_TRACING_ENDPOINT"
+	EnvTracingSampleRatio = "MINIO_TRACING_SAMPLE_RATIO"
+)
+
+// Lookup - initializes the tracing config from the environment,
+// tracing is enabled if MINIO_TRACING is set to "on".
+func Lookup() (c Config, err error) {
+	if !strings.EqualFold(env.Get(EnvTracing, "off"), "on") {
+		return c, nil
+	}
+
+	c.Endpoint = env.Get(EnvTracingEndpoint, defaultEndpoint)
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return c, errors.New("Tracing endpoint err:" + err.Error())
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return c, errors.New("Tracing endpoint has to be a http or https URL")
+	}
+
+	c.SampleRatio = defaultSampleRatio
+	if v := env.Get(EnvTracingSampleRatio, ""); v != "" {
+		if c.SampleRatio, err = strconv.ParseFloat(v, 64); err != nil {
+			return c, errors.New("Tracing sample ratio err:" + err.Error())
+		}
+		if c.SampleRatio < 0 || c.SampleRatio > 1 {
+			return c, errors.New("Tracing sample ratio has to be between 0 and 1")
+		}
+	}
+
+	c.Enabled = true
+	return c, nil
+}
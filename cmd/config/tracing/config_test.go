@@ -0,0 +1,58 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	envs := []string{EnvTracing, EnvTracingEndpoint, EnvTracingSampleRatio}
+	testCases := []struct {
+		env         map[string]string
+		expected    Config
+		expectedErr bool
+	}{
+		{map[string]string{}, Config{}, false},
+		{map[string]string{EnvTracing: "on"}, Config{Enabled: true, Endpoint: defaultEndpoint, SampleRatio: defaultSampleRatio}, false},
+		{map[string]string{EnvTracing: "on", EnvTracingEndpoint: "https://jaeger:4318/v1/traces", EnvTracingSampleRatio: "0.01"},
+			Config{Enabled: true, Endpoint: "https://jaeger:4318/v1/traces", SampleRatio: 0.01}, false},
+		{map[string]string{EnvTracing: "on", EnvTracingEndpoint: "jaeger:4318"}, Config{}, true},
+		{map[string]string{EnvTracing: "on", EnvTracingSampleRatio: "1.5"}, Config{}, true},
+		{map[string]string{EnvTracing: "on", EnvTracingSampleRatio: "all"}, Config{}, true},
+	}
+
+	for i, testCase := range testCases {
+		for _, key := range envs {
+			os.Unsetenv(key)
+		}
+		for key, value := range testCase.env {
+			os.Setenv(key, value)
+		}
+		c, err := Lookup()
+		if testCase.expectedErr != (err != nil) {
+			t.Fatalf("Test %d: expected error %v, got %v", i+1, testCase.expectedErr, err)
+		}
+		if err == nil && c != testCase.expected {
+			t.Fatalf("Test %d: expected %v, got %v", i+1, testCase.expected, c)
+		}
+	}
+	for _, key := range envs {
+		os.Unsetenv(key)
+	}
+}
@@ -0,0 +1,47 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package xlmeta
+
+import (
+	"strings"
+
+	"github.com/minio/minio/pkg/env"
+)
+
+// Config contains the format in which object metadata is written.
+//
+// Metadata is written as JSON `xl.json` by default, which all
+// releases can read. Once binary metadata is enabled, servers of
+// releases without support for it can no longer read the objects,
+// it must only be enabled after all servers were upgraded.
+type Config struct {
+	// Write metadata in the binary format and migrate the
+	// metadata written as JSON before.
+	Binary bool
+}
+
+// Metadata format envs.
+const (
+	EnvXLMetaBinary = "MINIO_XL_META_BINARY"
+)
+
+// Lookup - initializes the metadata format config from the environment,
+// metadata is written as JSON unless MINIO_XL_META_BINARY is set to "on".
+func Lookup() (c Config, err error) {
+	c.Binary = strings.EqualFold(env.Get(EnvXLMetaBinary, "off"), "on")
+	return c, nil
+}
@@ -61,15 +61,19 @@ func newBgHealSequence(numDisks int) *healSequence {
 }
 
 func getLocalBackgroundHealStatus() madmin.BgHealState {
+	var state madmin.BgHealState
+	if globalBackgroundHealing != nil {
+		state.MRFQueueDepth, state.MRFHealedCount, state.MRFFailedCount, state.MRFDroppedCount = globalBackgroundHealing.mrfStatus()
+	}
+
 	backgroundSequence, ok := globalSweepHealState.getHealSequenceByToken(bgHealingUUID)
 	if !ok {
-		return madmin.BgHealState{}
+		return state
 	}
 
-	return madmin.BgHealState{
-		ScannedItemsCount: backgroundSequence.scannedItemsCount,
-		LastHealActivity:  backgroundSequence.lastHealActivity,
-	}
+	state.ScannedItemsCount = backgroundSequence.scannedItemsCount
+	state.LastHealActivity = backgroundSequence.lastHealActivity
+	return state
 }
 
 func initDailyHeal() {
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/lifecycle"
 )
 
@@ -140,18 +141,34 @@ func lifecycleRound(ctx context.Context, objAPI ObjectLayer) error {
 				continue
 			}
 			var objects []string
+			var expired []ObjectInfo
 			for _, obj := range res.Objects {
 				// Find the action that need to be executed
 				action := l.ComputeAction(obj.Name, obj.ModTime)
 				switch action {
 				case lifecycle.DeleteAction:
 					objects = append(objects, obj.Name)
+					expired = append(expired, obj)
 				default:
 					// Do nothing, for now.
 				}
 			}
 			// Deletes a list of objects.
-			objAPI.DeleteObjects(ctx, bucket.Name, objects)
+			deleteErrs, err := objAPI.DeleteObjects(ctx, bucket.Name, objects)
+			if err == nil {
+				for i, obj := range expired {
+					if deleteErrs[i] != nil {
+						continue
+					}
+					// Notify lifecycle expiration event.
+					sendEvent(eventArgs{
+						EventName:  event.LifecycleExpirationDelete,
+						BucketName: bucket.Name,
+						Object:     obj,
+						UserAgent:  "Internal: [Lifecycle]",
+					})
+				}
+			}
 			if !res.IsTruncated {
 				// We are done here, proceed to next bucket.
 				break
@@ -0,0 +1,565 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+const (
+	// Decommission state file.
+	decommissionConfigFile = "decommission.json"
+
+	// Lock of the meta bucket held by the server moving objects.
+	decommissionLockPath = "decommission.lock"
+
+	// Number of moved objects after which the progress is saved.
+	decommissionSaveInterval = 100
+)
+
+// Wait time before a failed or blocked decommission is retried.
+var decommissionRetryInterval = time.Minute
+
+// Only these prefixes of the meta bucket hold objects which are
+// moved, multipart and temporary data is never drained.
+var decommissionMetaPrefixes = []string{bucketConfigPrefix + SlashSeparator, minioConfigPrefix + SlashSeparator}
+
+var decommissionTimeout = newDynamicTimeout(60*time.Second, time.Second)
+
+// decommissionState - on disk format of the decommission state.
+type decommissionState struct {
+	Version int                     `json:"version"`
+	Info    madmin.DecommissionInfo `json:"info"`
+}
+
+// DecommissionSys - drains a zone or an erasure set of a zone.
+type DecommissionSys struct {
+	sync.RWMutex
+	info madmin.DecommissionInfo
+
+	// Set while this server moves objects.
+	active bool
+}
+
+// NewDecommissionSys - creates new decommission system.
+func NewDecommissionSys() *DecommissionSys {
+	return &DecommissionSys{}
+}
+
+// Draining - returns the zone and set being decommissioned, set is
+// -1 if the whole zone is decommissioned.
+func (sys *DecommissionSys) Draining() (zone, set int, ok bool) {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if sys.info.Status != madmin.DecommissionRunning {
+		return -1, -1, false
+	}
+	return sys.info.Zone, sys.info.Set, true
+}
+
+// IsExcluded - returns true if new objects can not be placed on the
+// set of the zone, either the set or the zone is being decommissioned
+// or it was retired by a completed decommission.
+func (sys *DecommissionSys) IsExcluded(zone, set int) bool {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if sys.info.Status == madmin.DecommissionRunning && sys.info.Zone == zone &&
+		(sys.info.Set < 0 || sys.info.Set == set) {
+		return true
+	}
+	return isDecommissionRetired(sys.info.Retired, zone, set)
+}
+
+// Returns true if the set of the zone, or the whole zone, is retired.
+func isDecommissionRetired(retired []madmin.DecommissionRetired, zone, set int) bool {
+	for _, r := range retired {
+		if r.Zone == zone && (r.Set < 0 || r.Set == set) {
+			return true
+		}
+	}
+	return false
+}
+
+// Info - returns the state of the current or the last decommission.
+func (sys *DecommissionSys) Info() madmin.DecommissionInfo {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	return sys.info
+}
+
+// IsActive - returns true if this server moves objects.
+func (sys *DecommissionSys) IsActive() bool {
+	sys.RLock()
+	defer sys.RUnlock()
+
+	return sys.active
+}
+
+func (sys *DecommissionSys) set(info madmin.DecommissionInfo) {
+	sys.Lock()
+	defer sys.Unlock()
+
+	sys.info = info
+}
+
+// Init - loads the decommission state and resumes a running
+// decommission.
+func (sys *DecommissionSys) Init(objAPI ObjectLayer) error {
+	if objAPI == nil {
+		return errServerNotInitialized
+	}
+
+	// Decommission is not supported in gateway mode.
+	if globalIsGateway {
+		return nil
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Initializing decommission needs a retry mechanism for
+	// the following reasons:
+	//  - Read quorum is lost just after the initialization
+	//    of the object layer.
+	retryTimerCh := newRetryTimerSimple(doneCh)
+	for {
+		select {
+		case <-retryTimerCh:
+			if err := sys.load(objAPI); err != nil {
+				if err == errDiskNotFound ||
+					strings.Contains(err.Error(), InsufficientReadQuorum{}.Error()) ||
+					strings.Contains(err.Error(), InsufficientWriteQuorum{}.Error()) {
+					logger.Info("Waiting for decommission subsystem to be initialized..")
+					continue
+				}
+				return err
+			}
+			if z, ok := objAPI.(*xlZones); ok {
+				if _, _, draining := sys.Draining(); draining {
+					go sys.run(z)
+				}
+			}
+			return nil
+		case <-globalOSSignalCh:
+			return fmt.Errorf("Initializing decommission sub-system gracefully stopped")
+		}
+	}
+}
+
+// Loads the decommission state into DecommissionSys.
+func (sys *DecommissionSys) load(objAPI ObjectLayer) error {
+	info, err := readDecommissionConfig(context.Background(), objAPI)
+	if err != nil {
+		return err
+	}
+	sys.set(info)
+	return nil
+}
+
+// Start - starts draining the zone, or only the given erasure set
+// when set is not negative.
+func (sys *DecommissionSys) Start(ctx context.Context, objAPI ObjectLayer, zone, set int) error {
+	z, ok := objAPI.(*xlZones)
+	if !ok {
+		return errDecommissionNotSupported
+	}
+	if zone < 0 || zone >= len(z.zones) || set < -1 || set >= len(z.zones[zone].sets) {
+		return errInvalidArgument
+	}
+
+	if _, _, draining := sys.Draining(); draining {
+		return errDecommissionRunning
+	}
+	current, err := readDecommissionConfig(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	if current.Status == madmin.DecommissionRunning {
+		sys.set(current)
+		return errDecommissionRunning
+	}
+
+	// A retired zone or set is already empty.
+	if isDecommissionRetired(current.Retired, zone, set) {
+		return errInvalidArgument
+	}
+	// At least one zone has to receive new objects.
+	if set < 0 {
+		available := false
+		for index := range z.zones {
+			if index != zone && !isDecommissionRetired(current.Retired, index, -1) {
+				available = true
+				break
+			}
+		}
+		if !available {
+			return errInvalidArgument
+		}
+	}
+
+	info := madmin.DecommissionInfo{
+		Zone:      zone,
+		Set:       set,
+		Status:    madmin.DecommissionRunning,
+		StartTime: UTCNow(),
+		Retired:   current.Retired,
+	}
+	if err = saveDecommissionConfig(ctx, objAPI, info); err != nil {
+		return err
+	}
+	sys.set(info)
+	notifyDecommission()
+
+	go sys.run(z)
+	return nil
+}
+
+// Cancel - stops a running decommission, objects already moved
+// stay on their new zone.
+func (sys *DecommissionSys) Cancel(ctx context.Context, objAPI ObjectLayer) error {
+	info, err := readDecommissionConfig(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	if info.Status != madmin.DecommissionRunning {
+		return errDecommissionNotRunning
+	}
+
+	// Report the progress known to the server moving objects.
+	if local := sys.Info(); local.StartTime.Equal(info.StartTime) && local.ObjectsMoved > info.ObjectsMoved {
+		info = local
+	}
+	info.Status = madmin.DecommissionCanceled
+	info.EndTime = UTCNow()
+	if err = saveDecommissionConfig(ctx, objAPI, info); err != nil {
+		return err
+	}
+	sys.set(info)
+	notifyDecommission()
+	return nil
+}
+
+// Moves objects off the decommissioned zone or set until it is
+// empty, only one server moves objects at a time.
+func (sys *DecommissionSys) run(z *xlZones) {
+	sys.Lock()
+	if sys.active {
+		sys.Unlock()
+		return
+	}
+	sys.active = true
+	sys.Unlock()
+
+	defer func() {
+		sys.Lock()
+		sys.active = false
+		sys.Unlock()
+	}()
+
+	ctx := context.Background()
+	for {
+		if _, _, draining := sys.Draining(); !draining {
+			return
+		}
+
+		decommissionLock := globalNSMutex.NewNSLock(ctx, minioMetaBucket, decommissionLockPath)
+		if err := decommissionLock.GetLock(decommissionTimeout); err != nil {
+			// Another server is moving objects.
+			time.Sleep(decommissionRetryInterval)
+			continue
+		}
+		err := sys.drain(ctx, z)
+		decommissionLock.Unlock()
+		switch err {
+		case nil:
+			return
+		case errDecommissionNotRunning, errDecommissionIncomplete:
+			// Reported by the decommission state.
+		default:
+			logger.LogIf(ctx, err)
+		}
+		time.Sleep(decommissionRetryInterval)
+	}
+}
+
+// Moves all objects off the decommissioned zone or set, resuming
+// from the last saved progress.
+func (sys *DecommissionSys) drain(ctx context.Context, z *xlZones) error {
+	// Pick up the progress saved by other servers.
+	if err := sys.load(z); err != nil {
+		return err
+	}
+	info := sys.Info()
+	if info.Status != madmin.DecommissionRunning {
+		return nil
+	}
+	if info.Bucket == "" {
+		// A new pass over all buckets, failed objects are retried.
+		info.ObjectsFailed = 0
+	}
+
+	buckets, err := z.ListBuckets(ctx)
+	if err != nil {
+		return err
+	}
+	var bucketNames []string
+	for _, bucket := range buckets {
+		bucketNames = append(bucketNames, bucket.Name)
+	}
+	sort.Strings(bucketNames)
+	// Configuration is moved before the objects.
+	bucketNames = append([]string{minioMetaBucket}, bucketNames...)
+
+	// Resume from the last saved bucket.
+	resumeBuckets := bucketNames
+	for i, bucket := range bucketNames {
+		if bucket == info.Bucket {
+			resumeBuckets = bucketNames[i:]
+			break
+		}
+	}
+
+	var moved int
+	for _, bucket := range resumeBuckets {
+		prefixes := []string{""}
+		if bucket == minioMetaBucket {
+			prefixes = decommissionMetaPrefixes
+		}
+		if bucket != info.Bucket {
+			info.Bucket = bucket
+			info.Marker = ""
+		}
+		for _, prefix := range prefixes {
+			marker := info.Marker
+			if marker != "" && !strings.HasPrefix(marker, prefix) {
+				if marker > prefix {
+					// Prefix is already drained.
+					continue
+				}
+				marker = ""
+			}
+			for {
+				loi, err := sys.list(ctx, z, info, bucket, prefix, marker)
+				if err != nil {
+					if _, ok := err.(BucketNotFound); ok {
+						// Bucket was removed meanwhile.
+						break
+					}
+					return err
+				}
+				for _, obj := range loi.Objects {
+					if _, _, draining := sys.Draining(); !draining {
+						return errDecommissionNotRunning
+					}
+					size, err := z.moveObject(ctx, info.Zone, bucket, obj.Name)
+					if err != nil {
+						logger.LogIf(ctx, fmt.Errorf("Unable to move %s/%s: %v", bucket, obj.Name, err))
+						info.ObjectsFailed++
+					} else {
+						info.ObjectsMoved++
+						info.BytesMoved += size
+					}
+					info.Marker = obj.Name
+					sys.set(info)
+
+					moved++
+					if moved%decommissionSaveInterval == 0 {
+						if err = sys.checkpoint(ctx, z, info); err != nil {
+							return err
+						}
+					}
+				}
+				if !loi.IsTruncated {
+					break
+				}
+				marker = loi.NextMarker
+			}
+		}
+	}
+
+	// Objects which failed to move are retried by the next pass.
+	info.Bucket, info.Marker = "", ""
+	if info.ObjectsFailed > 0 {
+		if err = sys.checkpoint(ctx, z, info); err != nil {
+			return err
+		}
+		return errDecommissionIncomplete
+	}
+
+	// Multipart uploads in progress are not moved. New uploads are
+	// started on the remaining zones and uploads completed on the
+	// drained zone or set are moved once complete, the decommission
+	// waits until the uploads left are completed, aborted or expired.
+	info.PendingUploads = int64(len(z.listPendingUploads(info.Zone, info.Set)))
+	if info.PendingUploads > 0 {
+		if err = sys.checkpoint(ctx, z, info); err != nil {
+			return err
+		}
+		return errDecommissionIncomplete
+	}
+
+	// Only an empty zone or set is reported complete, e.g. uploads
+	// completed meanwhile may have failed to move.
+	drained, err := sys.isDrained(ctx, z, info, bucketNames)
+	if err != nil {
+		return err
+	}
+	if !drained {
+		if err = sys.checkpoint(ctx, z, info); err != nil {
+			return err
+		}
+		return errDecommissionIncomplete
+	}
+
+	// The emptied zone or set never receives new objects again.
+	info.Status = madmin.DecommissionComplete
+	info.EndTime = UTCNow()
+	info.Retired = append(info.Retired, madmin.DecommissionRetired{Zone: info.Zone, Set: info.Set})
+	if err = sys.checkpoint(ctx, z, info); err != nil {
+		return err
+	}
+	notifyDecommission()
+	return nil
+}
+
+// Lists the objects on the decommissioned zone or set.
+func (sys *DecommissionSys) list(ctx context.Context, z *xlZones, info madmin.DecommissionInfo, bucket, prefix, marker string) (ListObjectsInfo, error) {
+	if info.Set < 0 {
+		return z.zones[info.Zone].ListObjects(ctx, bucket, prefix, marker, "", maxObjectList)
+	}
+	return z.zones[info.Zone].sets[info.Set].ListObjects(ctx, bucket, prefix, marker, "", maxObjectList)
+}
+
+// Saves the progress unless the decommission was canceled meanwhile.
+func (sys *DecommissionSys) checkpoint(ctx context.Context, objAPI ObjectLayer, info madmin.DecommissionInfo) error {
+	saved, err := readDecommissionConfig(ctx, objAPI)
+	if err != nil {
+		return err
+	}
+	if saved.Status != madmin.DecommissionRunning || !saved.StartTime.Equal(info.StartTime) {
+		sys.set(saved)
+		return errDecommissionNotRunning
+	}
+	if err = saveDecommissionConfig(ctx, objAPI, info); err != nil {
+		return err
+	}
+	sys.set(info)
+	return nil
+}
+
+// Returns true if the decommissioned zone or set holds no objects
+// of the buckets.
+func (sys *DecommissionSys) isDrained(ctx context.Context, z *xlZones, info madmin.DecommissionInfo, buckets []string) (bool, error) {
+	for _, bucket := range buckets {
+		prefixes := []string{""}
+		if bucket == minioMetaBucket {
+			prefixes = decommissionMetaPrefixes
+		}
+		for _, prefix := range prefixes {
+			loi, err := sys.list(ctx, z, info, bucket, prefix, "")
+			if err != nil {
+				if _, ok := err.(BucketNotFound); ok {
+					break
+				}
+				return false, err
+			}
+			if len(loi.Objects) > 0 {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// Returns the IDs of the multipart uploads on the decommissioned
+// zone or set.
+func (z *xlZones) listPendingUploads(zoneIdx, setIdx int) (uploadIDs []string) {
+	for index, set := range z.zones[zoneIdx].sets {
+		if setIdx >= 0 && index != setIdx {
+			continue
+		}
+		for _, disk := range set.getLoadBalancedDisks() {
+			if disk == nil {
+				continue
+			}
+			shaDirs, err := disk.ListDir(minioMetaMultipartBucket, "", -1, "")
+			if err != nil {
+				continue
+			}
+			for _, shaDir := range shaDirs {
+				uploadIDDirs, err := disk.ListDir(minioMetaMultipartBucket, shaDir, -1, "")
+				if err != nil {
+					continue
+				}
+				for _, uploadIDDir := range uploadIDDirs {
+					uploadIDs = append(uploadIDs, strings.TrimSuffix(uploadIDDir, SlashSeparator))
+				}
+			}
+			break
+		}
+	}
+	sort.Strings(uploadIDs)
+	return uploadIDs
+}
+
+// Reloads the decommission state on all peers.
+func notifyDecommission() {
+	if globalNotificationSys == nil {
+		return
+	}
+	// Errors are logged by the notification group.
+	globalNotificationSys.LoadDecommission()
+}
+
+func saveDecommissionConfig(ctx context.Context, objAPI ObjectLayer, info madmin.DecommissionInfo) error {
+	data, err := json.Marshal(decommissionState{Version: 1, Info: info})
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, path.Join(minioConfigPrefix, decommissionConfigFile), data)
+}
+
+// readDecommissionConfig - returns the saved decommission state, an
+// empty state if no decommission was ever started.
+func readDecommissionConfig(ctx context.Context, objAPI ObjectLayer) (madmin.DecommissionInfo, error) {
+	configData, err := readConfig(ctx, objAPI, path.Join(minioConfigPrefix, decommissionConfigFile))
+	if err != nil {
+		if err == errConfigNotFound {
+			return madmin.DecommissionInfo{Zone: -1, Set: -1}, nil
+		}
+		return madmin.DecommissionInfo{}, err
+	}
+
+	var state decommissionState
+	if err = json.Unmarshal(configData, &state); err != nil {
+		return madmin.DecommissionInfo{}, err
+	}
+	return state.Info, nil
+}
@@ -0,0 +1,200 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestDecommissionZone(t *testing.T) {
+	z, fsDirs := prepareXLZones(t)
+	defer removeRoots(fsDirs)
+
+	globalDecommissionSys = NewDecommissionSys()
+	defer func() {
+		globalDecommissionSys = NewDecommissionSys()
+	}()
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err := z.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Place all objects on the first zone.
+	src := z.zones[0]
+	data := bytes.Repeat([]byte("a"), 1024)
+	etags := make(map[string]string)
+	for i := 0; i < 5; i++ {
+		object := fmt.Sprintf("object%d", i)
+		objInfo, err := src.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{UserDefined: map[string]string{"x-amz-meta-index": object}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		etags[object] = objInfo.ETag
+	}
+
+	// A multipart object keeps its parts.
+	partData := bytes.Repeat([]byte("b"), 5*humanize.MiByte)
+	uploadID, err := src.NewMultipartUpload(ctx, bucket, "multipart", ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parts []CompletePart
+	for i := 1; i <= 2; i++ {
+		pi, perr := src.PutObjectPart(ctx, bucket, "multipart", uploadID, i, mustGetPutObjReader(t, bytes.NewReader(partData), int64(len(partData)), "", ""), ObjectOptions{})
+		if perr != nil {
+			t.Fatal(perr)
+		}
+		parts = append(parts, CompletePart{PartNumber: i, ETag: pi.ETag})
+	}
+	objInfo, err := src.CompleteMultipartUpload(ctx, bucket, "multipart", uploadID, parts, ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	etags["multipart"] = objInfo.ETag
+
+	// The decommission waits for uploads in progress.
+	pendingID, err := src.NewMultipartUpload(ctx, bucket, "pending", ObjectOptions{UserDefined: map[string]string{"x-amz-meta-index": "pending"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	savedRetryInterval := decommissionRetryInterval
+	decommissionRetryInterval = 100 * time.Millisecond
+	defer func() { decommissionRetryInterval = savedRetryInterval }()
+
+	if err = globalDecommissionSys.Start(ctx, z, 0, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err = globalDecommissionSys.Start(ctx, z, 0, -1); err != errDecommissionRunning {
+		t.Fatalf("Expected %v, got %v", errDecommissionRunning, err)
+	}
+
+	deadline := time.Now().Add(time.Minute)
+	for info := globalDecommissionSys.Info(); info.PendingUploads == 0; info = globalDecommissionSys.Info() {
+		if info.Status != madmin.DecommissionRunning || time.Now().After(deadline) {
+			t.Fatalf("Expected the decommission to wait for the upload, got %v", info)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	info := globalDecommissionSys.Info()
+	if info.Status != madmin.DecommissionRunning || info.PendingUploads != 1 {
+		t.Fatalf("Expected the decommission to wait for the upload, got %v", info)
+	}
+	if info.ObjectsMoved < int64(len(etags)) {
+		t.Fatalf("Expected at least %d moved objects, got %d", len(etags), info.ObjectsMoved)
+	}
+
+	// New uploads are started on the remaining zone.
+	newID, err := z.NewMultipartUpload(ctx, bucket, "new-upload", ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = z.zones[1].ListObjectParts(ctx, bucket, "new-upload", newID, 0, 1, ObjectOptions{}); err != nil {
+		t.Fatalf("Expected the new upload on the remaining zone, got %v", err)
+	}
+
+	// The upload completed on the drained zone is moved off it.
+	pi, err := z.PutObjectPart(ctx, bucket, "pending", pendingID, 1, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	objInfo, err = z.CompleteMultipartUpload(ctx, bucket, "pending", pendingID, []CompletePart{{PartNumber: 1, ETag: pi.ETag}}, ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	etags["pending"] = objInfo.ETag
+
+	for globalDecommissionSys.Info().Status == madmin.DecommissionRunning {
+		if time.Now().After(deadline) {
+			t.Fatal("Decommission did not finish")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	info = globalDecommissionSys.Info()
+	if info.Status != madmin.DecommissionComplete || info.ObjectsFailed != 0 || info.PendingUploads != 0 {
+		t.Fatalf("Unexpected decommission state %v", info)
+	}
+
+	for object, etag := range etags {
+		if _, err = src.GetObjectInfo(ctx, bucket, object, ObjectOptions{}); !isErrObjectNotFound(err) {
+			t.Fatalf("Expected %s to be removed from the zone, got %v", object, err)
+		}
+		objInfo, err = z.zones[1].GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if objInfo.ETag != etag {
+			t.Fatalf("Expected ETag %s for %s, got %s", etag, object, objInfo.ETag)
+		}
+		var buf bytes.Buffer
+		if err = z.GetObject(ctx, bucket, object, 0, objInfo.Size, &buf, "", ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if object == "multipart" {
+			if len(objInfo.Parts) != 2 || buf.Len() != 2*len(partData) {
+				t.Fatalf("Unexpected multipart object %v", objInfo)
+			}
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), data) || objInfo.UserDefined["x-amz-meta-index"] != object {
+			t.Fatalf("Unexpected object %s", object)
+		}
+	}
+
+	if err = globalDecommissionSys.Cancel(ctx, z); err != errDecommissionNotRunning {
+		t.Fatalf("Expected %v, got %v", errDecommissionNotRunning, err)
+	}
+
+	// The retired zone never receives new objects again, also
+	// after the state is reloaded.
+	if err = globalDecommissionSys.load(z); err != nil {
+		t.Fatal(err)
+	}
+	if !z.isDraining(0, "new-object") {
+		t.Fatal("Expected the retired zone to be excluded")
+	}
+	if zoneIdx := z.getAvailableZoneIdx(ctx, "new-object"); zoneIdx != 1 {
+		t.Fatalf("Expected new objects on zone 1, got %d", zoneIdx)
+	}
+	if err = globalDecommissionSys.Start(ctx, z, 0, -1); err != errInvalidArgument {
+		t.Fatalf("Expected %v, got %v", errInvalidArgument, err)
+	}
+	if err = globalDecommissionSys.Start(ctx, z, 1, -1); err != errInvalidArgument {
+		t.Fatalf("Expected %v, got %v", errInvalidArgument, err)
+	}
+}
+
+func TestDecommissionNotSupported(t *testing.T) {
+	objLayer, fsDir, err := prepareFS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots([]string{fsDir})
+
+	if err = NewDecommissionSys().Start(context.Background(), objLayer, 0, -1); err != errDecommissionNotSupported {
+		t.Fatalf("Expected %v, got %v", errDecommissionNotSupported, err)
+	}
+}
@@ -264,20 +264,95 @@ func GetAllSets(args ...string) ([][]string, error) {
 	return setArgs, nil
 }
 
-// CreateServerEndpoints - validates and creates new endpoints from input args, supports
-// both ellipses and without ellipses transparently.
-func createServerEndpoints(serverAddr string, args ...string) (string, EndpointList, SetupType, int, int, error) {
+// Creates the endpoints of a single zone spanning all args.
+func createZoneEndpoints(serverAddr string, args ...string) (string, EndpointZones, SetupType, error) {
 	setArgs, err := GetAllSets(args...)
 	if err != nil {
-		return serverAddr, nil, -1, 0, 0, err
+		return serverAddr, nil, -1, err
 	}
-
 	var endpoints EndpointList
 	var setupType SetupType
 	serverAddr, endpoints, setupType, err = CreateEndpoints(serverAddr, setArgs...)
 	if err != nil {
-		return serverAddr, nil, -1, 0, 0, err
+		return serverAddr, nil, -1, err
+	}
+	return serverAddr, EndpointZones{{
+		SetCount:     len(setArgs),
+		DrivesPerSet: len(setArgs[0]),
+		Endpoints:    endpoints,
+	}}, setupType, nil
+}
+
+// Returns true if a local drive is formatted with erasure sets spanning
+// the drives of all zones, as deployments started with several ellipses
+// args before zones were introduced are. Such deployments keep using a
+// single zone.
+func isSingleZoneFormatted(zones EndpointZones) bool {
+	if len(zones) < 2 {
+		return false
+	}
+	endpoints := zones.Endpoints()
+	for _, endpoint := range endpoints {
+		if !endpoint.IsLocal {
+			continue
+		}
+		sets, err := formatXLGetSets(pathJoin(endpoint.Path, minioMetaBucket, formatConfigFile))
+		if err != nil || len(sets) == 0 {
+			continue
+		}
+		return len(sets)*len(sets[0]) == len(endpoints)
+	}
+	return false
+}
+
+// CreateServerEndpoints - validates and creates new endpoints from input args, supports
+// both ellipses and without ellipses transparently. When ellipses are used every
+// argument describes its own zone, an independent group of erasure coded sets,
+// unless the drives were already formatted as a single zone spanning all args.
+func createServerEndpoints(serverAddr string, args ...string) (string, EndpointZones, SetupType, error) {
+	if len(args) == 0 {
+		return serverAddr, nil, -1, errInvalidArgument
+	}
+
+	if !ellipses.HasEllipses(args...) {
+		return createZoneEndpoints(serverAddr, args...)
+	}
+
+	var zones EndpointZones
+	var setupType SetupType
+	uniqueEndpoints := set.NewStringSet()
+	for i, arg := range args {
+		setArgs, err := GetAllSets(arg)
+		if err != nil {
+			return serverAddr, nil, -1, err
+		}
+		var endpoints EndpointList
+		var zoneSetupType SetupType
+		serverAddr, endpoints, zoneSetupType, err = CreateEndpoints(serverAddr, setArgs...)
+		if err != nil {
+			return serverAddr, nil, -1, err
+		}
+		if i == 0 {
+			setupType = zoneSetupType
+		} else if setupType != zoneSetupType {
+			return serverAddr, nil, -1, config.ErrInvalidErasureEndpoints(nil).Msg(fmt.Sprintf("Mixed setup types across zones (%s) are not supported", args))
+		}
+		for _, endpoint := range endpoints {
+			if uniqueEndpoints.Contains(endpoint.String()) {
+				return serverAddr, nil, -1, config.ErrInvalidErasureEndpoints(nil).Msg(fmt.Sprintf("Input args (%s) have duplicate endpoints across zones", args))
+			}
+			uniqueEndpoints.Add(endpoint.String())
+		}
+		zones = append(zones, ZoneEndpoints{
+			SetCount:     len(setArgs),
+			DrivesPerSet: len(setArgs[0]),
+			Endpoints:    endpoints,
+		})
+	}
+
+	if isSingleZoneFormatted(zones) {
+		return createZoneEndpoints(serverAddr, args...)
 	}
 
-	return serverAddr, endpoints, setupType, len(setArgs), len(setArgs[0]), nil
+	return serverAddr, zones, setupType, nil
 }
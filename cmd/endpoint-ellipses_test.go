@@ -17,8 +17,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path"
 	"reflect"
 	"testing"
 
@@ -55,7 +58,7 @@ func TestCreateServerEndpoints(t *testing.T) {
 	}
 
 	for i, testCase := range testCases {
-		_, _, _, _, _, err := createServerEndpoints(testCase.serverAddr, testCase.args...)
+		_, _, _, err := createServerEndpoints(testCase.serverAddr, testCase.args...)
 		if err != nil && testCase.success {
 			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
 		}
@@ -65,6 +68,101 @@ func TestCreateServerEndpoints(t *testing.T) {
 	}
 }
 
+// Tests zones created from ellipses args.
+func TestCreateServerEndpointsZones(t *testing.T) {
+	testCases := []struct {
+		args         []string
+		zones        int
+		setCount     int
+		drivesPerSet int
+		success      bool
+	}{
+		// Without ellipses all args form a single zone.
+		{[]string{"/export1", "/export2", "/export3", "/export4"}, 1, 1, 4, true},
+		{[]string{"/export1{1...64}"}, 1, 4, 16, true},
+		// Every ellipses arg is a zone of its own.
+		{[]string{"/export1{1...32}", "/export1{33...64}"}, 2, 4, 16, true},
+		{[]string{"/export1{1...16}", "/export2{1...8}"}, 2, 2, 8, true},
+		// Duplicate disks across zones not allowed.
+		{[]string{"/export1{1...16}", "/export1{9...24}"}, 0, 0, 0, false},
+	}
+
+	for i, testCase := range testCases {
+		_, zones, _, err := createServerEndpoints(":9000", testCase.args...)
+		if err != nil && testCase.success {
+			t.Errorf("Test %d: Expected success but failed instead %s", i+1, err)
+		}
+		if err == nil && !testCase.success {
+			t.Errorf("Test %d: Expected failure but passed instead", i+1)
+		}
+		if err != nil {
+			continue
+		}
+		if len(zones) != testCase.zones {
+			t.Errorf("Test %d: Expected %d zones, got %d", i+1, testCase.zones, len(zones))
+		}
+		if zones.SetCount() != testCase.setCount {
+			t.Errorf("Test %d: Expected %d sets, got %d", i+1, testCase.setCount, zones.SetCount())
+		}
+		if zones.DrivesPerSet() != testCase.drivesPerSet {
+			t.Errorf("Test %d: Expected %d drives per set, got %d", i+1, testCase.drivesPerSet, zones.DrivesPerSet())
+		}
+	}
+}
+
+// Tests that drives formatted with several ellipses args before zones
+// were introduced are kept in a single zone.
+func TestCreateServerEndpointsUpgradedZones(t *testing.T) {
+	dir, err := ioutil.TempDir(globalTestTmpDir, "minio-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	args := []string{dir + "/export{1...4}", dir + "/export{5...8}"}
+	_, zones, _, err := createServerEndpoints(":9000", args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 2 {
+		t.Fatalf("Expected 2 zones for unformatted drives, got %d", len(zones))
+	}
+
+	// Format the first drive like a single zone spanning both args.
+	writeFormat := func(format *formatXLV3) {
+		t.Helper()
+		formatPath := pathJoin(dir, "export1", minioMetaBucket, formatConfigFile)
+		if err = os.MkdirAll(path.Dir(formatPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(formatPath, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFormat(newFormatXLV3(2, 4))
+	_, zones, _, err = createServerEndpoints(":9000", args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 1 || zones[0].SetCount != 2 || zones[0].DrivesPerSet != 4 {
+		t.Fatalf("Expected a single zone of 2 sets of 4 drives, got %v", zones)
+	}
+
+	// Drives formatted as a zone of their own keep their zones.
+	writeFormat(newFormatXLV3(1, 4))
+	_, zones, _, err = createServerEndpoints(":9000", args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zones) != 2 {
+		t.Fatalf("Expected 2 zones, got %d", len(zones))
+	}
+}
+
 func TestGetDivisibleSize(t *testing.T) {
 	testCases := []struct {
 		totalSizes []uint64
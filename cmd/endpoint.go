@@ -284,6 +284,44 @@ func (endpoints EndpointList) UpdateIsLocal() error {
 
 }
 
+// ZoneEndpoints - represents a single zone, an independent group of
+// erasure coded sets with its own `format.json`.
+type ZoneEndpoints struct {
+	SetCount     int
+	DrivesPerSet int
+	Endpoints    EndpointList
+}
+
+// EndpointZones - list of all zones that make up the deployment.
+type EndpointZones []ZoneEndpoints
+
+// Endpoints - returns the endpoints of all zones as a single list.
+func (z EndpointZones) Endpoints() (endpoints EndpointList) {
+	for _, zone := range z {
+		endpoints = append(endpoints, zone.Endpoints...)
+	}
+	return endpoints
+}
+
+// SetCount - returns the total number of erasure sets across all zones.
+func (z EndpointZones) SetCount() (setCount int) {
+	for _, zone := range z {
+		setCount += zone.SetCount
+	}
+	return setCount
+}
+
+// DrivesPerSet - returns the smallest erasure set size across all zones,
+// parity settings have to be valid for every zone.
+func (z EndpointZones) DrivesPerSet() (drivesPerSet int) {
+	for i, zone := range z {
+		if i == 0 || zone.DrivesPerSet < drivesPerSet {
+			drivesPerSet = zone.DrivesPerSet
+		}
+	}
+	return drivesPerSet
+}
+
 // NewEndpointList - returns new endpoint list based on input args.
 func NewEndpointList(args ...string) (endpoints EndpointList, err error) {
 	var endpointType EndpointType
@@ -158,6 +158,21 @@ func formatXLGetVersion(formatPath string) (string, error) {
 	return format.XL.Version, nil
 }
 
+// Returns the erasure sets layout of an XL `format.json`, used to
+// detect how the drives were formatted before the disks are
+// initialized.
+func formatXLGetSets(formatPath string) ([][]string, error) {
+	format := &formatXLV3{}
+	b, err := ioutil.ReadFile(formatPath)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, format); err != nil {
+		return nil, err
+	}
+	return format.XL.Sets, nil
+}
+
 // Returns format meta format version from `format.json`. This code
 // is specifically used to detect meta format.
 func formatMetaGetFormatBackendXL(formatPath string) (string, error) {
@@ -739,9 +754,14 @@ func fixFormatXLV3(storageDisks []StorageAPI, endpoints EndpointList, formats []
 	return nil
 }
 
-// initFormatXL - save XL format configuration on all disks.
-func initFormatXL(ctx context.Context, storageDisks []StorageAPI, setCount, disksPerSet int) (format *formatXLV3, err error) {
+// initFormatXL - save XL format configuration on all disks, a non-empty
+// deploymentID is used instead of generating a new one, this is needed
+// when formatting additional zones of an existing deployment.
+func initFormatXL(ctx context.Context, storageDisks []StorageAPI, setCount, disksPerSet int, deploymentID string) (format *formatXLV3, err error) {
 	format = newFormatXLV3(setCount, disksPerSet)
+	if deploymentID != "" {
+		format.ID = deploymentID
+	}
 	formats := make([]*formatXLV3, len(storageDisks))
 
 	for i := 0; i < setCount; i++ {
@@ -280,6 +280,15 @@ func StartGateway(ctx *cli.Context, gw Gateway) {
 	// Create new lifecycle system.
 	globalLifecycleSys = NewLifecycleSys()
 
+	// Create new ACL system.
+	globalACLSys = NewACLSys()
+
+	// Create new bucket logging system.
+	globalBucketLoggingSys = NewBucketLoggingSys()
+
+	// Create new bucket storage class system.
+	globalBucketStorageClassSys = NewBucketStorageClassSys()
+
 	// Create new notification system.
 	globalNotificationSys = NewNotificationSys(globalServerConfig, globalEndpoints)
 
@@ -25,6 +25,12 @@ import (
 
 	etcd "github.com/coreos/etcd/clientv3"
 	humanize "github.com/dustin/go-humanize"
+	"github.com/minio/minio/cmd/config/certidentity"
+	"github.com/minio/minio/cmd/config/drivehealth"
+	"github.com/minio/minio/cmd/config/listindex"
+	"github.com/minio/minio/cmd/config/notifyrules"
+	"github.com/minio/minio/cmd/config/scrubber"
+	"github.com/minio/minio/cmd/config/xlmeta"
 	"github.com/minio/minio/cmd/crypto"
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/pkg/auth"
@@ -33,6 +39,7 @@ import (
 	"github.com/minio/minio/pkg/iam/openid"
 	iampolicy "github.com/minio/minio/pkg/iam/policy"
 	"github.com/minio/minio/pkg/pubsub"
+	"github.com/minio/minio/pkg/tracing"
 )
 
 // minio configuration related constants.
@@ -101,10 +108,11 @@ var globalCLIContext = struct {
 }{}
 
 var (
-	// Indicates the total number of erasure coded sets configured.
+	// Indicates the total number of erasure coded sets configured
+	// across all zones.
 	globalXLSetCount int
 
-	// Indicates set drive count.
+	// Indicates set drive count, the smallest one across all zones.
 	globalXLSetDriveCount int
 
 	// Indicates if the running minio server is distributed setup.
@@ -156,6 +164,17 @@ var (
 
 	globalLifecycleSys *LifecycleSys
 
+	globalACLSys = NewACLSys()
+
+	globalBucketLoggingSys = NewBucketLoggingSys()
+
+	// Delivers server access logs, nil in gateway mode.
+	globalAccessLogger *accessLogger
+
+	globalBucketStorageClassSys = NewBucketStorageClassSys()
+
+	globalDecommissionSys = NewDecommissionSys()
+
 	// CA root certificates, a nil value means system certs pool will be used
 	globalRootCAs *x509.CertPool
 
@@ -178,12 +197,19 @@ var (
 
 	globalEndpoints EndpointList
 
+	// All zones of the deployment, globalEndpoints holds
+	// the endpoints of every zone.
+	globalEndpointZones EndpointZones
+
 	// Global server's network statistics
 	globalConnStats = newConnStats()
 
 	// Global HTTP request statisitics
 	globalHTTPStats = newHTTPStats()
 
+	// S3 API statistics by API and bucket
+	globalAPIStats = newAPIStatsSys()
+
 	// Time when object layer was initialized on start up.
 	globalBootTime time.Time
 
@@ -205,6 +231,8 @@ var (
 	globalRRStorageClass storageClass
 	// Set to store standard storage class
 	globalStandardStorageClass storageClass
+	// Set to store named storage classes
+	globalCustomStorageClasses map[string]storageClass
 
 	globalIsEnvWORM bool
 	// Is worm enabled
@@ -271,6 +299,27 @@ var (
 	// OPA policy system.
 	globalPolicyOPA *iampolicy.Opa
 
+	// Client certificate identity used by AssumeRoleWithCertificate.
+	globalCertIdentityConfig certidentity.Config
+
+	// Background bitrot scrubber configuration.
+	globalScrubberConfig scrubber.Config
+
+	// Thresholds above which drives are excluded from erasure sets.
+	globalDriveHealthConfig drivehealth.Config
+
+	// Buckets listed from a persistent metadata index.
+	globalListIndexConfig listindex.Config
+
+	// Notification rules applied to the events of all buckets.
+	globalNotifyRulesConfig notifyrules.Config
+
+	// Format in which object metadata is written.
+	globalXLMetaConfig xlmeta.Config
+
+	// Exports the spans of traced requests, nil if tracing is disabled.
+	globalTracer *tracing.Tracer
+
 	// Deployment ID - unique per deployment
 	globalDeploymentID string
 
@@ -326,26 +326,28 @@ func extractPostPolicyFormValues(ctx context.Context, form *multipart.Form) (fil
 
 // Log headers and body.
 func httpTraceAll(f http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+	name := getHandlerName(f)
+	return collectAPIStats(name, traceHTTPRequest(name, func(w http.ResponseWriter, r *http.Request) {
 		if !globalHTTPTrace.HasSubscribers() {
 			f.ServeHTTP(w, r)
 			return
 		}
 		trace := Trace(f, true, w, r)
 		globalHTTPTrace.Publish(trace)
-	}
+	}))
 }
 
 // Log only the headers.
 func httpTraceHdrs(f http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+	name := getHandlerName(f)
+	return collectAPIStats(name, traceHTTPRequest(name, func(w http.ResponseWriter, r *http.Request) {
 		if !globalHTTPTrace.HasSubscribers() {
 			f.ServeHTTP(w, r)
 			return
 		}
 		trace := Trace(f, false, w, r)
 		globalHTTPTrace.Publish(trace)
-	}
+	}))
 }
 
 // Returns "/bucketName/objectName" for path-style or virtual-host-style requests.
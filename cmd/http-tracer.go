@@ -22,10 +22,12 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
 	"reflect"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
@@ -94,9 +96,14 @@ func getOpName(name string) (op string) {
 	return op
 }
 
+// Returns the operation name of a handler, e.g. s3.PutObject.
+func getHandlerName(f http.HandlerFunc) string {
+	return getOpName(runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name())
+}
+
 // Trace gets trace of http request
 func Trace(f http.HandlerFunc, logBody bool, w http.ResponseWriter, r *http.Request) trace.Info {
-	name := getOpName(runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name())
+	name := getHandlerName(f)
 
 	// Setup a http request body recorder
 	reqHeaders := r.Header.Clone()
@@ -156,3 +163,176 @@ func Trace(f http.HandlerFunc, logBody bool, w http.ResponseWriter, r *http.Requ
 	}
 	return t
 }
+
+// Returns the name of this node in storage and lock trace records.
+func getTraceNodeName() string {
+	nodeName := GetLocalPeer(globalEndpoints)
+	// strip port from the host address
+	if host, _, err := net.SplitHostPort(nodeName); err == nil {
+		nodeName = host
+	}
+	return nodeName
+}
+
+// traceOptions - selects the trace records sent to a client.
+type traceOptions struct {
+	// Internal API requests are traced as well.
+	all bool
+	// Only failed requests and calls are traced.
+	errOnly bool
+	// Storage and lock calls are traced as well.
+	storage, lock bool
+
+	node       string
+	api        string
+	bucket     string
+	statusCode int
+	// Requests and calls faster than threshold are not traced.
+	threshold time.Duration
+}
+
+// Number of trace subscribers listening on storage and on lock calls,
+// nobody pays for tracing the calls unless somebody listens.
+var storageTraceSubscribers, lockTraceSubscribers int32
+
+// subscribeTrace - sends the trace records matching opts to ch
+// until doneCh is closed.
+func subscribeTrace(ch chan interface{}, doneCh chan struct{}, opts traceOptions) {
+	if opts.storage {
+		atomic.AddInt32(&storageTraceSubscribers, 1)
+	}
+	if opts.lock {
+		atomic.AddInt32(&lockTraceSubscribers, 1)
+	}
+	globalHTTPTrace.Subscribe(ch, doneCh, func(entry interface{}) bool {
+		return mustTrace(entry, opts)
+	})
+	if !opts.storage && !opts.lock {
+		return
+	}
+	go func() {
+		<-doneCh
+		if opts.storage {
+			atomic.AddInt32(&storageTraceSubscribers, -1)
+		}
+		if opts.lock {
+			atomic.AddInt32(&lockTraceSubscribers, -1)
+		}
+	}()
+}
+
+// getTraceOptions - parses the options of a trace request.
+func getTraceOptions(values url.Values) (opts traceOptions, err error) {
+	opts.all = values.Get(peerRESTTraceAll) == "true"
+	opts.errOnly = values.Get(peerRESTTraceErr) == "true"
+	opts.storage = values.Get(peerRESTTraceStorage) == "true"
+	opts.lock = values.Get(peerRESTTraceLock) == "true"
+	opts.node = values.Get(peerRESTTraceNode)
+	if host, _, err := net.SplitHostPort(opts.node); err == nil {
+		opts.node = host
+	}
+	opts.api = values.Get(peerRESTTraceAPI)
+	opts.bucket = values.Get(peerRESTTraceBucket)
+	if v := values.Get(peerRESTTraceStatusCode); v != "" {
+		if opts.statusCode, err = strconv.Atoi(v); err != nil {
+			return opts, err
+		}
+	}
+	if v := values.Get(peerRESTTraceThreshold); v != "" {
+		if opts.threshold, err = time.ParseDuration(v); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// queryValues - returns the options to be sent to peers.
+func (opts traceOptions) queryValues() url.Values {
+	values := make(url.Values)
+	values.Set(peerRESTTraceAll, strconv.FormatBool(opts.all))
+	values.Set(peerRESTTraceErr, strconv.FormatBool(opts.errOnly))
+	values.Set(peerRESTTraceStorage, strconv.FormatBool(opts.storage))
+	values.Set(peerRESTTraceLock, strconv.FormatBool(opts.lock))
+	if opts.node != "" {
+		values.Set(peerRESTTraceNode, opts.node)
+	}
+	if opts.api != "" {
+		values.Set(peerRESTTraceAPI, opts.api)
+	}
+	if opts.bucket != "" {
+		values.Set(peerRESTTraceBucket, opts.bucket)
+	}
+	if opts.statusCode != 0 {
+		values.Set(peerRESTTraceStatusCode, strconv.Itoa(opts.statusCode))
+	}
+	if opts.threshold != 0 {
+		values.Set(peerRESTTraceThreshold, opts.threshold.String())
+	}
+	return values
+}
+
+// Returns true if the trace.Info should be traced,
+// false if certain conditions are not met.
+// - input entry is not of the type *trace.Info*
+// - storage or lock calls are not requested.
+// - all entries to be traced, if not trace only S3 API requests.
+// - errOnly entries are to be traced, not status code 2xx, 3xx.
+// - the entry does not match the node, API, bucket or status code.
+// - the entry is faster than the minimum latency.
+func mustTrace(entry interface{}, opts traceOptions) bool {
+	trcInfo, ok := entry.(trace.Info)
+	if !ok {
+		return false
+	}
+	if opts.node != "" && trcInfo.NodeName != opts.node {
+		return false
+	}
+	// API names match with or without their prefix, e.g. s3.PutObject or PutObject.
+	if opts.api != "" && trcInfo.FuncName != opts.api && !hasSuffix(trcInfo.FuncName, "."+opts.api) {
+		return false
+	}
+
+	var (
+		path    string
+		failed  bool
+		latency time.Duration
+	)
+	switch trcInfo.TraceType {
+	case trace.Storage, trace.Lock:
+		if trcInfo.TraceType == trace.Storage && !opts.storage ||
+			trcInfo.TraceType == trace.Lock && !opts.lock {
+			return false
+		}
+		// Calls have no status code.
+		if opts.statusCode != 0 {
+			return false
+		}
+		path = trcInfo.CallInfo.Path
+		failed = trcInfo.CallInfo.Error != ""
+		latency = trcInfo.CallInfo.Duration
+	default:
+		if !opts.all && hasPrefix(trcInfo.ReqInfo.Path, minioReservedBucketPath+SlashSeparator) {
+			return false
+		}
+		if opts.statusCode != 0 && trcInfo.RespInfo.StatusCode != opts.statusCode {
+			return false
+		}
+		path = trcInfo.ReqInfo.Path
+		failed = trcInfo.RespInfo.StatusCode >= http.StatusBadRequest
+		latency = trcInfo.CallStats.Latency
+	}
+
+	if opts.errOnly && !failed {
+		return false
+	}
+	if latency < opts.threshold {
+		return false
+	}
+	if opts.bucket != "" {
+		bucket := strings.SplitN(strings.TrimPrefix(path, SlashSeparator), SlashSeparator, 2)[0]
+		if bucket != opts.bucket {
+			return false
+		}
+	}
+	return true
+}
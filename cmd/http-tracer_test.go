@@ -0,0 +1,287 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/cmd/logger/message/audit"
+	"github.com/minio/minio/pkg/auth"
+	"github.com/minio/minio/pkg/trace"
+)
+
+func TestGetTraceOptions(t *testing.T) {
+	opts := traceOptions{
+		all:        true,
+		storage:    true,
+		node:       "server1",
+		api:        "s3.PutObject",
+		bucket:     "photos",
+		statusCode: 503,
+		threshold:  time.Second,
+	}
+	got, err := getTraceOptions(opts.queryValues())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, opts) {
+		t.Fatalf("Expected %v, got %v", opts, got)
+	}
+
+	for _, values := range []url.Values{
+		{peerRESTTraceStatusCode: []string{"ok"}},
+		{peerRESTTraceThreshold: []string{"10"}},
+	} {
+		if _, err = getTraceOptions(values); err == nil {
+			t.Errorf("Expected %v to be invalid", values)
+		}
+	}
+}
+
+func TestMustTrace(t *testing.T) {
+	putObject := trace.Info{
+		NodeName:  "server1",
+		FuncName:  "s3.PutObject",
+		ReqInfo:   trace.RequestInfo{Path: "/photos/2019/cat.jpg"},
+		RespInfo:  trace.ResponseInfo{StatusCode: 200},
+		CallStats: trace.CallStats{Latency: 2 * time.Second},
+	}
+	internal := trace.Info{
+		NodeName: "server2",
+		FuncName: "internal.ReadFile",
+		ReqInfo:  trace.RequestInfo{Path: minioReservedBucketPath + "/storage/v10/readfile"},
+		RespInfo: trace.ResponseInfo{StatusCode: 500},
+	}
+	readFile := trace.Info{
+		TraceType: trace.Storage,
+		NodeName:  "server1",
+		FuncName:  "storage.ReadFile",
+		CallInfo:  trace.CallInfo{Path: "photos/2019/cat.jpg/part.1", Duration: time.Millisecond, Error: "file not found"},
+	}
+	getLock := trace.Info{
+		TraceType: trace.Lock,
+		NodeName:  "server1",
+		FuncName:  "lock.GetLock",
+		CallInfo:  trace.CallInfo{Path: "photos/2019/cat.jpg", Duration: time.Second},
+	}
+
+	testCases := []struct {
+		entry interface{}
+		opts  traceOptions
+		trace bool
+	}{
+		{"not a trace", traceOptions{all: true}, false},
+		{putObject, traceOptions{}, true},
+		{internal, traceOptions{}, false},
+		{internal, traceOptions{all: true}, true},
+		{internal, traceOptions{all: true, errOnly: true}, true},
+		{putObject, traceOptions{errOnly: true}, false},
+		// Storage and lock calls are opt-in.
+		{readFile, traceOptions{all: true}, false},
+		{readFile, traceOptions{storage: true}, true},
+		{getLock, traceOptions{storage: true}, false},
+		{getLock, traceOptions{lock: true}, true},
+		{readFile, traceOptions{storage: true, errOnly: true}, true},
+		{getLock, traceOptions{lock: true, errOnly: true}, false},
+		// Filters.
+		{putObject, traceOptions{node: "server1"}, true},
+		{putObject, traceOptions{node: "server2"}, false},
+		{putObject, traceOptions{api: "PutObject"}, true},
+		{putObject, traceOptions{api: "s3.PutObject"}, true},
+		{putObject, traceOptions{api: "Object"}, false},
+		{readFile, traceOptions{storage: true, api: "ReadFile"}, true},
+		{putObject, traceOptions{bucket: "photos"}, true},
+		{putObject, traceOptions{bucket: "photo"}, false},
+		{readFile, traceOptions{storage: true, bucket: "photos"}, true},
+		{putObject, traceOptions{statusCode: 200}, true},
+		{putObject, traceOptions{statusCode: 503}, false},
+		{getLock, traceOptions{lock: true, statusCode: 200}, false},
+		{putObject, traceOptions{threshold: time.Second}, true},
+		{readFile, traceOptions{storage: true, threshold: time.Second}, false},
+		{getLock, traceOptions{lock: true, threshold: time.Second}, true},
+	}
+	for i, testCase := range testCases {
+		if got := mustTrace(testCase.entry, testCase.opts); got != testCase.trace {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.trace, got)
+		}
+	}
+}
+
+func TestPosixTracer(t *testing.T) {
+	posixStorage, diskPath, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(diskPath)
+	disk := newPosixTracer(posixStorage)
+
+	// No records are published without subscribers.
+	if err = disk.MakeVol("bucket"); err != nil {
+		t.Fatal(err)
+	}
+
+	traceCh := make(chan interface{}, 10)
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	subscribeTrace(traceCh, doneCh, traceOptions{storage: true})
+
+	if _, err = disk.StatFile("bucket", "object"); err != errFileNotFound {
+		t.Fatalf("Expected %v, got %v", errFileNotFound, err)
+	}
+	select {
+	case entry := <-traceCh:
+		info := entry.(trace.Info)
+		if info.TraceType != trace.Storage || info.FuncName != "storage.StatFile" {
+			t.Fatalf("Unexpected trace record %v", info)
+		}
+		if info.CallInfo.Disk != diskPath || info.CallInfo.Path != "bucket/object" || info.CallInfo.Error != errFileNotFound.Error() {
+			t.Fatalf("Unexpected call %v", info.CallInfo)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a trace record of StatFile")
+	}
+	if len(traceCh) != 0 {
+		t.Fatalf("Expected a single trace record, got %d more", len(traceCh))
+	}
+}
+
+// testAuditTarget - records the audit entries of requests.
+type testAuditTarget struct {
+	mu      sync.Mutex
+	entries []audit.Entry
+}
+
+func (target *testAuditTarget) Send(entry interface{}) error {
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	target.entries = append(target.entries, entry.(audit.Entry))
+	return nil
+}
+
+// Tests traces and audit logs of requests served by the API router.
+func TestTraceAuditRouter(t *testing.T) {
+	ExecObjectLayerAPITest(t, testTraceAuditRouter, nil)
+}
+
+func testTraceAuditRouter(obj ObjectLayer, instanceType, bucketName string, apiRouter http.Handler,
+	credentials auth.Credentials, t *testing.T) {
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+
+	savedTargets := logger.AuditTargets
+	defer func() { logger.AuditTargets = savedTargets }()
+	target := &testAuditTarget{}
+	logger.AuditTargets = []logger.Target{target}
+
+	traceCh := make(chan interface{}, 100)
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	globalHTTPTrace.Subscribe(traceCh, doneCh, func(entry interface{}) bool {
+		info, ok := entry.(trace.Info)
+		return ok && info.TraceType == trace.HTTP
+	})
+
+	// The response writer is wrapped for audit logs as by the server.
+	router := addCustomHeaders(apiRouter)
+
+	testCases := []struct {
+		url        string
+		api        string
+		statusCode int
+		errorCode  string
+	}{
+		{getListBucketURL(""), "ListBuckets", http.StatusOK, ""},
+		{getGetObjectURL("", bucketName, "missing"), "GetObject", http.StatusNotFound, "NoSuchKey"},
+	}
+	for i, testCase := range testCases {
+		req, err := newTestSignedRequestV4(http.MethodGet, testCase.url, 0, nil, credentials.AccessKey, credentials.SecretKey, nil)
+		if err != nil {
+			t.Fatalf("%s: test %d: %v", instanceType, i+1, err)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != testCase.statusCode {
+			t.Fatalf("%s: test %d: expected status %d, got %d", instanceType, i+1, testCase.statusCode, rec.Code)
+		}
+		if code := rec.Header().Get("x-minio-error-code"); code != "" {
+			t.Fatalf("%s: test %d: expected no error code header, got %s", instanceType, i+1, code)
+		}
+
+		select {
+		case entry := <-traceCh:
+			info := entry.(trace.Info)
+			if info.FuncName != "s3."+testCase.api || info.RespInfo.StatusCode != testCase.statusCode {
+				t.Fatalf("%s: test %d: unexpected trace %s %d", instanceType, i+1, info.FuncName, info.RespInfo.StatusCode)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: test %d: expected a trace record", instanceType, i+1)
+		}
+	}
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	if len(target.entries) != len(testCases) {
+		t.Fatalf("%s: expected %d audit entries, got %d", instanceType, len(testCases), len(target.entries))
+	}
+	for i, entry := range target.entries {
+		testCase := testCases[i]
+		if entry.API.Name != testCase.api || entry.API.StatusCode != testCase.statusCode || entry.API.ErrorCode != testCase.errorCode {
+			t.Fatalf("%s: test %d: unexpected audit entry %+v", instanceType, i+1, entry.API)
+		}
+		if entry.API.TimeToResponse == "0s" || entry.API.PolicyDecision != audit.DecisionAllow {
+			t.Fatalf("%s: test %d: expected response time and policy decision, got %+v", instanceType, i+1, entry.API)
+		}
+	}
+}
+
+func TestSubscribeTrace(t *testing.T) {
+	traceCh := make(chan interface{}, 1)
+	doneCh := make(chan struct{})
+
+	// Plain HTTP tracing does not time storage and lock calls.
+	subscribeTrace(traceCh, doneCh, traceOptions{})
+	if !storageTraceStart().IsZero() || !lockTraceStart().IsZero() {
+		t.Fatal("expected no storage and lock tracing")
+	}
+
+	storageDoneCh := make(chan struct{})
+	subscribeTrace(traceCh, storageDoneCh, traceOptions{storage: true})
+	if storageTraceStart().IsZero() {
+		t.Fatal("expected storage tracing")
+	}
+	if !lockTraceStart().IsZero() {
+		t.Fatal("expected no lock tracing")
+	}
+
+	close(storageDoneCh)
+	close(doneCh)
+	for i := 0; !storageTraceStart().IsZero(); i++ {
+		if i == 100 {
+			t.Fatal("expected storage tracing to stop")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -58,6 +58,13 @@ const (
 	AmzCopySourceVersionID = "X-Amz-Copy-Source-Version-Id"
 	AmzCopySourceRange     = "X-Amz-Copy-Source-Range"
 
+	// Version of the object of a request.
+	AmzVersionID = "X-Amz-Version-Id"
+
+	// ACL related headers.
+	AmzACL         = "X-Amz-Acl"
+	AmzGrantPrefix = "X-Amz-Grant-"
+
 	// Signature V4 related contants.
 	AmzContentSha256        = "X-Amz-Content-Sha256"
 	AmzDate                 = "X-Amz-Date"
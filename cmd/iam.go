@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/minio/minio-go/v6/pkg/set"
 	"github.com/minio/minio/cmd/logger"
@@ -533,7 +534,7 @@ func (sys *IAMSys) SetTempUser(accessKey string, cred auth.Credentials, policyNa
 	// temporary user which match with pre-configured canned
 	// policies for this server.
 	if globalPolicyOPA == nil && policyName != "" {
-		p, ok := sys.iamPolicyDocsMap[policyName]
+		p, ok := sys.combinedPolicyDoc(policyName)
 		if !ok {
 			return errInvalidArgument
 		}
@@ -712,6 +713,124 @@ func (sys *IAMSys) SetUserSecretKey(accessKey string, secretKey string) error {
 	return nil
 }
 
+const (
+	// ldapUserCheckInterval - interval at which temporary credentials
+	// issued to LDAP users are checked against the directory.
+	ldapUserCheckInterval = 5 * time.Minute
+
+	// Lock of the meta bucket held by the server checking the users.
+	ldapUserCheckLockPath = "ldap-user-check.lock"
+)
+
+var ldapUserCheckTimeout = newDynamicTimeout(30*time.Second, time.Second)
+
+// initLDAPUserCheck starts the routine that periodically disables
+// STS credentials of users removed from the LDAP directory.
+func (sys *IAMSys) initLDAPUserCheck() {
+	if sys.usersSysType != LDAPUsersSysType ||
+		!globalServerConfig.LDAPServerConfig.IsLookupBindEnabled() {
+		return
+	}
+	go sys.ldapUserCheck()
+}
+
+// ldapUserCheck - checks the users as long as this server holds the
+// lock, only one server checks them. The lock of a server which goes
+// away is released, another server takes over then.
+func (sys *IAMSys) ldapUserCheck() {
+	checkLock := globalNSMutex.NewNSLock(context.Background(), minioMetaBucket, ldapUserCheckLockPath)
+	for {
+		if err := checkLock.GetLock(ldapUserCheckTimeout); err == nil {
+			break
+		}
+		select {
+		case <-GlobalServiceDoneCh:
+			return
+		case <-time.After(ldapUserCheckInterval):
+		}
+	}
+	defer checkLock.Unlock()
+
+	for {
+		select {
+		case <-GlobalServiceDoneCh:
+			return
+		case <-time.After(ldapUserCheckInterval):
+			sys.disableRemovedLDAPUsers()
+		}
+	}
+}
+
+// disableRemovedLDAPUsers - disables all the temporary credentials
+// whose LDAP user no longer exists in the directory.
+func (sys *IAMSys) disableRemovedLDAPUsers() {
+	objectAPI := newObjectLayerFn()
+	if objectAPI == nil {
+		return
+	}
+
+	// Collect the LDAP users of all enabled temporary credentials.
+	ldapUsers := make(map[string][]string)
+	sys.RLock()
+	for accessKey, cred := range sys.iamUsersMap {
+		if cred.SessionToken == "" || !cred.IsValid() || cred.IsExpired() {
+			continue
+		}
+		claims, err := getClaimsFromSessionToken(cred.SessionToken)
+		if err != nil {
+			continue
+		}
+		if user, ok := claims[ldapUser].(string); ok {
+			ldapUsers[user] = append(ldapUsers[user], accessKey)
+		}
+	}
+	sys.RUnlock()
+
+	ctx := context.Background()
+	for user, accessKeys := range ldapUsers {
+		exists, err := globalServerConfig.LDAPServerConfig.DoesUsernameExist(user)
+		if err != nil {
+			logger.LogIf(ctx, err)
+			continue
+		}
+		if exists {
+			continue
+		}
+		for _, accessKey := range accessKeys {
+			if err = sys.disableTempUser(accessKey); err != nil {
+				logger.LogIf(ctx, err)
+				continue
+			}
+			// Notify all other MinIO peers to reload temp users
+			for _, nerr := range globalNotificationSys.LoadUser(accessKey, true) {
+				if nerr.Err != nil {
+					logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+					logger.LogIf(ctx, nerr.Err)
+				}
+			}
+		}
+	}
+}
+
+// disableTempUser - marks the temporary credentials as disabled.
+func (sys *IAMSys) disableTempUser(accessKey string) error {
+	sys.Lock()
+	defer sys.Unlock()
+
+	cred, ok := sys.iamUsersMap[accessKey]
+	if !ok {
+		return errNoSuchUser
+	}
+
+	cred.Status = statusDisabled
+	if err := sys.store.saveUserIdentity(accessKey, true, newUserIdentity(cred)); err != nil {
+		return err
+	}
+
+	sys.iamUsersMap[accessKey] = cred
+	return nil
+}
+
 // GetUser - get user credentials
 func (sys *IAMSys) GetUser(accessKey string) (cred auth.Credentials, ok bool) {
 	sys.RLock()
@@ -1070,50 +1189,12 @@ func (sys *IAMSys) policyDBGet(name string, isGroup bool) ([]string, error) {
 func (sys *IAMSys) IsAllowedSTS(args iampolicy.Args) bool {
 	// If it is an LDAP request, check that user and group
 	// policies allow the request.
-	if userIface, ok := args.Claims[ldapUser]; ok {
-		var user string
-		if u, ok := userIface.(string); ok {
-			user = u
-		} else {
-			return false
-		}
-
-		var groups []string
-		groupsVal := args.Claims[ldapGroups]
-		if g, ok := groupsVal.([]interface{}); ok {
-			for _, eachG := range g {
-				if eachGStr, ok := eachG.(string); ok {
-					groups = append(groups, eachGStr)
-				}
-			}
-		} else {
-			return false
-		}
-
+	if _, ok := args.Claims[ldapUser]; ok {
 		sys.RLock()
 		defer sys.RUnlock()
 
-		// We look up the policy mapping directly to bypass
-		// users exists, group exists validations that do not
-		// apply here.
-		var policies []iampolicy.Policy
-		if policy, ok := sys.iamUserPolicyMap[user]; ok {
-			p, found := sys.iamPolicyDocsMap[policy.Policy]
-			if found {
-				policies = append(policies, p)
-			}
-		}
-		for _, group := range groups {
-			policy, ok := sys.iamGroupPolicyMap[group]
-			if !ok {
-				continue
-			}
-			p, found := sys.iamPolicyDocsMap[policy.Policy]
-			if found {
-				policies = append(policies, p)
-			}
-		}
-		if len(policies) == 0 {
+		policies, ok := sys.ldapPolicyDocs(args.Claims)
+		if !ok || len(policies) == 0 {
 			return false
 		}
 		combinedPolicy := policies[0]
@@ -1159,7 +1240,7 @@ func (sys *IAMSys) IsAllowedSTS(args iampolicy.Args) bool {
 	if !ok {
 		// Sub policy not set, this is most common since subPolicy
 		// is optional, use the top level policy only.
-		p, ok := sys.iamPolicyDocsMap[pnameStr]
+		p, ok := sys.combinedPolicyDoc(pnameStr)
 		return ok && p.IsAllowed(args)
 	}
 
@@ -1188,6 +1269,133 @@ func (sys *IAMSys) IsAllowedSTS(args iampolicy.Args) bool {
 	return ok && p.IsAllowed(args) && subPolicy.IsAllowed(args)
 }
 
+// ldapPolicyDocs - returns the policies mapped to the LDAP user and
+// groups of the claims, returns false if the claims are malformed.
+// The caller must hold the IAMSys lock.
+func (sys *IAMSys) ldapPolicyDocs(claims map[string]interface{}) ([]iampolicy.Policy, bool) {
+	user, ok := claims[ldapUser].(string)
+	if !ok {
+		return nil, false
+	}
+
+	var groups []string
+	groupsVal := claims[ldapGroups]
+	if g, ok := groupsVal.([]interface{}); ok {
+		for _, eachG := range g {
+			if eachGStr, ok := eachG.(string); ok {
+				groups = append(groups, eachGStr)
+			}
+		}
+	} else {
+		return nil, false
+	}
+
+	// We look up the policy mapping directly to bypass
+	// users exists, group exists validations that do not
+	// apply here.
+	var policies []iampolicy.Policy
+	if policy, ok := sys.iamUserPolicyMap[user]; ok {
+		p, found := sys.iamPolicyDocsMap[policy.Policy]
+		if found {
+			policies = append(policies, p)
+		}
+	}
+	for _, group := range groups {
+		policy, ok := sys.iamGroupPolicyMap[group]
+		if !ok {
+			continue
+		}
+		p, found := sys.iamPolicyDocsMap[policy.Policy]
+		if found {
+			policies = append(policies, p)
+		}
+	}
+	return policies, true
+}
+
+// combinedPolicyDoc - returns the policy combining the statements of
+// all the policies in the comma separated policy names, as mapped
+// from OpenID claims. Returns false if any of the policies is missing.
+// The caller must hold the IAMSys lock.
+func (sys *IAMSys) combinedPolicyDoc(policyNames string) (iampolicy.Policy, bool) {
+	var combinedPolicy iampolicy.Policy
+	for i, name := range strings.Split(policyNames, ",") {
+		p, ok := sys.iamPolicyDocsMap[strings.TrimSpace(name)]
+		if !ok {
+			return iampolicy.Policy{}, false
+		}
+		if i == 0 {
+			combinedPolicy = p
+			combinedPolicy.Statements = append([]iampolicy.Statement{}, p.Statements...)
+			continue
+		}
+		combinedPolicy.Statements = append(combinedPolicy.Statements, p.Statements...)
+	}
+	return combinedPolicy, true
+}
+
+// IsDenied - checks whether a deny statement of the policies of the
+// account matches the given args. OPA does not tell a deny apart from
+// a missing allow, so with OPA nothing counts as explicitly denied.
+func (sys *IAMSys) IsDenied(args iampolicy.Args) bool {
+	if globalPolicyOPA != nil {
+		return false
+	}
+
+	var policies []iampolicy.Policy
+	switch {
+	case len(args.Claims) > 0:
+		sys.RLock()
+		if _, ok := args.Claims[ldapUser]; ok {
+			var ldapPolicies []iampolicy.Policy
+			if ldapPolicies, ok = sys.ldapPolicyDocs(args.Claims); !ok {
+				sys.RUnlock()
+				return true
+			}
+			policies = ldapPolicies
+		} else if pname, ok := args.Claims[iampolicy.PolicyName].(string); ok {
+			if p, ok := sys.combinedPolicyDoc(pname); ok {
+				policies = append(policies, p)
+			}
+		}
+		sys.RUnlock()
+
+		if spolicy, ok := args.Claims[iampolicy.SessionPolicyName]; ok {
+			spolicyStr, ok := spolicy.(string)
+			if !ok {
+				return true
+			}
+			subPolicy, err := iampolicy.ParseConfig(bytes.NewReader([]byte(spolicyStr)))
+			if err != nil {
+				return true
+			}
+			policies = append(policies, *subPolicy)
+		}
+	case args.IsOwner:
+		return false
+	default:
+		pnames, err := sys.PolicyDBGet(args.AccountName, false)
+		if err != nil {
+			logger.LogIf(context.Background(), err)
+			return true
+		}
+		sys.RLock()
+		for _, pname := range pnames {
+			if p, found := sys.iamPolicyDocsMap[pname]; found {
+				policies = append(policies, p)
+			}
+		}
+		sys.RUnlock()
+	}
+
+	for _, p := range policies {
+		if p.IsDenied(args) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsAllowed - checks given policy args is allowed to continue the Rest API.
 func (sys *IAMSys) IsAllowed(args iampolicy.Args) bool {
 	// If opa is configured, use OPA always.
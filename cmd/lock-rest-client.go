@@ -31,6 +31,7 @@ import (
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/cmd/rest"
 	xnet "github.com/minio/minio/pkg/net"
+	"github.com/minio/minio/pkg/tracing"
 )
 
 // lockRESTClient is authenticable lock REST client
@@ -101,7 +102,7 @@ func (client *lockRESTClient) markHostDown() {
 // Wrapper to restClient.Call to handle network errors, in case of network error the connection is marked disconnected
 // permanently. The only way to restore the connection is at the xl-sets layer by xlsets.monitorAndConnectEndpoints()
 // after verifying format.json
-func (client *lockRESTClient) call(method string, values url.Values, body io.Reader, length int64) (respBody io.ReadCloser, err error) {
+func (client *lockRESTClient) call(ctx context.Context, method string, values url.Values, body io.Reader, length int64) (respBody io.ReadCloser, err error) {
 	if !client.isHostUp() {
 		return nil, errors.New("Lock rest server node is down")
 	}
@@ -110,7 +111,7 @@ func (client *lockRESTClient) call(method string, values url.Values, body io.Rea
 		values = make(url.Values)
 	}
 
-	respBody, err = client.restClient.Call(method, values, body, length)
+	respBody, err = client.restClient.CallWithContext(ctx, method, values, body, length)
 	if err == nil {
 		return respBody, nil
 	}
@@ -148,16 +149,21 @@ func (client *lockRESTClient) restCall(call string, args dsync.LockArgs) (reply
 	values.Set(lockRESTServerAddr, args.ServerAddr)
 	values.Set(lockRESTServerEndpoint, args.ServiceEndpoint)
 
-	respBody, err := client.call(call, values, nil, -1)
+	// Lock requests of traced requests continue their trace.
+	ctx, span := tracing.Start(globalLockSpans.context(args.UID), "lock."+call, tracing.KindClient)
+	span.SetAttribute("server", client.String())
+	span.SetAttribute("resource", args.Resource)
+
+	respBody, err := client.call(ctx, call, values, nil, -1)
 	defer http.DrainBody(respBody)
 	switch err {
 	case nil:
-		return true, nil
+		reply = true
 	case errLockConflict, errLockNotExpired:
-		return false, nil
-	default:
-		return false, err
+		err = nil
 	}
+	finishSpan(span, err)
+	return reply, err
 }
 
 // RLock calls read lock REST API.
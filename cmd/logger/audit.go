@@ -17,13 +17,19 @@
 package logger
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
+	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger/message/audit"
 )
 
@@ -37,11 +43,18 @@ type ResponseWriter struct {
 	StartTime       time.Time
 	// number of bytes written
 	bytesWritten int
+	// number of body bytes written
+	bodyBytesWritten int
 	// Internal recording buffer
 	headers bytes.Buffer
 	body    bytes.Buffer
 	// Indicate if headers are written in the log
 	headersLogged bool
+	// Result of the policy evaluation of the request.
+	PolicyDecision string
+	// S3 error code of a failed request, not sent to the client
+	// outside of the error response.
+	ErrorCode string
 }
 
 // NewResponseWriter - returns a wrapped response writer to trap
@@ -57,6 +70,7 @@ func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
 func (lrw *ResponseWriter) Write(p []byte) (int, error) {
 	n, err := lrw.ResponseWriter.Write(p)
 	lrw.bytesWritten += n
+	lrw.bodyBytesWritten += n
 	if lrw.TimeToFirstByte == 0 {
 		lrw.TimeToFirstByte = time.Now().UTC().Sub(lrw.StartTime)
 	}
@@ -115,20 +129,85 @@ func (lrw *ResponseWriter) Flush() {
 	lrw.ResponseWriter.(http.Flusher).Flush()
 }
 
+// Hijack - Calls the underlying Hijack, e.g. to take over the
+// connection of a WebSocket.
+func (lrw *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := lrw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
 // Size - reutrns the number of bytes written
 func (lrw *ResponseWriter) Size() int {
 	return lrw.bytesWritten
 }
 
+// BodySize - returns the number of response body bytes written
+func (lrw *ResponseWriter) BodySize() int {
+	return lrw.bodyBytesWritten
+}
+
 // AuditTargets is the list of enabled audit loggers
 var AuditTargets = []Target{}
 
+// auditHeaderFilter selects the headers recorded in audit logs.
+var auditHeaderFilter audit.HeaderFilter
+
+// SetAuditHeaderFilter sets the headers recorded in audit logs,
+// all headers are recorded if allow is empty. Denied headers are
+// never recorded.
+func SetAuditHeaderFilter(allow, deny []string) {
+	auditHeaderFilter = audit.HeaderFilter{Allow: allow, Deny: deny}
+}
+
+// Key of the audited response writer of a request in its context.
+const auditWriterKey = contextKeyType("audit-writer")
+
+// SetAuditWriter returns ctx carrying the response writer of the
+// request, if it is audited.
+func SetAuditWriter(ctx context.Context, w http.ResponseWriter) context.Context {
+	if lrw, ok := w.(*ResponseWriter); ok {
+		return context.WithValue(ctx, auditWriterKey, lrw)
+	}
+	return ctx
+}
+
+// SetPolicyDecision records the result of the policy evaluation
+// of the request of ctx in its audit log.
+func SetPolicyDecision(ctx context.Context, allowed bool) {
+	lrw, ok := ctx.Value(auditWriterKey).(*ResponseWriter)
+	if !ok {
+		return
+	}
+	lrw.PolicyDecision = audit.DecisionDeny
+	if allowed {
+		lrw.PolicyDecision = audit.DecisionAllow
+	}
+}
+
 // AddAuditTarget adds a new audit logger target to the
 // list of enabled loggers
 func AddAuditTarget(t Target) {
 	AuditTargets = append(AuditTargets, t)
 }
 
+// AccessTarget is the interface of the server access logger, unlike
+// audit targets it also receives the request and the response writer.
+type AccessTarget interface {
+	SendAccess(r *http.Request, w *ResponseWriter, entry audit.Entry)
+}
+
+// accessTarget is the server access logger, nil when disabled.
+var accessTarget AccessTarget
+
+// SetAccessTarget sets the server access logger which receives
+// every audited request against a bucket.
+func SetAccessTarget(t AccessTarget) {
+	accessTarget = t
+}
+
 // AuditLog - logs audit logs to all audit targets.
 func AuditLog(w http.ResponseWriter, r *http.Request, api string, reqClaims map[string]interface{}) {
 	var statusCode int
@@ -145,9 +224,8 @@ func AuditLog(w http.ResponseWriter, r *http.Request, api string, reqClaims map[
 	bucket := vars["bucket"]
 	object := vars["object"]
 
-	// Send audit logs only to http targets.
-	for _, t := range AuditTargets {
-		entry := audit.ToEntry(w, r, reqClaims, globalDeploymentID)
+	toEntry := func() audit.Entry {
+		entry := audit.ToEntry(w, r, reqClaims, globalDeploymentID, auditHeaderFilter)
 		entry.API.Name = api
 		entry.API.Bucket = bucket
 		entry.API.Object = object
@@ -155,6 +233,41 @@ func AuditLog(w http.ResponseWriter, r *http.Request, api string, reqClaims map[
 		entry.API.StatusCode = statusCode
 		entry.API.TimeToFirstByte = timeToFirstByte.String()
 		entry.API.TimeToResponse = timeToResponse.String()
-		_ = t.Send(entry)
+		if ok {
+			if statusCode >= http.StatusBadRequest {
+				entry.API.ErrorCode = lrw.ErrorCode
+			}
+			entry.API.OutputBytes = int64(lrw.BodySize())
+			entry.API.PolicyDecision = lrw.PolicyDecision
+		}
+		// Object size is only known for object uploads and downloads.
+		if object != "" {
+			switch r.Method {
+			case http.MethodPut:
+				entry.API.ObjectSize = r.ContentLength
+				if size, err := strconv.ParseInt(r.Header.Get(xhttp.AmzDecodedContentLength), 10, 64); err == nil {
+					entry.API.ObjectSize = size
+				}
+			case http.MethodGet, http.MethodHead:
+				entry.API.ObjectSize, _ = strconv.ParseInt(w.Header().Get(xhttp.ContentLength), 10, 64)
+			}
+			if entry.API.ObjectSize < 0 {
+				entry.API.ObjectSize = 0
+			}
+		}
+		return entry
+	}
+
+	// Audit targets queue the entry until it is delivered, the
+	// entry is only lost if the queue of a target is full.
+	for _, t := range AuditTargets {
+		if err := t.Send(toEntry()); err != nil {
+			LogOnceIf(context.Background(), fmt.Errorf("Unable to queue audit log: %v", err), t)
+		}
+	}
+
+	// Server access logs are only recorded for bucket requests.
+	if accessTarget != nil && ok && bucket != "" {
+		accessTarget.SendAccess(r, lrw, toEntry())
 	}
 }
@@ -39,8 +39,16 @@ type Entry struct {
 		Object          string `json:"object,omitempty"`
 		Status          string `json:"status,omitempty"`
 		StatusCode      int    `json:"statusCode,omitempty"`
+		ErrorCode       string `json:"errorCode,omitempty"`
 		TimeToFirstByte string `json:"timeToFirstByte,omitempty"`
 		TimeToResponse  string `json:"timeToResponse,omitempty"`
+		InputBytes      int64  `json:"rx,omitempty"`
+		OutputBytes     int64  `json:"tx,omitempty"`
+		ObjectSize      int64  `json:"objectSize,omitempty"`
+		ETag            string `json:"etag,omitempty"`
+		VersionID       string `json:"versionId,omitempty"`
+		// Result of the policy evaluation, allow or deny.
+		PolicyDecision string `json:"policyDecision,omitempty"`
 	} `json:"api"`
 	RemoteHost string                 `json:"remotehost,omitempty"`
 	RequestID  string                 `json:"requestID,omitempty"`
@@ -51,21 +59,110 @@ type Entry struct {
 	RespHeader map[string]string      `json:"responseHeader,omitempty"`
 }
 
-// ToEntry - constructs an audit entry object.
-func ToEntry(w http.ResponseWriter, r *http.Request, reqClaims map[string]interface{}, deploymentID string) Entry {
+// Policy decisions of a request.
+const (
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
+)
+
+// Redacted - replaces the values of sensitive headers and query
+// parameters.
+const Redacted = "*REDACTED*"
+
+// Headers and query parameters carrying credentials or keys, their
+// values are never logged.
+var sensitiveKeys = map[string]bool{
+	"Authorization":        true,
+	"Cookie":               true,
+	xhttp.AmzSecurityToken: true,
+	xhttp.AmzSignature:     true,
+	xhttp.AmzSignatureV2:   true,
+	"X-Amz-Server-Side-Encryption-Customer-Key":             true,
+	"X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key": true,
+}
+
+// Query parameters carrying credentials, e.g. of STS requests, in
+// lower case.
+var sensitiveQueryKeys = map[string]bool{
+	"ldappassword":     true,
+	"webidentitytoken": true,
+	"token":            true,
+	"x-amz-credential": true,
+	"awsaccesskeyid":   true,
+}
+
+func isSensitive(key string) bool {
+	return sensitiveKeys[http.CanonicalHeaderKey(key)]
+}
+
+func isSensitiveQuery(key string) bool {
+	return isSensitive(key) || sensitiveQueryKeys[strings.ToLower(key)]
+}
+
+// HeaderFilter - selects the request and response headers recorded in
+// audit entries. Header names match case insensitively, a name ending
+// with * matches all headers with its prefix, e.g. X-Amz-Meta-*.
+type HeaderFilter struct {
+	// Only these headers are recorded, all headers if empty.
+	Allow []string
+	// These headers are never recorded, they take precedence over
+	// allowed headers.
+	Deny []string
+}
+
+func matchHeader(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if strings.HasSuffix(pattern, "*") {
+			prefix := strings.TrimSuffix(pattern, "*")
+			if len(name) >= len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+				return true
+			}
+		} else if strings.EqualFold(name, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match - returns true if the header name is recorded.
+func (f HeaderFilter) Match(name string) bool {
+	if len(f.Allow) > 0 && !matchHeader(f.Allow, name) {
+		return false
+	}
+	return !matchHeader(f.Deny, name)
+}
+
+func (f HeaderFilter) toMap(header http.Header) map[string]string {
+	m := make(map[string]string)
+	for k, v := range header {
+		if !f.Match(k) {
+			continue
+		}
+		if isSensitive(k) {
+			m[k] = Redacted
+			continue
+		}
+		m[k] = strings.Join(v, ",")
+	}
+	return m
+}
+
+// ToEntry - constructs an audit entry object, the headers not matching
+// the filter are left out and sensitive values are redacted.
+func ToEntry(w http.ResponseWriter, r *http.Request, reqClaims map[string]interface{}, deploymentID string, filter HeaderFilter) Entry {
 	reqQuery := make(map[string]string)
 	for k, v := range r.URL.Query() {
+		if isSensitiveQuery(k) {
+			reqQuery[k] = Redacted
+			continue
+		}
 		reqQuery[k] = strings.Join(v, ",")
 	}
-	reqHeader := make(map[string]string)
-	for k, v := range r.Header {
-		reqHeader[k] = strings.Join(v, ",")
+	reqHeader := filter.toMap(r.Header)
+	respHeader := filter.toMap(w.Header())
+	if _, ok := respHeader[xhttp.ETag]; ok {
+		respHeader[xhttp.ETag] = strings.Trim(respHeader[xhttp.ETag], `"`)
 	}
-	respHeader := make(map[string]string)
-	for k, v := range w.Header() {
-		respHeader[k] = strings.Join(v, ",")
-	}
-	respHeader[xhttp.ETag] = strings.Trim(respHeader[xhttp.ETag], `"`)
 
 	entry := Entry{
 		Version:      Version,
@@ -79,6 +176,13 @@ func ToEntry(w http.ResponseWriter, r *http.Request, reqClaims map[string]interf
 		ReqClaims:    reqClaims,
 		RespHeader:   respHeader,
 	}
+	entry.API.ETag = strings.Trim(w.Header().Get(xhttp.ETag), `"`)
+	if entry.API.VersionID = w.Header().Get(xhttp.AmzVersionID); entry.API.VersionID == "" {
+		entry.API.VersionID = r.URL.Query().Get("versionId")
+	}
+	if r.ContentLength > 0 {
+		entry.API.InputBytes = r.ContentLength
+	}
 
 	return entry
 }
@@ -0,0 +1,106 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHeaderFilter(t *testing.T) {
+	testCases := []struct {
+		filter  HeaderFilter
+		header  string
+		matches bool
+	}{
+		{HeaderFilter{}, "Content-Type", true},
+		{HeaderFilter{Allow: []string{"content-type"}}, "Content-Type", true},
+		{HeaderFilter{Allow: []string{"Content-Type"}}, "Content-Length", false},
+		{HeaderFilter{Allow: []string{"X-Amz-Meta-*"}}, "X-Amz-Meta-Owner", true},
+		{HeaderFilter{Allow: []string{"X-Amz-Meta-*"}}, "X-Amz-Date", false},
+		{HeaderFilter{Deny: []string{"Cookie"}}, "Cookie", false},
+		{HeaderFilter{Allow: []string{"X-Amz-*"}, Deny: []string{"x-amz-meta-*"}}, "X-Amz-Meta-Owner", false},
+		{HeaderFilter{Allow: []string{"X-Amz-*"}, Deny: []string{"x-amz-meta-*"}}, "X-Amz-Date", true},
+	}
+	for i, testCase := range testCases {
+		if matches := testCase.filter.Match(testCase.header); matches != testCase.matches {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.matches, matches)
+		}
+	}
+}
+
+func TestToEntry(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPut, "/bucket/object?versionId=v1&X-Amz-Signature=abcdef", strings.NewReader("hello"))
+	r.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=minio/20190812/us-east-1/s3/aws4_request")
+	r.Header.Set("X-Amz-Server-Side-Encryption-Customer-Key", "MzJieXRlc2xvbmdzZWNyZXRrZXltdXN0cHJvdmlkZWQ=")
+	r.Header.Set("X-Amz-Meta-Owner", "alice")
+	r.Header.Set("Cookie", "session=1")
+	w := httptest.NewRecorder()
+	w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592"`)
+
+	entry := ToEntry(w, r, nil, "", HeaderFilter{Deny: []string{"Cookie"}})
+	expected := map[string]string{
+		"Authorization": Redacted,
+		"X-Amz-Server-Side-Encryption-Customer-Key": Redacted,
+		"X-Amz-Meta-Owner":                          "alice",
+	}
+	if !reflect.DeepEqual(entry.ReqHeader, expected) {
+		t.Fatalf("Expected request headers %v, got %v", expected, entry.ReqHeader)
+	}
+	if entry.ReqQuery["X-Amz-Signature"] != Redacted {
+		t.Fatalf("Expected the signature to be redacted, got %s", entry.ReqQuery["X-Amz-Signature"])
+	}
+	if entry.API.ETag != "5d41402abc4b2a76b9719d911017c592" || entry.API.VersionID != "v1" || entry.API.InputBytes != 5 {
+		t.Fatalf("Unexpected API details %+v", entry.API)
+	}
+}
+
+func TestToEntrySTS(t *testing.T) {
+	testCases := []struct {
+		query     string
+		sensitive []string
+	}{
+		{"Action=AssumeRoleWithLDAPIdentity&Version=2011-06-15&LDAPUsername=alice&LDAPPassword=secret",
+			[]string{"LDAPPassword"}},
+		{"Action=AssumeRoleWithWebIdentity&Version=2011-06-15&WebIdentityToken=eyJhbGciOiJSUzI1NiJ9",
+			[]string{"WebIdentityToken"}},
+		{"Action=AssumeRoleWithClientGrants&Version=2011-06-15&Token=eyJhbGciOiJSUzI1NiJ9",
+			[]string{"Token"}},
+		{"X-Amz-Credential=minio%2F20190812%2Fus-east-1%2Fsts%2Faws4_request&X-Amz-Security-Token=token&X-Amz-Signature=abcdef",
+			[]string{"X-Amz-Credential", "X-Amz-Security-Token", "X-Amz-Signature"}},
+	}
+
+	for i, testCase := range testCases {
+		r := httptest.NewRequest(http.MethodPost, "/?"+testCase.query, nil)
+		entry := ToEntry(httptest.NewRecorder(), r, nil, "", HeaderFilter{})
+		for _, key := range testCase.sensitive {
+			if entry.ReqQuery[key] != Redacted {
+				t.Fatalf("Test %d: expected %s to be redacted, got %s", i+1, key, entry.ReqQuery[key])
+			}
+		}
+	}
+
+	// The LDAP user name is not a secret.
+	r := httptest.NewRequest(http.MethodPost, "/?Action=AssumeRoleWithLDAPIdentity&LDAPUsername=alice&LDAPPassword=secret", nil)
+	entry := ToEntry(httptest.NewRecorder(), r, nil, "", HeaderFilter{})
+	if entry.ReqQuery["LDAPUsername"] != "alice" || entry.ReqQuery["Action"] != "AssumeRoleWithLDAPIdentity" {
+		t.Fatalf("Unexpected query %v", entry.ReqQuery)
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/cmd/logger/target/queue"
+)
+
+// Format of the time suffix of rotated files, sorts in time order.
+const rotateTimeFormat = "2006-01-02T15-04-05.000"
+
+// Args - arguments of a file logger target.
+type Args struct {
+	// Directory of the log files.
+	Dir string
+	// Name of the current log file, e.g. audit.log, rotated files
+	// are named audit-<time>.log.
+	Filename string
+	// The current file is rotated once it reaches MaxSize bytes,
+	// zero means it is never rotated.
+	MaxSize int64
+	// Number of rotated files kept, zero means all are kept.
+	MaxFiles int
+
+	// Queueing and batching of the log entries.
+	Queue queue.Config
+	// Called with the error of a failed write.
+	OnError func(error)
+}
+
+// Target implements logger.Target and appends the json format of
+// log entries, one per line, to a local file which is rotated once
+// it is too large.
+type Target struct {
+	*queue.Queue
+
+	args Args
+
+	// Only used by the delivery of the queue.
+	file *os.File
+	size int64
+}
+
+func (f *Target) path() string {
+	return filepath.Join(f.args.Dir, f.args.Filename)
+}
+
+func (f *Target) open() (err error) {
+	f.file, err = os.OpenFile(f.path(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.file.Stat()
+	if err != nil {
+		f.file.Close()
+		f.file = nil
+		return err
+	}
+	f.size = fi.Size()
+	return nil
+}
+
+// rotate - renames the current file and removes the oldest rotated
+// files exceeding MaxFiles.
+func (f *Target) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	ext := filepath.Ext(f.args.Filename)
+	prefix := strings.TrimSuffix(f.args.Filename, ext) + "-"
+	rotated := prefix + time.Now().UTC().Format(rotateTimeFormat) + ext
+	if err := os.Rename(f.path(), filepath.Join(f.args.Dir, rotated)); err != nil {
+		return err
+	}
+	if f.args.MaxFiles <= 0 {
+		return nil
+	}
+
+	entries, err := ioutil.ReadDir(f.args.Dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, entry := range entries {
+		if name := entry.Name(); strings.HasPrefix(name, prefix) && strings.HasSuffix(name, ext) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for len(names) > f.args.MaxFiles {
+		if err = os.Remove(filepath.Join(f.args.Dir, names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+func (f *Target) send(batch [][]byte) error {
+	if f.file != nil && f.args.MaxSize > 0 && f.size >= f.args.MaxSize {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return err
+		}
+	}
+
+	var buf []byte
+	for _, data := range batch {
+		buf = append(buf, data...)
+		buf = append(buf, '\n')
+	}
+	n, err := f.file.Write(buf)
+	f.size += int64(n)
+	if err != nil {
+		return err
+	}
+	// Entries are only removed from the queue once they are on disk.
+	return f.file.Sync()
+}
+
+// Close - stops the delivery and closes the current file.
+func (f *Target) Close() error {
+	f.Queue.Close()
+	if f.file != nil {
+		return f.file.Close()
+	}
+	return nil
+}
+
+// New initializes a new logger target which
+// appends logs to a local file
+func New(args Args) (*Target, error) {
+	if args.Dir == "" || args.Filename == "" {
+		return nil, errors.New("no log file found")
+	}
+	if err := os.MkdirAll(args.Dir, 0700); err != nil {
+		return nil, err
+	}
+
+	f := &Target{args: args}
+	q, err := queue.New(args.Queue, f.send, args.OnError)
+	if err != nil {
+		return nil, err
+	}
+	f.Queue = q
+	return f, nil
+}
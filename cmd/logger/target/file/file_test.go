@@ -0,0 +1,72 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package file
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileTarget(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minio-audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	target := &Target{args: Args{Dir: dir, Filename: "audit.log", MaxSize: 10, MaxFiles: 2}}
+	defer target.file.Close()
+
+	for i := 0; i < 4; i++ {
+		if err = target.send([][]byte{[]byte(`{"api":"PutObject"}`)}); err != nil {
+			t.Fatal(err)
+		}
+		// Rotated files are named by time.
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rotated int
+	for _, entry := range entries {
+		switch {
+		case entry.Name() == "audit.log":
+		case strings.HasPrefix(entry.Name(), "audit-") && strings.HasSuffix(entry.Name(), ".log"):
+			rotated++
+		default:
+			t.Fatalf("Unexpected file %s", entry.Name())
+		}
+	}
+	// Every entry exceeds the maximum size, only 2 rotated files are kept.
+	if rotated != 2 {
+		t.Fatalf("Expected 2 rotated files, got %d", rotated)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "{\"api\":\"PutObject\"}\n" {
+		t.Fatalf("Unexpected log file %q", data)
+	}
+}
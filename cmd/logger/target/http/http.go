@@ -18,86 +18,96 @@ package http
 
 import (
 	"bytes"
-	"encoding/json"
-	"errors"
-	"net/http"
+	"fmt"
 	gohttp "net/http"
+	"time"
 
 	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/cmd/logger/target/queue"
 )
 
-// Target implements logger.Target and sends the json
-// format of a log entry to the configured http endpoint.
-// An internal buffer of logs is maintained but when the
-// buffer is full, new logs are just ignored and an error
-// is returned to the caller.
-type Target struct {
-	// Channel of log entries
-	logCh chan interface{}
+// Timeout of a single request to the endpoint.
+const requestTimeout = 30 * time.Second
 
+// Args - arguments of a http logger target.
+type Args struct {
 	// HTTP(s) endpoint
-	endpoint string
+	Endpoint string
+	// Value of the Authorization header of each request, e.g.
+	// "Bearer <token>", no header is sent if empty.
+	AuthToken string
 	// User-Agent to be set on each log request sent to the `endpoint`
-	userAgent string
-	client    gohttp.Client
+	UserAgent string
+	Transport *gohttp.Transport
+
+	// Queueing and batching of the log entries.
+	Queue queue.Config
+	// Called with the error of a failed request.
+	OnError func(error)
+}
+
+// Target implements logger.Target and sends the json
+// format of log entries to the configured http endpoint.
+// Log entries are queued until the endpoint accepts them,
+// failed requests are retried. Entries are sent as JSON
+// objects, or as a JSON array if the batch size exceeds one.
+type Target struct {
+	*queue.Queue
+
+	args   Args
+	client gohttp.Client
 }
 
-func (h *Target) startHTTPLogger() {
-	// Create a routine which sends json logs received
-	// from an internal channel.
-	go func() {
-		for entry := range h.logCh {
-			logJSON, err := json.Marshal(&entry)
-			if err != nil {
-				continue
-			}
+func (h *Target) send(batch [][]byte) error {
+	var body []byte
+	if h.args.Queue.BatchSize <= 1 {
+		body = batch[0]
+	} else {
+		body = append([]byte("["), bytes.Join(batch, []byte(","))...)
+		body = append(body, ']')
+	}
+
+	req, err := gohttp.NewRequest(gohttp.MethodPost, h.args.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(xhttp.ContentType, "application/json")
 
-			req, err := gohttp.NewRequest(http.MethodPost, h.endpoint, bytes.NewBuffer(logJSON))
-			if err != nil {
-				continue
-			}
-			req.Header.Set(xhttp.ContentType, "application/json")
+	// Set user-agent to indicate MinIO release
+	// version to the configured log endpoint
+	req.Header.Set("User-Agent", h.args.UserAgent)
+	if h.args.AuthToken != "" {
+		req.Header.Set("Authorization", h.args.AuthToken)
+	}
 
-			// Set user-agent to indicate MinIO release
-			// version to the configured log endpoint
-			req.Header.Set("User-Agent", h.userAgent)
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Unable to send %d log entries to %s: %v", len(batch), h.args.Endpoint, err)
+	}
 
-			resp, err := h.client.Do(req)
-			if err != nil {
-				continue
-			}
+	// Drain any response.
+	xhttp.DrainBody(resp.Body)
 
-			// Drain any response.
-			xhttp.DrainBody(resp.Body)
-		}
-	}()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Unable to send %d log entries to %s: %s", len(batch), h.args.Endpoint, resp.Status)
+	}
+	return nil
 }
 
 // New initializes a new logger target which
 // sends log over http to the specified endpoint
-func New(endpoint, userAgent string, transport *gohttp.Transport) *Target {
-	h := Target{
-		endpoint:  endpoint,
-		userAgent: userAgent,
+func New(args Args) (*Target, error) {
+	h := &Target{
+		args: args,
 		client: gohttp.Client{
-			Transport: transport,
+			Transport: args.Transport,
+			Timeout:   requestTimeout,
 		},
-		logCh: make(chan interface{}, 10000),
 	}
-
-	h.startHTTPLogger()
-	return &h
-}
-
-// Send log message 'e' to http target.
-func (h *Target) Send(entry interface{}) error {
-	select {
-	case h.logCh <- entry:
-	default:
-		// log channel is full, do not wait and return
-		// an error immediately to the caller
-		return errors.New("log buffer full")
+	q, err := queue.New(args.Queue, h.send, args.OnError)
+	if err != nil {
+		return nil, err
 	}
-
-	return nil
+	h.Queue = q
+	return h, nil
 }
@@ -0,0 +1,80 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package http
+
+import (
+	"encoding/json"
+	gohttp "net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/logger/target/queue"
+)
+
+func TestHTTPTarget(t *testing.T) {
+	requests := make(chan []map[string]string, 10)
+	var failed int32
+	server := httptest.NewServer(gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(gohttp.StatusForbidden)
+			return
+		}
+		// The first request fails and has to be retried.
+		if atomic.CompareAndSwapInt32(&failed, 0, 1) {
+			w.WriteHeader(gohttp.StatusServiceUnavailable)
+			return
+		}
+		var entries []map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+			w.WriteHeader(gohttp.StatusBadRequest)
+			return
+		}
+		requests <- entries
+	}))
+	defer server.Close()
+
+	target, err := New(Args{
+		Endpoint:  server.URL,
+		AuthToken: "Bearer secret",
+		UserAgent: "MinIO",
+		Transport: &gohttp.Transport{},
+		Queue:     queue.Config{BatchSize: 2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer target.Close()
+
+	for _, api := range []string{"PutObject", "GetObject"} {
+		if err = target.Send(map[string]string{"api": api}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case entries := <-requests:
+		expected := []map[string]string{{"api": "PutObject"}, {"api": "GetObject"}}
+		if !reflect.DeepEqual(entries, expected) {
+			t.Fatalf("Expected %v, got %v", expected, entries)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the batch")
+	}
+}
@@ -0,0 +1,136 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/minio/minio/cmd/logger/target/queue"
+	sarama "gopkg.in/Shopify/sarama.v1"
+)
+
+// Args - arguments of a Kafka logger target.
+type Args struct {
+	Brokers []string
+	Topic   string
+	TLS     struct {
+		Enable     bool
+		RootCAs    *x509.CertPool
+		SkipVerify bool
+	}
+	SASL struct {
+		Enable   bool
+		User     string
+		Password string
+	}
+
+	// Queueing and batching of the log entries.
+	Queue queue.Config
+	// Called with the error of a failed delivery.
+	OnError func(error)
+}
+
+// Validate - checks the arguments of the target.
+func (args Args) Validate() error {
+	if len(args.Brokers) == 0 {
+		return errors.New("no broker address found")
+	}
+	if args.Topic == "" {
+		return errors.New("no topic found")
+	}
+	return nil
+}
+
+// Target implements logger.Target and publishes the json format
+// of log entries to a Kafka topic. Log entries are queued until
+// the brokers acknowledge them, batches are published at once.
+type Target struct {
+	*queue.Queue
+
+	args   Args
+	config *sarama.Config
+
+	// Connected lazily, the brokers may not be up yet. Only used
+	// by the delivery of the queue.
+	producer sarama.SyncProducer
+}
+
+func (k *Target) send(batch [][]byte) (err error) {
+	if k.producer == nil {
+		if k.producer, err = sarama.NewSyncProducer(k.args.Brokers, k.config); err != nil {
+			k.producer = nil
+			return fmt.Errorf("Unable to connect to Kafka brokers %v: %v", k.args.Brokers, err)
+		}
+	}
+
+	msgs := make([]*sarama.ProducerMessage, 0, len(batch))
+	for _, data := range batch {
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic: k.args.Topic,
+			Value: sarama.ByteEncoder(data),
+		})
+	}
+	if err = k.producer.SendMessages(msgs); err != nil {
+		// Reconnect on the next attempt, e.g. if the circuit
+		// breaker of the producer is open.
+		k.producer.Close()
+		k.producer = nil
+		return fmt.Errorf("Unable to send %d log entries to Kafka topic %s: %v", len(batch), k.args.Topic, err)
+	}
+	return nil
+}
+
+// Close - stops the delivery and closes the connection to the brokers.
+func (k *Target) Close() error {
+	k.Queue.Close()
+	if k.producer != nil {
+		return k.producer.Close()
+	}
+	return nil
+}
+
+// New initializes a new logger target which
+// publishes logs to a Kafka topic
+func New(args Args) (*Target, error) {
+	if err := args.Validate(); err != nil {
+		return nil, err
+	}
+
+	config := sarama.NewConfig()
+	config.Net.SASL.Enable = args.SASL.Enable
+	config.Net.SASL.User = args.SASL.User
+	config.Net.SASL.Password = args.SASL.Password
+	config.Net.TLS.Enable = args.TLS.Enable
+	config.Net.TLS.Config = &tls.Config{
+		InsecureSkipVerify: args.TLS.SkipVerify,
+		RootCAs:            args.TLS.RootCAs,
+	}
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Retry.Max = 10
+	config.Producer.Return.Successes = true
+
+	k := &Target{args: args, config: config}
+	q, err := queue.New(args.Queue, k.send, args.OnError)
+	if err != nil {
+		return nil, err
+	}
+	k.Queue = q
+	return k, nil
+}
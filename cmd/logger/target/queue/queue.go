@@ -0,0 +1,202 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package queue implements the queueing, batching and retrying of log
+// entries shared by the logger targets.
+package queue
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const (
+	// Time waited for a partial batch to fill up.
+	batchInterval = time.Second
+
+	// Failed batches are retried with exponential backoff.
+	minRetryInterval = time.Second
+	maxRetryInterval = time.Minute
+)
+
+// Config - queueing and batching of a target.
+type Config struct {
+	// Directory in which undelivered entries are kept, entries are
+	// kept in memory if empty.
+	Dir string
+
+	// Maximum number of queued entries, zero means no limit for a
+	// queue directory and 10000 entries in memory.
+	Limit uint64
+
+	// Maximum number of entries sent at once, defaults to one.
+	BatchSize int
+}
+
+// SendFunc - delivers a batch of JSON encoded entries, the batch is
+// sent again if an error is returned.
+type SendFunc func(batch [][]byte) error
+
+// Queue - queues the log entries of a target and delivers them in
+// order and in batches, failed batches are retried until they are
+// delivered.
+type Queue struct {
+	store     Store
+	batchSize int
+	send      SendFunc
+	// Called with the error of a failed batch.
+	onError func(error)
+
+	notifyCh  chan struct{}
+	doneCh    chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// New - returns a queue delivering its entries with send. Entries of
+// a previous run found in the queue directory are delivered first.
+func New(cfg Config, send SendFunc, onError func(error)) (*Queue, error) {
+	var store Store
+	if cfg.Dir != "" {
+		diskStore, err := NewDiskStore(cfg.Dir, cfg.Limit)
+		if err != nil {
+			return nil, err
+		}
+		store = diskStore
+	} else {
+		store = newMemStore(cfg.Limit)
+	}
+	return newQueue(store, cfg.BatchSize, send, onError), nil
+}
+
+func newQueue(store Store, batchSize int, send SendFunc, onError func(error)) *Queue {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	q := &Queue{
+		store:     store,
+		batchSize: batchSize,
+		send:      send,
+		onError:   onError,
+		notifyCh:  make(chan struct{}, 1),
+		doneCh:    make(chan struct{}),
+	}
+	q.wg.Add(1)
+	go q.run()
+	return q
+}
+
+// Send - queues the JSON encoding of entry, an error is returned if
+// the entry could not be queued, e.g. as the queue is full.
+func (q *Queue) Send(entry interface{}) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err = q.store.Put(data); err != nil {
+		return err
+	}
+	select {
+	case q.notifyCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Close - stops the delivery, undelivered entries are kept in the
+// queue directory.
+func (q *Queue) Close() {
+	q.closeOnce.Do(func() { close(q.doneCh) })
+	q.wg.Wait()
+}
+
+func (q *Queue) run() {
+	defer q.wg.Done()
+
+	retryInterval := minRetryInterval
+	// Time at which a partial batch is sent.
+	var deadline time.Time
+	for {
+		keys := q.store.List(q.batchSize)
+		if len(keys) == 0 {
+			deadline = time.Time{}
+			select {
+			case <-q.notifyCh:
+				continue
+			case <-q.doneCh:
+				return
+			}
+		}
+		if len(keys) < q.batchSize {
+			if deadline.IsZero() {
+				deadline = time.Now().Add(batchInterval)
+			}
+			if wait := time.Until(deadline); wait > 0 {
+				select {
+				case <-q.notifyCh:
+				case <-time.After(wait):
+				case <-q.doneCh:
+					return
+				}
+				continue
+			}
+		}
+		deadline = time.Time{}
+
+		if err := q.deliver(keys); err != nil {
+			if q.onError != nil {
+				q.onError(err)
+			}
+			select {
+			case <-time.After(retryInterval):
+			case <-q.doneCh:
+				return
+			}
+			if retryInterval *= 2; retryInterval > maxRetryInterval {
+				retryInterval = maxRetryInterval
+			}
+			continue
+		}
+		retryInterval = minRetryInterval
+	}
+}
+
+// deliver - sends the entries with keys and removes them from the
+// store once they are delivered.
+func (q *Queue) deliver(keys []string) error {
+	batch := make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		data, err := q.store.Get(key)
+		if err != nil {
+			// Unreadable entries would block the queue forever.
+			q.store.Del(key)
+			continue
+		}
+		batch = append(batch, data)
+	}
+	if len(batch) > 0 {
+		if err := q.send(batch); err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		if err := q.store.Del(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package queue
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Records the delivered batches, the first failures batches fail.
+type testSender struct {
+	mu       sync.Mutex
+	failures int
+	batches  [][]string
+	done     chan struct{}
+	expected int
+}
+
+func (s *testSender) send(batch [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("target is down")
+	}
+	var entries []string
+	for _, data := range batch {
+		entries = append(entries, string(data))
+	}
+	s.batches = append(s.batches, entries)
+	if s.expected -= len(entries); s.expected == 0 {
+		close(s.done)
+	}
+	return nil
+}
+
+func (s *testSender) wait(t *testing.T) {
+	select {
+	case <-s.done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the delivery")
+	}
+}
+
+func TestQueueRetry(t *testing.T) {
+	sender := &testSender{failures: 2, done: make(chan struct{}), expected: 3}
+	var errCount int
+	q := newQueue(newMemStore(0), 2, sender.send, func(error) { errCount++ })
+	defer q.Close()
+
+	for _, entry := range []string{"a", "b", "c"} {
+		if err := q.Send(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sender.wait(t)
+
+	// Entries are delivered in order, failed batches are retried.
+	expected := [][]string{{`"a"`, `"b"`}, {`"c"`}}
+	if !reflect.DeepEqual(sender.batches, expected) {
+		t.Fatalf("Expected %v, got %v", expected, sender.batches)
+	}
+	if errCount != 2 {
+		t.Fatalf("Expected 2 failed deliveries, got %d", errCount)
+	}
+}
+
+func TestQueueLimit(t *testing.T) {
+	sender := &testSender{failures: 1000, done: make(chan struct{})}
+	q := newQueue(newMemStore(2), 1, sender.send, nil)
+	defer q.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := q.Send(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Send(2); err != errLimitExceeded {
+		t.Fatalf("Expected %v, got %v", errLimitExceeded, err)
+	}
+}
+
+func TestDiskStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minio-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Entries are kept until they are delivered.
+	sender := &testSender{failures: 1000, done: make(chan struct{})}
+	q, err := New(Config{Dir: dir}, sender.send, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range []string{"a", "b"} {
+		if err = q.Send(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Close()
+
+	// Partial writes of a crash are ignored.
+	if err = ioutil.WriteFile(dir+"/00000000000000000003.tmp", []byte(`"c`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	sender = &testSender{done: make(chan struct{}), expected: 3}
+	q, err = New(Config{Dir: dir, BatchSize: 10}, sender.send, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer q.Close()
+	if err = q.Send("d"); err != nil {
+		t.Fatal(err)
+	}
+	sender.wait(t)
+
+	var entries []string
+	for _, batch := range sender.batches {
+		entries = append(entries, batch...)
+	}
+	if expected := []string{`"a"`, `"b"`, `"d"`}; !reflect.DeepEqual(entries, expected) {
+		t.Fatalf("Expected %v, got %v", expected, entries)
+	}
+}
@@ -0,0 +1,240 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// Entries kept in memory if no queue directory is configured.
+	defaultMemLimit = 10000
+
+	logExt = ".log"
+)
+
+// errLimitExceeded - returned when the queue is full.
+var errLimitExceeded = errors.New("the maximum queue limit reached")
+
+// Store - persists log entries until they are delivered.
+type Store interface {
+	// Put - adds an entry at the end of the queue.
+	Put(data []byte) error
+	// Get - returns the entry with key.
+	Get(key string) ([]byte, error)
+	// Del - removes a delivered entry.
+	Del(key string) error
+	// List - returns the keys of up to n entries, oldest first.
+	List(n int) []string
+}
+
+// memStore - keeps the entries in memory, they are lost on restart.
+type memStore struct {
+	mu      sync.Mutex
+	limit   uint64
+	seq     uint64
+	keys    []string
+	entries map[string][]byte
+}
+
+func newMemStore(limit uint64) *memStore {
+	if limit == 0 {
+		limit = defaultMemLimit
+	}
+	return &memStore{limit: limit, entries: make(map[string][]byte)}
+}
+
+func (s *memStore) Put(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if uint64(len(s.keys)) >= s.limit {
+		return errLimitExceeded
+	}
+	s.seq++
+	key := strconv.FormatUint(s.seq, 10)
+	s.keys = append(s.keys, key)
+	s.entries[key] = data
+	return nil
+}
+
+func (s *memStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.entries[key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return data, nil
+}
+
+func (s *memStore) Del(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[key]; !ok {
+		return nil
+	}
+	delete(s.entries, key)
+	s.keys = removeKey(s.keys, key)
+	return nil
+}
+
+func (s *memStore) List(n int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return firstKeys(s.keys, n)
+}
+
+// DiskStore - keeps every entry in a file of a directory until it is
+// delivered, entries survive restarts of the server.
+type DiskStore struct {
+	mu        sync.Mutex
+	directory string
+	limit     uint64
+	seq       uint64
+	keys      []string
+}
+
+// NewDiskStore - returns a store queueing up to limit entries in
+// directory, a limit of zero means no limit. The entries of a
+// previous run are queued first.
+func NewDiskStore(directory string, limit uint64) (*DiskStore, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+	s := &DiskStore{directory: directory, limit: limit}
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".tmp") {
+			// Partial write of a crashed server.
+			os.Remove(filepath.Join(directory, file.Name()))
+			continue
+		}
+		key := strings.TrimSuffix(file.Name(), logExt)
+		seq, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || file.IsDir() || !strings.HasSuffix(file.Name(), logExt) {
+			continue
+		}
+		if seq > s.seq {
+			s.seq = seq
+		}
+		s.keys = append(s.keys, key)
+	}
+	// Keys are zero padded, their order is the order of the entries.
+	sort.Strings(s.keys)
+	return s, nil
+}
+
+func (s *DiskStore) path(key string) string {
+	return filepath.Join(s.directory, key+logExt)
+}
+
+// Put - writes the entry to a new file.
+func (s *DiskStore) Put(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limit > 0 && uint64(len(s.keys)) >= s.limit {
+		return errLimitExceeded
+	}
+	key := fmt.Sprintf("%020d", s.seq+1)
+
+	// Write to a temporary file first, a crash must not leave a
+	// partial entry behind.
+	tmpPath := filepath.Join(s.directory, key+".tmp")
+	if err := writeFileSync(tmpPath, data); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path(key)); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	// The entry is only durable once the rename is.
+	if err := syncDir(s.directory); err != nil {
+		return err
+	}
+	s.seq++
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+// writeFileSync - writes data to a new file and flushes it to the disk.
+func writeFileSync(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Get - reads the entry with key.
+func (s *DiskStore) Get(key string) ([]byte, error) {
+	return ioutil.ReadFile(s.path(key))
+}
+
+// Del - removes the file of a delivered entry.
+func (s *DiskStore) Del(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	s.keys = removeKey(s.keys, key)
+	return nil
+}
+
+// List - returns the keys of up to n entries, oldest first.
+func (s *DiskStore) List(n int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return firstKeys(s.keys, n)
+}
+
+func firstKeys(keys []string, n int) []string {
+	if n > len(keys) {
+		n = len(keys)
+	}
+	return append([]string(nil), keys[:n]...)
+}
+
+// Entries are delivered in order, the key is usually the first one.
+func removeKey(keys []string, key string) []string {
+	for i := range keys {
+		if keys[i] == key {
+			return append(keys[:i], keys[i+1:]...)
+		}
+	}
+	return keys
+}
@@ -24,7 +24,8 @@ import (
 )
 
 const (
-	prometheusMetricsPath = "/prometheus/metrics"
+	prometheusMetricsPath        = "/prometheus/metrics"
+	prometheusClusterMetricsPath = "/prometheus/cluster"
 )
 
 // Standard env prometheus auth type
@@ -48,7 +49,9 @@ func registerMetricsRouter(router *mux.Router) {
 	switch prometheusAuthType(authType) {
 	case prometheusPublic:
 		metricsRouter.Handle(prometheusMetricsPath, metricsHandler())
+		metricsRouter.Handle(prometheusClusterMetricsPath, clusterMetricsHandler())
 	default:
 		metricsRouter.Handle(prometheusMetricsPath, AuthMiddleware(metricsHandler()))
+		metricsRouter.Handle(prometheusClusterMetricsPath, AuthMiddleware(clusterMetricsHandler()))
 	}
 }
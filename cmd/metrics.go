@@ -19,8 +19,11 @@ package cmd
 import (
 	"context"
 	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
@@ -101,6 +104,60 @@ func (c *minioCollector) Collect(ch chan<- prometheus.Metric) {
 		float64(globalConnStats.getTotalInputBytes()),
 	)
 
+	// S3 API stats of current MinIO server instance
+	collectAPIMetrics(ch, globalAPIStats.stats(), nil)
+
+	// Delivery state of the notification targets
+	collectNotificationMetrics(ch)
+
+	// Server access log records dropped as delivery could not keep up
+	if globalAccessLogger != nil {
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "access_log", "dropped_records_total"),
+				"Total number of server access log records dropped by current MinIO server instance",
+				nil, nil),
+			prometheus.CounterValue,
+			float64(globalAccessLogger.Dropped()),
+		)
+	}
+
+	// Expose bitrot scrubber stats only if enabled
+	if globalScrubberConfig.Enabled {
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "scrubber", "objects_scanned_total"),
+				"Total number of objects verified by the bitrot scrubber of current MinIO server instance",
+				nil, nil),
+			prometheus.CounterValue,
+			float64(atomic.LoadUint64(&globalScrubberStats.objectsScanned)),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "scrubber", "bytes_verified_total"),
+				"Total number of bytes verified by the bitrot scrubber of current MinIO server instance",
+				nil, nil),
+			prometheus.CounterValue,
+			float64(atomic.LoadUint64(&globalScrubberStats.bytesVerified)),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "scrubber", "corrupted_shards_total"),
+				"Total number of corrupted or missing shards found by the bitrot scrubber of current MinIO server instance",
+				nil, nil),
+			prometheus.CounterValue,
+			float64(atomic.LoadUint64(&globalScrubberStats.corruptedShards)),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "scrubber", "repaired_shards_total"),
+				"Total number of shards repaired by the bitrot scrubber of current MinIO server instance",
+				nil, nil),
+			prometheus.CounterValue,
+			float64(atomic.LoadUint64(&globalScrubberStats.repairedShards)),
+		)
+	}
+
 	// Expose cache stats only if available
 	cacheObjLayer := newCacheObjectsFn()
 	if cacheObjLayer != nil {
@@ -132,6 +189,8 @@ func (c *minioCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
+	collectBucketUsageMetrics(ch)
+
 	s := objLayer.StorageInfo(context.Background())
 
 	// Gateways don't provide disk info
@@ -196,6 +255,199 @@ func (c *minioCollector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(offlineDisks),
 	)
+
+	// Health of the drives as seen by current MinIO server instance
+	for _, set := range s.Backend.Sets {
+		for _, drive := range set {
+			if drive.UUID == "" {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(
+				prometheus.NewDesc(
+					prometheus.BuildFQName("minio", "disk", "latency_seconds"),
+					"Average latency of recent requests to the disk from current MinIO server instance",
+					[]string{"disk"}, nil),
+				prometheus.GaugeValue,
+				drive.Latency.Seconds(),
+				drive.Endpoint,
+			)
+			ch <- prometheus.MustNewConstMetric(
+				prometheus.NewDesc(
+					prometheus.BuildFQName("minio", "disk", "error_rate"),
+					"Fraction of recent requests to the disk from current MinIO server instance which failed",
+					[]string{"disk"}, nil),
+				prometheus.GaugeValue,
+				drive.ErrorRate,
+				drive.Endpoint,
+			)
+			var excluded float64
+			if drive.State == madmin.DriveStateExcluded {
+				excluded = 1
+			}
+			ch <- prometheus.MustNewConstMetric(
+				prometheus.NewDesc(
+					prometheus.BuildFQName("minio", "disk", "excluded"),
+					"Whether the disk is excluded as slow or faulty by current MinIO server instance",
+					[]string{"disk"}, nil),
+				prometheus.GaugeValue,
+				excluded,
+				drive.Endpoint,
+			)
+		}
+	}
+}
+
+// collectAPIMetrics - exposes S3 API requests, latencies and
+// errors by API and bucket, labeled with the given labels.
+func collectAPIMetrics(ch chan<- prometheus.Metric, stats APIStats, labels prometheus.Labels) {
+	for _, r := range stats.Requests {
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "s3", "requests_total"),
+				"Total number of S3 requests by API and bucket",
+				[]string{"api", "bucket"}, labels),
+			prometheus.CounterValue,
+			float64(r.Count),
+			r.API, r.Bucket,
+		)
+
+		// Histogram buckets are cumulative.
+		buckets := make(map[float64]uint64, len(apiDurationBuckets))
+		var count uint64
+		for i, bound := range apiDurationBuckets {
+			if i < len(r.Buckets) {
+				count += r.Buckets[i]
+			}
+			buckets[bound] = count
+		}
+		ch <- prometheus.MustNewConstHistogram(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "s3", "requests_duration_seconds"),
+				"Time taken by S3 requests by API and bucket",
+				[]string{"api", "bucket"}, labels),
+			r.Count,
+			r.Duration,
+			buckets,
+			r.API, r.Bucket,
+		)
+	}
+	for _, e := range stats.Errors {
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "s3", "errors_total"),
+				"Total number of failed S3 requests by API, bucket and S3 error code",
+				[]string{"api", "bucket", "code"}, labels),
+			prometheus.CounterValue,
+			float64(e.Count),
+			e.API, e.Bucket, e.Code,
+		)
+	}
+}
+
+// collectNotificationMetrics - exposes the online state and the queued
+// events of the notification targets of current MinIO server instance.
+func collectNotificationMetrics(ch chan<- prometheus.Metric) {
+	for _, info := range localNotificationTargets() {
+		online := 0.0
+		if info.Online {
+			online = 1
+		}
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "notify", "target_online"),
+				"Whether the last delivery to the notification target succeeded",
+				[]string{"target"}, nil),
+			prometheus.GaugeValue,
+			online,
+			info.ARN,
+		)
+		if !info.Queued {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "notify", "queue_length"),
+				"Number of undelivered events in the queue directory of the notification target",
+				[]string{"target"}, nil),
+			prometheus.GaugeValue,
+			float64(info.QueueLength),
+			info.ARN,
+		)
+		var age float64
+		if !info.OldestEvent.IsZero() {
+			age = time.Since(info.OldestEvent).Seconds()
+		}
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "notify", "queue_oldest_event_age_seconds"),
+				"Age of the oldest undelivered event in the queue directory of the notification target",
+				[]string{"target"}, nil),
+			prometheus.GaugeValue,
+			age,
+			info.ARN,
+		)
+	}
+}
+
+// collectBucketUsageMetrics - exposes the number of objects and
+// bytes of all buckets as of the last usage crawl.
+func collectBucketUsageMetrics(ch chan<- prometheus.Metric) {
+	for bucket, usage := range globalBucketUsage.get() {
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "bucket", "objects_count"),
+				"Total number of objects in the bucket",
+				[]string{"bucket"}, nil),
+			prometheus.GaugeValue,
+			float64(usage.ObjectsCount),
+			bucket,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName("minio", "bucket", "usage_bytes"),
+				"Total number of bytes stored in the bucket",
+				[]string{"bucket"}, nil),
+			prometheus.GaugeValue,
+			float64(usage.Size),
+			bucket,
+		)
+	}
+}
+
+// newClusterCollector describes the collector of the metrics
+// of all servers of the cluster.
+func newClusterCollector() *clusterCollector {
+	return &clusterCollector{
+		desc: prometheus.NewDesc("minio_cluster_stats", "Statistics exposed by all MinIO servers of the cluster", nil, nil),
+	}
+}
+
+// clusterCollector collects the metrics of all servers of the
+// cluster from their peers.
+type clusterCollector struct {
+	desc *prometheus.Desc
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+func (c *clusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect is called by the Prometheus registry when collecting metrics.
+func (c *clusterCollector) Collect(ch chan<- prometheus.Metric) {
+	// Counters are exposed per server, a sum over the servers
+	// would decrease whenever a server is unreachable.
+	collectAPIMetrics(ch, globalAPIStats.stats(), prometheus.Labels{"node": GetLocalPeer(globalEndpoints)})
+	if globalNotificationSys != nil {
+		for node, stats := range globalNotificationSys.APIStats(context.Background()) {
+			collectAPIMetrics(ch, stats, prometheus.Labels{"node": node})
+		}
+	}
+
+	// Bucket usage is computed for the whole cluster.
+	if newObjectLayerFn() != nil {
+		collectBucketUsageMetrics(ch)
+	}
 }
 
 func metricsHandler() http.Handler {
@@ -226,6 +478,23 @@ func metricsHandler() http.Handler {
 
 }
 
+// clusterMetricsHandler - serves the metrics of all servers,
+// so that one scrape covers the whole cluster.
+func clusterMetricsHandler() http.Handler {
+	registry := prometheus.NewRegistry()
+
+	err := registry.Register(newClusterCollector())
+	logger.LogIf(context.Background(), err)
+
+	return promhttp.InstrumentMetricHandler(
+		registry,
+		promhttp.HandlerFor(registry,
+			promhttp.HandlerOpts{
+				ErrorHandling: promhttp.ContinueOnError,
+			}),
+	)
+}
+
 // AuthMiddleware checks if the bearer token is valid and authorized.
 func AuthMiddleware(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -32,6 +32,7 @@ import (
 	"github.com/minio/minio-go/v6/pkg/set"
 	"github.com/minio/minio/cmd/logger"
 	xnet "github.com/minio/minio/pkg/net"
+	"github.com/minio/minio/pkg/trace"
 )
 
 // Global name space lock.
@@ -250,12 +251,17 @@ func (n *nsLockMap) ForceUnlock(volume, path string) {
 
 // dsync's distributed lock instance.
 type distLockInstance struct {
+	ctx                 context.Context
 	rwMutex             *dsync.DRWMutex
 	volume, path, opsID string
 }
 
 // Lock - block until write lock is taken or timeout has occurred.
 func (di *distLockInstance) GetLock(timeout *dynamicTimeout) (timedOutErr error) {
+	span := globalLockSpans.start(di.ctx, di.opsID, "GetLock", pathJoin(di.volume, di.path))
+	defer func() { globalLockSpans.finish(di.opsID, span, timedOutErr) }()
+	defer publishLockTrace("GetLock", lockTraceStart(), di.volume, di.path, &timedOutErr)
+
 	lockSource := getSource()
 	start := UTCNow()
 
@@ -269,11 +275,18 @@ func (di *distLockInstance) GetLock(timeout *dynamicTimeout) (timedOutErr error)
 
 // Unlock - block until write lock is released.
 func (di *distLockInstance) Unlock() {
+	span := globalLockSpans.start(di.ctx, di.opsID, "Unlock", pathJoin(di.volume, di.path))
+	defer publishLockTrace("Unlock", lockTraceStart(), di.volume, di.path, nil)
 	di.rwMutex.Unlock()
+	globalLockSpans.finish(di.opsID, span, nil)
 }
 
 // RLock - block until read lock is taken or timeout has occurred.
 func (di *distLockInstance) GetRLock(timeout *dynamicTimeout) (timedOutErr error) {
+	span := globalLockSpans.start(di.ctx, di.opsID, "GetRLock", pathJoin(di.volume, di.path))
+	defer func() { globalLockSpans.finish(di.opsID, span, timedOutErr) }()
+	defer publishLockTrace("GetRLock", lockTraceStart(), di.volume, di.path, &timedOutErr)
+
 	lockSource := getSource()
 	start := UTCNow()
 	if !di.rwMutex.GetRLock(di.opsID, lockSource, timeout.Timeout()) {
@@ -286,7 +299,10 @@ func (di *distLockInstance) GetRLock(timeout *dynamicTimeout) (timedOutErr error
 
 // RUnlock - block until read lock is released.
 func (di *distLockInstance) RUnlock() {
+	span := globalLockSpans.start(di.ctx, di.opsID, "RUnlock", pathJoin(di.volume, di.path))
+	defer publishLockTrace("RUnlock", lockTraceStart(), di.volume, di.path, nil)
 	di.rwMutex.RUnlock()
+	globalLockSpans.finish(di.opsID, span, nil)
 }
 
 // localLockInstance - frontend/top-level interface for namespace locks.
@@ -302,13 +318,15 @@ type localLockInstance struct {
 func (n *nsLockMap) NewNSLock(ctx context.Context, volume, path string) RWLocker {
 	opsID := mustGetUUID()
 	if n.isDistXL {
-		return &distLockInstance{dsync.NewDRWMutex(ctx, pathJoin(volume, path), globalDsync), volume, path, opsID}
+		return &distLockInstance{ctx, dsync.NewDRWMutex(ctx, pathJoin(volume, path), globalDsync), volume, path, opsID}
 	}
 	return &localLockInstance{ctx, n, volume, path, opsID}
 }
 
 // Lock - block until write lock is taken or timeout has occurred.
 func (li *localLockInstance) GetLock(timeout *dynamicTimeout) (timedOutErr error) {
+	defer publishLockTrace("GetLock", lockTraceStart(), li.volume, li.path, &timedOutErr)
+
 	lockSource := getSource()
 	start := UTCNow()
 	readLock := false
@@ -322,12 +340,15 @@ func (li *localLockInstance) GetLock(timeout *dynamicTimeout) (timedOutErr error
 
 // Unlock - block until write lock is released.
 func (li *localLockInstance) Unlock() {
+	defer publishLockTrace("Unlock", lockTraceStart(), li.volume, li.path, nil)
 	readLock := false
 	li.ns.unlock(li.volume, li.path, li.opsID, readLock)
 }
 
 // RLock - block until read lock is taken or timeout has occurred.
 func (li *localLockInstance) GetRLock(timeout *dynamicTimeout) (timedOutErr error) {
+	defer publishLockTrace("GetRLock", lockTraceStart(), li.volume, li.path, &timedOutErr)
+
 	lockSource := getSource()
 	start := UTCNow()
 	readLock := true
@@ -341,10 +362,24 @@ func (li *localLockInstance) GetRLock(timeout *dynamicTimeout) (timedOutErr erro
 
 // RUnlock - block until read lock is released.
 func (li *localLockInstance) RUnlock() {
+	defer publishLockTrace("RUnlock", lockTraceStart(), li.volume, li.path, nil)
 	readLock := true
 	li.ns.unlock(li.volume, li.path, li.opsID, readLock)
 }
 
+// publishLockTrace - publishes the trace record of a lock call started
+// at start, errp points to the error of the call if it may fail.
+func publishLockTrace(call string, start time.Time, volume, path string, errp *error) {
+	if start.IsZero() {
+		return
+	}
+	var err error
+	if errp != nil {
+		err = *errp
+	}
+	globalHTTPTrace.Publish(newCallTrace(trace.Lock, "lock."+call, "", pathJoin(volume, path), start, err))
+}
+
 func getSource() string {
 	var funcName string
 	pc, filename, lineNum, ok := runtime.Caller(2)
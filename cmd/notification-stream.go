@@ -0,0 +1,576 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/event"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// Number of recent events every server keeps for listeners
+	// resuming from a cursor or falling behind.
+	listenHistorySize = 1000
+
+	// Number of events read from the history at once.
+	listenBatchSize = 100
+
+	// Interval of keep-alives and position updates of listeners.
+	listenKeepAliveInterval = time.Second
+
+	// Events are kept for this long after the last listener
+	// has gone, for listeners to reconnect without losing events.
+	listenResumeWindow = time.Minute
+)
+
+// Query parameters of the listen stream requests of clients.
+const (
+	listenStream          = "stream"
+	listenStreamSSE       = "sse"
+	listenStreamWebSocket = "websocket"
+	listenStreamCursor    = "cursor"
+)
+
+var (
+	errInvalidListenCursor = errors.New("invalid listen cursor")
+	errListenerGone        = errors.New("listener has gone away")
+)
+
+// Events of the stream of a listener not selecting any event names.
+var listenAllEventNames = []event.Name{
+	event.ObjectAccessedAll,
+	event.ObjectCreatedAll,
+	event.ObjectRemovedAll,
+	event.BucketCreated,
+	event.BucketRemoved,
+	event.LifecycleExpirationAll,
+	event.ObjectHealedAll,
+	event.MultipartUploadAll,
+}
+
+// listenPosition - the position of a listener in the event stream of a server.
+type listenPosition struct {
+	// Start time of the stream, positions in the streams of
+	// previous runs of the server are void.
+	Epoch int64
+	// Sequence number of the last event processed.
+	Seq uint64
+}
+
+// listenEvent - an event of the stream of a server. Events without
+// event data only report the position reached by the listener.
+type listenEvent struct {
+	Node  string
+	Pos   listenPosition
+	Event *event.Event
+	// Number of events lost before this one as the listener
+	// fell behind the history kept by the server.
+	Missed uint64
+}
+
+// listenCursor - the positions of a listener in the event streams of all servers.
+type listenCursor map[string]listenPosition
+
+// String - encodes the cursor as an opaque URL safe string.
+func (cursor listenCursor) String() string {
+	nodes := make([]string, 0, len(cursor))
+	for node := range cursor {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	var s strings.Builder
+	for i, node := range nodes {
+		if i > 0 {
+			s.WriteByte(',')
+		}
+		pos := cursor[node]
+		fmt.Fprintf(&s, "%s=%d.%d", node, pos.Epoch, pos.Seq)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(s.String()))
+}
+
+// parseListenCursor - parses a cursor encoded by listenCursor.String().
+func parseListenCursor(s string) (listenCursor, error) {
+	cursor := make(listenCursor)
+	if s == "" {
+		return cursor, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidListenCursor
+	}
+	for _, entry := range strings.Split(string(data), ",") {
+		i := strings.LastIndex(entry, "=")
+		if i <= 0 {
+			return nil, errInvalidListenCursor
+		}
+		fields := strings.SplitN(entry[i+1:], ".", 2)
+		if len(fields) != 2 {
+			return nil, errInvalidListenCursor
+		}
+		var pos listenPosition
+		if pos.Epoch, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+			return nil, errInvalidListenCursor
+		}
+		if pos.Seq, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+			return nil, errInvalidListenCursor
+		}
+		cursor[entry[:i]] = pos
+	}
+	return cursor, nil
+}
+
+// listenOptions - selects the events sent to a listener.
+type listenOptions struct {
+	// Events of all buckets are sent if bucket is empty.
+	bucket         string
+	prefix, suffix string
+	eventNames     []event.Name
+	rulesMap       event.RulesMap
+}
+
+// Identifies the rules of a listener.
+var listenTargetID = event.TargetID{ID: "listen", Name: "stream"}
+
+// getListenOptions - parses the options of a listen request.
+func getListenOptions(values url.Values) (opts listenOptions, err error) {
+	opts.bucket = values.Get(peerRESTBucket)
+
+	if len(values[peerRESTListenPrefix]) > 1 {
+		return opts, &event.ErrFilterNamePrefix{}
+	}
+	if opts.prefix = values.Get(peerRESTListenPrefix); opts.prefix != "" {
+		if err = event.ValidateFilterRuleValue(opts.prefix); err != nil {
+			return opts, err
+		}
+	}
+
+	if len(values[peerRESTListenSuffix]) > 1 {
+		return opts, &event.ErrFilterNameSuffix{}
+	}
+	if opts.suffix = values.Get(peerRESTListenSuffix); opts.suffix != "" {
+		if err = event.ValidateFilterRuleValue(opts.suffix); err != nil {
+			return opts, err
+		}
+	}
+
+	for _, s := range values[peerRESTListenEvents] {
+		eventName, err := event.ParseName(s)
+		if err != nil {
+			return opts, err
+		}
+		opts.eventNames = append(opts.eventNames, eventName)
+	}
+
+	eventNames := opts.eventNames
+	if len(eventNames) == 0 {
+		eventNames = listenAllEventNames
+	}
+	opts.rulesMap = event.NewRulesMap(eventNames, event.NewPattern(opts.prefix, opts.suffix), listenTargetID)
+	return opts, nil
+}
+
+// queryValues - returns the options to be sent to peers.
+func (opts listenOptions) queryValues() url.Values {
+	values := make(url.Values)
+	if opts.bucket != "" {
+		values.Set(peerRESTBucket, opts.bucket)
+	}
+	if opts.prefix != "" {
+		values.Set(peerRESTListenPrefix, opts.prefix)
+	}
+	if opts.suffix != "" {
+		values.Set(peerRESTListenSuffix, opts.suffix)
+	}
+	for _, eventName := range opts.eventNames {
+		values.Add(peerRESTListenEvents, eventName.String())
+	}
+	return values
+}
+
+// match - returns whether the event is sent to the listener.
+func (opts listenOptions) match(eventData *event.Event) bool {
+	if opts.bucket != "" && eventData.S3.Bucket.Name != opts.bucket {
+		return false
+	}
+	objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
+	if err != nil {
+		return false
+	}
+	return len(opts.rulesMap.Match(eventData.EventName, objectName, event.ObjectProperties{})) > 0
+}
+
+// eventStream - numbers the events of this server and keeps the recent
+// ones for listeners. Listeners read the history at their own pace,
+// publishing never waits for slow listeners. All methods of a nil
+// eventStream are no-ops.
+type eventStream struct {
+	// Time of the last listener leaving, in nanoseconds since
+	// epoch, and the number of listeners following the stream.
+	// Accessed atomically, keep first for 64-bit alignment.
+	lastFollowed int64
+	followers    int32
+
+	sync.RWMutex
+	node  string
+	epoch int64
+	// Sequence number of the last published event.
+	seq uint64
+	// Sequence number of the first event kept, events before it
+	// were skipped while no listener followed the stream.
+	first uint64
+	// Ring of the recent events, the event with sequence
+	// number seq is kept at history[(seq-1)%len(history)].
+	history []event.Event
+	// Listeners waiting for new events.
+	wakeChs map[chan struct{}]struct{}
+}
+
+func newEventStream(node string, size int) *eventStream {
+	return &eventStream{
+		node:    node,
+		epoch:   UTCNow().UnixNano(),
+		first:   1,
+		history: make([]event.Event, size),
+		wakeChs: make(map[chan struct{}]struct{}),
+	}
+}
+
+// active - returns whether listeners follow the stream, or did so
+// recently enough to resume. Events are only published while active,
+// otherwise they are skipped.
+func (s *eventStream) active() bool {
+	if s == nil {
+		return false
+	}
+	if atomic.LoadInt32(&s.followers) > 0 {
+		return true
+	}
+	return UTCNow().UnixNano()-atomic.LoadInt64(&s.lastFollowed) < int64(listenResumeWindow)
+}
+
+// Publish - adds the event to the stream and wakes up waiting listeners.
+func (s *eventStream) Publish(eventData event.Event) {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.seq++
+	s.history[(s.seq-1)%uint64(len(s.history))] = eventData
+	for wakeCh := range s.wakeChs {
+		select {
+		case wakeCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Skip - numbers an event which is not kept as no listener follows
+// the stream, listeners resuming from before it are told that they
+// missed it.
+func (s *eventStream) Skip() {
+	if s == nil {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	s.seq++
+	s.first = s.seq + 1
+}
+
+// head - returns the position after the last published event.
+func (s *eventStream) head() listenPosition {
+	if s == nil {
+		return listenPosition{}
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	return listenPosition{Epoch: s.epoch, Seq: s.seq}
+}
+
+// since - returns up to count events published after pos, the first
+// event returned counts the events no longer kept in the history. If
+// only skipped events follow pos, a single event without event data
+// counts them.
+func (s *eventStream) since(pos listenPosition, count int) []listenEvent {
+	if s == nil {
+		return nil
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	oldest := s.first
+	if size := uint64(len(s.history)); s.seq > size && s.seq-size+1 > oldest {
+		oldest = s.seq - size + 1
+	}
+
+	next := oldest
+	var missed uint64
+	if pos.Epoch == s.epoch {
+		next = pos.Seq + 1
+		if next < oldest {
+			missed = oldest - next
+			next = oldest
+		}
+	}
+
+	var events []listenEvent
+	for ; next <= s.seq && len(events) < count; next++ {
+		eventData := s.history[(next-1)%uint64(len(s.history))]
+		events = append(events, listenEvent{
+			Node:   s.node,
+			Pos:    listenPosition{Epoch: s.epoch, Seq: next},
+			Event:  &eventData,
+			Missed: missed,
+		})
+		missed = 0
+	}
+	if missed > 0 {
+		events = append(events, listenEvent{
+			Node:   s.node,
+			Pos:    listenPosition{Epoch: s.epoch, Seq: s.seq},
+			Missed: missed,
+		})
+	}
+	return events
+}
+
+// follow - passes the events matching opts published after pos to send
+// until doneCh is closed or send fails. The position reached is passed
+// right away and after every keep-alive interval.
+func (s *eventStream) follow(pos listenPosition, opts listenOptions, send func(listenEvent) error, doneCh <-chan struct{}) {
+	if s == nil {
+		return
+	}
+
+	atomic.AddInt32(&s.followers, 1)
+	wakeCh := make(chan struct{}, 1)
+	s.Lock()
+	s.wakeChs[wakeCh] = struct{}{}
+	s.Unlock()
+	defer func() {
+		s.Lock()
+		delete(s.wakeChs, wakeCh)
+		s.Unlock()
+		atomic.StoreInt64(&s.lastFollowed, UTCNow().UnixNano())
+		atomic.AddInt32(&s.followers, -1)
+	}()
+
+	keepAliveTicker := time.NewTicker(listenKeepAliveInterval)
+	defer keepAliveTicker.Stop()
+
+	var missed uint64
+	keepAlive := func() error {
+		err := send(listenEvent{Node: s.node, Pos: pos, Missed: missed})
+		missed = 0
+		return err
+	}
+	if err := keepAlive(); err != nil {
+		return
+	}
+
+	for {
+		for events := s.since(pos, listenBatchSize); len(events) > 0; events = s.since(pos, listenBatchSize) {
+			for _, ev := range events {
+				pos = ev.Pos
+				missed += ev.Missed
+				if ev.Event == nil || !opts.match(ev.Event) {
+					continue
+				}
+				ev.Missed = missed
+				missed = 0
+				if err := send(ev); err != nil {
+					return
+				}
+			}
+		}
+
+		select {
+		case <-wakeCh:
+		case <-keepAliveTicker.C:
+			if err := keepAlive(); err != nil {
+				return
+			}
+		case <-doneCh:
+			return
+		}
+	}
+}
+
+// listenMessage - a message sent to a listener. Records is empty for
+// messages only reporting missed events or the cursor.
+type listenMessage struct {
+	Records []event.Event `json:"Records,omitempty"`
+	// Number of events lost before the records as the listener
+	// fell behind.
+	Missed uint64 `json:"missed,omitempty"`
+	// Position after the records to resume from.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// listenWriter - writes the messages of a listen stream to a client.
+type listenWriter interface {
+	write(msg listenMessage) error
+	// keepAlive - keeps the connection alive, cursor is
+	// not empty if it changed since the last message.
+	keepAlive(cursor string) error
+}
+
+// serveListenEvents - writes the events received on eventCh to the client
+// until doneCh is closed or writing fails.
+func serveListenEvents(lw listenWriter, cursor listenCursor, eventCh <-chan listenEvent, doneCh <-chan struct{}) {
+	keepAliveTicker := time.NewTicker(listenKeepAliveInterval)
+	defer keepAliveTicker.Stop()
+
+	sent := cursor.String()
+	for {
+		select {
+		case ev := <-eventCh:
+			cursor[ev.Node] = ev.Pos
+			if ev.Event == nil && ev.Missed == 0 {
+				continue
+			}
+			msg := listenMessage{Missed: ev.Missed, Cursor: cursor.String()}
+			if ev.Event != nil {
+				msg.Records = []event.Event{*ev.Event}
+			}
+			if err := lw.write(msg); err != nil {
+				return
+			}
+			sent = msg.Cursor
+		case <-keepAliveTicker.C:
+			current := cursor.String()
+			if current == sent {
+				current = ""
+			} else {
+				sent = current
+			}
+			if err := lw.keepAlive(current); err != nil {
+				return
+			}
+		case <-doneCh:
+			return
+		case <-GlobalServiceDoneCh:
+			return
+		}
+	}
+}
+
+// listenSSEWriter - writes a listen stream as Server-Sent Events. The
+// cursor is sent as event ID, browsers resume from it when reconnecting.
+type listenSSEWriter struct {
+	w http.ResponseWriter
+}
+
+func newListenSSEWriter(w http.ResponseWriter) *listenSSEWriter {
+	w.Header().Set(xhttp.ContentType, "text/event-stream")
+	w.Header().Set(xhttp.CacheControl, "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+	return &listenSSEWriter{w: w}
+}
+
+func (lw *listenSSEWriter) write(msg listenMessage) error {
+	id := msg.Cursor
+	msg.Cursor = ""
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return lw.send(fmt.Sprintf("id: %s\ndata: %s\n\n", id, data))
+}
+
+func (lw *listenSSEWriter) keepAlive(cursor string) error {
+	if cursor != "" {
+		// An event without data only updates the last event ID.
+		return lw.send(fmt.Sprintf("id: %s\n\n", cursor))
+	}
+	return lw.send(":\n\n")
+}
+
+func (lw *listenSSEWriter) send(s string) error {
+	if _, err := io.WriteString(lw.w, s); err != nil {
+		return err
+	}
+	lw.w.(http.Flusher).Flush()
+	return nil
+}
+
+// listenWebSocketWriter - writes a listen stream as JSON text messages
+// carrying their cursor.
+type listenWebSocketWriter struct {
+	ws *websocket.Conn
+}
+
+func (lw *listenWebSocketWriter) write(msg listenMessage) error {
+	return websocket.JSON.Send(lw.ws, msg)
+}
+
+func (lw *listenWebSocketWriter) keepAlive(cursor string) error {
+	if cursor != "" {
+		return lw.write(listenMessage{Cursor: cursor})
+	}
+	lw.ws.PayloadType = websocket.PingFrame
+	defer func() {
+		lw.ws.PayloadType = websocket.TextFrame
+	}()
+	_, err := lw.ws.Write(nil)
+	return err
+}
+
+// serveListenWebSocket - upgrades the request to a WebSocket and writes
+// the events received on eventCh to it until the client goes away.
+func serveListenWebSocket(w http.ResponseWriter, r *http.Request, cursor listenCursor, eventCh <-chan listenEvent) {
+	websocket.Server{
+		Handler: func(ws *websocket.Conn) {
+			// Messages of the client are ignored, reading
+			// detects the client closing the connection.
+			closedCh := make(chan struct{})
+			go func() {
+				io.Copy(ioutil.Discard, ws)
+				close(closedCh)
+			}()
+			serveListenEvents(&listenWebSocketWriter{ws: ws}, cursor, eventCh, closedCh)
+		},
+	}.ServeHTTP(w, r)
+}
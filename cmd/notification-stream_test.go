@@ -0,0 +1,312 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio/pkg/event"
+	"golang.org/x/net/websocket"
+)
+
+func newListenTestEvent(eventName event.Name, bucketName, objectName string) event.Event {
+	var eventData event.Event
+	eventData.EventName = eventName
+	eventData.S3.Bucket.Name = bucketName
+	eventData.S3.Object.Key = url.QueryEscape(objectName)
+	return eventData
+}
+
+func TestListenCursor(t *testing.T) {
+	cursor := listenCursor{
+		"10.0.0.1:9000":   {Epoch: 1570000000000000000, Seq: 42},
+		"[::1]:9000":      {Epoch: 1570000000000000001, Seq: 0},
+		"minio-3.lan:443": {Epoch: 1, Seq: 18446744073709551615},
+	}
+	parsed, err := parseListenCursor(cursor.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, cursor) {
+		t.Fatalf("expected: %v, got: %v", cursor, parsed)
+	}
+
+	if parsed, err = parseListenCursor(""); err != nil || len(parsed) != 0 {
+		t.Fatalf("expected: empty cursor, got: %v, %v", parsed, err)
+	}
+
+	for i, s := range []string{"!", "bm9kZQ", "bm9kZT0x", "bm9kZT14LjE", "bm9kZT0xLi0x"} {
+		if _, err = parseListenCursor(s); err != errInvalidListenCursor {
+			t.Fatalf("test %v: expected: %v, got: %v", i+1, errInvalidListenCursor, err)
+		}
+	}
+}
+
+func TestListenOptions(t *testing.T) {
+	testCases := []struct {
+		values    url.Values
+		eventData event.Event
+		match     bool
+		expectErr bool
+	}{
+		{url.Values{}, newListenTestEvent(event.ObjectCreatedPut, "bucket", "a/b.jpg"), true, false},
+		{url.Values{}, newListenTestEvent(event.BucketCreated, "bucket", ""), true, false},
+		{url.Values{"bucket": {"other"}}, newListenTestEvent(event.ObjectCreatedPut, "bucket", "a/b.jpg"), false, false},
+		{url.Values{"prefix": {"a/"}, "suffix": {".jpg"}}, newListenTestEvent(event.ObjectCreatedPut, "bucket", "a/b c.jpg"), true, false},
+		{url.Values{"prefix": {"a/"}}, newListenTestEvent(event.ObjectCreatedPut, "bucket", "b/c.jpg"), false, false},
+		{url.Values{"prefix": {"a/"}}, newListenTestEvent(event.BucketCreated, "bucket", ""), false, false},
+		{url.Values{"events": {"s3:ObjectRemoved:*"}}, newListenTestEvent(event.ObjectCreatedPut, "bucket", "a"), false, false},
+		{url.Values{"events": {"s3:ObjectRemoved:*"}}, newListenTestEvent(event.ObjectRemovedDelete, "bucket", "a"), true, false},
+		{url.Values{"prefix": {"a", "b"}}, event.Event{}, false, true},
+		{url.Values{"suffix": {"a", "b"}}, event.Event{}, false, true},
+		{url.Values{"events": {"invalid"}}, event.Event{}, false, true},
+	}
+
+	for i, testCase := range testCases {
+		opts, err := getListenOptions(testCase.values)
+		if expectErr := (err != nil); expectErr != testCase.expectErr {
+			t.Fatalf("test %v: error: expected: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+		if err != nil {
+			continue
+		}
+		if match := opts.match(&testCase.eventData); match != testCase.match {
+			t.Fatalf("test %v: match: expected: %v, got: %v", i+1, testCase.match, match)
+		}
+
+		// Peers select the same events.
+		peerOpts, err := getListenOptions(opts.queryValues())
+		if err != nil {
+			t.Fatalf("test %v: %v", i+1, err)
+		}
+		if match := peerOpts.match(&testCase.eventData); match != testCase.match {
+			t.Fatalf("test %v: peer match: expected: %v, got: %v", i+1, testCase.match, match)
+		}
+	}
+}
+
+func TestEventStreamSince(t *testing.T) {
+	stream := newEventStream("node", 4)
+	start := stream.head()
+	for i := 0; i < 6; i++ {
+		stream.Publish(newListenTestEvent(event.ObjectCreatedPut, "bucket", "object"))
+	}
+
+	testCases := []struct {
+		pos       listenPosition
+		count     int
+		firstSeq  uint64
+		length    int
+		missed    uint64
+		expectNil bool
+	}{
+		// Events 1 and 2 are no longer kept.
+		{start, 10, 3, 4, 2, false},
+		{listenPosition{Epoch: start.Epoch, Seq: 2}, 10, 3, 4, 0, false},
+		{listenPosition{Epoch: start.Epoch, Seq: 4}, 10, 5, 2, 0, false},
+		{listenPosition{Epoch: start.Epoch, Seq: 4}, 1, 5, 1, 0, false},
+		{stream.head(), 10, 0, 0, 0, true},
+		// Positions of another run start with the oldest event kept.
+		{listenPosition{Epoch: start.Epoch - 1, Seq: 5}, 10, 3, 4, 0, false},
+	}
+
+	for i, testCase := range testCases {
+		events := stream.since(testCase.pos, testCase.count)
+		if len(events) != testCase.length {
+			t.Fatalf("test %v: length: expected: %v, got: %v", i+1, testCase.length, len(events))
+		}
+		if testCase.expectNil {
+			continue
+		}
+		if events[0].Pos.Seq != testCase.firstSeq {
+			t.Fatalf("test %v: first: expected: %v, got: %v", i+1, testCase.firstSeq, events[0].Pos.Seq)
+		}
+		if events[0].Missed != testCase.missed {
+			t.Fatalf("test %v: missed: expected: %v, got: %v", i+1, testCase.missed, events[0].Missed)
+		}
+	}
+}
+
+func TestEventStreamFollow(t *testing.T) {
+	stream := newEventStream("node", 10)
+	opts, err := getListenOptions(url.Values{"prefix": {"match/"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errDone := errors.New("done")
+	eventCh := make(chan listenEvent, 10)
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	go stream.follow(stream.head(), opts, func(ev listenEvent) error {
+		eventCh <- ev
+		if ev.Event != nil {
+			return errDone
+		}
+		return nil
+	}, doneCh)
+
+	// The position is reported right away.
+	if ev := <-eventCh; ev.Event != nil || ev.Pos != stream.head() {
+		t.Fatalf("expected: position %v, got: %v", stream.head(), ev)
+	}
+
+	stream.Publish(newListenTestEvent(event.ObjectCreatedPut, "bucket", "other/object"))
+	stream.Publish(newListenTestEvent(event.ObjectCreatedPut, "bucket", "match/object"))
+
+	ev := <-eventCh
+	for ev.Event == nil {
+		ev = <-eventCh
+	}
+	if ev.Pos.Seq != 2 || ev.Event.S3.Object.Key != url.QueryEscape("match/object") {
+		t.Fatalf("expected: match/object at 2, got: %v at %v", ev.Event.S3.Object.Key, ev.Pos.Seq)
+	}
+}
+
+func TestEventStreamActive(t *testing.T) {
+	// A nil stream is never active and ignores events.
+	var nilStream *eventStream
+	nilStream.Publish(newListenTestEvent(event.ObjectCreatedPut, "bucket", "object"))
+	if nilStream.active() || nilStream.head() != (listenPosition{}) || nilStream.since(listenPosition{}, 1) != nil {
+		t.Fatal("expected: inactive nil stream")
+	}
+
+	stream := newEventStream("node", 10)
+	if stream.active() {
+		t.Fatal("expected: inactive stream without listeners")
+	}
+
+	eventCh := make(chan listenEvent, 1)
+	doneCh := make(chan struct{})
+	followDoneCh := make(chan struct{})
+	go func() {
+		stream.follow(stream.head(), listenOptions{}, func(ev listenEvent) error {
+			eventCh <- ev
+			return nil
+		}, doneCh)
+		close(followDoneCh)
+	}()
+	<-eventCh
+	if !stream.active() {
+		t.Fatal("expected: active stream with a listener")
+	}
+
+	// Listeners leaving may resume for a while.
+	close(doneCh)
+	<-followDoneCh
+	if !stream.active() {
+		t.Fatal("expected: active stream after the listener left")
+	}
+	stream.lastFollowed -= int64(listenResumeWindow)
+	if stream.active() {
+		t.Fatal("expected: inactive stream after the resume window")
+	}
+}
+
+func TestEventStreamResume(t *testing.T) {
+	stream := newEventStream("node", 10)
+	stream.Publish(newListenTestEvent(event.ObjectCreatedPut, "bucket", "object"))
+	pos := stream.head()
+
+	// Events sent after the resume window are skipped.
+	stream.lastFollowed = UTCNow().UnixNano() - int64(listenResumeWindow)
+	if stream.active() {
+		t.Fatal("expected: inactive stream after the resume window")
+	}
+	stream.Skip()
+	stream.Skip()
+
+	// A listener resuming meanwhile is told about the missed events.
+	events := stream.since(pos, 10)
+	if len(events) != 1 || events[0].Event != nil || events[0].Missed != 2 || events[0].Pos != stream.head() {
+		t.Fatalf("expected: 2 missed events, got: %v", events)
+	}
+
+	opts, err := getListenOptions(url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventCh := make(chan listenEvent, 10)
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	go stream.follow(pos, opts, func(ev listenEvent) error {
+		eventCh <- ev
+		return nil
+	}, doneCh)
+
+	var missed uint64
+	for ev := range eventCh {
+		missed += ev.Missed
+		if ev.Event != nil {
+			if ev.Pos.Seq != 4 {
+				t.Fatalf("expected: event at 4, got: %v", ev.Pos.Seq)
+			}
+			break
+		}
+		if ev.Pos == stream.head() {
+			stream.Publish(newListenTestEvent(event.ObjectCreatedPut, "bucket", "object"))
+		}
+	}
+	if missed != 2 {
+		t.Fatalf("expected: 2 missed events, got: %v", missed)
+	}
+}
+
+func TestServeListenWebSocket(t *testing.T) {
+	eventData := newListenTestEvent(event.ObjectCreatedPut, "bucket", "object")
+	eventCh := make(chan listenEvent, 2)
+	eventCh <- listenEvent{Node: "node", Pos: listenPosition{Epoch: 1, Seq: 1}, Event: &eventData}
+	eventCh <- listenEvent{Node: "node", Pos: listenPosition{Epoch: 1, Seq: 3}, Event: &eventData, Missed: 1}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveListenWebSocket(w, r, make(listenCursor), eventCh)
+	}))
+	defer server.Close()
+
+	ws, err := websocket.Dial(strings.Replace(server.URL, "http", "ws", 1), "", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	expected := []listenMessage{
+		{
+			Records: []event.Event{eventData},
+			Cursor:  listenCursor{"node": {Epoch: 1, Seq: 1}}.String(),
+		},
+		{
+			Records: []event.Event{eventData},
+			Missed:  1,
+			Cursor:  listenCursor{"node": {Epoch: 1, Seq: 3}}.String(),
+		},
+	}
+	for i := range expected {
+		var msg listenMessage
+		if err = websocket.JSON.Receive(ws, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(msg, expected[i]) {
+			t.Fatalf("message %v: expected: %v, got: %v", i+1, expected[i], msg)
+		}
+	}
+}
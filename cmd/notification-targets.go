@@ -0,0 +1,116 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/minio/minio/pkg/event"
+	"github.com/minio/minio/pkg/event/target"
+	"github.com/minio/minio/pkg/madmin"
+)
+
+// Actions on the queued events of a notification target.
+const (
+	notificationQueuePeek   = "peek"
+	notificationQueuePurge  = "purge"
+	notificationQueueReplay = "replay"
+)
+
+var (
+	errNotificationTargetNotFound = errors.New("notification target not found")
+	errNotificationTargetNoQueue  = errors.New("notification target has no queue directory")
+)
+
+// localNotificationTargets - returns the delivery state of the configured
+// notification targets of this server, sorted by ARN.
+func localNotificationTargets() []madmin.NotificationTargetInfo {
+	if globalNotificationSys == nil {
+		return nil
+	}
+
+	node := GetLocalPeer(globalEndpoints)
+	targetList := globalNotificationSys.targetList
+
+	var infos []madmin.NotificationTargetInfo
+	for _, id := range targetList.List() {
+		// Only configured targets keep a queue, listeners
+		// of bucket notifications are left out.
+		t, ok := targetList.Target(id).(target.QueuedTarget)
+		if !ok {
+			continue
+		}
+
+		info := madmin.NotificationTargetInfo{
+			Node: node,
+			ARN:  id.ToARN(globalServerRegion).String(),
+		}
+		if q := t.Queue(); q != nil {
+			info.Online = q.Online()
+			info.Queued = true
+			info.QueueLength = q.Len()
+			info.OldestEvent = q.Oldest()
+		} else {
+			err := targetList.Err(id)
+			info.Online = err == nil
+			if err != nil {
+				info.Error = err.Error()
+			}
+		}
+		infos = append(infos, info)
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ARN < infos[j].ARN
+	})
+	return infos
+}
+
+// localNotificationQueue - peeks at up to count queued events, purges or
+// replays the queued events of the notification target with arn on this
+// server.
+func localNotificationQueue(arn *event.ARN, action string, count int) madmin.NotificationQueueInfo {
+	info := madmin.NotificationQueueInfo{Node: GetLocalPeer(globalEndpoints)}
+	if globalNotificationSys == nil {
+		info.Error = errServerNotInitialized.Error()
+		return info
+	}
+
+	t, ok := globalNotificationSys.targetList.Target(arn.TargetID).(target.QueuedTarget)
+	if !ok {
+		info.Error = errNotificationTargetNotFound.Error()
+		return info
+	}
+	q := t.Queue()
+	if q == nil {
+		info.Error = errNotificationTargetNoQueue.Error()
+		return info
+	}
+
+	switch action {
+	case notificationQueuePeek:
+		info.Events = q.Peek(count)
+	case notificationQueuePurge:
+		info.Purged = q.Purge()
+	case notificationQueueReplay:
+		q.Replay()
+	default:
+		info.Error = errInvalidArgument.Error()
+	}
+	return info
+}
@@ -33,6 +33,8 @@ import (
 	"github.com/klauspost/compress/zip"
 	"github.com/minio/minio/cmd/crypto"
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/accesslog"
+	"github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/lifecycle"
 	"github.com/minio/minio/pkg/madmin"
@@ -46,7 +48,9 @@ type NotificationSys struct {
 	targetList                 *event.TargetList
 	bucketRulesMap             map[string]event.RulesMap
 	bucketRemoteTargetRulesMap map[string]map[event.TargetID]event.RulesMap
+	serverRulesMap             event.RulesMap
 	peerClients                []*peerRESTClient
+	eventStream                *eventStream
 }
 
 // GetARNList - returns available ARNs.
@@ -247,6 +251,19 @@ func (sys *NotificationSys) LoadUsers() []NotificationPeerErr {
 	return ng.Wait()
 }
 
+// LoadDecommission - reloads the decommission state on all peers.
+func (sys *NotificationSys) LoadDecommission() []NotificationPeerErr {
+	ng := WithNPeers(len(sys.peerClients))
+	for idx, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		client := client
+		ng.Go(context.Background(), client.LoadDecommission, idx, *client.host)
+	}
+	return ng.Wait()
+}
+
 // LoadGroup - loads a specific group on all peers.
 func (sys *NotificationSys) LoadGroup(group string) []NotificationPeerErr {
 	ng := WithNPeers(len(sys.peerClients))
@@ -278,6 +295,24 @@ func (sys *NotificationSys) BackgroundHealStatus() []madmin.BgHealState {
 	return states
 }
 
+// DrivesHealStatus - returns the healing progress of the replaced drives of all peers
+func (sys *NotificationSys) DrivesHealStatus() []madmin.DriveHealInfo {
+	var drives []madmin.DriveHealInfo
+	for _, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		peerDrives, err := client.DrivesHealStatus()
+		if err != nil {
+			logger.LogIf(context.Background(), err)
+			continue
+		}
+		drives = append(drives, peerDrives...)
+	}
+
+	return drives
+}
+
 // BackgroundOpsStatus - returns the status of all background operations of all peers
 func (sys *NotificationSys) BackgroundOpsStatus() []BgOpsStatus {
 	states := make([]BgOpsStatus, len(sys.peerClients))
@@ -296,6 +331,101 @@ func (sys *NotificationSys) BackgroundOpsStatus() []BgOpsStatus {
 	return states
 }
 
+// APIStats - returns the S3 API statistics of all reachable peers
+// by peer address.
+func (sys *NotificationSys) APIStats(ctx context.Context) map[string]APIStats {
+	stats := make([]*APIStats, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			st, err := client.APIStats()
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+				ctx := logger.SetReqInfo(ctx, reqInfo)
+				logger.LogIf(ctx, err)
+				return
+			}
+			stats[idx] = &st
+		}(index, client)
+	}
+	wg.Wait()
+
+	peerStats := make(map[string]APIStats, len(stats))
+	for index, st := range stats {
+		if st != nil {
+			peerStats[sys.peerClients[index].host.String()] = *st
+		}
+	}
+	return peerStats
+}
+
+// NotificationTargets - returns the delivery state of the notification
+// targets of all reachable peers.
+func (sys *NotificationSys) NotificationTargets(ctx context.Context) []madmin.NotificationTargetInfo {
+	infos := make([][]madmin.NotificationTargetInfo, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			info, err := client.NotificationTargets()
+			if err != nil {
+				reqInfo := (&logger.ReqInfo{}).AppendTags("peerAddress", client.host.String())
+				ctx := logger.SetReqInfo(ctx, reqInfo)
+				logger.LogIf(ctx, err)
+				return
+			}
+			infos[idx] = info
+		}(index, client)
+	}
+	wg.Wait()
+
+	var all []madmin.NotificationTargetInfo
+	for _, info := range infos {
+		all = append(all, info...)
+	}
+	return all
+}
+
+// NotificationQueue - peeks at, purges or replays the queued events of a
+// notification target on all peers.
+func (sys *NotificationSys) NotificationQueue(arn, action string, count int) []madmin.NotificationQueueInfo {
+	infos := make([]madmin.NotificationQueueInfo, len(sys.peerClients))
+	var wg sync.WaitGroup
+	for index, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(idx int, client *peerRESTClient) {
+			defer wg.Done()
+			info, err := client.NotificationQueue(arn, action, count)
+			if err != nil {
+				info = madmin.NotificationQueueInfo{Error: err.Error()}
+			}
+			info.Node = client.host.String()
+			infos[idx] = info
+		}(index, client)
+	}
+	wg.Wait()
+
+	var all []madmin.NotificationQueueInfo
+	for i, client := range sys.peerClients {
+		if client != nil {
+			all = append(all, infos[i])
+		}
+	}
+	return all
+}
+
 // StartProfiling - start profiling on remote peers, by initiating a remote RPC.
 func (sys *NotificationSys) StartProfiling(profiler string) []NotificationPeerErr {
 	ng := WithNPeers(len(sys.peerClients))
@@ -602,6 +732,69 @@ func (sys *NotificationSys) RemoveBucketLifecycle(ctx context.Context, bucketNam
 	}()
 }
 
+// SetBucketACL - calls SetBucketACL on all peers.
+func (sys *NotificationSys) SetBucketACL(ctx context.Context, bucketName string, cannedACL acl.CannedACL) {
+	go func() {
+		var wg sync.WaitGroup
+		for _, client := range sys.peerClients {
+			if client == nil {
+				continue
+			}
+			wg.Add(1)
+			go func(client *peerRESTClient) {
+				defer wg.Done()
+				if err := client.SetBucketACL(bucketName, cannedACL); err != nil {
+					logger.GetReqInfo(ctx).AppendTags("remotePeer", client.host.Name)
+					logger.LogIf(ctx, err)
+				}
+			}(client)
+		}
+		wg.Wait()
+	}()
+}
+
+// SetBucketLogging - calls SetBucketLogging on all peers.
+func (sys *NotificationSys) SetBucketLogging(ctx context.Context, bucketName string, config *accesslog.Config) {
+	go func() {
+		var wg sync.WaitGroup
+		for _, client := range sys.peerClients {
+			if client == nil {
+				continue
+			}
+			wg.Add(1)
+			go func(client *peerRESTClient) {
+				defer wg.Done()
+				if err := client.SetBucketLogging(bucketName, config); err != nil {
+					logger.GetReqInfo(ctx).AppendTags("remotePeer", client.host.Name)
+					logger.LogIf(ctx, err)
+				}
+			}(client)
+		}
+		wg.Wait()
+	}()
+}
+
+// SetBucketStorageClass - calls SetBucketStorageClass on all peers.
+func (sys *NotificationSys) SetBucketStorageClass(ctx context.Context, bucketName string, sc madmin.BucketStorageClass) {
+	go func() {
+		var wg sync.WaitGroup
+		for _, client := range sys.peerClients {
+			if client == nil {
+				continue
+			}
+			wg.Add(1)
+			go func(client *peerRESTClient) {
+				defer wg.Done()
+				if err := client.SetBucketStorageClass(bucketName, sc); err != nil {
+					logger.GetReqInfo(ctx).AppendTags("remotePeer", client.host.Name)
+					logger.LogIf(ctx, err)
+				}
+			}(client)
+		}
+		wg.Wait()
+	}()
+}
+
 // PutBucketNotification - calls PutBucketNotification RPC call on all peers.
 func (sys *NotificationSys) PutBucketNotification(ctx context.Context, bucketName string, rulesMap event.RulesMap) {
 	go func() {
@@ -645,6 +838,37 @@ func (sys *NotificationSys) ListenBucketNotification(ctx context.Context, bucket
 	}()
 }
 
+// ListenEvents - sends the events of all servers matching opts after the
+// positions of cursor to eventCh until doneCh is closed. Servers without a
+// position in cursor start after their last event.
+func (sys *NotificationSys) ListenEvents(opts listenOptions, cursor listenCursor, eventCh chan<- listenEvent, doneCh <-chan struct{}) {
+	if stream := sys.eventStream; stream != nil {
+		pos, ok := cursor[stream.node]
+		if !ok {
+			pos = stream.head()
+		}
+		go stream.follow(pos, opts, func(ev listenEvent) error {
+			select {
+			case eventCh <- ev:
+				return nil
+			case <-doneCh:
+				return errListenerGone
+			}
+		}, doneCh)
+	}
+
+	for _, client := range sys.peerClients {
+		if client == nil {
+			continue
+		}
+		var peerPos *listenPosition
+		if pos, ok := cursor[client.host.String()]; ok {
+			peerPos = &pos
+		}
+		client.EventStream(opts, peerPos, eventCh, doneCh)
+	}
+}
+
 // AddRemoteTarget - adds event rules map, HTTP/PeerRPC client target to bucket name.
 func (sys *NotificationSys) AddRemoteTarget(bucketName string, target event.Target, rulesMap event.RulesMap) error {
 	if err := sys.targetList.Add(target); err != nil {
@@ -803,6 +1027,16 @@ func (sys *NotificationSys) Init(buckets []BucketInfo, objAPI ObjectLayer) error
 		return nil
 	}
 
+	// Server-wide rules apply to the events of all buckets,
+	// including buckets without a notification configuration.
+	serverRulesMap, err := globalNotifyRulesConfig.Load(globalServerConfig.GetRegion(), sys.targetList)
+	if err != nil {
+		return err
+	}
+	sys.Lock()
+	sys.serverRulesMap = serverRulesMap
+	sys.Unlock()
+
 	doneCh := make(chan struct{})
 	defer close(doneCh)
 
@@ -918,15 +1152,32 @@ func (sys *NotificationSys) send(bucketName string, eventData event.Event, targe
 // Send - sends event data to all matching targets.
 func (sys *NotificationSys) Send(args eventArgs) []event.TargetIDErr {
 	sys.RLock()
-	targetIDSet := sys.bucketRulesMap[args.BucketName].Match(args.EventName, args.Object.Name)
+	targetIDSet := sys.bucketRulesMap[args.BucketName].Match(args.EventName, args.Object.Name, args.objectProperties())
+	targetIDSet = targetIDSet.Union(sys.serverRulesMap.Match(args.EventName, args.Object.Name, args.objectProperties()))
 	sys.RUnlock()
 
+	// Events are only kept for listeners while any follow
+	// the stream of this server, locally or from a peer.
+	// Otherwise they are skipped, so that listeners resuming
+	// later learn about the events they missed.
+	listening := sys.eventStream.active()
+	if !listening {
+		sys.eventStream.Skip()
+	}
+	if len(targetIDSet) == 0 && !listening {
+		return nil
+	}
+
+	eventData := args.ToEvent()
+	if listening {
+		sys.eventStream.Publish(eventData)
+	}
 	if len(targetIDSet) == 0 {
 		return nil
 	}
 
 	targetIDs := targetIDSet.ToSlice()
-	return sys.send(args.BucketName, args.ToEvent(), targetIDs...)
+	return sys.send(args.BucketName, eventData, targetIDs...)
 }
 
 // NetReadPerfInfo - Network read performance information.
@@ -1094,6 +1345,7 @@ func NewNotificationSys(config *serverConfig, endpoints EndpointList) *Notificat
 		bucketRulesMap:             make(map[string]event.RulesMap),
 		bucketRemoteTargetRulesMap: make(map[string]map[event.TargetID]event.RulesMap),
 		peerClients:                remoteClients,
+		eventStream:                newEventStream(GetLocalPeer(endpoints), listenHistorySize),
 	}
 }
 
@@ -1162,7 +1414,7 @@ func (args eventArgs) ToEvent() event.Event {
 		},
 	}
 
-	if args.EventName != event.ObjectRemovedDelete {
+	if !args.EventName.RemovesObject() {
 		newEvent.S3.Object.ETag = args.Object.ETag
 		newEvent.S3.Object.Size = args.Object.Size
 		if args.Object.IsCompressed() {
@@ -1175,6 +1427,25 @@ func (args eventArgs) ToEvent() event.Event {
 	return newEvent
 }
 
+// objectProperties - returns the object properties matched by the
+// object filters of notification rules.
+func (args eventArgs) objectProperties() event.ObjectProperties {
+	props := event.ObjectProperties{
+		Size:         args.Object.Size,
+		ContentType:  args.Object.ContentType,
+		UserMetadata: make(map[string]string),
+	}
+	if args.Object.IsCompressed() {
+		props.Size = args.Object.GetActualSize()
+	}
+	for k, v := range args.Object.UserDefined {
+		if k = strings.ToLower(k); strings.HasPrefix(k, "x-amz-meta-") {
+			props.UserMetadata[k] = v
+		}
+	}
+	return props
+}
+
 func sendEvent(args eventArgs) {
 
 	// remove sensitive encryption entries in metadata.
@@ -34,6 +34,9 @@ const (
 	// Staging buffer read size for all internal operations version 1.
 	readSizeV1 = 1 * humanize.MiByte
 
+	// Objects up to this size are stored inline in `xl.json`.
+	smallFileThreshold = 16 * humanize.KiByte
+
 	// Buckets meta prefix.
 	bucketMetaPrefix = "buckets"
 
@@ -94,12 +97,25 @@ func deleteBucketMetadata(ctx context.Context, bucket string, objAPI ObjectLayer
 
 	// Delete listener config, if present - ignore any errors.
 	removeListenerConfig(ctx, objAPI, bucket)
+
+	// Delete ACL config, if present - ignore any errors.
+	removeBucketACLConfig(ctx, objAPI, bucket)
+
+	// Delete logging config, if present - ignore any errors.
+	removeBucketLoggingConfig(ctx, objAPI, bucket)
+
+	// Delete storage class config, if present - ignore any errors.
+	removeBucketStorageClassConfig(ctx, objAPI, bucket)
 }
 
 // Depending on the disk type network or local, initialize storage API.
 func newStorageAPI(endpoint Endpoint) (storage StorageAPI, err error) {
 	if endpoint.IsLocal {
-		return newPosix(endpoint.Path)
+		storage, err := newPosix(endpoint.Path)
+		if err != nil {
+			return nil, err
+		}
+		return newPosixTracer(storage), nil
 	}
 
 	return newStorageRESTClient(endpoint)
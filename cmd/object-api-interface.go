@@ -20,6 +20,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/minio/minio-go/v6/pkg/encrypt"
 	"github.com/minio/minio/pkg/lifecycle"
@@ -35,6 +36,11 @@ type ObjectOptions struct {
 	ServerSideEncryption encrypt.ServerSide
 	UserDefined          map[string]string
 	CheckCopyPrecondFn   CheckCopyPreconditionFn
+
+	// Used when moving objects between zones, keeps the
+	// ETag and modification time of the source object.
+	PreserveETag string
+	MTime        time.Time
 }
 
 // LockType represents required locking for ObjectLayer operations
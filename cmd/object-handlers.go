@@ -709,6 +709,16 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// ACLs are not copied, the destination gets the requested canned ACL.
+	cannedACL, aclRequested, s3Error := getCannedACLFromHeader(r.Header)
+	if s3Error == ErrNone && aclRequested {
+		s3Error = checkRequestAuthType(ctx, r, policy.PutObjectACLAction, dstBucket, dstObject)
+	}
+	if s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+
 	// This request header needs to be set prior to setting ObjectOptions
 	if globalAutoEncryption && !crypto.SSEC.IsRequested(r.Header) {
 		r.Header.Add(crypto.SSEHeader, crypto.SSEAlgorithmAES256)
@@ -941,6 +951,8 @@ func (api objectAPIHandlers) CopyObjectHandler(w http.ResponseWriter, r *http.Re
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	setObjectACL(srcInfo.UserDefined, cannedACL)
+	setBucketStorageClass(srcInfo.UserDefined, dstBucket, dstObject)
 
 	// Store the preserved compression metadata.
 	for k, v := range compressMetadata {
@@ -1109,6 +1121,14 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	cannedACL, aclRequested, s3Error := getCannedACLFromHeader(r.Header)
+	if s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	setObjectACL(metadata, cannedACL)
+	setBucketStorageClass(metadata, bucket, object)
+
 	if rAuthType == authTypeStreamingSigned {
 		if contentEncoding, ok := metadata["content-encoding"]; ok {
 			contentEncoding = trimAwsChunkedContentEncoding(contentEncoding)
@@ -1139,11 +1159,19 @@ func (api objectAPIHandlers) PutObjectHandler(w http.ResponseWriter, r *http.Req
 	reader = r.Body
 
 	// Check if put is allowed
-	if s3Err = isPutAllowed(rAuthType, bucket, object, r); s3Err != ErrNone {
+	if s3Err = isPutAllowed(ctx, rAuthType, bucket, object, r); s3Err != ErrNone {
 		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Err), r.URL, guessIsBrowserReq(r))
 		return
 	}
 
+	// Setting an ACL also requires the permission to change ACLs.
+	if aclRequested {
+		if s3Err = isPutActionAllowed(ctx, rAuthType, bucket, object, r, policy.PutObjectACLAction); s3Err != ErrNone {
+			writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Err), r.URL, guessIsBrowserReq(r))
+			return
+		}
+	}
+
 	switch rAuthType {
 	case authTypeStreamingSigned:
 		// Initialize stream signature verifier.
@@ -1383,6 +1411,17 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 		return
 	}
 
+	cannedACL, aclRequested, s3Error := getCannedACLFromHeader(r.Header)
+	if s3Error == ErrNone && aclRequested {
+		s3Error = checkRequestAuthType(ctx, r, policy.PutObjectACLAction, bucket, object)
+	}
+	if s3Error != ErrNone {
+		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
+		return
+	}
+	setObjectACL(metadata, cannedACL)
+	setBucketStorageClass(metadata, bucket, object)
+
 	// We need to preserve the encryption headers set in EncryptRequest,
 	// so we do not want to override them, copy them instead.
 	for k, v := range encMetadata {
@@ -1415,6 +1454,22 @@ func (api objectAPIHandlers) NewMultipartUploadHandler(w http.ResponseWriter, r
 
 	// Write success response.
 	writeSuccessResponseXML(w, encodedSuccessResponse)
+
+	// Notify multipart upload initiated event.
+	sendEvent(eventArgs{
+		EventName:  event.MultipartUploadInitiate,
+		BucketName: bucket,
+		Object: ObjectInfo{
+			Bucket:      bucket,
+			Name:        object,
+			ContentType: metadata["content-type"],
+			UserDefined: metadata,
+		},
+		ReqParams:    extractReqParams(r),
+		RespElements: extractRespElements(w),
+		UserAgent:    r.UserAgent(),
+		Host:         handlers.GetSourceIP(r),
+	})
 }
 
 // CopyObjectPartHandler - uploads a part by copying data from an existing object as data source.
@@ -1821,7 +1876,7 @@ func (api objectAPIHandlers) PutObjectPartHandler(w http.ResponseWriter, r *http
 		s3Error   APIErrorCode
 	)
 	reader = r.Body
-	if s3Error = isPutAllowed(rAuthType, bucket, object, r); s3Error != ErrNone {
+	if s3Error = isPutAllowed(ctx, rAuthType, bucket, object, r); s3Error != ErrNone {
 		writeErrorResponse(ctx, w, errorCodes.ToAPIErr(s3Error), r.URL, guessIsBrowserReq(r))
 		return
 	}
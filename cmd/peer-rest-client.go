@@ -21,6 +21,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/gob"
+	"encoding/xml"
 	"io"
 	"math/rand"
 	"net/url"
@@ -30,6 +31,8 @@ import (
 	"github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/cmd/rest"
+	"github.com/minio/minio/pkg/accesslog"
+	"github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/lifecycle"
 	"github.com/minio/minio/pkg/madmin"
@@ -406,6 +409,56 @@ func (client *peerRESTClient) SetBucketLifecycle(bucket string, bucketLifecycle
 	return nil
 }
 
+// SetBucketACL - Set bucket canned ACL on the peer node
+func (client *peerRESTClient) SetBucketACL(bucket string, cannedACL acl.CannedACL) error {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+	values.Set(peerRESTACL, string(cannedACL))
+	respBody, err := client.call(peerRESTMethodBucketACLSet, values, nil, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// SetBucketLogging - Set bucket logging status on the peer node
+func (client *peerRESTClient) SetBucketLogging(bucket string, config *accesslog.Config) error {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+
+	data, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := client.call(peerRESTMethodBucketLoggingSet, values, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
+// SetBucketStorageClass - Set bucket default storage classes on the peer node
+func (client *peerRESTClient) SetBucketStorageClass(bucket string, sc madmin.BucketStorageClass) error {
+	values := make(url.Values)
+	values.Set(peerRESTBucket, bucket)
+
+	var reader bytes.Buffer
+	err := gob.NewEncoder(&reader).Encode(sc)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := client.call(peerRESTMethodBucketStorageClassSet, values, &reader, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
 // PutBucketNotification - Put bucket notification on the peer node.
 func (client *peerRESTClient) PutBucketNotification(bucket string, rulesMap event.RulesMap) error {
 	values := make(url.Values)
@@ -504,6 +557,16 @@ func (client *peerRESTClient) LoadUsers() (err error) {
 	return nil
 }
 
+// LoadDecommission - send load decommission state command to peer nodes.
+func (client *peerRESTClient) LoadDecommission() error {
+	respBody, err := client.call(peerRESTMethodLoadDecommission, nil, nil, -1)
+	if err != nil {
+		return err
+	}
+	defer http.DrainBody(respBody)
+	return nil
+}
+
 // LoadGroup - send load group command to peers.
 func (client *peerRESTClient) LoadGroup(group string) error {
 	values := make(url.Values)
@@ -558,6 +621,20 @@ func (client *peerRESTClient) BackgroundHealStatus() (madmin.BgHealState, error)
 	return state, err
 }
 
+// DrivesHealStatus - returns the healing progress of the replaced
+// drives of the peer.
+func (client *peerRESTClient) DrivesHealStatus() ([]madmin.DriveHealInfo, error) {
+	respBody, err := client.call(peerRESTMethodDrivesHealStatus, nil, nil, -1)
+	if err != nil {
+		return nil, err
+	}
+	defer http.DrainBody(respBody)
+
+	var drives []madmin.DriveHealInfo
+	err = gob.NewDecoder(respBody).Decode(&drives)
+	return drives, err
+}
+
 // BgLifecycleOpsStatus describes the status
 // of the background lifecycle operations
 type BgLifecycleOpsStatus struct {
@@ -584,10 +661,47 @@ func (client *peerRESTClient) BackgroundOpsStatus() (BgOpsStatus, error) {
 	return state, err
 }
 
-func (client *peerRESTClient) doTrace(traceCh chan interface{}, doneCh chan struct{}, trcAll, trcErr bool) {
+// APIStats - fetch the S3 API statistics of the peer node.
+func (client *peerRESTClient) APIStats() (stats APIStats, err error) {
+	respBody, err := client.call(peerRESTMethodAPIStats, nil, nil, -1)
+	if err != nil {
+		return stats, err
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&stats)
+	return stats, err
+}
+
+// NotificationTargets - fetch the delivery state of the notification
+// targets of the peer node.
+func (client *peerRESTClient) NotificationTargets() (infos []madmin.NotificationTargetInfo, err error) {
+	respBody, err := client.call(peerRESTMethodNotificationTargets, nil, nil, -1)
+	if err != nil {
+		return nil, err
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&infos)
+	return infos, err
+}
+
+// NotificationQueue - peek at, purge or replay the queued events of a
+// notification target of the peer node.
+func (client *peerRESTClient) NotificationQueue(arn, action string, count int) (info madmin.NotificationQueueInfo, err error) {
 	values := make(url.Values)
-	values.Set(peerRESTTraceAll, strconv.FormatBool(trcAll))
-	values.Set(peerRESTTraceErr, strconv.FormatBool(trcErr))
+	values.Set(peerRESTARN, arn)
+	values.Set(peerRESTQueueAction, action)
+	values.Set(peerRESTQueueCount, strconv.Itoa(count))
+	respBody, err := client.call(peerRESTMethodNotificationQueue, values, nil, -1)
+	if err != nil {
+		return info, err
+	}
+	defer http.DrainBody(respBody)
+	err = gob.NewDecoder(respBody).Decode(&info)
+	return info, err
+}
+
+func (client *peerRESTClient) doTrace(traceCh chan interface{}, doneCh chan struct{}, opts traceOptions) {
+	values := opts.queryValues()
 
 	// To cancel the REST request in case doneCh gets closed.
 	ctx, cancel := context.WithCancel(context.Background())
@@ -627,10 +741,10 @@ func (client *peerRESTClient) doTrace(traceCh chan interface{}, doneCh chan stru
 }
 
 // Trace - send http trace request to peer nodes
-func (client *peerRESTClient) Trace(traceCh chan interface{}, doneCh chan struct{}, trcAll, trcErr bool) {
+func (client *peerRESTClient) Trace(traceCh chan interface{}, doneCh chan struct{}, opts traceOptions) {
 	go func() {
 		for {
-			client.doTrace(traceCh, doneCh, trcAll, trcErr)
+			client.doTrace(traceCh, doneCh, opts)
 			select {
 			case <-doneCh:
 				return
@@ -642,6 +756,68 @@ func (client *peerRESTClient) Trace(traceCh chan interface{}, doneCh chan struct
 	}()
 }
 
+func (client *peerRESTClient) doEventStream(opts listenOptions, pos *listenPosition, eventCh chan<- listenEvent, doneCh <-chan struct{}) *listenPosition {
+	values := opts.queryValues()
+	if pos != nil {
+		values.Set(peerRESTListenEpoch, strconv.FormatInt(pos.Epoch, 10))
+		values.Set(peerRESTListenSeq, strconv.FormatUint(pos.Seq, 10))
+	}
+
+	// To cancel the REST request in case doneCh gets closed.
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancelCh := make(chan struct{})
+	defer close(cancelCh)
+	go func() {
+		select {
+		case <-doneCh:
+		case <-cancelCh:
+			// There was an error in the REST request.
+		}
+		cancel()
+	}()
+
+	respBody, err := client.callWithContext(ctx, peerRESTMethodEventStream, values, nil, -1)
+	defer http.DrainBody(respBody)
+
+	if err != nil {
+		return pos
+	}
+
+	dec := gob.NewDecoder(respBody)
+	for {
+		var ev listenEvent
+		if err = dec.Decode(&ev); err != nil {
+			return pos
+		}
+		// Block until the listener takes the event, the
+		// peer keeps the events it falls behind with.
+		select {
+		case eventCh <- ev:
+		case <-doneCh:
+			return pos
+		}
+		pos = &ev.Pos
+	}
+}
+
+// EventStream - sends the events of the peer node matching opts after
+// pos, or after its last event if pos is nil, to eventCh until doneCh
+// is closed.
+func (client *peerRESTClient) EventStream(opts listenOptions, pos *listenPosition, eventCh chan<- listenEvent, doneCh <-chan struct{}) {
+	go func() {
+		for {
+			pos = client.doEventStream(opts, pos, eventCh, doneCh)
+			select {
+			case <-doneCh:
+				return
+			case <-time.After(5 * time.Second):
+				// There was error in the REST request, retry after sometime as probably the peer is down.
+			}
+		}
+	}()
+}
+
 // ConsoleLog - sends request to peer nodes to get console logs
 func (client *peerRESTClient) ConsoleLog(logCh chan interface{}, doneCh chan struct{}) {
 	go func() {
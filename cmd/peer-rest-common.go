@@ -16,7 +16,7 @@
 
 package cmd
 
-const peerRESTVersion = "v5"
+const peerRESTVersion = "v10"
 const peerRESTPath = minioReservedBucketPath + "/peer/" + peerRESTVersion
 
 const (
@@ -30,6 +30,7 @@ const (
 	peerRESTMethodServerUpdate             = "serverupdate"
 	peerRESTMethodSignalService            = "signalservice"
 	peerRESTMethodBackgroundHealStatus     = "backgroundhealstatus"
+	peerRESTMethodDrivesHealStatus         = "driveshealstatus"
 	peerRESTMethodBackgroundOpsStatus      = "backgroundopsstatus"
 	peerRESTMethodGetLocks                 = "getlocks"
 	peerRESTMethodBucketPolicyRemove       = "removebucketpolicy"
@@ -51,26 +52,50 @@ const (
 	peerRESTMethodTrace                    = "trace"
 	peerRESTMethodBucketLifecycleSet       = "setbucketlifecycle"
 	peerRESTMethodBucketLifecycleRemove    = "removebucketlifecycle"
+	peerRESTMethodBucketACLSet             = "setbucketacl"
+	peerRESTMethodBucketLoggingSet         = "setbucketlogging"
+	peerRESTMethodBucketStorageClassSet    = "setbucketstorageclass"
 	peerRESTMethodLog                      = "log"
 	peerRESTMethodHardwareCPUInfo          = "cpuhardwareinfo"
+	peerRESTMethodLoadDecommission         = "loaddecommission"
+	peerRESTMethodAPIStats                 = "apistats"
+	peerRESTMethodNotificationTargets      = "notificationtargets"
+	peerRESTMethodNotificationQueue        = "notificationqueue"
+	peerRESTMethodEventStream              = "eventstream"
 )
 
 const (
-	peerRESTNetPerfSize   = "netperfsize"
-	peerRESTDrivePerfSize = "driveperfsize"
-	peerRESTBucket        = "bucket"
-	peerRESTUser          = "user"
-	peerRESTGroup         = "group"
-	peerRESTUserTemp      = "user-temp"
-	peerRESTPolicy        = "policy"
-	peerRESTUserOrGroup   = "user-or-group"
-	peerRESTIsGroup       = "is-group"
-	peerRESTUpdateURL     = "updateURL"
-	peerRESTSha256Hex     = "sha256Hex"
-	peerRESTLatestRelease = "latestReleaseTime"
-	peerRESTSignal        = "signal"
-	peerRESTProfiler      = "profiler"
-	peerRESTDryRun        = "dry-run"
-	peerRESTTraceAll      = "all"
-	peerRESTTraceErr      = "err"
+	peerRESTNetPerfSize     = "netperfsize"
+	peerRESTDrivePerfSize   = "driveperfsize"
+	peerRESTBucket          = "bucket"
+	peerRESTUser            = "user"
+	peerRESTGroup           = "group"
+	peerRESTUserTemp        = "user-temp"
+	peerRESTPolicy          = "policy"
+	peerRESTUserOrGroup     = "user-or-group"
+	peerRESTIsGroup         = "is-group"
+	peerRESTUpdateURL       = "updateURL"
+	peerRESTSha256Hex       = "sha256Hex"
+	peerRESTLatestRelease   = "latestReleaseTime"
+	peerRESTSignal          = "signal"
+	peerRESTProfiler        = "profiler"
+	peerRESTDryRun          = "dry-run"
+	peerRESTTraceAll        = "all"
+	peerRESTTraceErr        = "err"
+	peerRESTTraceStorage    = "storage"
+	peerRESTTraceLock       = "lock"
+	peerRESTTraceNode       = "node"
+	peerRESTTraceAPI        = "api"
+	peerRESTTraceBucket     = "bucket"
+	peerRESTTraceStatusCode = "statuscode"
+	peerRESTTraceThreshold  = "threshold"
+	peerRESTACL             = "acl"
+	peerRESTARN             = "arn"
+	peerRESTQueueAction     = "action"
+	peerRESTQueueCount      = "count"
+	peerRESTListenPrefix    = "prefix"
+	peerRESTListenSuffix    = "suffix"
+	peerRESTListenEvents    = "events"
+	peerRESTListenEpoch     = "epoch"
+	peerRESTListenSeq       = "seq"
 )
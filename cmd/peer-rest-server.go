@@ -30,8 +30,11 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/accesslog"
+	"github.com/minio/minio/pkg/acl"
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/lifecycle"
+	"github.com/minio/minio/pkg/madmin"
 	xnet "github.com/minio/minio/pkg/net"
 	"github.com/minio/minio/pkg/policy"
 	trace "github.com/minio/minio/pkg/trace"
@@ -322,6 +325,27 @@ func (s *peerRESTServer) LoadUsersHandler(w http.ResponseWriter, r *http.Request
 	w.(http.Flusher).Flush()
 }
 
+// LoadDecommissionHandler - reloads the decommission state.
+func (s *peerRESTServer) LoadDecommissionHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	objAPI := newObjectLayerFn()
+	if objAPI == nil {
+		s.writeErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	if err := globalDecommissionSys.load(objAPI); err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	w.(http.Flusher).Flush()
+}
+
 // LoadGroupHandler - reloads group along with members list.
 func (s *peerRESTServer) LoadGroupHandler(w http.ResponseWriter, r *http.Request) {
 	if !s.IsValid(w, r) {
@@ -496,6 +520,13 @@ func (s *peerRESTServer) DeleteBucketHandler(w http.ResponseWriter, r *http.Requ
 
 	globalNotificationSys.RemoveNotification(bucketName)
 	globalPolicySys.Remove(bucketName)
+	globalACLSys.Remove(bucketName)
+	globalBucketLoggingSys.Remove(bucketName)
+	globalBucketStorageClassSys.Remove(bucketName)
+	globalAPIStats.removeBucket(bucketName)
+	if objAPI := newObjectLayerFn(); objAPI != nil {
+		dropListIndex(objAPI, bucketName)
+	}
 
 	w.(http.Flusher).Flush()
 }
@@ -625,6 +656,86 @@ func (s *peerRESTServer) SetBucketLifecycleHandler(w http.ResponseWriter, r *htt
 	w.(http.Flusher).Flush()
 }
 
+// SetBucketACLHandler - Set bucket canned ACL.
+func (s *peerRESTServer) SetBucketACLHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucketName := vars[peerRESTBucket]
+	if bucketName == "" {
+		s.writeErrorResponse(w, errors.New("Bucket name is missing"))
+		return
+	}
+
+	cannedACL, err := acl.Parse(vars[peerRESTACL])
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	globalACLSys.Set(bucketName, cannedACL)
+	w.(http.Flusher).Flush()
+}
+
+// SetBucketLoggingHandler - Set bucket logging status.
+func (s *peerRESTServer) SetBucketLoggingHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucketName := vars[peerRESTBucket]
+	if bucketName == "" {
+		s.writeErrorResponse(w, errors.New("Bucket name is missing"))
+		return
+	}
+	if r.ContentLength < 0 {
+		s.writeErrorResponse(w, errInvalidArgument)
+		return
+	}
+
+	config, err := accesslog.ParseConfig(r.Body)
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	globalBucketLoggingSys.Set(bucketName, *config)
+	w.(http.Flusher).Flush()
+}
+
+// SetBucketStorageClassHandler - Set bucket default storage classes.
+func (s *peerRESTServer) SetBucketStorageClassHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	bucketName := vars[peerRESTBucket]
+	if bucketName == "" {
+		s.writeErrorResponse(w, errors.New("Bucket name is missing"))
+		return
+	}
+	if r.ContentLength < 0 {
+		s.writeErrorResponse(w, errInvalidArgument)
+		return
+	}
+
+	var sc madmin.BucketStorageClass
+	if err := gob.NewDecoder(r.Body).Decode(&sc); err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	globalBucketStorageClassSys.Set(bucketName, sc)
+	w.(http.Flusher).Flush()
+}
+
 type remoteTargetExistsResp struct {
 	Exists bool
 }
@@ -866,8 +977,11 @@ func (s *peerRESTServer) TraceHandler(w http.ResponseWriter, r *http.Request) {
 		s.writeErrorResponse(w, errors.New("Invalid request"))
 		return
 	}
-	trcAll := r.URL.Query().Get(peerRESTTraceAll) == "true"
-	trcErr := r.URL.Query().Get(peerRESTTraceErr) == "true"
+	opts, err := getTraceOptions(r.URL.Query())
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
 
 	w.WriteHeader(http.StatusOK)
 	w.(http.Flusher).Flush()
@@ -879,9 +993,7 @@ func (s *peerRESTServer) TraceHandler(w http.ResponseWriter, r *http.Request) {
 	// Use buffered channel to take care of burst sends or slow w.Write()
 	ch := make(chan interface{}, 2000)
 
-	globalHTTPTrace.Subscribe(ch, doneCh, func(entry interface{}) bool {
-		return mustTrace(entry, trcAll, trcErr)
-	})
+	subscribeTrace(ch, doneCh, opts)
 
 	keepAliveTicker := time.NewTicker(500 * time.Millisecond)
 	defer keepAliveTicker.Stop()
@@ -903,6 +1015,53 @@ func (s *peerRESTServer) TraceHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// EventStreamHandler - sends the events of this server matching the
+// options of the request to the peer listening for them.
+func (s *peerRESTServer) EventStreamHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+	if globalNotificationSys == nil || globalNotificationSys.eventStream == nil {
+		s.writeErrorResponse(w, errServerNotInitialized)
+		return
+	}
+
+	values := r.URL.Query()
+	opts, err := getListenOptions(values)
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	stream := globalNotificationSys.eventStream
+	pos := stream.head()
+	if values.Get(peerRESTListenEpoch) != "" {
+		if pos.Epoch, err = strconv.ParseInt(values.Get(peerRESTListenEpoch), 10, 64); err != nil {
+			s.writeErrorResponse(w, err)
+			return
+		}
+		if pos.Seq, err = strconv.ParseUint(values.Get(peerRESTListenSeq), 10, 64); err != nil {
+			s.writeErrorResponse(w, err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+
+	// The stream is read at the pace of the peer, publishing
+	// events does not wait for it.
+	enc := gob.NewEncoder(w)
+	stream.follow(pos, opts, func(ev listenEvent) error {
+		if err := enc.Encode(&ev); err != nil {
+			return err
+		}
+		w.(http.Flusher).Flush()
+		return nil
+	}, r.Context().Done())
+}
+
 func (s *peerRESTServer) BackgroundHealStatusHandler(w http.ResponseWriter, r *http.Request) {
 	if !s.IsValid(w, r) {
 		s.writeErrorResponse(w, errors.New("invalid request"))
@@ -917,6 +1076,22 @@ func (s *peerRESTServer) BackgroundHealStatusHandler(w http.ResponseWriter, r *h
 	logger.LogIf(ctx, gob.NewEncoder(w).Encode(state))
 }
 
+// DrivesHealStatusHandler - returns the healing progress of the
+// replaced drives of this server.
+func (s *peerRESTServer) DrivesHealStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "DrivesHealStatus")
+
+	drives := getLocalDrivesHealStatus()
+
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(drives))
+}
+
 func (s *peerRESTServer) BackgroundOpsStatusHandler(w http.ResponseWriter, r *http.Request) {
 	if !s.IsValid(w, r) {
 		s.writeErrorResponse(w, errors.New("invalid request"))
@@ -933,6 +1108,59 @@ func (s *peerRESTServer) BackgroundOpsStatusHandler(w http.ResponseWriter, r *ht
 	logger.LogIf(ctx, gob.NewEncoder(w).Encode(state))
 }
 
+// APIStatsHandler - returns the S3 API statistics of this node.
+func (s *peerRESTServer) APIStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "APIStats")
+
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(globalAPIStats.stats()))
+}
+
+// NotificationTargetsHandler - returns the delivery state of the
+// notification targets of this node.
+func (s *peerRESTServer) NotificationTargetsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "NotificationTargets")
+
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(localNotificationTargets()))
+}
+
+// NotificationQueueHandler - peeks at, purges or replays the queued
+// events of a notification target of this node.
+func (s *peerRESTServer) NotificationQueueHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.IsValid(w, r) {
+		s.writeErrorResponse(w, errors.New("Invalid request"))
+		return
+	}
+
+	ctx := newContext(r, w, "NotificationQueue")
+
+	vars := mux.Vars(r)
+	arn, err := event.ParseARN(vars[peerRESTARN])
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+	count, err := strconv.Atoi(vars[peerRESTQueueCount])
+	if err != nil {
+		s.writeErrorResponse(w, err)
+		return
+	}
+
+	defer w.(http.Flusher).Flush()
+	logger.LogIf(ctx, gob.NewEncoder(w).Encode(localNotificationQueue(arn, vars[peerRESTQueueAction], count)))
+}
+
 // ConsoleLogHandler sends console logs of this node back to peer rest client
 func (s *peerRESTServer) ConsoleLogHandler(w http.ResponseWriter, r *http.Request) {
 	if !s.IsValid(w, r) {
@@ -1003,6 +1231,7 @@ func registerPeerRESTHandlers(router *mux.Router) {
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodDeleteUser).HandlerFunc(httpTraceAll(server.LoadUserHandler)).Queries(restQueries(peerRESTUser)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadUser).HandlerFunc(httpTraceAll(server.LoadUserHandler)).Queries(restQueries(peerRESTUser, peerRESTUserTemp)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadUsers).HandlerFunc(httpTraceAll(server.LoadUsersHandler))
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadDecommission).HandlerFunc(httpTraceAll(server.LoadDecommissionHandler))
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLoadGroup).HandlerFunc(httpTraceAll(server.LoadGroupHandler)).Queries(restQueries(peerRESTGroup)...)
 
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodStartProfiling).HandlerFunc(httpTraceAll(server.StartProfilingHandler)).Queries(restQueries(peerRESTProfiler)...)
@@ -1016,10 +1245,18 @@ func registerPeerRESTHandlers(router *mux.Router) {
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodReloadFormat).HandlerFunc(httpTraceHdrs(server.ReloadFormatHandler)).Queries(restQueries(peerRESTDryRun)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketLifecycleSet).HandlerFunc(httpTraceHdrs(server.SetBucketLifecycleHandler)).Queries(restQueries(peerRESTBucket)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketLifecycleRemove).HandlerFunc(httpTraceHdrs(server.RemoveBucketLifecycleHandler)).Queries(restQueries(peerRESTBucket)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketLoggingSet).HandlerFunc(httpTraceHdrs(server.SetBucketLoggingHandler)).Queries(restQueries(peerRESTBucket)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketStorageClassSet).HandlerFunc(httpTraceHdrs(server.SetBucketStorageClassHandler)).Queries(restQueries(peerRESTBucket)...)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBucketACLSet).HandlerFunc(httpTraceHdrs(server.SetBucketACLHandler)).Queries(restQueries(peerRESTBucket, peerRESTACL)...)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBackgroundOpsStatus).HandlerFunc(server.BackgroundOpsStatusHandler)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodAPIStats).HandlerFunc(server.APIStatsHandler)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodNotificationTargets).HandlerFunc(server.NotificationTargetsHandler)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodNotificationQueue).HandlerFunc(httpTraceHdrs(server.NotificationQueueHandler)).Queries(restQueries(peerRESTARN, peerRESTQueueAction, peerRESTQueueCount)...)
 
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodTrace).HandlerFunc(server.TraceHandler)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodEventStream).HandlerFunc(server.EventStreamHandler)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodBackgroundHealStatus).HandlerFunc(server.BackgroundHealStatusHandler)
+	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodDrivesHealStatus).HandlerFunc(server.DrivesHealStatusHandler)
 	subrouter.Methods(http.MethodPost).Path(SlashSeparator + peerRESTMethodLog).HandlerFunc(server.ConsoleLogHandler)
 
 	router.MethodNotAllowedHandler = http.HandlerFunc(httpTraceAll(versionMismatchHandler))
@@ -64,6 +64,29 @@ func (sys *PolicySys) Remove(bucketName string) {
 	delete(sys.bucketPolicyMap, bucketName)
 }
 
+// IsDenied - checks whether a deny statement of the bucket policy
+// matches the given args.
+func (sys *PolicySys) IsDenied(args policy.Args) bool {
+	if globalIsGateway {
+		objAPI := newObjectLayerFn()
+		if objAPI != nil {
+			config, err := objAPI.GetBucketPolicy(context.Background(), args.BucketName)
+			if err == nil {
+				return config.IsDenied(args)
+			}
+		}
+		return false
+	}
+
+	sys.RLock()
+	defer sys.RUnlock()
+
+	if p, found := sys.bucketPolicyMap[args.BucketName]; found {
+		return p.IsDenied(args)
+	}
+	return false
+}
+
 // IsAllowed - checks given policy args is allowed to continue the Rest API.
 func (sys *PolicySys) IsAllowed(args policy.Args) bool {
 	if globalIsGateway {
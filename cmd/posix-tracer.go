@@ -0,0 +1,189 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio/pkg/trace"
+)
+
+// posixTracer - publishes a trace record for every call to a local
+// disk while a client is listening on trace.
+type posixTracer struct {
+	StorageAPI
+}
+
+// newPosixTracer - returns the local disk publishing its calls.
+func newPosixTracer(disk StorageAPI) StorageAPI {
+	return &posixTracer{disk}
+}
+
+// Returns the start time of a storage call, zero if nobody
+// listens on storage calls.
+func storageTraceStart() time.Time {
+	if atomic.LoadInt32(&storageTraceSubscribers) == 0 {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// Returns the start time of a lock call, zero if nobody
+// listens on lock calls.
+func lockTraceStart() time.Time {
+	if atomic.LoadInt32(&lockTraceSubscribers) == 0 {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+func (p *posixTracer) publish(call string, start time.Time, volume, path string, err error) {
+	if start.IsZero() {
+		return
+	}
+	globalHTTPTrace.Publish(newCallTrace(trace.Storage, "storage."+call, p.String(), pathJoin(volume, path), start, err))
+}
+
+// newCallTrace - returns the trace record of a storage or lock call.
+func newCallTrace(traceType trace.Type, funcName, disk, path string, start time.Time, err error) trace.Info {
+	info := trace.Info{
+		TraceType: traceType,
+		NodeName:  getTraceNodeName(),
+		FuncName:  funcName,
+		CallInfo: trace.CallInfo{
+			Time:     start.UTC(),
+			Disk:     disk,
+			Path:     path,
+			Duration: time.Since(start),
+		},
+	}
+	if err != nil {
+		info.CallInfo.Error = err.Error()
+	}
+	return info
+}
+
+func (p *posixTracer) DiskInfo() (info DiskInfo, err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("DiskInfo", start, "", "", err) }()
+	return p.StorageAPI.DiskInfo()
+}
+
+func (p *posixTracer) MakeVol(volume string) (err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("MakeVol", start, volume, "", err) }()
+	return p.StorageAPI.MakeVol(volume)
+}
+
+func (p *posixTracer) ListVols() (vols []VolInfo, err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("ListVols", start, "", "", err) }()
+	return p.StorageAPI.ListVols()
+}
+
+func (p *posixTracer) StatVol(volume string) (vol VolInfo, err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("StatVol", start, volume, "", err) }()
+	return p.StorageAPI.StatVol(volume)
+}
+
+func (p *posixTracer) DeleteVol(volume string) (err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("DeleteVol", start, volume, "", err) }()
+	return p.StorageAPI.DeleteVol(volume)
+}
+
+func (p *posixTracer) Walk(volume, dirPath string, marker string, recursive bool, leafFile string,
+	readMetadataFn readMetadataFunc, endWalkCh chan struct{}) (ch chan FileInfo, err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("Walk", start, volume, dirPath, err) }()
+	return p.StorageAPI.Walk(volume, dirPath, marker, recursive, leafFile, readMetadataFn, endWalkCh)
+}
+
+func (p *posixTracer) ListDir(volume, dirPath string, count int, leafFile string) (entries []string, err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("ListDir", start, volume, dirPath, err) }()
+	return p.StorageAPI.ListDir(volume, dirPath, count, leafFile)
+}
+
+func (p *posixTracer) ReadFile(volume string, path string, offset int64, buf []byte, verifier *BitrotVerifier) (n int64, err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("ReadFile", start, volume, path, err) }()
+	return p.StorageAPI.ReadFile(volume, path, offset, buf, verifier)
+}
+
+func (p *posixTracer) AppendFile(volume string, path string, buf []byte) (err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("AppendFile", start, volume, path, err) }()
+	return p.StorageAPI.AppendFile(volume, path, buf)
+}
+
+func (p *posixTracer) CreateFile(volume, path string, size int64, reader io.Reader) (err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("CreateFile", start, volume, path, err) }()
+	return p.StorageAPI.CreateFile(volume, path, size, reader)
+}
+
+func (p *posixTracer) ReadFileStream(volume, path string, offset, length int64) (rc io.ReadCloser, err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("ReadFileStream", start, volume, path, err) }()
+	return p.StorageAPI.ReadFileStream(volume, path, offset, length)
+}
+
+func (p *posixTracer) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) (err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("RenameFile", start, dstVolume, dstPath, err) }()
+	return p.StorageAPI.RenameFile(srcVolume, srcPath, dstVolume, dstPath)
+}
+
+func (p *posixTracer) StatFile(volume string, path string) (file FileInfo, err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("StatFile", start, volume, path, err) }()
+	return p.StorageAPI.StatFile(volume, path)
+}
+
+func (p *posixTracer) DeleteFile(volume string, path string) (err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("DeleteFile", start, volume, path, err) }()
+	return p.StorageAPI.DeleteFile(volume, path)
+}
+
+func (p *posixTracer) DeleteFileBulk(volume string, paths []string) (errs []error, err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("DeleteFileBulk", start, volume, "", err) }()
+	return p.StorageAPI.DeleteFileBulk(volume, paths)
+}
+
+func (p *posixTracer) VerifyFile(volume, path string, size int64, algo BitrotAlgorithm, sum []byte, shardSize int64) (err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("VerifyFile", start, volume, path, err) }()
+	return p.StorageAPI.VerifyFile(volume, path, size, algo, sum, shardSize)
+}
+
+func (p *posixTracer) WriteAll(volume string, path string, reader io.Reader) (err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("WriteAll", start, volume, path, err) }()
+	return p.StorageAPI.WriteAll(volume, path, reader)
+}
+
+func (p *posixTracer) ReadAll(volume string, path string) (buf []byte, err error) {
+	start := storageTraceStart()
+	defer func() { p.publish("ReadAll", start, volume, path, err) }()
+	return p.StorageAPI.ReadAll(volume, path)
+}
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/tracing"
 )
 
 // DefaultRESTTimeout - default RPC timeout is one minute.
@@ -67,6 +68,8 @@ func (c *Client) CallWithContext(ctx context.Context, method string, values url.
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.newAuthToken())
 	req.Header.Set("X-Minio-Time", time.Now().UTC().Format(time.RFC3339))
+	// Calls made for a traced request continue its trace on the server.
+	tracing.Inject(ctx, req.Header)
 	if length > 0 {
 		req.ContentLength = length
 	}
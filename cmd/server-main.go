@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/gob"
 	"fmt"
 	"net/http"
@@ -29,6 +30,13 @@ import (
 	"github.com/minio/cli"
 	"github.com/minio/dsync/v2"
 	"github.com/minio/minio/cmd/config"
+	"github.com/minio/minio/cmd/config/certidentity"
+	"github.com/minio/minio/cmd/config/drivehealth"
+	"github.com/minio/minio/cmd/config/listindex"
+	"github.com/minio/minio/cmd/config/notifyrules"
+	"github.com/minio/minio/cmd/config/scrubber"
+	xtracing "github.com/minio/minio/cmd/config/tracing"
+	"github.com/minio/minio/cmd/config/xlmeta"
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/certs"
@@ -160,12 +168,16 @@ func serverHandleCmdArgs(ctx *cli.Context) {
 
 	endpoints := strings.Fields(env.Get("MINIO_ENDPOINTS", ""))
 	if len(endpoints) > 0 {
-		globalMinioAddr, globalEndpoints, setupType, globalXLSetCount, globalXLSetDriveCount, err = createServerEndpoints(globalCLIContext.Addr, endpoints...)
+		globalMinioAddr, globalEndpointZones, setupType, err = createServerEndpoints(globalCLIContext.Addr, endpoints...)
 	} else {
-		globalMinioAddr, globalEndpoints, setupType, globalXLSetCount, globalXLSetDriveCount, err = createServerEndpoints(globalCLIContext.Addr, ctx.Args()...)
+		globalMinioAddr, globalEndpointZones, setupType, err = createServerEndpoints(globalCLIContext.Addr, ctx.Args()...)
 	}
 	logger.FatalIf(err, "Invalid command line arguments")
 
+	globalEndpoints = globalEndpointZones.Endpoints()
+	globalXLSetCount = globalEndpointZones.SetCount()
+	globalXLSetDriveCount = globalEndpointZones.DrivesPerSet()
+
 	logger.LogIf(context.Background(), checkEndpointsSubOptimal(ctx, setupType, globalEndpoints))
 
 	globalMinioHost, globalMinioPort = mustSplitHostPort(globalMinioAddr)
@@ -193,6 +205,30 @@ func serverHandleEnvVars() {
 		globalServerRegion = serverRegion
 	}
 
+	var err error
+	globalCertIdentityConfig, err = certidentity.Lookup()
+	logger.FatalIf(err, "Unable to parse client certificate identity configuration from env")
+
+	globalScrubberConfig, err = scrubber.Lookup()
+	logger.FatalIf(err, "Unable to parse scrubber configuration from env")
+
+	globalDriveHealthConfig, err = drivehealth.Lookup()
+	logger.FatalIf(err, "Unable to parse drive health configuration from env")
+
+	globalListIndexConfig, err = listindex.Lookup()
+	logger.FatalIf(err, "Unable to parse list index configuration from env")
+
+	globalNotifyRulesConfig, err = notifyrules.Lookup()
+	logger.FatalIf(err, "Unable to parse notification rules configuration from env")
+
+	globalXLMetaConfig, err = xlmeta.Lookup()
+	logger.FatalIf(err, "Unable to parse metadata format configuration from env")
+
+	tracingConfig, err := xtracing.Lookup()
+	logger.FatalIf(err, "Unable to parse tracing configuration from env")
+	if tracingConfig.Enabled {
+		globalTracer = newTracer(tracingConfig.Endpoint, tracingConfig.SampleRatio)
+	}
 }
 
 // serverMain handler called for 'minio server' command.
@@ -306,13 +342,18 @@ func serverMain(ctx *cli.Context) {
 	}
 
 	globalHTTPServer = xhttp.NewServer([]string{globalMinioAddr}, criticalErrorHandler{handler}, getCert)
+	if globalCertIdentityConfig.Enabled && globalHTTPServer.TLSConfig != nil {
+		// Client certificates are verified by AssumeRoleWithCertificate,
+		// requests without one are still allowed.
+		globalHTTPServer.TLSConfig.ClientAuth = tls.RequestClientCert
+	}
 	globalHTTPServer.UpdateBytesReadFunc = globalConnStats.incInputBytes
 	globalHTTPServer.UpdateBytesWrittenFunc = globalConnStats.incOutputBytes
 	go func() {
 		globalHTTPServerErrorCh <- globalHTTPServer.Start()
 	}()
 
-	newObject, err := newObjectLayer(globalEndpoints)
+	newObject, err := newObjectLayer(globalEndpointZones)
 	logger.SetDeploymentID(globalDeploymentID)
 	if err != nil {
 		// Stop watching for any certificate changes.
@@ -347,11 +388,22 @@ func serverMain(ctx *cli.Context) {
 		logger.Fatal(err, "Unable to initialize IAM system")
 	}
 
+	// Disable STS credentials of users removed from LDAP.
+	globalIAMSys.initLDAPUserCheck()
+
 	buckets, err := newObject.ListBuckets(context.Background())
 	if err != nil {
 		logger.Fatal(err, "Unable to list buckets on your backend")
 	}
 
+	// Writes of buckets which are not indexed bypass the list
+	// index, it is rebuilt once they are indexed again.
+	if globalIsXL {
+		if err = clearListIndexes(context.Background(), newObject, globalListIndexConfig, buckets); err != nil {
+			logger.Fatal(err, "Unable to initialize list index")
+		}
+	}
+
 	// Create new policy system.
 	globalPolicySys = NewPolicySys()
 
@@ -368,6 +420,42 @@ func serverMain(ctx *cli.Context) {
 		logger.Fatal(err, "Unable to initialize lifecycle system")
 	}
 
+	// Create new ACL system.
+	globalACLSys = NewACLSys()
+
+	// Initialize ACL system.
+	if err = globalACLSys.Init(buckets, newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize ACL system")
+	}
+
+	// Create new bucket logging system.
+	globalBucketLoggingSys = NewBucketLoggingSys()
+
+	// Initialize bucket logging system.
+	if err = globalBucketLoggingSys.Init(buckets, newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize bucket logging system")
+	}
+
+	// Create new bucket storage class system.
+	globalBucketStorageClassSys = NewBucketStorageClassSys()
+
+	// Initialize bucket storage class system.
+	if err = globalBucketStorageClassSys.Init(buckets, newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize bucket storage class system")
+	}
+
+	// Create new decommission system.
+	globalDecommissionSys = NewDecommissionSys()
+
+	// Initialize decommission system, resumes a running decommission.
+	if err = globalDecommissionSys.Init(newObject); err != nil {
+		logger.Fatal(err, "Unable to initialize decommission system")
+	}
+
+	// Deliver server access logs of buckets with logging enabled.
+	globalAccessLogger = newAccessLogger()
+	logger.SetAccessTarget(globalAccessLogger)
+
 	// Create new notification system.
 	globalNotificationSys = NewNotificationSys(globalServerConfig, globalEndpoints)
 
@@ -382,11 +470,19 @@ func serverMain(ctx *cli.Context) {
 	verifyObjectLayerFeatures("server", newObject)
 
 	initDailyLifecycle()
+	initBucketUsageCrawler()
 
 	if globalIsXL {
 		initBackgroundHealing()
+		initLocalDisksAutoHeal()
 		initDailyHeal()
 		initDailySweeper()
+		if globalScrubberConfig.Enabled {
+			initBackgroundScrubber()
+		}
+		if globalXLMetaConfig.Binary {
+			initBackgroundXLMetaMigration()
+		}
 	}
 
 	globalObjLayerMutex.Lock()
@@ -403,19 +499,14 @@ func serverMain(ctx *cli.Context) {
 }
 
 // Initialize object layer with the supplied disks, objectLayer is nil upon any error.
-func newObjectLayer(endpoints EndpointList) (newObject ObjectLayer, err error) {
+func newObjectLayer(endpointZones EndpointZones) (newObject ObjectLayer, err error) {
 	// For FS only, directly use the disk.
 
-	isFS := len(endpoints) == 1
+	isFS := len(endpointZones) == 1 && len(endpointZones[0].Endpoints) == 1
 	if isFS {
 		// Initialize new FS object layer.
-		return NewFSObjectLayer(endpoints[0].Path)
-	}
-
-	format, err := waitForFormatXL(context.Background(), endpoints[0].IsLocal, endpoints, globalXLSetCount, globalXLSetDriveCount)
-	if err != nil {
-		return nil, err
+		return NewFSObjectLayer(endpointZones[0].Endpoints[0].Path)
 	}
 
-	return newXLSets(endpoints, format, len(format.XL.Sets), len(format.XL.Sets[0]))
+	return newXLZones(endpointZones)
 }
@@ -17,9 +17,11 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"io"
@@ -35,6 +37,9 @@ import (
 
 	humanize "github.com/dustin/go-humanize"
 	xhttp "github.com/minio/minio/cmd/http"
+	"github.com/minio/minio/pkg/accesslog"
+	"github.com/minio/minio/pkg/acl"
+	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/policy"
 )
 
@@ -77,6 +82,10 @@ func runAllTests(suite *TestSuiteCommon, c *check) {
 	suite.SetUpSuite(c)
 	suite.TestObjectDir(c)
 	suite.TestBucketPolicy(c)
+	suite.TestBucketACL(c)
+	suite.TestObjectACLPermission(c)
+	suite.TestBucketACLDeniedByPolicy(c)
+	suite.TestBucketLogging(c)
 	suite.TestDeleteBucket(c)
 	suite.TestDeleteBucketNotEmpty(c)
 	suite.TestDeleteMultipleObjects(c)
@@ -116,6 +125,7 @@ func runAllTests(suite *TestSuiteCommon, c *check) {
 	suite.TestObjectMultipartListError(c)
 	suite.TestObjectValidMD5(c)
 	suite.TestObjectMultipart(c)
+	suite.TestListenNotificationStreamHandler(c)
 	suite.TearDownSuite(c)
 }
 
@@ -381,6 +391,97 @@ func (s *TestSuiteCommon) TestBucketPolicy(c *check) {
 	c.Assert(response.StatusCode, http.StatusNotFound)
 }
 
+// TestBucketLogging - validates PUT and GET bucket logging operations.
+func (s *TestSuiteCommon) TestBucketLogging(c *check) {
+	bucketName := getRandomBucketName()
+	targetBucketName := getRandomBucketName()
+
+	client := http.Client{Transport: s.transport}
+	for _, bucket := range []string{bucketName, targetBucketName} {
+		// HTTP request to create the bucket.
+		request, err := newTestSignedRequest("PUT", getMakeBucketURL(s.endPoint, bucket),
+			0, nil, s.accessKey, s.secretKey, s.signer)
+		c.Assert(err, nil)
+
+		response, err := client.Do(request)
+		c.Assert(err, nil)
+		c.Assert(response.StatusCode, http.StatusOK)
+	}
+
+	loggingStatusBuf := `<BucketLoggingStatus><LoggingEnabled><TargetBucket>%s</TargetBucket><TargetPrefix>logs/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`
+
+	// Logging into a non-existent target bucket is rejected.
+	loggingStatus := fmt.Sprintf(loggingStatusBuf, getRandomBucketName())
+	request, err := newTestSignedRequest("PUT", s.endPoint+SlashSeparator+bucketName+"?logging",
+		int64(len(loggingStatus)), strings.NewReader(loggingStatus), s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	response, err := client.Do(request)
+	c.Assert(err, nil)
+	verifyError(c, response, "InvalidTargetBucketForLogging", "The target bucket for logging does not exist.", http.StatusBadRequest)
+
+	// Enable logging into the target bucket.
+	loggingStatus = fmt.Sprintf(loggingStatusBuf, targetBucketName)
+	request, err = newTestSignedRequest("PUT", s.endPoint+SlashSeparator+bucketName+"?logging",
+		int64(len(loggingStatus)), strings.NewReader(loggingStatus), s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// Fetch the logging status.
+	request, err = newTestSignedRequest("GET", s.endPoint+SlashSeparator+bucketName+"?logging",
+		0, nil, s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	config, err := accesslog.ParseConfig(response.Body)
+	c.Assert(err, nil)
+	c.Assert(config.IsEnabled(), true)
+	c.Assert(config.LoggingEnabled.TargetBucket, targetBucketName)
+	c.Assert(config.LoggingEnabled.TargetPrefix, "logs/")
+
+	// Disable logging.
+	loggingStatus = `<BucketLoggingStatus></BucketLoggingStatus>`
+	request, err = newTestSignedRequest("PUT", s.endPoint+SlashSeparator+bucketName+"?logging",
+		int64(len(loggingStatus)), strings.NewReader(loggingStatus), s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	_, ok := globalBucketLoggingSys.Get(bucketName)
+	c.Assert(ok, false)
+
+	// Anonymous users allowed to configure logging of the bucket
+	// may not deliver logs into a target bucket they cannot write.
+	bucketPolicyStr := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Action":["s3:PutBucketLogging"],"Effect":"Allow","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::%s"]}]}`, bucketName)
+	request, err = newTestSignedRequest("PUT", getPutPolicyURL(s.endPoint, bucketName),
+		int64(len(bucketPolicyStr)), bytes.NewReader([]byte(bucketPolicyStr)), s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusNoContent)
+
+	loggingStatus = fmt.Sprintf(loggingStatusBuf, targetBucketName)
+	request, err = newTestRequest("PUT", s.endPoint+SlashSeparator+bucketName+"?logging",
+		int64(len(loggingStatus)), strings.NewReader(loggingStatus))
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	verifyError(c, response, "AccessDenied", "Access Denied.", http.StatusForbidden)
+
+	_, ok = globalBucketLoggingSys.Get(bucketName)
+	c.Assert(ok, false)
+}
+
 // TestDeleteBucket - validates DELETE bucket operation.
 func (s *TestSuiteCommon) TestDeleteBucket(c *check) {
 	bucketName := getRandomBucketName()
@@ -521,6 +622,98 @@ func (s *TestSuiteCommon) TestListenBucketNotificationHandler(c *check) {
 	}
 }
 
+// Tests streaming events as Server-Sent Events and resuming the stream from a cursor.
+func (s *TestSuiteCommon) TestListenNotificationStreamHandler(c *check) {
+	client := http.Client{Transport: s.transport}
+
+	// listen - opens a stream resuming from cursor if it is not empty.
+	listen := func(bucketName string, events []string, cursor string) *http.Response {
+		req, err := newTestSignedRequest("GET",
+			getListenNotificationStreamURL(s.endPoint, bucketName, "sse", events),
+			0, nil, s.accessKey, s.secretKey, s.signer)
+		c.Assert(err, nil)
+		if cursor != "" {
+			req.Header.Set("Last-Event-ID", cursor)
+		}
+		response, err := client.Do(req)
+		c.Assert(err, nil)
+		c.Assert(response.StatusCode, http.StatusOK)
+		return response
+	}
+
+	// readEvent - returns the ID and the first record of the next event with records.
+	readEvent := func(reader *bufio.Reader) (string, event.Event) {
+		var id string
+		for {
+			line, err := reader.ReadString('\n')
+			c.Assert(err, nil)
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "data: "):
+				var msg listenMessage
+				c.Assert(json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &msg), nil)
+				if len(msg.Records) > 0 {
+					return id, msg.Records[0]
+				}
+			}
+		}
+	}
+
+	putObject := func(bucketName, objectName string) {
+		req, err := newTestSignedRequest("PUT", getPutObjectURL(s.endPoint, bucketName, objectName),
+			0, nil, s.accessKey, s.secretKey, s.signer)
+		c.Assert(err, nil)
+		response, err := client.Do(req)
+		c.Assert(err, nil)
+		c.Assert(response.StatusCode, http.StatusOK)
+	}
+
+	// Bucket created events reach listeners of all buckets.
+	response := listen("", []string{"s3:BucketCreated:*"}, "")
+	bucketName := getRandomBucketName()
+	req, err := newTestSignedRequest("PUT", getMakeBucketURL(s.endPoint, bucketName),
+		0, nil, s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+	bucketResponse, err := client.Do(req)
+	c.Assert(err, nil)
+	c.Assert(bucketResponse.StatusCode, http.StatusOK)
+
+	reader := bufio.NewReader(response.Body)
+	for {
+		_, eventData := readEvent(reader)
+		c.Assert(eventData.EventName, event.BucketCreated)
+		if eventData.S3.Bucket.Name == bucketName {
+			break
+		}
+	}
+	response.Body.Close()
+
+	events := []string{"s3:ObjectCreated:*"}
+	response = listen(bucketName, events, "")
+	putObject(bucketName, "object1")
+	cursor, eventData := readEvent(bufio.NewReader(response.Body))
+	c.Assert(eventData.S3.Object.Key, "object1")
+	response.Body.Close()
+
+	// Events published while disconnected are sent after the cursor.
+	putObject(bucketName, "object2")
+	response = listen(bucketName, events, cursor)
+	_, eventData = readEvent(bufio.NewReader(response.Body))
+	c.Assert(eventData.S3.Object.Key, "object2")
+	response.Body.Close()
+
+	// An invalid stream type is rejected.
+	req, err = newTestSignedRequest("GET",
+		getListenNotificationStreamURL(s.endPoint, bucketName, "invalid", events),
+		0, nil, s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+	response, err = client.Do(req)
+	c.Assert(err, nil)
+	verifyError(c, response, "InvalidRequest", "Invalid Request", http.StatusBadRequest)
+}
+
 // Test deletes multple objects and verifies server resonse.
 func (s *TestSuiteCommon) TestDeleteMultipleObjects(c *check) {
 	// generate a random bucket name.
@@ -1804,10 +1997,16 @@ func (s *TestSuiteCommon) TestPutBucketErrors(c *check) {
 	verifyError(c, response, "BucketAlreadyOwnedByYou", "Your previous request to create the named bucket succeeded and you already own it.",
 		http.StatusConflict)
 
-	// request for ACL.
-	// Since MinIO server doesn't support ACL's the request is expected to fail with  "NotImplemented" error message.
-	request, err = newTestSignedRequest("PUT", s.endPoint+SlashSeparator+bucketName+"?acl",
-		0, nil, s.accessKey, s.secretKey, s.signer)
+	// request for ACL with explicit grants.
+	// Since MinIO server only supports canned ACL's the request is expected to fail with "NotImplemented" error message.
+	request, err = newTestRequest("PUT", s.endPoint+SlashSeparator+bucketName+"?acl", 0, nil)
+	c.Assert(err, nil)
+	request.Header.Set("X-Amz-Grant-Read", "uri=http://acs.amazonaws.com/groups/global/AllUsers")
+	if s.signer == signerV4 {
+		err = signRequestV4(request, s.accessKey, s.secretKey)
+	} else {
+		err = signRequestV2(request, s.accessKey, s.secretKey)
+	}
 	c.Assert(err, nil)
 
 	response, err = client.Do(request)
@@ -1815,6 +2014,295 @@ func (s *TestSuiteCommon) TestPutBucketErrors(c *check) {
 	verifyError(c, response, "NotImplemented", "A header you provided implies functionality that is not implemented", http.StatusNotImplemented)
 }
 
+// TestBucketACL - validates canned ACLs on buckets.
+func (s *TestSuiteCommon) TestBucketACL(c *check) {
+	bucketName := getRandomBucketName()
+
+	// HTTP request to create the bucket.
+	request, err := newTestSignedRequest("PUT", getMakeBucketURL(s.endPoint, bucketName),
+		0, nil, s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	client := http.Client{Transport: s.transport}
+	response, err := client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// Anonymous listing is denied for a private bucket.
+	request, err = newTestRequest("GET", getListObjectsV1URL(s.endPoint, bucketName, "", "1000", ""), 0, nil)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusForbidden)
+
+	// Set public-read canned ACL on the bucket.
+	request, err = newTestRequest("PUT", s.endPoint+SlashSeparator+bucketName+"?acl", 0, nil)
+	c.Assert(err, nil)
+	request.Header.Set(xhttp.AmzACL, "public-read")
+	if s.signer == signerV4 {
+		err = signRequestV4(request, s.accessKey, s.secretKey)
+	} else {
+		err = signRequestV2(request, s.accessKey, s.secretKey)
+	}
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// Fetch the ACL and verify the AllUsers READ grant.
+	request, err = newTestSignedRequest("GET", s.endPoint+SlashSeparator+bucketName+"?acl",
+		0, nil, s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	acp, err := acl.ParseAccessControlPolicy(response.Body)
+	c.Assert(err, nil)
+	cannedACL, err := acp.CannedACL()
+	c.Assert(err, nil)
+	c.Assert(cannedACL, acl.PublicRead)
+
+	// Anonymous listing is now allowed.
+	request, err = newTestRequest("GET", getListObjectsV1URL(s.endPoint, bucketName, "", "1000", ""), 0, nil)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// Anonymous uploads are denied for a public-read bucket.
+	data := []byte("hello world")
+	request, err = newTestRequest("PUT", getPutObjectURL(s.endPoint, bucketName, "object"), int64(len(data)), bytes.NewReader(data))
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	verifyError(c, response, "AccessDenied", "Access Denied.", http.StatusForbidden)
+
+	request, err = newTestRequest("POST", getNewMultipartURL(s.endPoint, bucketName, "multipart"), 0, nil)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	verifyError(c, response, "AccessDenied", "Access Denied.", http.StatusForbidden)
+
+	// Set public-read-write canned ACL on the bucket.
+	request, err = newTestRequest("PUT", s.endPoint+SlashSeparator+bucketName+"?acl", 0, nil)
+	c.Assert(err, nil)
+	request.Header.Set(xhttp.AmzACL, "public-read-write")
+	if s.signer == signerV4 {
+		err = signRequestV4(request, s.accessKey, s.secretKey)
+	} else {
+		err = signRequestV2(request, s.accessKey, s.secretKey)
+	}
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// Anonymous uploads are now allowed.
+	request, err = newTestRequest("PUT", getPutObjectURL(s.endPoint, bucketName, "object"), int64(len(data)), bytes.NewReader(data))
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	request, err = newTestRequest("POST", getNewMultipartURL(s.endPoint, bucketName, "multipart"), 0, nil)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	newResponse := &InitiateMultipartUploadResponse{}
+	err = xml.NewDecoder(response.Body).Decode(newResponse)
+	c.Assert(err, nil)
+	c.Assert(len(newResponse.UploadID) > 0, true)
+
+	request, err = newTestRequest("PUT", getPutObjectPartURL(s.endPoint, bucketName, "multipart", newResponse.UploadID, "1"), int64(len(data)), bytes.NewReader(data))
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	completeBytes, err := xml.Marshal(&CompleteMultipartUpload{
+		Parts: []CompletePart{{PartNumber: 1, ETag: response.Header.Get(xhttp.ETag)}},
+	})
+	c.Assert(err, nil)
+	request, err = newTestRequest("POST", getCompleteMultipartUploadURL(s.endPoint, bucketName, "multipart", newResponse.UploadID),
+		int64(len(completeBytes)), bytes.NewReader(completeBytes))
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// Invalid canned ACL is rejected.
+	request, err = newTestRequest("PUT", s.endPoint+SlashSeparator+bucketName+"?acl", 0, nil)
+	c.Assert(err, nil)
+	request.Header.Set(xhttp.AmzACL, "invalid-acl")
+	if s.signer == signerV4 {
+		err = signRequestV4(request, s.accessKey, s.secretKey)
+	} else {
+		err = signRequestV2(request, s.accessKey, s.secretKey)
+	}
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	verifyError(c, response, "InvalidArgument", "The canned ACL you provided is not valid.", http.StatusBadRequest)
+}
+
+// TestObjectACLPermission - validates that setting the ACL of new
+// objects requires the permission to change ACLs.
+func (s *TestSuiteCommon) TestObjectACLPermission(c *check) {
+	bucketName := getRandomBucketName()
+
+	request, err := newTestSignedRequest("PUT", getMakeBucketURL(s.endPoint, bucketName),
+		0, nil, s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	client := http.Client{Transport: s.transport}
+	response, err := client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// Anonymous uploads are allowed, changing ACLs is not.
+	bucketPolicyStr := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Action":["s3:PutObject"],"Effect":"Allow","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::%s/*"]}]}`, bucketName)
+	request, err = newTestSignedRequest("PUT", getPutPolicyURL(s.endPoint, bucketName),
+		int64(len(bucketPolicyStr)), bytes.NewReader([]byte(bucketPolicyStr)), s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusNoContent)
+
+	data := []byte("hello world")
+	request, err = newTestRequest("PUT", getPutObjectURL(s.endPoint, bucketName, "object"), int64(len(data)), bytes.NewReader(data))
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	request, err = newTestRequest("PUT", getPutObjectURL(s.endPoint, bucketName, "public"), int64(len(data)), bytes.NewReader(data))
+	c.Assert(err, nil)
+	request.Header.Set(xhttp.AmzACL, "public-read")
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	verifyError(c, response, "AccessDenied", "Access Denied.", http.StatusForbidden)
+
+	request, err = newTestRequest("POST", getNewMultipartURL(s.endPoint, bucketName, "public"), 0, nil)
+	c.Assert(err, nil)
+	request.Header.Set(xhttp.AmzACL, "public-read")
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	verifyError(c, response, "AccessDenied", "Access Denied.", http.StatusForbidden)
+
+	// The owner may set the ACL of new objects.
+	request, err = newTestRequest("PUT", getPutObjectURL(s.endPoint, bucketName, "public"), int64(len(data)), bytes.NewReader(data))
+	c.Assert(err, nil)
+	request.Header.Set(xhttp.AmzACL, "public-read")
+	if s.signer == signerV4 {
+		err = signRequestV4(request, s.accessKey, s.secretKey)
+	} else {
+		err = signRequestV2(request, s.accessKey, s.secretKey)
+	}
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+}
+
+// TestBucketACLDeniedByPolicy - validates that ACL grants do not
+// override deny statements of the bucket policy.
+func (s *TestSuiteCommon) TestBucketACLDeniedByPolicy(c *check) {
+	bucketName := getRandomBucketName()
+
+	request, err := newTestSignedRequest("PUT", getMakeBucketURL(s.endPoint, bucketName),
+		0, nil, s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	client := http.Client{Transport: s.transport}
+	response, err := client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// Upload an object readable by anyone.
+	data := []byte("hello world")
+	request, err = newTestRequest("PUT", getPutObjectURL(s.endPoint, bucketName, "private/object"), int64(len(data)), bytes.NewReader(data))
+	c.Assert(err, nil)
+	request.Header.Set(xhttp.AmzACL, "public-read")
+	if s.signer == signerV4 {
+		err = signRequestV4(request, s.accessKey, s.secretKey)
+	} else {
+		err = signRequestV2(request, s.accessKey, s.secretKey)
+	}
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// Set public-read canned ACL on the bucket.
+	request, err = newTestRequest("PUT", s.endPoint+SlashSeparator+bucketName+"?acl", 0, nil)
+	c.Assert(err, nil)
+	request.Header.Set(xhttp.AmzACL, "public-read")
+	if s.signer == signerV4 {
+		err = signRequestV4(request, s.accessKey, s.secretKey)
+	} else {
+		err = signRequestV2(request, s.accessKey, s.secretKey)
+	}
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// Anonymous downloads are allowed by the object ACL.
+	request, err = newTestRequest("GET", getGetObjectURL(s.endPoint, bucketName, "private/object"), 0, nil)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusOK)
+
+	// Deny anonymous downloads and listings in the bucket policy.
+	bucketPolicyStr := fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Action":["s3:GetObject"],"Effect":"Deny","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::%s/private/*"]},{"Action":["s3:ListBucket"],"Effect":"Deny","Principal":{"AWS":["*"]},"Resource":["arn:aws:s3:::%s"]}]}`, bucketName, bucketName)
+	request, err = newTestSignedRequest("PUT", getPutPolicyURL(s.endPoint, bucketName),
+		int64(len(bucketPolicyStr)), bytes.NewReader([]byte(bucketPolicyStr)), s.accessKey, s.secretKey, s.signer)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusNoContent)
+
+	// The public-read ACLs do not override the deny statements.
+	request, err = newTestRequest("GET", getGetObjectURL(s.endPoint, bucketName, "private/object"), 0, nil)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	verifyError(c, response, "AccessDenied", "Access Denied.", http.StatusForbidden)
+
+	request, err = newTestRequest("GET", getListObjectsV1URL(s.endPoint, bucketName, "", "1000", ""), 0, nil)
+	c.Assert(err, nil)
+
+	response, err = client.Do(request)
+	c.Assert(err, nil)
+	c.Assert(response.StatusCode, http.StatusForbidden)
+}
+
 func (s *TestSuiteCommon) TestGetObjectLarge10MiB(c *check) {
 	// generate a random bucket name.
 	bucketName := getRandomBucketName()
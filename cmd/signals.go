@@ -60,6 +60,9 @@ func handleSignals() {
 			logger.LogIf(context.Background(), oerr)
 		}
 
+		// Send the spans not exported yet.
+		globalTracer.Close()
+
 		return (err == nil && oerr == nil)
 	}
 
@@ -39,6 +39,8 @@ const (
 	reducedRedundancyStorageClassEnv = "MINIO_STORAGE_CLASS_RRS"
 	// Standard storage class environment variable
 	standardStorageClassEnv = "MINIO_STORAGE_CLASS_STANDARD"
+	// Named storage classes environment variable
+	customStorageClassEnv = "MINIO_STORAGE_CLASS_CUSTOM"
 	// Supported storage class scheme is EC
 	supportedStorageClassScheme = "EC"
 	// Minimum parity disks
@@ -68,13 +70,17 @@ func (sCfg *storageClassConfig) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
-	return validateParity(aux.Standard.Parity, aux.RRS.Parity)
+	return validateParity(aux.Standard.Parity, aux.RRS.Parity, nil)
 }
 
 // Validate if storage class in metadata
-// Only Standard and RRS Storage classes are supported
+// Standard, RRS and the named storage classes are supported
 func isValidStorageClassMeta(sc string) bool {
-	return sc == reducedRedundancyStorageClass || sc == standardStorageClass
+	if sc == reducedRedundancyStorageClass || sc == standardStorageClass {
+		return true
+	}
+	_, ok := globalCustomStorageClasses[sc]
+	return ok
 }
 
 func (sc *storageClass) UnmarshalText(b []byte) error {
@@ -130,9 +136,54 @@ func parseStorageClass(storageClassEnv string) (sc storageClass, err error) {
 	return sc, nil
 }
 
+// Parses given customStorageClassEnv and returns the named storage classes.
+// Supported format is a comma separated list of "NAME=Scheme:Number of parity disks",
+// e.g. "ARCHIVE=EC:8,SCRATCH=EC:2".
+func parseCustomStorageClasses(customStorageClassEnv string) (map[string]storageClass, error) {
+	classes := make(map[string]storageClass)
+	for _, entry := range strings.Split(customStorageClassEnv, ",") {
+		s := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(s) != 2 {
+			return nil, config.ErrStorageClassValue(nil).Msg("Missing storage class name in " + entry)
+		}
+
+		name := s[0]
+		if !isValidStorageClassName(name) {
+			return nil, config.ErrStorageClassValue(nil).Msg("Invalid storage class name " + name + ". Only upper case letters, digits and '_' are allowed")
+		}
+		if name == standardStorageClass || name == reducedRedundancyStorageClass {
+			return nil, config.ErrStorageClassValue(nil).Msg("Storage class " + name + " is reserved")
+		}
+		if _, ok := classes[name]; ok {
+			return nil, config.ErrStorageClassValue(nil).Msg("Storage class " + name + " is defined more than once")
+		}
+
+		sc, err := parseStorageClass(s[1])
+		if err != nil {
+			return nil, err
+		}
+		classes[name] = sc
+	}
+	return classes, nil
+}
+
+// Storage class names are sent in the x-amz-storage-class header,
+// only upper case letters, digits and '_' are allowed.
+func isValidStorageClassName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
 // Validates the parity disks.
-func validateParity(ssParity, rrsParity int) (err error) {
-	if ssParity == 0 && rrsParity == 0 {
+func validateParity(ssParity, rrsParity int, custom map[string]storageClass) (err error) {
+	if ssParity == 0 && rrsParity == 0 && len(custom) == 0 {
 		return nil
 	}
 
@@ -163,6 +214,16 @@ func validateParity(ssParity, rrsParity int) (err error) {
 			return fmt.Errorf("Standard storage class parity disks %d should be greater than or equal to Reduced redundancy storage class parity disks %d", ssParity, rrsParity)
 		}
 	}
+
+	// Named storage classes may use any parity in the allowed range.
+	for name, sc := range custom {
+		if sc.Parity < minimumParityDisks {
+			return fmt.Errorf("%s storage class parity %d should be greater than or equal to %d", name, sc.Parity, minimumParityDisks)
+		}
+		if sc.Parity > globalXLSetDriveCount/2 {
+			return fmt.Errorf("%s storage class parity %d should be less than or equal to %d", name, sc.Parity, globalXLSetDriveCount/2)
+		}
+	}
 	return nil
 }
 
@@ -173,6 +234,8 @@ func validateParity(ssParity, rrsParity int) (err error) {
 // If storage class is not set during startup, default values are returned
 // -- Default for Reduced Redundancy Storage class is, parity = 2 and data = N-Parity
 // -- Default for Standard Storage class is, parity = N/2, data = N/2
+// If storage class is a named storage class set using MINIO_STORAGE_CLASS_CUSTOM
+// -- its parity is returned
 // If storage class is empty
 // -- standard storage class is assumed and corresponding data and parity is returned
 func getRedundancyCount(sc string, totalDisks int) (data, parity int) {
@@ -191,11 +254,30 @@ func getRedundancyCount(sc string, totalDisks int) (data, parity int) {
 			// set the standard parity if available
 			parity = globalStandardStorageClass.Parity
 		}
+	default:
+		if customClass, ok := globalCustomStorageClasses[sc]; ok {
+			parity = customClass.Parity
+		}
 	}
 	// data is always totalDisks - parity
 	return totalDisks - parity, parity
 }
 
+// Returns the smallest parity drive count of all storage classes,
+// i.e. the number of drives objects of any storage class may lose.
+func getMinParityCount(totalDisks int) (parity int) {
+	_, parity = getRedundancyCount(standardStorageClass, totalDisks)
+	if _, rrsParity := getRedundancyCount(reducedRedundancyStorageClass, totalDisks); rrsParity < parity {
+		parity = rrsParity
+	}
+	for name := range globalCustomStorageClasses {
+		if _, customParity := getRedundancyCount(name, totalDisks); customParity < parity {
+			parity = customParity
+		}
+	}
+	return parity
+}
+
 // Returns per object readQuorum and writeQuorum
 // readQuorum is the minimum required disks to read data.
 // writeQuorum is the minimum required disks to write data.
@@ -75,6 +75,38 @@ func testParseStorageClass(obj ObjectLayer, instanceType string, t TestErrHandle
 	}
 }
 
+func TestParseCustomStorageClasses(t *testing.T) {
+	tests := []struct {
+		customStorageClassEnv string
+		wantClasses           map[string]storageClass
+		success               bool
+	}{
+		{"ARCHIVE=EC:8", map[string]storageClass{"ARCHIVE": {Scheme: "EC", Parity: 8}}, true},
+		{"ARCHIVE=EC:8, SCRATCH_1=EC:2", map[string]storageClass{
+			"ARCHIVE":   {Scheme: "EC", Parity: 8},
+			"SCRATCH_1": {Scheme: "EC", Parity: 2},
+		}, true},
+		{"ARCHIVE", nil, false},
+		{"archive=EC:8", nil, false},
+		{"=EC:8", nil, false},
+		{"STANDARD=EC:4", nil, false},
+		{"ARCHIVE=EC:8,ARCHIVE=EC:4", nil, false},
+		{"ARCHIVE=AB:8", nil, false},
+	}
+	for i, tt := range tests {
+		classes, err := parseCustomStorageClasses(tt.customStorageClassEnv)
+		if err != nil && tt.success {
+			t.Errorf("Test %d, Expected success, got %s", i+1, err)
+		}
+		if err == nil && !tt.success {
+			t.Errorf("Test %d, Expected failure, got success", i+1)
+		}
+		if tt.success && !reflect.DeepEqual(classes, tt.wantClasses) {
+			t.Errorf("Test %d, Expected %v, got %v", i+1, tt.wantClasses, classes)
+		}
+	}
+}
+
 func TestValidateParity(t *testing.T) {
 	ExecObjectLayerTestWithDirs(t, testValidateParity)
 }
@@ -93,7 +125,7 @@ func testValidateParity(obj ObjectLayer, instanceType string, dirs []string, t T
 	defer func() {
 		globalXLSetDriveCount = saveSetDriveCount
 	}()
-	globalXLSetCount = len(dirs)
+	globalXLSetDriveCount = len(dirs)
 
 	tests := []struct {
 		rrsParity int
@@ -109,7 +141,26 @@ func testValidateParity(obj ObjectLayer, instanceType string, dirs []string, t T
 		{2, 9, false},
 	}
 	for i, tt := range tests {
-		err := validateParity(tt.ssParity, tt.rrsParity)
+		err := validateParity(tt.ssParity, tt.rrsParity, nil)
+		if err != nil && tt.success {
+			t.Errorf("Test %d, Expected success, got %s", i+1, err)
+		}
+		if err == nil && !tt.success {
+			t.Errorf("Test %d, Expected failure, got success", i+1)
+		}
+	}
+
+	customTests := []struct {
+		parity  int
+		success bool
+	}{
+		{2, true},
+		{8, true},
+		{1, false},
+		{9, false},
+	}
+	for i, tt := range customTests {
+		err := validateParity(0, 0, map[string]storageClass{"ARCHIVE": {Scheme: "EC", Parity: tt.parity}})
 		if err != nil && tt.success {
 			t.Errorf("Test %d, Expected success, got %s", i+1, err)
 		}
@@ -164,6 +215,17 @@ func testGetRedundancyCount(obj ObjectLayer, instanceType string, dirs []string,
 			return
 		}
 	}
+
+	// Named storage classes use their own parity, unknown
+	// storage classes fall back to the default parity.
+	globalCustomStorageClasses = map[string]storageClass{"ARCHIVE": {Scheme: "EC", Parity: 3}}
+	defer resetGlobalStorageEnvs()
+	if data, parity := getRedundancyCount("ARCHIVE", len(xl.storageDisks)); data != 13 || parity != 3 {
+		t.Errorf("Expected 13 data and 3 parity disks, got %d and %d", data, parity)
+	}
+	if data, parity := getRedundancyCount("UNKNOWN", len(xl.storageDisks)); data != 8 || parity != 8 {
+		t.Errorf("Expected 8 data and 8 parity disks, got %d and %d", data, parity)
+	}
 }
 
 func TestObjectQuorumFromMeta(t *testing.T) {
@@ -0,0 +1,351 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/config/drivehealth"
+	"github.com/minio/minio/cmd/logger"
+)
+
+const (
+	// Length of the windows in which drive requests are counted,
+	// the error rate covers the current and the previous window.
+	diskHealthWindow = time.Minute
+
+	// Minimum number of requests for an error rate to be reported.
+	diskHealthMinRequests = 10
+
+	// Number of consecutive healthy probes after which an excluded
+	// drive is used again.
+	diskHealthProbes = 3
+
+	// Interval at which the slow or faulty drives of the erasure
+	// sets are recomputed.
+	diskHealthCheckInterval = time.Second
+)
+
+// Errors which indicate a faulty drive rather than a failed request.
+var diskFaultErrs = []error{
+	errFaultyDisk,
+	errFaultyRemoteDisk,
+	errDiskNotFound,
+	errDiskAccessDenied,
+	errUnexpected,
+}
+
+// monitoredDisk - tracks the latency and the error rate of requests
+// to a drive. Drives above the drive health thresholds are excluded
+// from erasure set operations until probing finds them healthy again.
+type monitoredDisk struct {
+	StorageAPI
+	*diskHealth
+}
+
+// diskHealth - recent requests of a drive, shared by the copies of
+// a monitored disk made for single requests.
+type diskHealth struct {
+	mu sync.Mutex
+	// Moving average of the request latency.
+	latency time.Duration
+	// Requests and faults of the current and the previous window.
+	windowStart              time.Time
+	requests, faults         uint64
+	prevRequests, prevFaults uint64
+	excluded                 bool
+}
+
+// newMonitoredDisk - starts tracking the health of a drive.
+func newMonitoredDisk(disk StorageAPI) StorageAPI {
+	if disk == nil {
+		return nil
+	}
+	if _, ok := disk.(*monitoredDisk); ok {
+		return disk
+	}
+	return &monitoredDisk{StorageAPI: disk, diskHealth: &diskHealth{windowStart: time.Now()}}
+}
+
+// Starts a new window once the current one is over.
+func (d *monitoredDisk) rotate(now time.Time) {
+	elapsed := now.Sub(d.windowStart)
+	if elapsed < diskHealthWindow {
+		return
+	}
+	d.prevRequests, d.prevFaults = d.requests, d.faults
+	if elapsed >= 2*diskHealthWindow {
+		// No requests in the previous window.
+		d.prevRequests, d.prevFaults = 0, 0
+	}
+	d.requests, d.faults = 0, 0
+	d.windowStart = now
+}
+
+// withContext - returns a copy of the disk making the calls on
+// behalf of the request of ctx, the health is shared.
+func (d *monitoredDisk) withContext(ctx context.Context) StorageAPI {
+	return &monitoredDisk{StorageAPI: contextDisk(ctx, d.StorageAPI), diskHealth: d.diskHealth}
+}
+
+// Records the outcome of a request, the latency of streaming
+// requests depends on the amount of data and is not recorded.
+func (d *monitoredDisk) track(start time.Time, err error, timed bool) {
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.rotate(now)
+	d.requests++
+	if err != nil && IsErr(err, diskFaultErrs...) {
+		d.faults++
+	}
+	if timed {
+		// Exponentially weighted, the latest request weighs 1/5.
+		d.latency += (now.Sub(start) - d.latency) / 5
+	}
+}
+
+// Health - returns the average latency and the error rate of recent
+// requests and whether the drive is excluded.
+func (d *monitoredDisk) Health() (latency time.Duration, errorRate float64, excluded bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.rotate(time.Now())
+	if requests := d.requests + d.prevRequests; requests >= diskHealthMinRequests {
+		errorRate = float64(d.faults+d.prevFaults) / float64(requests)
+	}
+	return d.latency, errorRate, d.excluded
+}
+
+// Returns true if the drive is above the thresholds.
+func (d *monitoredDisk) isFaulty(config drivehealth.Config) bool {
+	latency, errorRate, _ := d.Health()
+	return latency > config.MaxLatency || errorRate > config.MaxErrorRate
+}
+
+func (d *monitoredDisk) isExcluded() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.excluded
+}
+
+// exclude - marks the drive as excluded and probes it until it is
+// healthy again.
+func (d *monitoredDisk) exclude(config drivehealth.Config) {
+	d.mu.Lock()
+	if d.excluded {
+		d.mu.Unlock()
+		return
+	}
+	d.excluded = true
+	latency, errorRate := d.latency, 0.0
+	if requests := d.requests + d.prevRequests; requests > 0 {
+		errorRate = float64(d.faults+d.prevFaults) / float64(requests)
+	}
+	d.mu.Unlock()
+
+	logger.Info("Excluding drive %s, average latency %s, error rate %.2f", d.String(), latency, errorRate)
+	go d.probe(config)
+}
+
+// probe - re-admits the excluded drive after a number of consecutive
+// probes within the thresholds.
+func (d *monitoredDisk) probe(config drivehealth.Config) {
+	ticker := time.NewTicker(config.ProbeInterval)
+	defer ticker.Stop()
+
+	var healthy int
+	for {
+		select {
+		case <-GlobalServiceDoneCh:
+			return
+		case <-ticker.C:
+		}
+
+		// Disconnected drives are replaced on reconnect.
+		if !d.StorageAPI.IsOnline() {
+			return
+		}
+
+		start := time.Now()
+		_, err := d.StorageAPI.DiskInfo()
+		if err == nil {
+			_, err = d.StorageAPI.StatVol(minioMetaBucket)
+		}
+		latency := time.Since(start)
+		if err != nil || latency > config.MaxLatency {
+			healthy = 0
+			continue
+		}
+		if healthy++; healthy < diskHealthProbes {
+			continue
+		}
+
+		d.mu.Lock()
+		d.latency = latency
+		d.requests, d.faults = 0, 0
+		d.prevRequests, d.prevFaults = 0, 0
+		d.windowStart = time.Now()
+		d.excluded = false
+		d.mu.Unlock()
+
+		logger.Info("Drive %s is healthy again", d.String())
+		return
+	}
+}
+
+// excludeUnhealthyDisks - replaces excluded drives of an erasure set
+// with nil, i.e. treats them as offline. Drives are only excluded while
+// the set keeps write quorum for all storage classes. Called by the
+// drive health monitor of the erasure sets.
+func excludeUnhealthyDisks(disks []StorageAPI) []StorageAPI {
+	config := globalDriveHealthConfig
+	if !config.Enabled {
+		return disks
+	}
+
+	// Write quorum is data blocks + 1, so up to parity - 1
+	// drives may be unavailable for the storage class with
+	// the smallest parity.
+	available := getMinParityCount(len(disks)) - 1
+	for _, disk := range disks {
+		if disk == nil || !disk.IsOnline() {
+			available--
+		}
+	}
+
+	// Keep excluding already excluded drives before excluding new
+	// ones, excluded drives are used again if other drives went
+	// offline in the meantime.
+	for _, excluded := range []bool{true, false} {
+		for i, disk := range disks {
+			if available <= 0 {
+				return disks
+			}
+			md, ok := disk.(*monitoredDisk)
+			if !ok || md.isExcluded() != excluded {
+				continue
+			}
+			if !excluded {
+				if !md.isFaulty(config) {
+					continue
+				}
+				md.exclude(config)
+			}
+			disks[i] = nil
+			available--
+		}
+	}
+	return disks
+}
+
+func (d *monitoredDisk) DiskInfo() (info DiskInfo, err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.DiskInfo()
+}
+
+func (d *monitoredDisk) MakeVol(volume string) (err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.MakeVol(volume)
+}
+
+func (d *monitoredDisk) ListVols() (vols []VolInfo, err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.ListVols()
+}
+
+func (d *monitoredDisk) StatVol(volume string) (vol VolInfo, err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.StatVol(volume)
+}
+
+func (d *monitoredDisk) DeleteVol(volume string) (err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.DeleteVol(volume)
+}
+
+func (d *monitoredDisk) Walk(volume, dirPath string, marker string, recursive bool, leafFile string,
+	readMetadataFn readMetadataFunc, endWalkCh chan struct{}) (ch chan FileInfo, err error) {
+	defer func(start time.Time) { d.track(start, err, false) }(time.Now())
+	return d.StorageAPI.Walk(volume, dirPath, marker, recursive, leafFile, readMetadataFn, endWalkCh)
+}
+
+func (d *monitoredDisk) ListDir(volume, dirPath string, count int, leafFile string) (entries []string, err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.ListDir(volume, dirPath, count, leafFile)
+}
+
+func (d *monitoredDisk) ReadFile(volume string, path string, offset int64, buf []byte, verifier *BitrotVerifier) (n int64, err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.ReadFile(volume, path, offset, buf, verifier)
+}
+
+func (d *monitoredDisk) AppendFile(volume string, path string, buf []byte) (err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.AppendFile(volume, path, buf)
+}
+
+func (d *monitoredDisk) CreateFile(volume, path string, size int64, reader io.Reader) (err error) {
+	defer func(start time.Time) { d.track(start, err, false) }(time.Now())
+	return d.StorageAPI.CreateFile(volume, path, size, reader)
+}
+
+func (d *monitoredDisk) ReadFileStream(volume, path string, offset, length int64) (rc io.ReadCloser, err error) {
+	defer func(start time.Time) { d.track(start, err, false) }(time.Now())
+	return d.StorageAPI.ReadFileStream(volume, path, offset, length)
+}
+
+func (d *monitoredDisk) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) (err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.RenameFile(srcVolume, srcPath, dstVolume, dstPath)
+}
+
+func (d *monitoredDisk) StatFile(volume string, path string) (file FileInfo, err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.StatFile(volume, path)
+}
+
+func (d *monitoredDisk) DeleteFile(volume string, path string) (err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.DeleteFile(volume, path)
+}
+
+func (d *monitoredDisk) DeleteFileBulk(volume string, paths []string) (errs []error, err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.DeleteFileBulk(volume, paths)
+}
+
+func (d *monitoredDisk) VerifyFile(volume, path string, size int64, algo BitrotAlgorithm, sum []byte, shardSize int64) (err error) {
+	defer func(start time.Time) { d.track(start, err, false) }(time.Now())
+	return d.StorageAPI.VerifyFile(volume, path, size, algo, sum, shardSize)
+}
+
+func (d *monitoredDisk) WriteAll(volume string, path string, reader io.Reader) (err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.WriteAll(volume, path, reader)
+}
+
+func (d *monitoredDisk) ReadAll(volume string, path string) (buf []byte, err error) {
+	defer func(start time.Time) { d.track(start, err, true) }(time.Now())
+	return d.StorageAPI.ReadAll(volume, path)
+}
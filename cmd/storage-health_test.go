@@ -0,0 +1,177 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/config/drivehealth"
+)
+
+func TestMonitoredDiskHealth(t *testing.T) {
+	obj, fsDirs, err := prepareXL16()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	disk := newMonitoredDisk(obj.(*xlObjects).storageDisks[0]).(*monitoredDisk)
+	if newMonitoredDisk(disk) != disk {
+		t.Fatal("Expected monitored disk not to be wrapped again")
+	}
+
+	// Too few requests for an error rate.
+	disk.track(time.Now(), errFaultyDisk, false)
+	if _, errorRate, _ := disk.Health(); errorRate != 0 {
+		t.Fatalf("Expected no error rate, got %v", errorRate)
+	}
+
+	for i := 0; i < diskHealthMinRequests-1; i++ {
+		// Requests failing with errors unrelated to the drive are no faults.
+		disk.track(time.Now(), errFileNotFound, false)
+	}
+	if _, errorRate, _ := disk.Health(); errorRate != 0.1 {
+		t.Fatalf("Expected error rate 0.1, got %v", errorRate)
+	}
+
+	disk.track(time.Now().Add(-time.Second), nil, true)
+	if latency, _, _ := disk.Health(); latency < time.Second/5 || latency > time.Second/4 {
+		t.Fatalf("Expected latency of about %v, got %v", time.Second/5, latency)
+	}
+
+	// Requests older than two windows are forgotten.
+	disk.windowStart = disk.windowStart.Add(-2 * diskHealthWindow)
+	if _, errorRate, _ := disk.Health(); errorRate != 0 {
+		t.Fatalf("Expected no error rate, got %v", errorRate)
+	}
+}
+
+func TestExcludeUnhealthyDisks(t *testing.T) {
+	obj, fsDirs, err := prepareXL16()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	resetGlobalStorageEnvs()
+	defer resetGlobalStorageEnvs()
+
+	// The first ten requests to the faulty drives fail.
+	faultyErrs := make(map[int]error)
+	for i := 1; i <= diskHealthMinRequests; i++ {
+		faultyErrs[i] = errFaultyDisk
+	}
+	storageDisks := obj.(*xlObjects).storageDisks
+	disks := make([]StorageAPI, len(storageDisks))
+	for i := range storageDisks {
+		disk := storageDisks[i]
+		if i < 10 {
+			disk = newNaughtyDisk(disk, faultyErrs, nil)
+		}
+		disks[i] = newMonitoredDisk(disk)
+		for j := 0; j < diskHealthMinRequests; j++ {
+			disks[i].DiskInfo()
+		}
+	}
+
+	countExcluded := func(disks []StorageAPI) (n int) {
+		for _, disk := range disks {
+			if disk == nil {
+				n++
+			}
+		}
+		return n
+	}
+
+	savedConfig := globalDriveHealthConfig
+	defer func() { globalDriveHealthConfig = savedConfig }()
+
+	// Nothing is excluded while drive health tracking is disabled.
+	globalDriveHealthConfig = drivehealth.Config{}
+	if n := countExcluded(excludeUnhealthyDisks(append([]StorageAPI{}, disks...))); n != 0 {
+		t.Fatalf("Expected no excluded drives, got %d", n)
+	}
+
+	globalDriveHealthConfig = drivehealth.Config{
+		Enabled:       true,
+		MaxLatency:    time.Minute,
+		MaxErrorRate:  0.5,
+		ProbeInterval: 10 * time.Millisecond,
+	}
+
+	// Only parity - 1 drives are excluded to keep write quorum,
+	// reduced redundancy objects have a parity of 2 by default.
+	if n := countExcluded(excludeUnhealthyDisks(append([]StorageAPI{}, disks...))); n != 1 {
+		t.Fatalf("Expected 1 excluded drive, got %d", n)
+	}
+
+	globalRRStorageClass = storageClass{Scheme: "EC", Parity: 8}
+	if n := countExcluded(excludeUnhealthyDisks(append([]StorageAPI{}, disks...))); n != 7 {
+		t.Fatalf("Expected 7 excluded drives, got %d", n)
+	}
+
+	// Named storage classes with a smaller parity limit
+	// the excluded drives too.
+	globalCustomStorageClasses = map[string]storageClass{"ARCHIVE": {Scheme: "EC", Parity: 4}}
+	if n := countExcluded(excludeUnhealthyDisks(append([]StorageAPI{}, disks...))); n != 3 {
+		t.Fatalf("Expected 3 excluded drives, got %d", n)
+	}
+
+	// Drives work again after the first ten requests, so probing
+	// re-admits all excluded drives.
+	deadline := time.Now().Add(10 * time.Second)
+	for _, disk := range disks {
+		for disk.(*monitoredDisk).isExcluded() {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected drive %s to be re-admitted", disk)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// The remaining faulty drives are excluded now.
+	if n := countExcluded(excludeUnhealthyDisks(append([]StorageAPI{}, disks...))); n != 3 {
+		t.Fatalf("Expected 3 excluded drives, got %d", n)
+	}
+}
+
+func TestXLSetsExcludedDisks(t *testing.T) {
+	disks := make([]StorageAPI, 4)
+	for i := range disks {
+		disks[i] = &monitoredDisk{diskHealth: &diskHealth{windowStart: time.Now()}}
+	}
+	s := &xlSets{
+		xlDisks:       [][]StorageAPI{append([]StorageAPI{}, disks...)},
+		excludedDisks: [][]StorageAPI{{nil, disks[1], nil, nil}},
+		drivesPerSet:  len(disks),
+	}
+
+	if got := s.GetDisks(0)(); got[1] != nil || got[0] != disks[0] {
+		t.Fatal("Expected only drive 2 to be excluded")
+	}
+	// Heals use the excluded drives too.
+	if got := s.GetAllDisks(0)(); got[1] != disks[1] {
+		t.Fatal("Expected drive 2 to be healed")
+	}
+
+	// A reconnected drive is not excluded.
+	s.xlDisks[0][1] = &monitoredDisk{diskHealth: &diskHealth{windowStart: time.Now()}}
+	if got := s.GetDisks(0)(); got[1] == nil {
+		t.Fatal("Expected reconnected drive 2 not to be excluded")
+	}
+}
@@ -19,6 +19,7 @@ package cmd
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/gob"
 	"encoding/hex"
@@ -105,6 +106,16 @@ func toStorageErr(err error) error {
 
 // Abstracts a remote disk.
 type storageRESTClient struct {
+	*storageRESTConn
+
+	// Context of the request the calls are made for, nil for
+	// calls made on behalf of the server.
+	ctx context.Context
+}
+
+// storageRESTConn - connection to a remote disk, shared by the
+// copies of a client made for single requests.
+type storageRESTConn struct {
 	endpoint   Endpoint
 	restClient *rest.Client
 	connected  bool
@@ -123,7 +134,11 @@ func (client *storageRESTClient) call(method string, values url.Values, body io.
 		values = make(url.Values)
 	}
 	values.Set(storageRESTInstanceID, client.instanceID)
-	respBody, err = client.restClient.Call(method, values, body, length)
+	ctx := client.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	respBody, err = client.restClient.CallWithContext(ctx, method, values, body, length)
 	if err == nil {
 		return respBody, nil
 	}
@@ -135,6 +150,12 @@ func (client *storageRESTClient) call(method string, values url.Values, body io.
 	return nil, toStorageErr(err)
 }
 
+// withContext - returns a copy of the client making the calls on
+// behalf of the request of ctx, e.g. to propagate its trace.
+func (client *storageRESTClient) withContext(ctx context.Context) StorageAPI {
+	return &storageRESTClient{storageRESTConn: client.storageRESTConn, ctx: ctx}
+}
+
 // Stringer provides a canonicalized representation of network device.
 func (client *storageRESTClient) String() string {
 	return client.endpoint.String()
@@ -496,7 +517,7 @@ func newStorageRESTClient(endpoint Endpoint) (*storageRESTClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	client := &storageRESTClient{endpoint: endpoint, restClient: restClient, connected: true}
+	client := &storageRESTClient{storageRESTConn: &storageRESTConn{endpoint: endpoint, restClient: restClient, connected: true}}
 	client.connected = client.getInstanceID() == nil
 	return client, nil
 }
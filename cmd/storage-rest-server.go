@@ -38,7 +38,7 @@ var errConnectionStale = errors.New("connection stale, REST client/server instan
 
 // To abstract a disk over network.
 type storageRESTServer struct {
-	storage *posix
+	storage StorageAPI
 	// Used to detect reboot of servers so that peers revalidate format.json as
 	// different disk might be available on the same mount point after reboot.
 	instanceID string
@@ -355,7 +355,7 @@ func (s *storageRESTServer) ReadFileStreamHandler(w http.ResponseWriter, r *http
 type readMetadataFunc func(buf []byte, volume, entry string) FileInfo
 
 func readMetadata(buf []byte, volume, entry string) FileInfo {
-	m, err := xlMetaV1UnmarshalJSON(context.Background(), buf)
+	m, err := xlMetaV1Unmarshal(context.Background(), buf, xlMetaErasureSection)
 	if err != nil {
 		return FileInfo{}
 	}
@@ -578,7 +578,7 @@ func registerStorageRESTHandlers(router *mux.Router, endpoints EndpointList) {
 				"Unable to initialize posix backend")
 		}
 
-		server := &storageRESTServer{storage, mustGetUUID()}
+		server := &storageRESTServer{newPosixTracer(storage), mustGetUUID()}
 
 		subrouter := router.PathPrefix(path.Join(storageRESTPath, endpoint.Path)).Subrouter()
 
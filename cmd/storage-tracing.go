@@ -0,0 +1,190 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"github.com/minio/minio/pkg/tracing"
+)
+
+// storageWithContext - implemented by disks which can make their
+// calls on behalf of a request, e.g. to propagate its trace.
+type storageWithContext interface {
+	withContext(ctx context.Context) StorageAPI
+}
+
+// contextDisk - returns the disk making its calls on behalf of the
+// request of ctx, if supported.
+func contextDisk(ctx context.Context, disk StorageAPI) StorageAPI {
+	if c, ok := disk.(storageWithContext); ok {
+		return c.withContext(ctx)
+	}
+	return disk
+}
+
+// newTracedDisks - returns the disks recording a span for every call
+// made on behalf of the request of ctx, the disks are returned as is
+// if the request is not traced.
+func newTracedDisks(ctx context.Context, disks []StorageAPI) []StorageAPI {
+	if tracing.FromContext(ctx) == nil {
+		return disks
+	}
+	tracedDisks := make([]StorageAPI, len(disks))
+	for i, disk := range disks {
+		if disk != nil {
+			tracedDisks[i] = &tracedDisk{StorageAPI: disk, ctx: ctx}
+		}
+	}
+	return tracedDisks
+}
+
+// tracedDisk - records the calls to a disk as children of the span
+// of a request. The span context is passed to the disk, remote disks
+// continue the trace on their server.
+type tracedDisk struct {
+	StorageAPI
+	ctx context.Context
+}
+
+func (d *tracedDisk) start(call, volume, path string) (StorageAPI, *tracing.Span) {
+	_, span := tracing.Start(d.ctx, "storage."+call, tracing.KindClient)
+	span.SetAttribute("disk", d.String())
+	if volume != "" {
+		span.SetAttribute("volume", volume)
+	}
+	if path != "" {
+		span.SetAttribute("path", path)
+	}
+	// Only the trace is passed on, calls are not canceled with the request.
+	return contextDisk(tracing.ContextWithSpan(context.Background(), span), d.StorageAPI), span
+}
+
+func finishSpan(span *tracing.Span, err error) {
+	span.SetError(err)
+	span.Finish()
+}
+
+func (d *tracedDisk) DiskInfo() (info DiskInfo, err error) {
+	disk, span := d.start("DiskInfo", "", "")
+	defer func() { finishSpan(span, err) }()
+	return disk.DiskInfo()
+}
+
+func (d *tracedDisk) MakeVol(volume string) (err error) {
+	disk, span := d.start("MakeVol", volume, "")
+	defer func() { finishSpan(span, err) }()
+	return disk.MakeVol(volume)
+}
+
+func (d *tracedDisk) ListVols() (vols []VolInfo, err error) {
+	disk, span := d.start("ListVols", "", "")
+	defer func() { finishSpan(span, err) }()
+	return disk.ListVols()
+}
+
+func (d *tracedDisk) StatVol(volume string) (vol VolInfo, err error) {
+	disk, span := d.start("StatVol", volume, "")
+	defer func() { finishSpan(span, err) }()
+	return disk.StatVol(volume)
+}
+
+func (d *tracedDisk) DeleteVol(volume string) (err error) {
+	disk, span := d.start("DeleteVol", volume, "")
+	defer func() { finishSpan(span, err) }()
+	return disk.DeleteVol(volume)
+}
+
+func (d *tracedDisk) Walk(volume, dirPath string, marker string, recursive bool, leafFile string,
+	readMetadataFn readMetadataFunc, endWalkCh chan struct{}) (ch chan FileInfo, err error) {
+	disk, span := d.start("Walk", volume, dirPath)
+	defer func() { finishSpan(span, err) }()
+	return disk.Walk(volume, dirPath, marker, recursive, leafFile, readMetadataFn, endWalkCh)
+}
+
+func (d *tracedDisk) ListDir(volume, dirPath string, count int, leafFile string) (entries []string, err error) {
+	disk, span := d.start("ListDir", volume, dirPath)
+	defer func() { finishSpan(span, err) }()
+	return disk.ListDir(volume, dirPath, count, leafFile)
+}
+
+func (d *tracedDisk) ReadFile(volume string, path string, offset int64, buf []byte, verifier *BitrotVerifier) (n int64, err error) {
+	disk, span := d.start("ReadFile", volume, path)
+	defer func() { finishSpan(span, err) }()
+	return disk.ReadFile(volume, path, offset, buf, verifier)
+}
+
+func (d *tracedDisk) AppendFile(volume string, path string, buf []byte) (err error) {
+	disk, span := d.start("AppendFile", volume, path)
+	defer func() { finishSpan(span, err) }()
+	return disk.AppendFile(volume, path, buf)
+}
+
+func (d *tracedDisk) CreateFile(volume, path string, size int64, reader io.Reader) (err error) {
+	disk, span := d.start("CreateFile", volume, path)
+	defer func() { finishSpan(span, err) }()
+	return disk.CreateFile(volume, path, size, reader)
+}
+
+func (d *tracedDisk) ReadFileStream(volume, path string, offset, length int64) (rc io.ReadCloser, err error) {
+	disk, span := d.start("ReadFileStream", volume, path)
+	defer func() { finishSpan(span, err) }()
+	return disk.ReadFileStream(volume, path, offset, length)
+}
+
+func (d *tracedDisk) RenameFile(srcVolume, srcPath, dstVolume, dstPath string) (err error) {
+	disk, span := d.start("RenameFile", srcVolume, srcPath)
+	defer func() { finishSpan(span, err) }()
+	return disk.RenameFile(srcVolume, srcPath, dstVolume, dstPath)
+}
+
+func (d *tracedDisk) StatFile(volume string, path string) (file FileInfo, err error) {
+	disk, span := d.start("StatFile", volume, path)
+	defer func() { finishSpan(span, err) }()
+	return disk.StatFile(volume, path)
+}
+
+func (d *tracedDisk) DeleteFile(volume string, path string) (err error) {
+	disk, span := d.start("DeleteFile", volume, path)
+	defer func() { finishSpan(span, err) }()
+	return disk.DeleteFile(volume, path)
+}
+
+func (d *tracedDisk) DeleteFileBulk(volume string, paths []string) (errs []error, err error) {
+	disk, span := d.start("DeleteFileBulk", volume, "")
+	defer func() { finishSpan(span, err) }()
+	return disk.DeleteFileBulk(volume, paths)
+}
+
+func (d *tracedDisk) VerifyFile(volume, path string, size int64, algo BitrotAlgorithm, sum []byte, shardSize int64) (err error) {
+	disk, span := d.start("VerifyFile", volume, path)
+	defer func() { finishSpan(span, err) }()
+	return disk.VerifyFile(volume, path, size, algo, sum, shardSize)
+}
+
+func (d *tracedDisk) WriteAll(volume string, path string, reader io.Reader) (err error) {
+	disk, span := d.start("WriteAll", volume, path)
+	defer func() { finishSpan(span, err) }()
+	return disk.WriteAll(volume, path, reader)
+}
+
+func (d *tracedDisk) ReadAll(volume string, path string) (buf []byte, err error) {
+	disk, span := d.start("ReadAll", volume, path)
+	defer func() { finishSpan(span, err) }()
+	return disk.ReadAll(volume, path)
+}
@@ -0,0 +1,118 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/tracing"
+)
+
+type testSpanExporter struct {
+	mu    sync.Mutex
+	spans []*tracing.Span
+}
+
+func (e *testSpanExporter) Export(span *tracing.Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+func (e *testSpanExporter) Close() error { return nil }
+
+func TestTracedDisk(t *testing.T) {
+	disk, diskPath, err := newPosixTestSetup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(diskPath)
+
+	// Disks are not wrapped for requests which are not traced.
+	disks := []StorageAPI{disk, nil}
+	if tracedDisks := newTracedDisks(context.Background(), disks); tracedDisks[0] != disk {
+		t.Fatal("Expected the disk of an untraced request to be returned as is")
+	}
+
+	exporter := &testSpanExporter{}
+	ctx, root := tracing.New(exporter, 1).Start(context.Background(), "s3.MakeBucket", tracing.KindServer)
+	tracedDisks := newTracedDisks(ctx, disks)
+	if tracedDisks[1] != nil {
+		t.Fatal("Expected offline disks to stay offline")
+	}
+	if err = tracedDisks[0].MakeVol("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if err = tracedDisks[0].MakeVol("bucket"); err != errVolumeExists {
+		t.Fatalf("Expected %v, got %v", errVolumeExists, err)
+	}
+	root.Finish()
+
+	if len(exporter.spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(exporter.spans))
+	}
+	for _, span := range exporter.spans[:2] {
+		if span.Name != "storage.MakeVol" || span.Parent != root.Context.SpanID {
+			t.Fatalf("Expected MakeVol to be a child of the request, got %s", span.Name)
+		}
+		if span.Attributes["volume"] != "bucket" {
+			t.Fatalf("Unexpected attributes %v", span.Attributes)
+		}
+	}
+	if exporter.spans[0].Err != "" || exporter.spans[1].Err != errVolumeExists.Error() {
+		t.Fatalf("Unexpected span errors %q and %q", exporter.spans[0].Err, exporter.spans[1].Err)
+	}
+}
+
+func TestTraceHTTPRequest(t *testing.T) {
+	exporter := &testSpanExporter{}
+	globalTracer = tracing.New(exporter, 0)
+	defer func() { globalTracer = nil }()
+
+	var handlerSpan *tracing.Span
+	handler := traceHTTPRequest("internal.CreateFile", func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = tracing.FromContext(r.Context())
+		w.WriteHeader(http.StatusInsufficientStorage)
+	})
+
+	// Internal requests are only traced as part of the trace of a caller.
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	if handlerSpan != nil || len(exporter.spans) != 0 {
+		t.Fatal("Expected no span without a caller")
+	}
+
+	ctx, call := tracing.New(&testSpanExporter{}, 1).Start(context.Background(), "storage.CreateFile", tracing.KindClient)
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	tracing.Inject(ctx, r.Header)
+	handler(logger.NewResponseWriter(httptest.NewRecorder()), r)
+
+	if handlerSpan == nil || len(exporter.spans) != 1 {
+		t.Fatal("Expected the request to continue the trace of the caller")
+	}
+	if handlerSpan.Context.TraceID != call.Context.TraceID || handlerSpan.Parent != call.Context.SpanID {
+		t.Fatal("Expected the handler to be a child of the call")
+	}
+	if handlerSpan.Attributes["http.status_code"] != "507" || handlerSpan.Err == "" {
+		t.Fatalf("Expected a failed request, got %v", handlerSpan.Attributes)
+	}
+}
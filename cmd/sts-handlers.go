@@ -24,14 +24,12 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
-	xldap "github.com/minio/minio/cmd/config/ldap"
 	xhttp "github.com/minio/minio/cmd/http"
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/auth"
 	"github.com/minio/minio/pkg/iam/openid"
 	iampolicy "github.com/minio/minio/pkg/iam/policy"
 	"github.com/minio/minio/pkg/wildcard"
-	ldap "gopkg.in/ldap.v3"
 )
 
 const (
@@ -42,6 +40,7 @@ const (
 	clientGrants = "AssumeRoleWithClientGrants"
 	webIdentity  = "AssumeRoleWithWebIdentity"
 	ldapIdentity = "AssumeRoleWithLDAPIdentity"
+	certIdentity = "AssumeRoleWithCertificate"
 	assumeRole   = "AssumeRole"
 
 	stsRequestBodyLimit = 10 * (1 << 20) // 10 MiB
@@ -70,7 +69,8 @@ func registerSTSRouter(router *mux.Router) {
 		return ctypeOk && authOk && noQueries
 	}).HandlerFunc(httpTraceAll(sts.AssumeRole))
 
-	// Assume roles with JWT handler, handles both ClientGrants and WebIdentity.
+	// Assume roles with JWT handler, handles both ClientGrants and WebIdentity,
+	// AssumeRoleWithCertificate form requests are dispatched from it as well.
 	stsRouter.Methods("POST").MatcherFunc(func(r *http.Request, rm *mux.RouteMatch) bool {
 		ctypeOk := wildcard.MatchSimple("application/x-www-form-urlencoded*", r.Header.Get(xhttp.ContentType))
 		noQueries := len(r.URL.Query()) == 0
@@ -95,6 +95,11 @@ func registerSTSRouter(router *mux.Router) {
 		Queries("Version", stsAPIVersion).
 		Queries("LDAPUsername", "{LDAPUsername:.*}").
 		Queries("LDAPPassword", "{LDAPPassword:.*}")
+
+	// AssumeRoleWithCertificate
+	stsRouter.Methods("POST").HandlerFunc(httpTraceAll(sts.AssumeRoleWithCertificate)).
+		Queries("Action", certIdentity).
+		Queries("Version", stsAPIVersion)
 }
 
 func checkAssumeRoleAuth(ctx context.Context, r *http.Request) (user auth.Credentials, stsErr STSErrorCode) {
@@ -256,6 +261,10 @@ func (sts *stsAPIHandlers) AssumeRoleWithJWT(w http.ResponseWriter, r *http.Requ
 	action := r.Form.Get("Action")
 	switch action {
 	case clientGrants, webIdentity:
+	case certIdentity:
+		// The form is already parsed, the body is not read again.
+		sts.AssumeRoleWithCertificate(w, r)
+		return
 	default:
 		writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, fmt.Errorf("Unsupported action %s", action))
 		return
@@ -269,17 +278,17 @@ func (sts *stsAPIHandlers) AssumeRoleWithJWT(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	v, err := globalOpenIDValidators.Get("jwt")
-	if err != nil {
-		writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, err)
-		return
-	}
-
 	token := r.Form.Get("Token")
 	if token == "" {
 		token = r.Form.Get("WebIdentityToken")
 	}
 
+	v, err := globalOpenIDValidators.GetForToken(token)
+	if err != nil {
+		writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, err)
+		return
+	}
+
 	m, err := v.Validate(token, r.Form.Get("DurationSeconds"))
 	if err != nil {
 		switch err {
@@ -462,58 +471,16 @@ func (sts *stsAPIHandlers) AssumeRoleWithLDAPIdentity(w http.ResponseWriter, r *
 		}
 	}
 
-	ldapConn, err := globalServerConfig.LDAPServerConfig.Connect()
+	_, groups, err := globalServerConfig.LDAPServerConfig.Bind(ldapUsername, ldapPassword)
 	if err != nil {
-		writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, fmt.Errorf("LDAP server connection failure: %v", err))
+		writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, err)
 		return
 	}
-	if ldapConn == nil {
-		writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, fmt.Errorf("LDAP server not configured: %v", err))
-		return
+	if groups == nil {
+		// Claims are expected to always carry a groups list.
+		groups = []string{}
 	}
 
-	usernameSubs, _ := xldap.NewSubstituter("username", ldapUsername)
-	// We ignore error below as we already validated the username
-	// format string at startup.
-	usernameDN, _ := usernameSubs.Substitute(globalServerConfig.LDAPServerConfig.UsernameFormat)
-	// Bind with user credentials to validate the password
-	err = ldapConn.Bind(usernameDN, ldapPassword)
-	if err != nil {
-		writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, fmt.Errorf("LDAP authentication failure: %v", err))
-		return
-	}
-
-	groups := []string{}
-	if globalServerConfig.LDAPServerConfig.GroupSearchFilter != "" {
-		// Verified user credentials. Now we find the groups they are
-		// a member of.
-		searchSubs, _ := xldap.NewSubstituter(
-			"username", ldapUsername,
-			"usernamedn", usernameDN,
-		)
-		// We ignore error below as we already validated the search string
-		// at startup.
-		groupSearchFilter, _ := searchSubs.Substitute(globalServerConfig.LDAPServerConfig.GroupSearchFilter)
-		baseDN, _ := searchSubs.Substitute(globalServerConfig.LDAPServerConfig.GroupSearchBaseDN)
-		searchRequest := ldap.NewSearchRequest(
-			baseDN,
-			ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
-			groupSearchFilter,
-			[]string{globalServerConfig.LDAPServerConfig.GroupNameAttribute},
-			nil,
-		)
-
-		sr, err := ldapConn.Search(searchRequest)
-		if err != nil {
-			writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, fmt.Errorf("LDAP search failure: %v", err))
-			return
-		}
-		for _, entry := range sr.Entries {
-			// We only queried one attribute, so we only look up
-			// the first one.
-			groups = append(groups, entry.Attributes[0].Values...)
-		}
-	}
 	expiryDur := globalServerConfig.LDAPServerConfig.GetExpiryDuration()
 	m := map[string]interface{}{
 		"exp":        UTCNow().Add(expiryDur).Unix(),
@@ -557,3 +524,124 @@ func (sts *stsAPIHandlers) AssumeRoleWithLDAPIdentity(w http.ResponseWriter, r *
 
 	writeSuccessResponseXML(w, encodedSuccessResponse)
 }
+
+// AssumeRoleWithCertificate - implements user auth with a client TLS
+// certificate, the certificate must be signed by one of the configured
+// client CAs and its subject common name is used as the policy name.
+//
+// Eg:-
+//    $ curl --cert client.crt --key client.key -X POST "https://minio:9000/?Action=AssumeRoleWithCertificate&Version=2011-06-15"
+func (sts *stsAPIHandlers) AssumeRoleWithCertificate(w http.ResponseWriter, r *http.Request) {
+	ctx := newContext(r, w, "AssumeRoleWithCertificate")
+
+	// Parse the incoming form data.
+	if err := r.ParseForm(); err != nil {
+		writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, err)
+		return
+	}
+
+	if r.Form.Get("Version") != stsAPIVersion {
+		writeSTSErrorResponse(ctx, w, ErrSTSMissingParameter, fmt.Errorf("Invalid STS API version %s, expecting %s", r.Form.Get("Version"), stsAPIVersion))
+		return
+	}
+
+	action := r.Form.Get("Action")
+	switch action {
+	case certIdentity:
+	default:
+		writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, fmt.Errorf("Unsupported action %s", action))
+		return
+	}
+
+	ctx = newContext(r, w, action)
+	defer logger.AuditLog(w, r, action, nil)
+
+	if !globalCertIdentityConfig.Enabled {
+		writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, fmt.Errorf("Client certificate identity is not configured"))
+		return
+	}
+
+	if r.TLS == nil {
+		writeSTSErrorResponse(ctx, w, ErrSTSAccessDenied, fmt.Errorf("AssumeRoleWithCertificate requires a TLS connection"))
+		return
+	}
+
+	cert, err := globalCertIdentityConfig.Verify(r.TLS.PeerCertificates)
+	if err != nil {
+		writeSTSErrorResponse(ctx, w, ErrSTSAccessDenied, fmt.Errorf("Client certificate verification failure: %v", err))
+		return
+	}
+
+	sessionPolicyStr := r.Form.Get("Policy")
+	// https://docs.aws.amazon.com/STS/latest/APIReference/API_AssumeRole.html
+	// The plain text that you use for both inline and managed session
+	// policies shouldn't exceed 2048 characters.
+	if len(sessionPolicyStr) > 2048 {
+		writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, fmt.Errorf("Session policy should not exceed 2048 characters"))
+		return
+	}
+
+	if len(sessionPolicyStr) > 0 {
+		sessionPolicy, err := iampolicy.ParseConfig(bytes.NewReader([]byte(sessionPolicyStr)))
+		if err != nil {
+			writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, err)
+			return
+		}
+
+		// Version in policy must not be empty
+		if sessionPolicy.Version == "" {
+			writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, fmt.Errorf("Version needs to be specified in session policy"))
+			return
+		}
+	}
+
+	// Credentials never outlive the certificate they were issued for.
+	expiry := UTCNow().Add(globalCertIdentityConfig.GetExpiryDuration())
+	if cert.NotAfter.Before(expiry) {
+		expiry = cert.NotAfter
+	}
+
+	// The subject common name of the certificate is the policy name.
+	policyName := cert.Subject.CommonName
+	m := map[string]interface{}{
+		"exp":                expiry.Unix(),
+		iampolicy.PolicyName: policyName,
+	}
+
+	if len(sessionPolicyStr) > 0 {
+		m[iampolicy.SessionPolicyName] = base64.StdEncoding.EncodeToString([]byte(sessionPolicyStr))
+	}
+
+	secret := globalServerConfig.GetCredential().SecretKey
+	cred, err := auth.GetNewCredentialsWithMetadata(m, secret)
+	if err != nil {
+		writeSTSErrorResponse(ctx, w, ErrSTSInternalError, err)
+		return
+	}
+
+	// Set the newly generated credentials.
+	if err = globalIAMSys.SetTempUser(cred.AccessKey, cred, policyName); err != nil {
+		if err == errInvalidArgument {
+			writeSTSErrorResponse(ctx, w, ErrSTSInvalidParameterValue, fmt.Errorf("No policy found for certificate common name %s", policyName))
+			return
+		}
+		writeSTSErrorResponse(ctx, w, ErrSTSInternalError, err)
+		return
+	}
+
+	// Notify all other MinIO peers to reload temp users
+	for _, nerr := range globalNotificationSys.LoadUser(cred.AccessKey, true) {
+		if nerr.Err != nil {
+			logger.GetReqInfo(ctx).SetTags("peerAddress", nerr.Host.String())
+			logger.LogIf(ctx, nerr.Err)
+		}
+	}
+
+	certIdentityResponse := &AssumeRoleWithCertificateResponse{
+		Result: CertificateIdentityResult{
+			Credentials: cred,
+		},
+	}
+	certIdentityResponse.ResponseMetadata.RequestID = w.Header().Get(xhttp.AmzRequestID)
+	writeSuccessResponseXML(w, encodeResponse(certIdentityResponse))
+}
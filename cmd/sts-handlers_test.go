@@ -0,0 +1,80 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/cmd/logger/message/audit"
+)
+
+// auditTestTarget records the audit entries sent to it.
+type auditTestTarget struct {
+	entries []audit.Entry
+}
+
+func (t *auditTestTarget) Send(entry interface{}) error {
+	t.entries = append(t.entries, entry.(audit.Entry))
+	return nil
+}
+
+// Tests that the credentials of STS requests are redacted in audit logs.
+func TestAssumeRoleWithLDAPIdentityAudit(t *testing.T) {
+	target := &auditTestTarget{}
+	auditTargets := logger.AuditTargets
+	logger.AuditTargets = []logger.Target{target}
+	defer func() { logger.AuditTargets = auditTargets }()
+
+	router := mux.NewRouter()
+	registerSTSRouter(router)
+
+	// The oversized session policy fails the request before
+	// the LDAP server is contacted.
+	query := url.Values{}
+	query.Set("Action", ldapIdentity)
+	query.Set("Version", stsAPIVersion)
+	query.Set("LDAPUsername", "alice")
+	query.Set("LDAPPassword", "ldap-secret")
+	query.Set("Policy", strings.Repeat("a", 2049))
+
+	r := httptest.NewRequest(http.MethodPost, "/?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	if len(target.entries) != 1 {
+		t.Fatalf("Expected one audit entry, got %d", len(target.entries))
+	}
+	entry := target.entries[0]
+	if entry.API.Name != ldapIdentity {
+		t.Fatalf("Expected API %s, got %s", ldapIdentity, entry.API.Name)
+	}
+	if entry.ReqQuery["LDAPPassword"] != audit.Redacted {
+		t.Fatalf("Expected the LDAP password to be redacted, got %s", entry.ReqQuery["LDAPPassword"])
+	}
+	if entry.ReqQuery["LDAPUsername"] != "alice" {
+		t.Fatalf("Expected the LDAP username alice, got %s", entry.ReqQuery["LDAPUsername"])
+	}
+}
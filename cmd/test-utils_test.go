@@ -190,7 +190,7 @@ func prepareXLSets32() (ObjectLayer, []string, error) {
 
 	endpoints := append(endpoints1, endpoints2...)
 	fsDirs := append(fsDirs1, fsDirs2...)
-	format, err := waitForFormatXL(context.Background(), true, endpoints, 2, 16)
+	format, err := waitForFormatXL(context.Background(), true, endpoints, 2, 16, "")
 	if err != nil {
 		removeRoots(fsDirs)
 		return nil, nil, err
@@ -373,6 +373,18 @@ func UnstartedTestServer(t TestErrHandler, instanceType string) TestServer {
 	globalLifecycleSys = NewLifecycleSys()
 	globalLifecycleSys.Init(buckets, objLayer)
 
+	globalACLSys = NewACLSys()
+	globalACLSys.Init(buckets, objLayer)
+
+	globalBucketLoggingSys = NewBucketLoggingSys()
+	globalBucketLoggingSys.Init(buckets, objLayer)
+
+	globalBucketStorageClassSys = NewBucketStorageClassSys()
+	globalBucketStorageClassSys.Init(buckets, objLayer)
+
+	globalDecommissionSys = NewDecommissionSys()
+	globalDecommissionSys.Init(objLayer)
+
 	return testServer
 }
 
@@ -474,6 +486,7 @@ func resetGlobalIsEnvs() {
 func resetGlobalStorageEnvs() {
 	globalStandardStorageClass = storageClass{}
 	globalRRStorageClass = storageClass{}
+	globalCustomStorageClasses = nil
 }
 
 // reset global heal state
@@ -1586,6 +1599,15 @@ func getListenBucketNotificationURL(endPoint, bucketName string, prefixes, suffi
 	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
 }
 
+// return URL for streaming the events of a bucket, or of all buckets
+// if bucketName is empty.
+func getListenNotificationStreamURL(endPoint, bucketName, stream string, events []string) string {
+	queryValue := url.Values{}
+	queryValue.Set("stream", stream)
+	queryValue["events"] = events
+	return makeTestTargetURL(endPoint, bucketName, "", queryValue)
+}
+
 // returns temp root directory. `
 func getTestRoot() (string, error) {
 	return ioutil.TempDir(globalTestTmpDir, "api-")
@@ -1615,7 +1637,7 @@ func newTestObjectLayer(endpoints EndpointList) (newObject ObjectLayer, err erro
 		return NewFSObjectLayer(endpoints[0].Path)
 	}
 
-	_, err = waitForFormatXL(context.Background(), endpoints[0].IsLocal, endpoints, 1, 16)
+	_, err = waitForFormatXL(context.Background(), endpoints[0].IsLocal, endpoints, 1, 16, "")
 	if err != nil {
 		return nil, err
 	}
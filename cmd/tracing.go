@@ -0,0 +1,139 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/tracing"
+)
+
+// newTracer - returns a tracer exporting the spans of this server to
+// the configured collector.
+func newTracer(endpoint string, sampleRatio float64) *tracing.Tracer {
+	resource := map[string]string{"service.name": "minio"}
+	if hostName, err := os.Hostname(); err == nil {
+		resource["host.name"] = hostName
+	}
+	exporter := tracing.NewHTTPExporter(endpoint, resource, NewCustomHTTPTransport(), func(err error) {
+		logger.LogOnceIf(context.Background(), err, endpoint)
+	})
+	return tracing.New(exporter, sampleRatio)
+}
+
+// traceHTTPRequest - records a span for a request. Requests of other
+// servers continue the trace of the caller, internal requests are
+// only traced as part of such a trace.
+func traceHTTPRequest(api string, f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tracer := globalTracer
+		if tracer == nil {
+			f.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		if parent, ok := tracing.Extract(r.Header); ok {
+			ctx = tracing.ContextWithRemoteParent(ctx, parent)
+		} else if strings.HasPrefix(api, "internal.") {
+			f.ServeHTTP(w, r)
+			return
+		}
+		ctx, span := tracer.Start(ctx, api, tracing.KindServer)
+		if span == nil {
+			f.ServeHTTP(w, r)
+			return
+		}
+		defer span.Finish()
+
+		span.SetAttribute("http.method", r.Method)
+		vars := mux.Vars(r)
+		if bucket := vars["bucket"]; bucket != "" {
+			span.SetAttribute("bucket", bucket)
+		}
+		if object := vars["object"]; object != "" {
+			span.SetAttribute("object", object)
+		}
+
+		f.ServeHTTP(w, r.WithContext(ctx))
+
+		lrw, ok := w.(*logger.ResponseWriter)
+		if !ok {
+			return
+		}
+		span.SetAttribute("http.status_code", strconv.Itoa(lrw.StatusCode))
+		if lrw.StatusCode >= http.StatusBadRequest {
+			code := lrw.ErrorCode
+			if code == "" {
+				code = http.StatusText(lrw.StatusCode)
+			}
+			span.SetError(errors.New(code))
+		}
+	}
+}
+
+// lockSpanMap - contexts of the lock requests of traced requests by
+// lock UID, as dsync calls the lock servers without a context.
+type lockSpanMap struct {
+	mu   sync.Mutex
+	ctxs map[string]context.Context
+}
+
+var globalLockSpans = &lockSpanMap{ctxs: make(map[string]context.Context)}
+
+// start - starts the span of a lock request, the calls to the lock
+// servers with the same UID are its children until it is finished.
+func (m *lockSpanMap) start(ctx context.Context, uid, call, resource string) *tracing.Span {
+	_, span := tracing.Start(ctx, "lock."+call, tracing.KindInternal)
+	if span == nil {
+		return nil
+	}
+	span.SetAttribute("resource", resource)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ctxs[uid] = tracing.ContextWithSpan(context.Background(), span)
+	return span
+}
+
+func (m *lockSpanMap) finish(uid string, span *tracing.Span, err error) {
+	if span == nil {
+		return
+	}
+	m.mu.Lock()
+	delete(m.ctxs, uid)
+	m.mu.Unlock()
+	finishSpan(span, err)
+}
+
+// context - returns the context of the lock request with uid.
+func (m *lockSpanMap) context(uid string) context.Context {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ctx, ok := m.ctxs[uid]; ok {
+		return ctx
+	}
+	return context.Background()
+}
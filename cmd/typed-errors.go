@@ -95,3 +95,16 @@ var errIAMActionNotAllowed = errors.New("Specified IAM action is not allowed und
 
 // error returned when access is denied.
 var errAccessDenied = errors.New("Do not have enough permissions to access this resource")
+
+// error returned when a decommission is requested for a server
+// without several zones.
+var errDecommissionNotSupported = errors.New("Decommission is only supported on servers with more than one zone")
+
+// error returned when a decommission is started while another one is running.
+var errDecommissionRunning = errors.New("A decommission is already running")
+
+// error returned when no decommission is running.
+var errDecommissionNotRunning = errors.New("No decommission is running")
+
+// errDecommissionIncomplete - objects or multipart uploads are left on the decommissioned zone or set.
+var errDecommissionIncomplete = errors.New("Decommission is waiting for objects and multipart uploads left on the zone")
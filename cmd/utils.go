@@ -422,7 +422,8 @@ func newContext(r *http.Request, w http.ResponseWriter, api string) context.Cont
 		BucketName:   bucket,
 		ObjectName:   object,
 	}
-	return logger.SetReqInfo(r.Context(), reqInfo)
+	// Policy decisions taken with this context are audited.
+	return logger.SetAuditWriter(logger.SetReqInfo(r.Context(), reqInfo), w)
 }
 
 // Used for registering with rest handlers (have a look at registerStorageRESTHandlers for usage example)
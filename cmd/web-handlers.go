@@ -981,6 +981,7 @@ func (web *webAPIHandlers) Upload(w http.ResponseWriter, r *http.Request) {
 		writeErrorResponse(ctx, w, toAPIError(ctx, err), r.URL, guessIsBrowserReq(r))
 		return
 	}
+	setBucketStorageClass(metadata, bucket, object)
 
 	var pReader *PutObjReader
 	var reader io.Reader = r.Body
@@ -0,0 +1,498 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+// The list index of a bucket is persisted as a snapshot and a journal
+// per server process, below
+//
+//   buckets/<bucket>/listindex/snapshot.bin
+//   buckets/<bucket>/listindex/journal/<journal id>/<segment number>
+//
+// Every update is appended to the journal of the server serving it
+// before the request completes, so a crash loses no update. Servers
+// apply the journals of each other in the background and before a
+// listing, and load the snapshot and all journals on start. From time
+// to time the server holding the list index lock writes a new snapshot
+// and removes the journal segments covered by the previous snapshot.
+const (
+	// Directory of the list index of a bucket.
+	listIndexDir = "listindex"
+
+	// Snapshot of a bucket list index.
+	listIndexSnapshotFile = "snapshot.bin"
+
+	// Directory of the journals of a bucket list index.
+	listIndexJournalDir = "journal"
+
+	// Version of the list index snapshot and journal format.
+	listIndexFormatVersion = 4
+
+	// Lock held while writing snapshots.
+	listIndexLockPath = "listindex.lock"
+
+	// Interval of reading the journals of other servers.
+	listIndexSyncInterval = 5 * time.Second
+
+	// Interval of writing snapshots.
+	listIndexCompactInterval = 5 * time.Minute
+
+	// Time deleted objects are remembered, older updates of them
+	// are applied by all servers long before.
+	listIndexDeletedExpiry = time.Hour
+)
+
+var listIndexLockTimeout = newDynamicTimeout(time.Second, time.Second)
+
+// listIndexSnapshot - persisted form of a bucket list index, along
+// with the last segment of each journal it holds the updates of.
+type listIndexSnapshot struct {
+	Version int
+	Time    time.Time
+	Applied map[string]int64
+	Entries []listIndexEntry
+	Deleted map[string]time.Time
+}
+
+// listIndexSegment - updates written at once to a journal.
+type listIndexSegment struct {
+	Version int
+	Records []listIndexRecord
+}
+
+// queue - queues updates to be written to the journal of this
+// server, returns the number of updates queued so far.
+func (b *bucketListIndex) queue(records ...listIndexRecord) uint64 {
+	b.pendingMu.Lock()
+	defer b.pendingMu.Unlock()
+
+	b.pending = append(b.pending, records...)
+	b.queued += uint64(len(records))
+	return b.queued
+}
+
+// flush - writes the queued updates as the next segment of the
+// journal of this server, unless the first n updates are written
+// already by a concurrent flush.
+func (b *bucketListIndex) flush(ctx context.Context, objAPI ObjectLayer, bucket, journalID string, n uint64) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	if b.flushed >= n {
+		return nil
+	}
+
+	b.pendingMu.Lock()
+	records, queued := b.pending, b.queued
+	b.pending = nil
+	b.pendingMu.Unlock()
+
+	if err := saveListIndexSegment(ctx, objAPI, bucket, journalID, b.seq+1, records); err != nil {
+		// Written again by the next flush.
+		b.pendingMu.Lock()
+		b.pending = append(records, b.pending...)
+		b.pendingMu.Unlock()
+		return err
+	}
+	b.seq++
+	b.flushed = queued
+	return nil
+}
+
+// flushPending - writes all queued updates to the journal of
+// this server.
+func (b *bucketListIndex) flushPending(ctx context.Context, objAPI ObjectLayer, bucket, journalID string) error {
+	b.pendingMu.Lock()
+	n := b.queued
+	b.pendingMu.Unlock()
+
+	return b.flush(ctx, objAPI, bucket, journalID, n)
+}
+
+// sync - applies the journal segments written by other servers since
+// the last sync. Without discover only the journals known already are
+// read, otherwise the journals are listed, which finds journals of
+// servers started since.
+func (b *bucketListIndex) sync(ctx context.Context, objAPI ObjectLayer, bucket, journalID string, discover bool) error {
+	b.syncMu.Lock()
+	defer b.syncMu.Unlock()
+
+	if !discover {
+		for id := range b.applied {
+			if id == journalID {
+				continue
+			}
+			for {
+				err := b.applySegment(ctx, objAPI, bucket, id, b.applied[id]+1)
+				if err == errConfigNotFound {
+					break
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	journals, err := listListIndexSegments(ctx, objAPI, bucket)
+	if err != nil {
+		return err
+	}
+
+	// Journals which are no longer listed are removed by a snapshot.
+	for id := range b.applied {
+		if _, ok := journals[id]; !ok {
+			delete(b.applied, id)
+		}
+	}
+
+	for id, seqs := range journals {
+		if id == journalID {
+			continue
+		}
+		i := sort.Search(len(seqs), func(i int) bool { return seqs[i] > b.applied[id] })
+		if i < len(seqs) && seqs[i] != b.applied[id]+1 {
+			// Segments were removed before they were applied, the
+			// snapshot written meanwhile holds their updates.
+			snapshot, err := readListIndexSnapshot(ctx, objAPI, bucket)
+			if err != nil {
+				return err
+			}
+			b.merge(snapshot)
+			break
+		}
+	}
+
+	for id, seqs := range journals {
+		if id == journalID {
+			continue
+		}
+		for _, seq := range seqs {
+			if seq <= b.applied[id] {
+				continue
+			}
+			err = b.applySegment(ctx, objAPI, bucket, id, seq)
+			if err == errConfigNotFound {
+				// Removed meanwhile, the next sync reads the snapshot.
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Applies a journal segment. Must be called with the sync lock held.
+func (b *bucketListIndex) applySegment(ctx context.Context, objAPI ObjectLayer, bucket, journalID string, seq int64) error {
+	records, err := readListIndexSegment(ctx, objAPI, bucket, journalID, seq)
+	if err != nil {
+		return err
+	}
+	b.apply(records...)
+	b.applied[journalID] = seq
+	return nil
+}
+
+// merge - applies a snapshot. Must be called with the sync lock held.
+func (b *bucketListIndex) merge(snapshot listIndexSnapshot) {
+	b.mu.Lock()
+	for _, entry := range snapshot.Entries {
+		b.applyRecord(listIndexRecord{Entry: entry})
+	}
+	for name, updated := range snapshot.Deleted {
+		b.applyRecord(listIndexRecord{Deleted: true, Entry: listIndexEntry{Name: name, Updated: updated}})
+	}
+	b.mu.Unlock()
+
+	for id, seq := range snapshot.Applied {
+		if seq > b.applied[id] {
+			b.applied[id] = seq
+		}
+	}
+}
+
+// snapshot - returns the entries of the index along with the last
+// segment applied of each journal. The entries are copied after the
+// journal positions, they hold at least the updates of the segments.
+func (b *bucketListIndex) snapshot(journalID string) listIndexSnapshot {
+	snapshot := listIndexSnapshot{
+		Version: listIndexFormatVersion,
+		Time:    UTCNow(),
+		Applied: make(map[string]int64),
+		Deleted: make(map[string]time.Time),
+	}
+
+	b.syncMu.Lock()
+	for id, seq := range b.applied {
+		snapshot.Applied[id] = seq
+	}
+	b.syncMu.Unlock()
+
+	// Updates of this server are applied before they are written.
+	b.flushMu.Lock()
+	if b.seq > 0 {
+		snapshot.Applied[journalID] = b.seq
+	}
+	b.flushMu.Unlock()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	snapshot.Entries = make([]listIndexEntry, len(b.names))
+	for i, name := range b.names {
+		snapshot.Entries[i] = b.entries[name]
+	}
+	for name, updated := range b.deleted {
+		snapshot.Deleted[name] = updated
+	}
+	return snapshot
+}
+
+// saveSnapshot - writes a snapshot of the index, the index is only
+// locked while it is copied. Returns the journal positions of the
+// snapshot.
+func (b *bucketListIndex) saveSnapshot(ctx context.Context, objAPI ObjectLayer, bucket, journalID string) (map[string]int64, error) {
+	snapshot := b.snapshot(journalID)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snapshot); err != nil {
+		return nil, err
+	}
+	configFile := path.Join(bucketConfigPrefix, bucket, listIndexDir, listIndexSnapshotFile)
+	if err := saveConfig(ctx, objAPI, configFile, buf.Bytes()); err != nil {
+		return nil, err
+	}
+	return snapshot.Applied, nil
+}
+
+// compact - writes a new snapshot unless the current one is more
+// recent than minAge, and removes the journal segments covered by
+// both the current and the new snapshot. Servers applying the
+// journals have had time to apply the removed segments, otherwise
+// they read the new snapshot. Must be called with the list index
+// lock held.
+func (b *bucketListIndex) compact(ctx context.Context, objAPI ObjectLayer, bucket, journalID string, minAge time.Duration) error {
+	prev, err := readListIndexSnapshot(ctx, objAPI, bucket)
+	if err != nil && err != errConfigNotFound && err != errInvalidListIndex {
+		return err
+	}
+	if err == nil && UTCNow().Sub(prev.Time) < minAge {
+		return nil
+	}
+
+	if err = b.flushPending(ctx, objAPI, bucket, journalID); err != nil {
+		return err
+	}
+	if err = b.sync(ctx, objAPI, bucket, journalID, true); err != nil {
+		return err
+	}
+	applied, err := b.saveSnapshot(ctx, objAPI, bucket, journalID)
+	if err != nil {
+		return err
+	}
+
+	journals, err := listListIndexSegments(ctx, objAPI, bucket)
+	if err != nil {
+		return err
+	}
+	for id, seqs := range journals {
+		last := prev.Applied[id]
+		if applied[id] < last {
+			last = applied[id]
+		}
+		for _, seq := range seqs {
+			if seq > last {
+				break
+			}
+			err = deleteConfig(ctx, objAPI, listIndexSegmentPath(bucket, id, seq))
+			if err != nil && !isErrObjectNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// run - applies the journals of other servers, writes updates which
+// could not be written before and writes snapshots until the server
+// shuts down.
+func (idx *xlListIndex) run() {
+	ctx := context.Background()
+
+	lastCompact := UTCNow()
+	for {
+		select {
+		case <-GlobalServiceDoneCh:
+			return
+		case <-time.After(listIndexSyncInterval):
+		}
+
+		for bucket, b := range idx.loaded() {
+			if !b.isReady() {
+				continue
+			}
+			logger.LogIf(ctx, b.flushPending(ctx, idx.objAPI, bucket, idx.journalID))
+			logger.LogIf(ctx, b.sync(ctx, idx.objAPI, bucket, idx.journalID, true))
+		}
+
+		if UTCNow().Sub(lastCompact) < listIndexCompactInterval {
+			continue
+		}
+		lastCompact = UTCNow()
+
+		// Buckets created by other servers.
+		logger.LogIf(ctx, idx.loadBuckets(ctx))
+		idx.compact(ctx)
+	}
+}
+
+// compact - writes snapshots of all buckets if no other server does.
+func (idx *xlListIndex) compact(ctx context.Context) {
+	for _, b := range idx.loaded() {
+		b.pruneDeleted(UTCNow().Add(-listIndexDeletedExpiry))
+	}
+
+	lock := globalNSMutex.NewNSLock(ctx, minioMetaBucket, listIndexLockPath)
+	if err := lock.GetLock(listIndexLockTimeout); err != nil {
+		// Another server writes the snapshots.
+		return
+	}
+	defer lock.Unlock()
+
+	for bucket, b := range idx.loaded() {
+		if !b.isReady() {
+			continue
+		}
+		if err := b.compact(ctx, idx.objAPI, bucket, idx.journalID, listIndexCompactInterval/2); err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to write list index snapshot of bucket %s: %v", bucket, err))
+		}
+	}
+}
+
+func listIndexSegmentPath(bucket, journalID string, seq int64) string {
+	return path.Join(bucketConfigPrefix, bucket, listIndexDir, listIndexJournalDir, journalID, fmt.Sprintf("%020d", seq))
+}
+
+func readListIndexSnapshot(ctx context.Context, objAPI ObjectLayer, bucket string) (listIndexSnapshot, error) {
+	var snapshot listIndexSnapshot
+	data, err := readConfig(ctx, objAPI, path.Join(bucketConfigPrefix, bucket, listIndexDir, listIndexSnapshotFile))
+	if err != nil {
+		return snapshot, err
+	}
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(&snapshot); err != nil || snapshot.Version != listIndexFormatVersion {
+		logger.LogIf(ctx, fmt.Errorf("Invalid list index of bucket %s: %v", bucket, err))
+		return snapshot, errInvalidListIndex
+	}
+	return snapshot, nil
+}
+
+func saveListIndexSegment(ctx context.Context, objAPI ObjectLayer, bucket, journalID string, seq int64, records []listIndexRecord) error {
+	var buf bytes.Buffer
+	segment := listIndexSegment{Version: listIndexFormatVersion, Records: records}
+	if err := gob.NewEncoder(&buf).Encode(segment); err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, listIndexSegmentPath(bucket, journalID, seq), buf.Bytes())
+}
+
+func readListIndexSegment(ctx context.Context, objAPI ObjectLayer, bucket, journalID string, seq int64) ([]listIndexRecord, error) {
+	data, err := readConfig(ctx, objAPI, listIndexSegmentPath(bucket, journalID, seq))
+	if err != nil {
+		return nil, err
+	}
+	var segment listIndexSegment
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(&segment); err != nil || segment.Version != listIndexFormatVersion {
+		return nil, fmt.Errorf("Invalid list index journal %s of bucket %s: %v", journalID, bucket, err)
+	}
+	return segment.Records, nil
+}
+
+// Lists the objects below the list index directory of a bucket.
+func listListIndexObjects(ctx context.Context, objAPI ObjectLayer, bucket string) ([]string, error) {
+	prefix := path.Join(bucketConfigPrefix, bucket, listIndexDir) + SlashSeparator
+	var objects []string
+	marker := ""
+	for {
+		loi, err := objAPI.ListObjects(ctx, minioMetaBucket, prefix, marker, "", maxObjectList)
+		if err != nil {
+			return nil, err
+		}
+		for _, objInfo := range loi.Objects {
+			objects = append(objects, objInfo.Name)
+		}
+		if !loi.IsTruncated {
+			return objects, nil
+		}
+		marker = loi.NextMarker
+	}
+}
+
+// Returns the sorted segment numbers of each journal of a bucket.
+func listListIndexSegments(ctx context.Context, objAPI ObjectLayer, bucket string) (map[string][]int64, error) {
+	objects, err := listListIndexObjects(ctx, objAPI, bucket)
+	if err != nil {
+		return nil, err
+	}
+	prefix := path.Join(bucketConfigPrefix, bucket, listIndexDir, listIndexJournalDir) + SlashSeparator
+	journals := make(map[string][]int64)
+	for _, object := range objects {
+		if !hasPrefix(object, prefix) {
+			continue
+		}
+		parts := strings.Split(strings.TrimPrefix(object, prefix), SlashSeparator)
+		if len(parts) != 2 {
+			continue
+		}
+		seq, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		// Names are zero padded, hence listed in order.
+		journals[parts[0]] = append(journals[parts[0]], seq)
+	}
+	return journals, nil
+}
+
+// removeListIndex - removes the snapshot and the journals of a bucket.
+func removeListIndex(ctx context.Context, objAPI ObjectLayer, bucket string) error {
+	objects, err := listListIndexObjects(ctx, objAPI, bucket)
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		if err = deleteConfig(ctx, objAPI, object); err != nil && !isErrObjectNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,674 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/config/listindex"
+	"github.com/minio/minio/cmd/crypto"
+	"github.com/minio/minio/cmd/logger"
+)
+
+var errInvalidListIndex = errors.New("invalid list index")
+
+// listIndexPart - size of a part of an encrypted multipart object.
+type listIndexPart struct {
+	Number int
+	Size   int64
+}
+
+// listIndexEntry - object information needed to list an object. Of the
+// metadata only internal entries are kept, they are needed along with the
+// part sizes of encrypted multipart objects to report the size and ETag of
+// compressed and encrypted objects. Updated is the time of the update
+// which wrote the entry, the latest update of an object wins.
+type listIndexEntry struct {
+	Updated      time.Time
+	Name         string
+	IsDir        bool
+	ModTime      time.Time
+	Size         int64
+	ETag         string
+	ContentType  string
+	StorageClass string
+	Metadata     map[string]string
+	Parts        []listIndexPart
+}
+
+func newListIndexEntry(objInfo ObjectInfo, updated time.Time) listIndexEntry {
+	entry := listIndexEntry{
+		Updated:      updated,
+		Name:         objInfo.Name,
+		IsDir:        objInfo.IsDir,
+		ModTime:      objInfo.ModTime,
+		Size:         objInfo.Size,
+		ETag:         objInfo.ETag,
+		ContentType:  objInfo.ContentType,
+		StorageClass: objInfo.StorageClass,
+	}
+	for k, v := range objInfo.UserDefined {
+		if !strings.HasPrefix(k, ReservedMetadataPrefix) {
+			continue
+		}
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]string)
+		}
+		entry.Metadata[k] = v
+	}
+	if crypto.IsMultiPart(objInfo.UserDefined) {
+		entry.Parts = make([]listIndexPart, len(objInfo.Parts))
+		for i, part := range objInfo.Parts {
+			entry.Parts[i] = listIndexPart{Number: part.Number, Size: part.Size}
+		}
+	}
+	return entry
+}
+
+func (e listIndexEntry) toObjectInfo(bucket string) ObjectInfo {
+	if e.IsDir {
+		return ObjectInfo{
+			Bucket: bucket,
+			Name:   e.Name,
+			IsDir:  true,
+		}
+	}
+	objInfo := ObjectInfo{
+		Bucket:       bucket,
+		Name:         e.Name,
+		ModTime:      e.ModTime,
+		Size:         e.Size,
+		ETag:         e.ETag,
+		ContentType:  e.ContentType,
+		StorageClass: e.StorageClass,
+		UserDefined:  e.Metadata,
+		backendType:  BackendErasure,
+	}
+	for _, part := range e.Parts {
+		objInfo.Parts = append(objInfo.Parts, ObjectPartInfo{Number: part.Number, Size: part.Size})
+	}
+	return objInfo
+}
+
+// listIndexRecord - update of the index, the entry of a written
+// object or the name and time of a deleted object.
+type listIndexRecord struct {
+	Deleted bool
+	Entry   listIndexEntry
+}
+
+// bucketListIndex - sorted object names of a bucket along with the
+// information needed to list them.
+//
+// The index is kept in memory. Every server appends its updates to
+// its own journal on the drives, and applies the journals of the
+// other servers, see xl-sets-list-index-journal.go. Updates of an
+// object are applied if they are newer than the last update applied,
+// so journals can be applied in any order.
+type bucketListIndex struct {
+	mu sync.RWMutex
+
+	names   []string
+	entries map[string]listIndexEntry
+
+	// Times of deleted objects, so that older updates
+	// applied afterwards do not restore them.
+	deleted map[string]time.Time
+
+	// Set once the index was loaded or rebuilt.
+	ready bool
+
+	// Updates of this server not yet written to its journal.
+	pendingMu sync.Mutex
+	pending   []listIndexRecord
+	queued    uint64
+
+	// Serializes writes of the journal of this server, seq is
+	// the last segment written.
+	flushMu sync.Mutex
+	flushed uint64
+	seq     int64
+
+	// Serializes reads of the journals, applied holds the
+	// last segment applied of each journal.
+	syncMu  sync.Mutex
+	applied map[string]int64
+}
+
+func newBucketListIndex() *bucketListIndex {
+	return &bucketListIndex{
+		entries: make(map[string]listIndexEntry),
+		deleted: make(map[string]time.Time),
+		applied: make(map[string]int64),
+	}
+}
+
+func (b *bucketListIndex) isReady() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.ready
+}
+
+// setReady - marks the index as complete.
+func (b *bucketListIndex) setReady() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.ready = true
+}
+
+// Inserts the name at its sorted position. Must be called with
+// the lock held.
+func (b *bucketListIndex) insertName(name string) {
+	i := sort.SearchStrings(b.names, name)
+	b.names = append(b.names, "")
+	copy(b.names[i+1:], b.names[i:])
+	b.names[i] = name
+}
+
+// Removes the name from the sorted names. Must be called with
+// the lock held.
+func (b *bucketListIndex) removeName(name string) {
+	i := sort.SearchStrings(b.names, name)
+	if i < len(b.names) && b.names[i] == name {
+		b.names = append(b.names[:i], b.names[i+1:]...)
+	}
+}
+
+// Adds or replaces an entry. Must be called with the lock held.
+func (b *bucketListIndex) putEntry(entry listIndexEntry) {
+	if _, ok := b.entries[entry.Name]; !ok {
+		b.insertName(entry.Name)
+	}
+	b.entries[entry.Name] = entry
+	delete(b.deleted, entry.Name)
+}
+
+// Removes an entry along with directories left empty, as the
+// drives remove them too. Must be called with the lock held.
+func (b *bucketListIndex) deleteEntry(name string, updated time.Time) {
+	b.deleted[name] = updated
+	if _, ok := b.entries[name]; !ok {
+		return
+	}
+	delete(b.entries, name)
+	b.removeName(name)
+
+	for dir := path.Dir(strings.TrimSuffix(name, SlashSeparator)); dir != "." && dir != SlashSeparator; dir = path.Dir(dir) {
+		dirName := dir + SlashSeparator
+		entry, ok := b.entries[dirName]
+		if !ok || !entry.IsDir {
+			continue
+		}
+		i := sort.SearchStrings(b.names, dirName)
+		if i+1 < len(b.names) && hasPrefix(b.names[i+1], dirName) {
+			// Directory is not empty.
+			break
+		}
+		delete(b.entries, dirName)
+		b.removeName(dirName)
+		b.deleted[dirName] = updated
+	}
+}
+
+// Applies the update unless a newer update of the object was
+// applied already. Must be called with the lock held.
+func (b *bucketListIndex) applyRecord(rec listIndexRecord) {
+	name := rec.Entry.Name
+	if entry, ok := b.entries[name]; ok && rec.Entry.Updated.Before(entry.Updated) {
+		return
+	}
+	if updated, ok := b.deleted[name]; ok && rec.Entry.Updated.Before(updated) {
+		return
+	}
+	if rec.Deleted {
+		b.deleteEntry(name, rec.Entry.Updated)
+	} else {
+		b.putEntry(rec.Entry)
+	}
+}
+
+// apply - applies updates made by this or another server.
+func (b *bucketListIndex) apply(records ...listIndexRecord) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, rec := range records {
+		b.applyRecord(rec)
+	}
+}
+
+// put - adds or replaces the entry of a written object.
+func (b *bucketListIndex) put(entry listIndexEntry) {
+	b.apply(listIndexRecord{Entry: entry})
+}
+
+// delete - removes the entry of a deleted object.
+func (b *bucketListIndex) delete(name string, updated time.Time) {
+	b.apply(listIndexRecord{Deleted: true, Entry: listIndexEntry{Name: name, Updated: updated}})
+}
+
+// pruneDeleted - forgets objects deleted before the given time,
+// updates of them are expected to be applied by now.
+func (b *bucketListIndex) pruneDeleted(before time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for name, updated := range b.deleted {
+		if updated.Before(before) {
+			delete(b.deleted, name)
+		}
+	}
+}
+
+// list - lists the index like a walk of all drives would.
+func (b *bucketListIndex) list(bucket, prefix, marker, delimiter string, maxKeys int) (loi ListObjectsInfo) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	start := prefix
+	if marker > start {
+		start = marker
+	}
+
+	var count int
+	var lastName string
+	for i := sort.SearchStrings(b.names, start); i < len(b.names); {
+		name := b.names[i]
+		if !hasPrefix(name, prefix) {
+			break
+		}
+		if name <= marker {
+			i++
+			continue
+		}
+
+		if delimiter != "" {
+			if index := strings.Index(name[len(prefix):], delimiter); index != -1 {
+				// Skip all names of the common prefix, they are contiguous.
+				commonPrefix := name[:len(prefix)+index+len(delimiter)]
+				n := sort.Search(len(b.names)-i, func(j int) bool {
+					return !hasPrefix(b.names[i+j], commonPrefix)
+				})
+				i += n
+				if commonPrefix <= marker {
+					continue
+				}
+				if count == maxKeys {
+					loi.IsTruncated = true
+					break
+				}
+				loi.Prefixes = append(loi.Prefixes, commonPrefix)
+				lastName = commonPrefix
+				count++
+				continue
+			}
+		}
+
+		i++
+		entry := b.entries[name]
+		if entry.IsDir && i < len(b.names) && hasPrefix(b.names[i], name) {
+			// Directories are only listed while they are empty.
+			continue
+		}
+		if count == maxKeys {
+			loi.IsTruncated = true
+			break
+		}
+		if entry.IsDir && delimiter == SlashSeparator {
+			loi.Prefixes = append(loi.Prefixes, name)
+		} else {
+			loi.Objects = append(loi.Objects, entry.toObjectInfo(bucket))
+		}
+		lastName = name
+		count++
+	}
+
+	if loi.IsTruncated {
+		loi.NextMarker = lastName
+	}
+	return loi
+}
+
+// listObjectsFromIndex - lists objects of an indexed bucket without
+// walking the drives.
+func (s *xlSets) listObjectsFromIndex(ctx context.Context, index *bucketListIndex, bucket, prefix, marker, delimiter string, maxKeys int) (loi ListObjectsInfo, err error) {
+	// Other delimiters are supported by listings of the drives too,
+	// without checking the arguments.
+	if delimiter == SlashSeparator || delimiter == "" {
+		if err = checkListObjsArgs(ctx, bucket, prefix, marker, delimiter, s); err != nil {
+			return loi, err
+		}
+	}
+
+	// Marker not common with prefix is not implemented. Send an empty response
+	if marker != "" && !hasPrefix(marker, prefix) {
+		return loi, nil
+	}
+
+	// With max keys of zero we have reached eof, return right here.
+	if maxKeys == 0 {
+		return loi, nil
+	}
+
+	// Same as a listing of the drives, there are no keys
+	// of the form '/keyName'.
+	if delimiter == SlashSeparator && prefix == SlashSeparator {
+		return loi, nil
+	}
+
+	// Over flowing count - reset to maxObjectList.
+	if maxKeys < 0 || maxKeys > maxObjectList {
+		maxKeys = maxObjectList
+	}
+
+	return index.list(bucket, prefix, marker, delimiter, maxKeys), nil
+}
+
+// xlListIndex - metadata index of the buckets of an erasure coded
+// deployment, updated by the object operations of the erasure sets
+// while they hold the object lock. Listings of indexed buckets are
+// served from memory instead of walking all drives.
+type xlListIndex struct {
+	mu      sync.RWMutex
+	config  listindex.Config
+	objAPI  ObjectLayer
+	buckets map[string]*bucketListIndex
+
+	// ID of the journal of this server, every server
+	// process writes its own journal.
+	journalID string
+}
+
+func newXLListIndex(config listindex.Config, objAPI ObjectLayer) *xlListIndex {
+	return &xlListIndex{
+		config:    config,
+		objAPI:    objAPI,
+		buckets:   make(map[string]*bucketListIndex),
+		journalID: mustGetUUID(),
+	}
+}
+
+// Returns the index of the bucket, creating it if it does not
+// exist yet. Returns nil if the bucket is not indexed.
+func (idx *xlListIndex) bucket(bucket string) *bucketListIndex {
+	if idx == nil || isMinioMetaBucketName(bucket) || !idx.config.IsIndexed(bucket) {
+		return nil
+	}
+
+	idx.mu.RLock()
+	b, ok := idx.buckets[bucket]
+	idx.mu.RUnlock()
+	if ok {
+		return b
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if b, ok = idx.buckets[bucket]; !ok {
+		b = newBucketListIndex()
+		idx.buckets[bucket] = b
+	}
+	return b
+}
+
+// Returns the indexes of all buckets known to this server.
+func (idx *xlListIndex) loaded() map[string]*bucketListIndex {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	buckets := make(map[string]*bucketListIndex, len(idx.buckets))
+	for bucket, b := range idx.buckets {
+		buckets[bucket] = b
+	}
+	return buckets
+}
+
+// Get - returns the index of the bucket if listings can be
+// served from it, after applying the updates other servers
+// have written to their journals since.
+func (idx *xlListIndex) Get(ctx context.Context, bucket string) *bucketListIndex {
+	if idx == nil {
+		return nil
+	}
+
+	idx.mu.RLock()
+	b, ok := idx.buckets[bucket]
+	idx.mu.RUnlock()
+	if !ok || !b.isReady() {
+		return nil
+	}
+	if err := b.sync(ctx, idx.objAPI, bucket, idx.journalID, false); err != nil {
+		// Listed from the drives until the journals can be read.
+		logger.LogIf(ctx, err)
+		return nil
+	}
+	return b
+}
+
+// MakeBucket - starts an empty index for a new bucket.
+func (idx *xlListIndex) MakeBucket(ctx context.Context, bucket string) {
+	b := idx.bucket(bucket)
+	if b == nil {
+		return
+	}
+	b.setReady()
+
+	// Without a snapshot the index would be rebuilt from the drives.
+	_, err := b.saveSnapshot(ctx, idx.objAPI, bucket, idx.journalID)
+	logger.LogIf(ctx, err)
+}
+
+// Put - records a written object, must be called while holding
+// the object lock. Returns once the update is written to the
+// journal of this server.
+func (idx *xlListIndex) Put(ctx context.Context, bucket string, objInfo ObjectInfo) {
+	b := idx.bucket(bucket)
+	if b == nil {
+		return
+	}
+	rec := listIndexRecord{Entry: newListIndexEntry(objInfo, UTCNow())}
+	b.apply(rec)
+	idx.journal(ctx, bucket, b, rec)
+}
+
+// Delete - records deleted objects, must be called while holding
+// the object locks. Returns once the updates are written to the
+// journal of this server.
+func (idx *xlListIndex) Delete(ctx context.Context, bucket string, objects ...string) {
+	b := idx.bucket(bucket)
+	if b == nil {
+		return
+	}
+	now := UTCNow()
+	records := make([]listIndexRecord, len(objects))
+	for i, object := range objects {
+		records[i] = listIndexRecord{Deleted: true, Entry: listIndexEntry{Name: object, Updated: now}}
+	}
+	b.apply(records...)
+	idx.journal(ctx, bucket, b, records...)
+}
+
+// Writes the updates to the journal of this server, along with
+// concurrent updates. Updates which could not be written are
+// written again by the next update or the background sync.
+func (idx *xlListIndex) journal(ctx context.Context, bucket string, b *bucketListIndex, records ...listIndexRecord) {
+	n := b.queue(records...)
+	if err := b.flush(ctx, idx.objAPI, bucket, idx.journalID, n); err != nil {
+		logger.LogIf(ctx, fmt.Errorf("Unable to write list index journal of bucket %s: %v", bucket, err))
+	}
+}
+
+// Drop - forgets the index of a bucket deleted by any server.
+func (idx *xlListIndex) Drop(bucket string) {
+	if idx == nil {
+		return
+	}
+
+	idx.mu.Lock()
+	delete(idx.buckets, bucket)
+	idx.mu.Unlock()
+}
+
+// Remove - drops the index of a deleted bucket along with its
+// snapshot and journals.
+func (idx *xlListIndex) Remove(ctx context.Context, bucket string) {
+	if idx == nil {
+		return
+	}
+
+	idx.Drop(bucket)
+	logger.LogIf(ctx, removeListIndex(ctx, idx.objAPI, bucket))
+}
+
+// dropListIndex - forgets the index of a bucket deleted by
+// another server.
+func dropListIndex(objAPI ObjectLayer, bucket string) {
+	for _, s := range getZoneSets(objAPI) {
+		s.listIndex.Drop(bucket)
+	}
+}
+
+// Init - loads or rebuilds the index of all indexed buckets.
+func (idx *xlListIndex) Init() {
+	ctx := context.Background()
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	// Retry as long as the erasure sets have no read quorum.
+	retryTimerCh := newRetryTimerSimple(doneCh)
+	for {
+		select {
+		case <-retryTimerCh:
+		case <-GlobalServiceDoneCh:
+			return
+		}
+
+		if err := idx.loadBuckets(ctx); err == nil {
+			return
+		}
+	}
+}
+
+// Loads the index of all indexed buckets which are not loaded yet,
+// including buckets created by other servers.
+func (idx *xlListIndex) loadBuckets(ctx context.Context) error {
+	buckets, err := idx.objAPI.ListBuckets(ctx)
+	if err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		b := idx.bucket(bucket.Name)
+		if b == nil {
+			continue
+		}
+		if err = b.load(ctx, idx.objAPI, bucket.Name, idx.journalID); err != nil {
+			logger.LogIf(ctx, fmt.Errorf("Unable to load list index of bucket %s: %v", bucket.Name, err))
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush - writes the updates of all buckets which are not written
+// to the journal yet. Called on a clean shutdown.
+func (idx *xlListIndex) Flush(ctx context.Context) error {
+	if idx == nil {
+		return nil
+	}
+
+	for bucket, b := range idx.loaded() {
+		if err := b.flushPending(ctx, idx.objAPI, bucket, idx.journalID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// load - fills the index from the snapshot and the journals of all
+// servers, or by listing all drives if there is no snapshot, i.e. if
+// the bucket was not indexed before or writes might have bypassed the
+// index.
+func (b *bucketListIndex) load(ctx context.Context, objAPI ObjectLayer, bucket, journalID string) error {
+	if b.isReady() {
+		return nil
+	}
+
+	var rebuild bool
+	snapshot, err := readListIndexSnapshot(ctx, objAPI, bucket)
+	switch err {
+	case nil:
+		b.syncMu.Lock()
+		b.merge(snapshot)
+		b.syncMu.Unlock()
+	case errConfigNotFound, errInvalidListIndex:
+		rebuild = true
+	default:
+		return err
+	}
+	if err = b.sync(ctx, objAPI, bucket, journalID, true); err != nil {
+		return err
+	}
+
+	if rebuild {
+		logger.Info("Rebuilding list index of bucket %s", bucket)
+		marker := ""
+		for {
+			loi, err := objAPI.ListObjects(ctx, bucket, "", marker, "", maxObjectList)
+			if err != nil {
+				return err
+			}
+			for _, objInfo := range loi.Objects {
+				// Updates made while listing are newer.
+				b.put(newListIndexEntry(objInfo, objInfo.ModTime))
+			}
+			if !loi.IsTruncated {
+				break
+			}
+			marker = loi.NextMarker
+		}
+		if _, err = b.saveSnapshot(ctx, objAPI, bucket, journalID); err != nil {
+			return err
+		}
+	}
+
+	b.setReady()
+	return nil
+}
+
+// clearListIndexes - removes the index of the buckets which are not
+// indexed, their writes are not recorded and their index has to be
+// rebuilt once they are indexed again.
+func clearListIndexes(ctx context.Context, objAPI ObjectLayer, config listindex.Config, buckets []BucketInfo) error {
+	for _, bucket := range buckets {
+		if config.IsIndexed(bucket.Name) {
+			continue
+		}
+		if err := removeListIndex(ctx, objAPI, bucket.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
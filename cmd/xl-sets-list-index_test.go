@@ -0,0 +1,297 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/cmd/config/listindex"
+)
+
+func TestBucketListIndexList(t *testing.T) {
+	b := newBucketListIndex()
+	for _, name := range []string{"a/1", "a/2", "a/b/1", "b", "c/", "d/", "d/1", "e-1", "e-2"} {
+		b.put(listIndexEntry{Name: name, IsDir: hasSuffix(name, SlashSeparator)})
+	}
+	b.setReady()
+
+	names := func(loi ListObjectsInfo) (objects []string) {
+		for _, objInfo := range loi.Objects {
+			objects = append(objects, objInfo.Name)
+		}
+		return objects
+	}
+
+	testCases := []struct {
+		prefix, marker, delimiter string
+		maxKeys                   int
+		objects, prefixes         []string
+		nextMarker                string
+	}{
+		// Directories with objects are not listed.
+		{"", "", "", 100, []string{"a/1", "a/2", "a/b/1", "b", "c/", "d/1", "e-1", "e-2"}, nil, ""},
+		{"", "", SlashSeparator, 100, []string{"b", "e-1", "e-2"}, []string{"a/", "c/", "d/"}, ""},
+		{"a/", "", SlashSeparator, 100, []string{"a/1", "a/2"}, []string{"a/b/"}, ""},
+		{"", "", SlashSeparator, 2, []string{"b"}, []string{"a/"}, "b"},
+		{"", "b", SlashSeparator, 2, nil, []string{"c/", "d/"}, "d/"},
+		// Common prefixes up to the marker are skipped.
+		{"", "a/1", SlashSeparator, 100, []string{"b", "e-1", "e-2"}, []string{"c/", "d/"}, ""},
+		{"", "", "-", 100, []string{"a/1", "a/2", "a/b/1", "b", "c/", "d/1"}, []string{"e-"}, ""},
+		{"e", "", "", 1, []string{"e-1"}, nil, "e-1"},
+	}
+	for i, testCase := range testCases {
+		loi := b.list("bucket", testCase.prefix, testCase.marker, testCase.delimiter, testCase.maxKeys)
+		if !reflect.DeepEqual(names(loi), testCase.objects) {
+			t.Fatalf("Test %d: expected objects %v, got %v", i+1, testCase.objects, names(loi))
+		}
+		if !reflect.DeepEqual(loi.Prefixes, testCase.prefixes) {
+			t.Fatalf("Test %d: expected prefixes %v, got %v", i+1, testCase.prefixes, loi.Prefixes)
+		}
+		if loi.IsTruncated != (testCase.nextMarker != "") || loi.NextMarker != testCase.nextMarker {
+			t.Fatalf("Test %d: expected next marker %q, got %q", i+1, testCase.nextMarker, loi.NextMarker)
+		}
+	}
+
+	// Empty directories are removed along with their last object.
+	b.delete("d/1", UTCNow())
+	if loi := b.list("bucket", "", "", "", 100); !reflect.DeepEqual(names(loi), []string{"a/1", "a/2", "a/b/1", "b", "c/", "e-1", "e-2"}) {
+		t.Fatalf("Expected d/ to be removed, got %v", names(loi))
+	}
+}
+
+func TestNewListIndexEntry(t *testing.T) {
+	entry := newListIndexEntry(ObjectInfo{
+		Name: "object",
+		Size: 10,
+		UserDefined: map[string]string{
+			"content-type":                         "text/plain",
+			"X-Amz-Meta-Key":                       "value",
+			ReservedMetadataPrefix + "compression": "klauspost/compress/s2",
+		},
+		Parts: []ObjectPartInfo{{Number: 1, Size: 10}},
+	}, UTCNow())
+	// Only the internal metadata needed for listings is kept.
+	if !reflect.DeepEqual(entry.Metadata, map[string]string{ReservedMetadataPrefix + "compression": "klauspost/compress/s2"}) {
+		t.Fatalf("Unexpected metadata %v", entry.Metadata)
+	}
+	if entry.Parts != nil {
+		t.Fatalf("Expected no parts for an unencrypted object, got %v", entry.Parts)
+	}
+}
+
+func TestBucketListIndexApply(t *testing.T) {
+	b := newBucketListIndex()
+	now := UTCNow()
+
+	// Updates applied out of order, the latest update wins.
+	b.put(listIndexEntry{Name: "a", Updated: now, Size: 2})
+	b.put(listIndexEntry{Name: "a", Updated: now.Add(-time.Second), Size: 1})
+	b.delete("b", now)
+	b.put(listIndexEntry{Name: "b", Updated: now.Add(-time.Second)})
+	b.delete("a", now.Add(-time.Second))
+
+	loi := b.list("bucket", "", "", "", 100)
+	if len(loi.Objects) != 1 || loi.Objects[0].Name != "a" || loi.Objects[0].Size != 2 {
+		t.Fatalf("Unexpected listing %v", loi.Objects)
+	}
+
+	b.pruneDeleted(now.Add(time.Second))
+	if len(b.deleted) != 0 {
+		t.Fatalf("Expected deleted objects to be pruned, got %v", b.deleted)
+	}
+}
+
+func TestXLSetsListIndex(t *testing.T) {
+	objLayer, fsDirs, err := prepareXLSets32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	s := objLayer.(*xlSets)
+
+	// Snapshots are written holding a cluster wide lock.
+	initNSLock(false)
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = s.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	putObjects := func(objects ...string) {
+		t.Helper()
+		for _, object := range objects {
+			data := []byte(object)
+			if hasSuffix(object, SlashSeparator) {
+				// Directory objects are empty.
+				data = nil
+			}
+			if _, err = s.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// Listings of the index of a server match listings of the drives.
+	checkListings := func(idx *xlListIndex) {
+		t.Helper()
+		index := idx.Get(ctx, bucket)
+		if index == nil {
+			t.Fatal("Expected the list index to be ready")
+		}
+		for _, args := range [][3]string{
+			{"", "", ""},
+			{"", "", SlashSeparator},
+			{"a/", "", SlashSeparator},
+			{"c/", "", ""},
+			{"", "a/1", ""},
+			{"", "a/3", SlashSeparator},
+		} {
+			for _, maxKeys := range []int{1, 2, 1000} {
+				expected, err := s.listObjects(ctx, bucket, args[0], args[1], args[2], maxKeys, false)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, err := s.listObjectsFromIndex(ctx, index, bucket, args[0], args[1], args[2], maxKeys)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(got.Objects) != len(expected.Objects) || !reflect.DeepEqual(got.Prefixes, expected.Prefixes) ||
+					got.IsTruncated != expected.IsTruncated || got.NextMarker != expected.NextMarker {
+					t.Fatalf("%v, %d: expected %v, got %v", args, maxKeys, expected, got)
+				}
+				for i := range got.Objects {
+					if got.Objects[i].Name != expected.Objects[i].Name || got.Objects[i].Size != expected.Objects[i].Size ||
+						got.Objects[i].ETag != expected.Objects[i].ETag {
+						t.Fatalf("%v, %d: expected %v, got %v", args, maxKeys, expected.Objects[i], got.Objects[i])
+					}
+				}
+			}
+		}
+	}
+
+	// Writes bypassing the index.
+	set := *s.getHashedSet("hidden")
+	set.listIndex = nil
+	putHidden := func() {
+		t.Helper()
+		if _, err = set.PutObject(ctx, bucket, "hidden", mustGetPutObjReader(t, bytes.NewReader([]byte("hidden")), 6, "", ""), ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	isListed := func(idx *xlListIndex, object string) bool {
+		return len(idx.Get(ctx, bucket).list(bucket, object, "", "", 1).Objects) == 1
+	}
+
+	// Objects written before the bucket is indexed are found
+	// by the rebuild.
+	putObjects("a/1", "a/2", "a/b/1", "b", "c/d/e/1")
+	config := listindex.Config{Enabled: true}
+	node1 := newXLListIndex(config, s)
+	s.setListIndex(node1)
+	node1.Init()
+	putObjects("a/3")
+	checkListings(node1)
+
+	// Another server loads the snapshot written by the rebuild
+	// and the journal of the first one, without listing the drives.
+	putHidden()
+	node2 := newXLListIndex(config, s)
+	node2.Init()
+	if isListed(node2, "hidden") || !isListed(node2, "a/3") {
+		t.Fatal("Expected the list index to be loaded from the snapshot and the journals")
+	}
+	if err = set.DeleteObject(ctx, bucket, "hidden"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Writes served by a server are listed by the other one.
+	putObjects("c/2", "dir/", "e", "f/1")
+	if _, err = s.DeleteObjects(ctx, bucket, []string{"a/2", "c/d/e/1"}); err != nil {
+		t.Fatal(err)
+	}
+	checkListings(node2)
+
+	s.setListIndex(node2)
+	if err = s.DeleteObject(ctx, bucket, "b"); err != nil {
+		t.Fatal(err)
+	}
+	putObjects("g")
+	if err = node1.buckets[bucket].sync(ctx, s, bucket, node1.journalID, true); err != nil {
+		t.Fatal(err)
+	}
+	checkListings(node1)
+
+	// Updates are journaled as they are made, a server which was
+	// not shut down cleanly does not lose them.
+	node3 := newXLListIndex(config, s)
+	node3.Init()
+	checkListings(node3)
+
+	// Snapshots remove the journal segments covered by the previous
+	// snapshot, a server which has not applied them yet reads the
+	// snapshot instead.
+	s.setListIndex(node1)
+	putObjects("h")
+	b := node1.buckets[bucket]
+	for i := 0; i < 2; i++ {
+		if err = b.compact(ctx, s, bucket, node1.journalID, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	journals, err := listListIndexSegments(ctx, s, bucket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journals) != 0 {
+		t.Fatalf("Expected the journals to be removed, got %v", journals)
+	}
+	putObjects("i")
+	if err = node2.buckets[bucket].sync(ctx, s, bucket, node2.journalID, true); err != nil {
+		t.Fatal(err)
+	}
+	checkListings(node2)
+
+	// The index of buckets no longer indexed is removed on start.
+	if err = clearListIndexes(ctx, s, config, []BucketInfo{{Name: bucket}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = readListIndexSnapshot(ctx, s, bucket); err != nil {
+		t.Fatal(err)
+	}
+	if err = clearListIndexes(ctx, s, listindex.Config{}, []BucketInfo{{Name: bucket}}); err != nil {
+		t.Fatal(err)
+	}
+	if objects, err := listListIndexObjects(ctx, s, bucket); err != nil || len(objects) != 0 {
+		t.Fatalf("Expected the list index to be removed, got %v, %v", objects, err)
+	}
+
+	// Deleted buckets are no longer indexed.
+	if _, err = s.DeleteObjects(ctx, bucket, []string{"a/1", "a/3", "a/b/1", "c/2", "dir/", "e", "f/1", "g", "h", "i"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.DeleteBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+	if s.listIndex.Get(ctx, bucket) != nil {
+		t.Fatal("Expected no list index for a deleted bucket")
+	}
+}
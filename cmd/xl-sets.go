@@ -65,6 +65,10 @@ type xlSets struct {
 	// Re-ordered list of disks per set.
 	xlDisks setsStorageAPI
 
+	// Drives per set excluded as slow or faulty, computed by the
+	// drive health monitor and protected by xlDisksMu.
+	excludedDisks setsStorageAPI
+
 	// List of endpoints provided on the command line.
 	endpoints EndpointList
 
@@ -79,6 +83,10 @@ type xlSets struct {
 
 	// Merge tree walk
 	pool *MergeWalkPool
+
+	// Metadata index of buckets listed without walking
+	// the drives, nil if disabled.
+	listIndex *xlListIndex
 }
 
 // isConnected - checks if the endpoint is connected or not.
@@ -164,7 +172,7 @@ func (s *xlSets) reInitDisks(refFormat *formatXLV3, storageDisks []StorageAPI, f
 			logger.LogIf(ctx, err)
 			continue
 		}
-		xlDisks[i][j] = storageDisks[k]
+		xlDisks[i][j] = newMonitoredDisk(storageDisks[k])
 	}
 	return xlDisks
 }
@@ -191,7 +199,7 @@ func (s *xlSets) connectDisksWithQuorum() {
 				printEndpointError(endpoint, err)
 				continue
 			}
-			s.xlDisks[i][j] = disk
+			s.xlDisks[i][j] = newMonitoredDisk(disk)
 			onlineDisks++
 		}
 		// Sleep for a while - so that we don't go into
@@ -220,7 +228,7 @@ func (s *xlSets) connectDisks() {
 			continue
 		}
 		s.xlDisksMu.Lock()
-		s.xlDisks[i][j] = disk
+		s.xlDisks[i][j] = newMonitoredDisk(disk)
 		s.xlDisksMu.Unlock()
 	}
 }
@@ -245,11 +253,59 @@ func (s *xlSets) monitorAndConnectEndpoints(monitorInterval time.Duration) {
 	}
 }
 
-// GetDisks returns a closure for a given set, which provides list of disks per set.
+// monitorDiskHealth - recomputes the slow or faulty drives of every set
+// at the given interval, drives are only excluded while their set
+// keeps write quorum.
+func (s *xlSets) monitorDiskHealth(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-GlobalServiceDoneCh:
+			return
+		case <-ticker.C:
+		}
+
+		for i := 0; i < s.setCount; i++ {
+			disks := s.GetAllDisks(i)()
+			excluded := make([]StorageAPI, len(disks))
+			for j, disk := range excludeUnhealthyDisks(append([]StorageAPI{}, disks...)) {
+				if disk == nil {
+					excluded[j] = disks[j]
+				}
+			}
+			s.xlDisksMu.Lock()
+			s.excludedDisks[i] = excluded
+			s.xlDisksMu.Unlock()
+		}
+	}
+}
+
+// GetDisks returns a closure for a given set, which provides list of disks per set,
+// slow or faulty disks which are temporarily excluded are returned as nil.
 func (s *xlSets) GetDisks(setIndex int) func() []StorageAPI {
 	return func() []StorageAPI {
-		s.xlDisksMu.Lock()
-		defer s.xlDisksMu.Unlock()
+		s.xlDisksMu.RLock()
+		defer s.xlDisksMu.RUnlock()
+		disks := make([]StorageAPI, s.drivesPerSet)
+		copy(disks, s.xlDisks[setIndex])
+		for i, disk := range s.excludedDisks[setIndex] {
+			// Reconnected drives are not excluded.
+			if disk != nil && disks[i] == disk {
+				disks[i] = nil
+			}
+		}
+		return disks
+	}
+}
+
+// GetAllDisks returns a closure for a given set, which provides list of disks
+// per set including the slow or faulty disks which are temporarily excluded.
+func (s *xlSets) GetAllDisks(setIndex int) func() []StorageAPI {
+	return func() []StorageAPI {
+		s.xlDisksMu.RLock()
+		defer s.xlDisksMu.RUnlock()
 		disks := make([]StorageAPI, s.drivesPerSet)
 		copy(disks, s.xlDisks[setIndex])
 		return disks
@@ -258,13 +314,23 @@ func (s *xlSets) GetDisks(setIndex int) func() []StorageAPI {
 
 const defaultMonitorConnectEndpointInterval = time.Second * 10 // Set to 10 secs.
 
+// setListIndex - sets the list index of all erasure sets, which
+// update it while holding the object lock.
+func (s *xlSets) setListIndex(idx *xlListIndex) {
+	s.listIndex = idx
+	for _, set := range s.sets {
+		set.listIndex = idx
+	}
+}
+
 // Initialize new set of erasure coded sets.
-func newXLSets(endpoints EndpointList, format *formatXLV3, setCount int, drivesPerSet int) (ObjectLayer, error) {
+func newXLSets(endpoints EndpointList, format *formatXLV3, setCount int, drivesPerSet int) (*xlSets, error) {
 
 	// Initialize the XL sets instance.
 	s := &xlSets{
 		sets:               make([]*xlObjects, setCount),
 		xlDisks:            make([][]StorageAPI, setCount),
+		excludedDisks:      make([][]StorageAPI, setCount),
 		endpoints:          endpoints,
 		setCount:           setCount,
 		drivesPerSet:       drivesPerSet,
@@ -285,9 +351,10 @@ func newXLSets(endpoints EndpointList, format *formatXLV3, setCount int, drivesP
 
 		// Initialize xl objects for a given set.
 		s.sets[i] = &xlObjects{
-			getDisks: s.GetDisks(i),
-			nsMutex:  mutex,
-			bp:       bp,
+			getDisks:     s.GetDisks(i),
+			getHealDisks: s.GetAllDisks(i),
+			nsMutex:      mutex,
+			bp:           bp,
 		}
 		go s.sets[i].cleanupStaleMultipartUploads(context.Background(), GlobalMultipartCleanupInterval, GlobalMultipartExpiry, GlobalServiceDoneCh)
 	}
@@ -298,6 +365,21 @@ func newXLSets(endpoints EndpointList, format *formatXLV3, setCount int, drivesP
 	// Start the disk monitoring and connect routine.
 	go s.monitorAndConnectEndpoints(defaultMonitorConnectEndpointInterval)
 
+	// Start excluding slow or faulty drives.
+	if globalDriveHealthConfig.Enabled {
+		go s.monitorDiskHealth(diskHealthCheckInterval)
+	}
+
+	// Load the list index in the background, buckets are listed
+	// from the drives until their index is ready.
+	if globalListIndexConfig.Enabled {
+		s.setListIndex(newXLListIndex(globalListIndexConfig, s))
+		go func() {
+			s.listIndex.Init()
+			s.listIndex.run()
+		}()
+	}
+
 	return s, nil
 }
 
@@ -392,12 +474,36 @@ func (s *xlSets) StorageInfo(ctx context.Context) StorageInfo {
 		}
 	}
 
+	// Add the health of online drives as seen by this server,
+	// online drives are placed by their UUID.
+	s.xlDisksMu.RLock()
+	defer s.xlDisksMu.RUnlock()
+	for i := range storageInfo.Backend.Sets {
+		for j := range storageInfo.Backend.Sets[i] {
+			drive := &storageInfo.Backend.Sets[i][j]
+			md, ok := s.xlDisks[i][j].(*monitoredDisk)
+			if !ok || drive.UUID == "" {
+				continue
+			}
+			var excluded bool
+			drive.Latency, drive.ErrorRate, excluded = md.Health()
+			if excluded {
+				drive.State = madmin.DriveStateExcluded
+			}
+		}
+	}
+
 	return storageInfo
 }
 
 // Shutdown shutsdown all erasure coded sets in parallel
 // returns error upon first error.
 func (s *xlSets) Shutdown(ctx context.Context) error {
+	// Write pending list index updates while the drives are connected.
+	if err := s.listIndex.Flush(ctx); err != nil {
+		logger.LogIf(ctx, err)
+	}
+
 	g := errgroup.WithNErrs(len(s.sets))
 
 	for index := range s.sets {
@@ -441,6 +547,8 @@ func (s *xlSets) MakeBucketWithLocation(ctx context.Context, bucket, location st
 		}
 	}
 
+	s.listIndex.MakeBucket(ctx, bucket)
+
 	// Success.
 	return nil
 }
@@ -497,7 +605,7 @@ func (s *xlSets) getHashedSet(input string) (set *xlObjects) {
 
 // GetBucketInfo - returns bucket info from one of the erasure coded set.
 func (s *xlSets) GetBucketInfo(ctx context.Context, bucket string) (bucketInfo BucketInfo, err error) {
-	return s.getHashedSet(bucket).GetBucketInfo(ctx, bucket)
+	return s.getHashedSet(bucket).withContext(ctx).GetBucketInfo(ctx, bucket)
 }
 
 // ListObjectsV2 lists all objects in bucket filtered by prefix
@@ -600,6 +708,7 @@ func (s *xlSets) DeleteBucket(ctx context.Context, bucket string) error {
 
 	// Delete all bucket metadata.
 	deleteBucketMetadata(ctx, bucket, s)
+	s.listIndex.Remove(ctx, bucket)
 
 	// Success.
 	return nil
@@ -634,27 +743,27 @@ func (s *xlSets) ListBuckets(ctx context.Context) (buckets []BucketInfo, err err
 
 // GetObjectNInfo - returns object info and locked object ReadCloser
 func (s *xlSets) GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error) {
-	return s.getHashedSet(object).GetObjectNInfo(ctx, bucket, object, rs, h, lockType, opts)
+	return s.getHashedSet(object).withContext(ctx).GetObjectNInfo(ctx, bucket, object, rs, h, lockType, opts)
 }
 
 // GetObject - reads an object from the hashedSet based on the object name.
 func (s *xlSets) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string, opts ObjectOptions) error {
-	return s.getHashedSet(object).GetObject(ctx, bucket, object, startOffset, length, writer, etag, opts)
+	return s.getHashedSet(object).withContext(ctx).GetObject(ctx, bucket, object, startOffset, length, writer, etag, opts)
 }
 
 // PutObject - writes an object to hashedSet based on the object name.
 func (s *xlSets) PutObject(ctx context.Context, bucket string, object string, data *PutObjReader, opts ObjectOptions) (objInfo ObjectInfo, err error) {
-	return s.getHashedSet(object).PutObject(ctx, bucket, object, data, opts)
+	return s.getHashedSet(object).withContext(ctx).PutObject(ctx, bucket, object, data, opts)
 }
 
 // GetObjectInfo - reads object metadata from the hashedSet based on the object name.
 func (s *xlSets) GetObjectInfo(ctx context.Context, bucket, object string, opts ObjectOptions) (objInfo ObjectInfo, err error) {
-	return s.getHashedSet(object).GetObjectInfo(ctx, bucket, object, opts)
+	return s.getHashedSet(object).withContext(ctx).GetObjectInfo(ctx, bucket, object, opts)
 }
 
 // DeleteObject - deletes an object from the hashedSet based on the object name.
 func (s *xlSets) DeleteObject(ctx context.Context, bucket string, object string) (err error) {
-	return s.getHashedSet(object).DeleteObject(ctx, bucket, object)
+	return s.getHashedSet(object).withContext(ctx).DeleteObject(ctx, bucket, object)
 }
 
 // DeleteObjects - bulk delete of objects
@@ -697,7 +806,7 @@ func (s *xlSets) DeleteObjects(ctx context.Context, bucket string, objects []str
 	// Invoke bulk delete on objects per set and save
 	// the result of the delete operation
 	for _, objsGroup := range objSetMap {
-		errs, err := s.getHashedSet(objsGroup[0].name).DeleteObjects(ctx, bucket, toNames(objsGroup))
+		errs, err := s.getHashedSet(objsGroup[0].name).withContext(ctx).DeleteObjects(ctx, bucket, toNames(objsGroup))
 		if err != nil {
 			return nil, err
 		}
@@ -711,8 +820,8 @@ func (s *xlSets) DeleteObjects(ctx context.Context, bucket string, objects []str
 
 // CopyObject - copies objects from one hashedSet to another hashedSet, on server side.
 func (s *xlSets) CopyObject(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, srcInfo ObjectInfo, srcOpts, dstOpts ObjectOptions) (objInfo ObjectInfo, err error) {
-	srcSet := s.getHashedSet(srcObject)
-	destSet := s.getHashedSet(destObject)
+	srcSet := s.getHashedSet(srcObject).withContext(ctx)
+	destSet := s.getHashedSet(destObject).withContext(ctx)
 
 	// Check if this request is only metadata update.
 	cpSrcDstSame := isStringEqual(pathJoin(srcBucket, srcObject), pathJoin(destBucket, destObject))
@@ -1189,6 +1298,9 @@ func (s *xlSets) listObjects(ctx context.Context, bucket, prefix, marker, delimi
 // walked and merged at this layer. Resulting value through the merge process sends
 // the data in lexically sorted order.
 func (s *xlSets) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (loi ListObjectsInfo, err error) {
+	if index := s.listIndex.Get(ctx, bucket); index != nil {
+		return s.listObjectsFromIndex(ctx, index, bucket, prefix, marker, delimiter, maxKeys)
+	}
 	return s.listObjects(ctx, bucket, prefix, marker, delimiter, maxKeys, false)
 }
 
@@ -1200,35 +1312,35 @@ func (s *xlSets) ListMultipartUploads(ctx context.Context, bucket, prefix, keyMa
 
 // Initiate a new multipart upload on a hashedSet based on object name.
 func (s *xlSets) NewMultipartUpload(ctx context.Context, bucket, object string, opts ObjectOptions) (uploadID string, err error) {
-	return s.getHashedSet(object).NewMultipartUpload(ctx, bucket, object, opts)
+	return s.getHashedSet(object).withContext(ctx).NewMultipartUpload(ctx, bucket, object, opts)
 }
 
 // Copies a part of an object from source hashedSet to destination hashedSet.
 func (s *xlSets) CopyObjectPart(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, uploadID string, partID int,
 	startOffset int64, length int64, srcInfo ObjectInfo, srcOpts, dstOpts ObjectOptions) (partInfo PartInfo, err error) {
-	destSet := s.getHashedSet(destObject)
+	destSet := s.getHashedSet(destObject).withContext(ctx)
 
 	return destSet.PutObjectPart(ctx, destBucket, destObject, uploadID, partID, NewPutObjReader(srcInfo.Reader, nil, nil), dstOpts)
 }
 
 // PutObjectPart - writes part of an object to hashedSet based on the object name.
 func (s *xlSets) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, data *PutObjReader, opts ObjectOptions) (info PartInfo, err error) {
-	return s.getHashedSet(object).PutObjectPart(ctx, bucket, object, uploadID, partID, data, opts)
+	return s.getHashedSet(object).withContext(ctx).PutObjectPart(ctx, bucket, object, uploadID, partID, data, opts)
 }
 
 // ListObjectParts - lists all uploaded parts to an object in hashedSet.
 func (s *xlSets) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker int, maxParts int, opts ObjectOptions) (result ListPartsInfo, err error) {
-	return s.getHashedSet(object).ListObjectParts(ctx, bucket, object, uploadID, partNumberMarker, maxParts, opts)
+	return s.getHashedSet(object).withContext(ctx).ListObjectParts(ctx, bucket, object, uploadID, partNumberMarker, maxParts, opts)
 }
 
 // Aborts an in-progress multipart operation on hashedSet based on the object name.
 func (s *xlSets) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) error {
-	return s.getHashedSet(object).AbortMultipartUpload(ctx, bucket, object, uploadID)
+	return s.getHashedSet(object).withContext(ctx).AbortMultipartUpload(ctx, bucket, object, uploadID)
 }
 
 // CompleteMultipartUpload - completes a pending multipart transaction, on hashedSet based on object name.
 func (s *xlSets) CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []CompletePart, opts ObjectOptions) (objInfo ObjectInfo, err error) {
-	return s.getHashedSet(object).CompleteMultipartUpload(ctx, bucket, object, uploadID, uploadedParts, opts)
+	return s.getHashedSet(object).withContext(ctx).CompleteMultipartUpload(ctx, bucket, object, uploadID, uploadedParts, opts)
 }
 
 /*
@@ -1672,7 +1784,7 @@ func (s *xlSets) HealBucket(ctx context.Context, bucket string, dryRun, remove b
 
 // HealObject - heals inconsistent object on a hashedSet based on object name.
 func (s *xlSets) HealObject(ctx context.Context, bucket, object string, dryRun, remove bool, scanMode madmin.HealScanMode) (madmin.HealResultItem, error) {
-	return s.getHashedSet(object).HealObject(ctx, bucket, object, dryRun, remove, scanMode)
+	return s.getHashedSet(object).withContext(ctx).HealObject(ctx, bucket, object, dryRun, remove, scanMode)
 }
 
 // Lists all buckets which need healing.
@@ -1680,7 +1792,7 @@ func (s *xlSets) ListBucketsHeal(ctx context.Context) ([]BucketInfo, error) {
 	listBuckets := []BucketInfo{}
 	var healBuckets = map[string]BucketInfo{}
 	for _, set := range s.sets {
-		buckets, _, err := listAllBuckets(set.getDisks())
+		buckets, _, err := listAllBuckets(set.healDisks())
 		if err != nil {
 			return nil, err
 		}
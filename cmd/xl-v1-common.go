@@ -53,10 +53,18 @@ func (xl xlObjects) parentDirIsObject(ctx context.Context, bucket, parent string
 // isObject - returns `true` if the prefix is an object i.e if
 // `xl.json` exists at the leaf, false otherwise.
 func (xl xlObjects) isObject(bucket, prefix string) (ok bool) {
+	ok, _ = xl.isObjectPresent(context.Background(), bucket, prefix)
+	return ok
+}
+
+// isObjectPresent - same as isObject, but returns an error if the
+// drives do not agree whether the object exists.
+func (xl xlObjects) isObjectPresent(ctx context.Context, bucket, prefix string) (bool, error) {
 	var errs = make([]error, len(xl.getDisks()))
 	var wg sync.WaitGroup
 	for index, disk := range xl.getDisks() {
 		if disk == nil {
+			errs[index] = errDiskNotFound
 			continue
 		}
 		wg.Add(1)
@@ -83,5 +91,12 @@ func (xl xlObjects) isObject(bucket, prefix string) (ok bool) {
 	// ignored if necessary.
 	readQuorum := len(xl.getDisks()) / 2
 
-	return reduceReadQuorumErrs(context.Background(), errs, objectOpIgnoredErrs, readQuorum) == nil
+	switch err := reduceReadQuorumErrs(ctx, errs, objectOpIgnoredErrs, readQuorum); err {
+	case nil:
+		return true, nil
+	case errFileNotFound, errVolumeNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
 }
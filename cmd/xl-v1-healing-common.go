@@ -122,7 +122,7 @@ func listOnlineDisks(disks []StorageAPI, partsMetadata []xlMetaV1, errs []error)
 func getLatestXLMeta(ctx context.Context, partsMetadata []xlMetaV1, errs []error) (xlMetaV1, error) {
 
 	// There should be atleast half correct entries, if not return failure
-	if reducedErr := reduceReadQuorumErrs(ctx, errs, objectOpIgnoredErrs, globalXLSetDriveCount/2); reducedErr != nil {
+	if reducedErr := reduceReadQuorumErrs(ctx, errs, objectOpIgnoredErrs, len(errs)/2); reducedErr != nil {
 		return xlMetaV1{}, reducedErr
 	}
 
@@ -182,6 +182,14 @@ func disksWithAllParts(ctx context.Context, onlineDisks []StorageAPI, partsMetad
 			// it needs healing too.
 			for _, part := range partsMetadata[i].Parts {
 				checksumInfo := erasureInfo.GetChecksumInfo(part.Name)
+				if partsMetadata[i].isInline() {
+					// Data stored in `xl.json` is verified in memory.
+					if err = verifyInlineBitrot(partsMetadata[i].Data, erasure.ShardFileSize(part.Size), checksumInfo.Algorithm, erasure.ShardSize()); err != nil {
+						dataErrs[i] = err
+						break
+					}
+					continue
+				}
 				err = onlineDisk.VerifyFile(bucket, pathJoin(object, part.Name), erasure.ShardFileSize(part.Size), checksumInfo.Algorithm, checksumInfo.Hash, erasure.ShardSize())
 				if err != nil {
 					if !IsErr(err, []error{
@@ -197,6 +205,10 @@ func disksWithAllParts(ctx context.Context, onlineDisks []StorageAPI, partsMetad
 				}
 			}
 		case madmin.HealNormalScan:
+			if partsMetadata[i].isInline() {
+				// Data is available along with `xl.json`.
+				break
+			}
 			for _, part := range partsMetadata[i].Parts {
 				_, err := onlineDisk.StatFile(bucket, pathJoin(object, part.Name))
 				if err != nil {
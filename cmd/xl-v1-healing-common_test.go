@@ -167,7 +167,8 @@ func TestListOnlineDisks(t *testing.T) {
 
 	bucket := "bucket"
 	object := "object"
-	data := bytes.Repeat([]byte("a"), 1024)
+	// Larger than smallFileThreshold so that the data is stored in part files.
+	data := bytes.Repeat([]byte("a"), smallFileThreshold+1)
 	xlDisks := obj.(*xlObjects).storageDisks
 	for i, test := range testCases {
 		// Prepare bucket/object backend for the tests below.
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/madmin"
 )
 
@@ -43,7 +44,7 @@ func (xl xlObjects) HealFormat(ctx context.Context, dryRun bool) (madmin.HealRes
 func (xl xlObjects) HealBucket(ctx context.Context, bucket string, dryRun, remove bool) (
 	result madmin.HealResultItem, err error) {
 
-	storageDisks := xl.getDisks()
+	storageDisks := xl.healDisks()
 
 	// get write quorum for an object
 	writeQuorum := len(storageDisks)/2 + 1
@@ -212,7 +213,7 @@ func (xl xlObjects) healObject(ctx context.Context, bucket string, object string
 
 	dataBlocks := latestXLMeta.Erasure.DataBlocks
 
-	storageDisks := xl.getDisks()
+	storageDisks := xl.healDisks()
 
 	// List of disks having latest version of the object xl.json
 	// (by modtime).
@@ -312,7 +313,11 @@ func (xl xlObjects) healObject(ctx context.Context, bucket string, object string
 	}
 
 	if disksToHealCount == 0 {
-		// Nothing to heal!
+		// Nothing to heal, migrate legacy JSON `xl.json` to the
+		// binary format while at it once it is enabled.
+		if !dryRun && globalXLMetaConfig.Binary {
+			migrateLegacyXLMeta(ctx, availableDisks, partsMetadata, bucket, object)
+		}
 		return result, nil
 	}
 
@@ -387,6 +392,10 @@ func (xl xlObjects) healObject(ctx context.Context, bucket string, object string
 				continue
 			}
 			checksumInfo := partsMetadata[i].Erasure.GetChecksumInfo(partName)
+			if partsMetadata[i].isInline() {
+				readers[i] = newInlineBitrotReader(partsMetadata[i].Data, tillOffset, checksumAlgo, erasure.ShardSize())
+				continue
+			}
 			readers[i] = newBitrotReader(disk, bucket, pathJoin(object, partName), tillOffset, checksumAlgo, checksumInfo.Hash, erasure.ShardSize())
 		}
 		writers := make([]io.Writer, len(outDatedDisks))
@@ -394,6 +403,10 @@ func (xl xlObjects) healObject(ctx context.Context, bucket string, object string
 			if disk == OfflineDisk {
 				continue
 			}
+			if latestMeta.isInline() {
+				writers[i] = newInlineBitrotWriter(checksumAlgo)
+				continue
+			}
 			writers[i] = newBitrotWriter(disk, minioMetaTmpBucket, pathJoin(tmpID, partName), tillOffset, checksumAlgo, erasure.ShardSize())
 		}
 		hErr := erasure.Heal(ctx, readers, writers, partSize)
@@ -417,6 +430,9 @@ func (xl xlObjects) healObject(ctx context.Context, bucket string, object string
 			}
 			partsMetadata[i].AddObjectPart(partNumber, partName, "", partSize, partActualSize)
 			partsMetadata[i].Erasure.AddChecksumInfo(ChecksumInfo{partName, checksumAlgo, bitrotWriterSum(writers[i])})
+			if w, ok := writers[i].(*inlineBitrotWriter); ok {
+				partsMetadata[i].Data = w.Bytes()
+			}
 		}
 
 		// If all disks are having errors, we give up.
@@ -463,10 +479,30 @@ func (xl xlObjects) healObject(ctx context.Context, bucket string, object string
 	return result, nil
 }
 
+// Rewrites legacy JSON `xl.json` of up to date disks in the binary
+// format, failures are logged and retried by the next heal or
+// migration.
+func migrateLegacyXLMeta(ctx context.Context, disks []StorageAPI, partsMetadata []xlMetaV1, bucket, object string) {
+	for i, disk := range disks {
+		if disk == nil || !partsMetadata[i].legacy {
+			continue
+		}
+		tmpID := mustGetUUID()
+		if err := writeXLMetadata(ctx, disk, minioMetaTmpBucket, tmpID, partsMetadata[i]); err != nil {
+			disk.DeleteFile(minioMetaTmpBucket, pathJoin(tmpID, xlMetaJSONFile))
+			continue
+		}
+		err := disk.RenameFile(minioMetaTmpBucket, pathJoin(tmpID, xlMetaJSONFile), bucket, pathJoin(object, xlMetaJSONFile))
+		logger.LogIf(ctx, err)
+		// Remove the temporary directory.
+		disk.DeleteFile(minioMetaTmpBucket, tmpID)
+	}
+}
+
 // healObjectDir - heals object directory specifically, this special call
 // is needed since we do not have a special backend format for directories.
 func (xl xlObjects) healObjectDir(ctx context.Context, bucket, object string, dryRun bool) (hr madmin.HealResultItem, err error) {
-	storageDisks := xl.getDisks()
+	storageDisks := xl.healDisks()
 
 	// Initialize heal result object
 	hr = madmin.HealResultItem{
@@ -698,7 +734,7 @@ func (xl xlObjects) HealObject(ctx context.Context, bucket, object string, dryRu
 		return xl.healObjectDir(healCtx, bucket, object, dryRun)
 	}
 
-	storageDisks := xl.getDisks()
+	storageDisks := xl.healDisks()
 
 	// Read metadata files from all the disks
 	partsMetadata, errs := readAllXLMetadata(healCtx, storageDisks, bucket, object)
@@ -708,7 +744,7 @@ func (xl xlObjects) HealObject(ctx context.Context, bucket, object string, dryRu
 	if m, ok := isObjectDangling(partsMetadata, errs, []error{}); ok {
 		writeQuorum := m.Erasure.DataBlocks + 1
 		if m.Erasure.DataBlocks == 0 {
-			writeQuorum = len(xl.getDisks())/2 + 1
+			writeQuorum = len(xl.healDisks())/2 + 1
 		}
 		if !dryRun && remove {
 			err = xl.deleteObject(healCtx, bucket, object, writeQuorum, false)
@@ -756,3 +792,36 @@ func (xl xlObjects) HealObject(ctx context.Context, bucket, object string, dryRu
 	// Heal the object.
 	return xl.healObject(healCtx, bucket, object, partsMetadata, errs, latestXLMeta, dryRun, remove, scanMode)
 }
+
+// sendHealEvent - notifies an object healed event if drives of the
+// object were repaired or if healing failed. Events are sent for heals
+// finding damaged objects, i.e. by the daily sweep, the MRF queue, admin
+// heal sequences and the bitrot scrubber. Healing replaced drives repairs
+// every object of the erasure set, its progress is reported by the drive
+// heal status instead.
+func sendHealEvent(bucket, object string, hr madmin.HealResultItem, err error) {
+	// Internal objects and directories are no events of any bucket.
+	if isMinioMetaBucketName(bucket) || hasSuffix(object, SlashSeparator) {
+		return
+	}
+
+	var eventName event.Name
+	if err != nil {
+		// Nothing to heal.
+		if isErrObjectNotFound(err) {
+			return
+		}
+		eventName = event.ObjectHealedFailed
+	} else {
+		if before, after := hr.GetOnlineCounts(); after <= before {
+			return
+		}
+		eventName = event.ObjectHealedRepaired
+	}
+	sendEvent(eventArgs{
+		EventName:  eventName,
+		BucketName: bucket,
+		Object:     ObjectInfo{Bucket: bucket, Name: object, Size: hr.ObjectSize},
+		UserAgent:  "Internal: [Heal]",
+	})
+}
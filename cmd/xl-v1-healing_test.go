@@ -22,6 +22,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/madmin"
 )
 
@@ -352,3 +353,54 @@ func TestHealEmptyDirectoryXL(t *testing.T) {
 		}
 	}
 }
+
+func TestSendHealEvent(t *testing.T) {
+	notificationSys := globalNotificationSys
+	defer func() { globalNotificationSys = notificationSys }()
+
+	// Keep the events published to the stream.
+	stream := newEventStream("", listenHistorySize)
+	stream.lastFollowed = UTCNow().UnixNano()
+	globalNotificationSys = &NotificationSys{eventStream: stream}
+
+	repaired := madmin.HealResultItem{
+		Before: struct {
+			Drives []madmin.HealDriveInfo `json:"drives"`
+		}{Drives: []madmin.HealDriveInfo{{State: madmin.DriveStateOk}, {State: madmin.DriveStateMissing}}},
+		After: struct {
+			Drives []madmin.HealDriveInfo `json:"drives"`
+		}{Drives: []madmin.HealDriveInfo{{State: madmin.DriveStateOk}, {State: madmin.DriveStateOk}}},
+	}
+
+	testCases := []struct {
+		bucket, object string
+		hr             madmin.HealResultItem
+		err            error
+		notified       bool
+		eventName      event.Name
+	}{
+		{"bucket", "object", repaired, nil, true, event.ObjectHealedRepaired},
+		{"bucket", "object", madmin.HealResultItem{}, errFaultyDisk, true, event.ObjectHealedFailed},
+		// Nothing healed.
+		{"bucket", "object", madmin.HealResultItem{}, nil, false, 0},
+		{"bucket", "object", madmin.HealResultItem{}, ObjectNotFound{}, false, 0},
+		// Internal objects and directories.
+		{minioMetaBucket, "config/config.json", repaired, nil, false, 0},
+		{"bucket", "dir/", repaired, nil, false, 0},
+	}
+
+	for i, testCase := range testCases {
+		pos := stream.head()
+		sendHealEvent(testCase.bucket, testCase.object, testCase.hr, testCase.err)
+		events := stream.since(pos, 1)
+		if !testCase.notified {
+			if len(events) != 0 {
+				t.Fatalf("Test %d: expected no event, got %v", i+1, events[0].Event.EventName)
+			}
+			continue
+		}
+		if len(events) != 1 || events[0].Event.EventName != testCase.eventName {
+			t.Fatalf("Test %d: expected %v event, got %v", i+1, testCase.eventName, events)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+)
+
+// Binary `xl.json` format, the file name is kept so that listing and
+// existing objects work unchanged, readers tell the formats apart by
+// the magic at the start of the file.
+//
+//	magic "XLB" | format version (1 byte) | number of versions (uvarint)
+//
+// followed by the object versions, latest first. Every version
+// consists of three sections prefixed with their length, readers
+// only decode the sections they need and skip the remaining ones.
+//
+//	stat section    - xl.json version, format, release, stat, data
+//	                  and parity blocks and user metadata
+//	erasure section - erasure info, bitrot checksums and parts
+//	data section    - inline data of small objects
+//
+// All integers are varint encoded, strings and byte slices are
+// prefixed with their length.
+var xlMetaBinaryMagic = []byte("XLB")
+
+const xlMetaBinaryVersion = 1
+
+// xlMetaSection - sections of the binary metadata to decode, every
+// section includes the previous ones.
+type xlMetaSection int
+
+const (
+	// Stat, erasure data and parity blocks and user metadata.
+	xlMetaStatSection xlMetaSection = iota + 1
+	// Additionally erasure info, bitrot checksums and parts.
+	xlMetaErasureSection
+	// Additionally inline data.
+	xlMetaDataSection
+)
+
+// isXLMetaBinary - returns true if the metadata is in binary format.
+func isXLMetaBinary(buf []byte) bool {
+	return bytes.HasPrefix(buf, xlMetaBinaryMagic)
+}
+
+// xlMetaEncoder - appends varint encoded values to a buffer.
+type xlMetaEncoder struct {
+	buf []byte
+}
+
+func (e *xlMetaEncoder) uvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, tmp[:binary.PutUvarint(tmp[:], v)]...)
+}
+
+func (e *xlMetaEncoder) varint(v int64) {
+	var tmp [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, tmp[:binary.PutVarint(tmp[:], v)]...)
+}
+
+func (e *xlMetaEncoder) bytes(b []byte) {
+	e.uvarint(uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *xlMetaEncoder) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *xlMetaEncoder) time(t time.Time) {
+	e.varint(t.Unix())
+	e.uvarint(uint64(t.Nanosecond()))
+}
+
+// xlMetaDecoder - reads varint encoded values, the first error is
+// kept and all following reads return zero values.
+type xlMetaDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *xlMetaDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errCorruptedFormat
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *xlMetaDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = errCorruptedFormat
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+// Returns the next length prefixed byte slice, the slice refers to
+// the decoded buffer.
+func (d *xlMetaDecoder) bytes() []byte {
+	n := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.buf)) {
+		d.err = errCorruptedFormat
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *xlMetaDecoder) string() string {
+	return string(d.bytes())
+}
+
+func (d *xlMetaDecoder) time() time.Time {
+	sec := d.varint()
+	nsec := d.uvarint()
+	if d.err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, int64(nsec)).UTC()
+}
+
+// Returns the number of elements of a list which has at least one
+// byte per element, protects against allocations of bogus sizes.
+func (d *xlMetaDecoder) count() int {
+	n := d.uvarint()
+	if n > uint64(len(d.buf)) {
+		d.err = errCorruptedFormat
+		return 0
+	}
+	return int(n)
+}
+
+// xlMetaV1MarshalBinary - encodes the metadata in binary format.
+func xlMetaV1MarshalBinary(m xlMetaV1) []byte {
+	var stat, erasure xlMetaEncoder
+
+	stat.string(m.Version)
+	stat.string(m.Format)
+	stat.string(m.Minio.Release)
+	stat.varint(m.Stat.Size)
+	stat.time(m.Stat.ModTime)
+	stat.uvarint(uint64(m.Erasure.DataBlocks))
+	stat.uvarint(uint64(m.Erasure.ParityBlocks))
+	stat.uvarint(uint64(len(m.Meta)))
+	for k, v := range m.Meta {
+		stat.string(k)
+		stat.string(v)
+	}
+
+	erasure.string(m.Erasure.Algorithm)
+	erasure.varint(m.Erasure.BlockSize)
+	erasure.uvarint(uint64(m.Erasure.Index))
+	erasure.uvarint(uint64(len(m.Erasure.Distribution)))
+	for _, index := range m.Erasure.Distribution {
+		erasure.uvarint(uint64(index))
+	}
+	erasure.uvarint(uint64(len(m.Erasure.Checksums)))
+	for _, sum := range m.Erasure.Checksums {
+		erasure.string(sum.Name)
+		erasure.string(sum.Algorithm.String())
+		erasure.bytes(sum.Hash)
+	}
+	erasure.uvarint(uint64(len(m.Parts)))
+	for _, part := range m.Parts {
+		erasure.uvarint(uint64(part.Number))
+		erasure.string(part.Name)
+		erasure.string(part.ETag)
+		erasure.varint(part.Size)
+		erasure.varint(part.ActualSize)
+	}
+
+	e := xlMetaEncoder{
+		buf: make([]byte, 0, len(stat.buf)+len(erasure.buf)+len(m.Data)+32),
+	}
+	e.buf = append(e.buf, xlMetaBinaryMagic...)
+	e.buf = append(e.buf, xlMetaBinaryVersion)
+	// Only the latest version is stored for now.
+	e.uvarint(1)
+	e.bytes(stat.buf)
+	e.bytes(erasure.buf)
+	e.bytes(m.Data)
+	return e.buf
+}
+
+// xlMetaV1UnmarshalBinary - decodes the latest version of the binary
+// metadata up to the given section.
+func xlMetaV1UnmarshalBinary(buf []byte, section xlMetaSection) (m xlMetaV1, err error) {
+	if !isXLMetaBinary(buf) || len(buf) < len(xlMetaBinaryMagic)+1 {
+		return m, errCorruptedFormat
+	}
+	if buf[len(xlMetaBinaryMagic)] != xlMetaBinaryVersion {
+		return m, errCorruptedFormat
+	}
+	d := &xlMetaDecoder{buf: buf[len(xlMetaBinaryMagic)+1:]}
+	if d.uvarint() == 0 && d.err == nil {
+		// No versions, as good as a corrupted file.
+		return m, errCorruptedFormat
+	}
+
+	stat := &xlMetaDecoder{buf: d.bytes()}
+	m.Version = stat.string()
+	m.Format = stat.string()
+	m.Minio.Release = stat.string()
+	m.Stat.Size = stat.varint()
+	m.Stat.ModTime = stat.time()
+	m.Erasure.DataBlocks = int(stat.uvarint())
+	m.Erasure.ParityBlocks = int(stat.uvarint())
+	if n := stat.count(); n > 0 {
+		m.Meta = make(map[string]string, n)
+		for i := 0; i < n; i++ {
+			k := stat.string()
+			m.Meta[k] = stat.string()
+		}
+	}
+	if d.err != nil || stat.err != nil {
+		return xlMetaV1{}, errCorruptedFormat
+	}
+	if section == xlMetaStatSection {
+		return m, nil
+	}
+
+	erasure := &xlMetaDecoder{buf: d.bytes()}
+	m.Erasure.Algorithm = erasure.string()
+	m.Erasure.BlockSize = erasure.varint()
+	m.Erasure.Index = int(erasure.uvarint())
+	if n := erasure.count(); n > 0 {
+		m.Erasure.Distribution = make([]int, n)
+		for i := range m.Erasure.Distribution {
+			m.Erasure.Distribution[i] = int(erasure.uvarint())
+		}
+	}
+	if n := erasure.count(); n > 0 {
+		m.Erasure.Checksums = make([]ChecksumInfo, n)
+		for i := range m.Erasure.Checksums {
+			m.Erasure.Checksums[i].Name = erasure.string()
+			m.Erasure.Checksums[i].Algorithm = BitrotAlgorithmFromString(erasure.string())
+			m.Erasure.Checksums[i].Hash = append([]byte(nil), erasure.bytes()...)
+			if erasure.err == nil && !m.Erasure.Checksums[i].Algorithm.Available() {
+				return xlMetaV1{}, errBitrotHashAlgoInvalid
+			}
+		}
+	}
+	if n := erasure.count(); n > 0 {
+		m.Parts = make([]ObjectPartInfo, n)
+		for i := range m.Parts {
+			m.Parts[i].Number = int(erasure.uvarint())
+			m.Parts[i].Name = erasure.string()
+			m.Parts[i].ETag = erasure.string()
+			m.Parts[i].Size = erasure.varint()
+			m.Parts[i].ActualSize = erasure.varint()
+		}
+	}
+	if d.err != nil || erasure.err != nil {
+		return xlMetaV1{}, errCorruptedFormat
+	}
+	if section == xlMetaErasureSection {
+		return m, nil
+	}
+
+	if data := d.bytes(); len(data) > 0 {
+		m.Data = append([]byte(nil), data...)
+	}
+	if d.err != nil {
+		return xlMetaV1{}, errCorruptedFormat
+	}
+	return m, nil
+}
@@ -0,0 +1,250 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/minio/minio/pkg/madmin"
+)
+
+func TestXLMetaV1Binary(t *testing.T) {
+	xlMeta := getSampleXLMeta(10)
+	xlMeta.Data = []byte("inline data")
+
+	buf := xlMetaV1MarshalBinary(xlMeta)
+	if !isXLMetaBinary(buf) {
+		t.Fatal("Expected binary metadata")
+	}
+
+	m, err := xlMetaV1UnmarshalBinary(buf, xlMetaDataSection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareXLMetaV1(t, xlMeta, m)
+	if !bytes.Equal(m.Data, xlMeta.Data) || m.legacy {
+		t.Fatalf("Unexpected inline data %q", m.Data)
+	}
+
+	// Partial decoding skips the remaining sections.
+	if m, err = xlMetaV1UnmarshalBinary(buf, xlMetaStatSection); err != nil {
+		t.Fatal(err)
+	}
+	if m.Stat.Size != xlMeta.Stat.Size || m.Meta["testKey1"] != "val1" || m.Erasure.DataBlocks != xlMeta.Erasure.DataBlocks {
+		t.Fatalf("Unexpected stat section %v", m)
+	}
+	if m.Parts != nil || m.Erasure.Checksums != nil || m.Data != nil {
+		t.Fatal("Expected only the stat section to be decoded")
+	}
+	if m, err = xlMetaV1UnmarshalBinary(buf, xlMetaErasureSection); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Parts) != 10 || m.Data != nil {
+		t.Fatal("Expected only the stat and erasure sections to be decoded")
+	}
+
+	// Truncated metadata is never decoded.
+	for i := 0; i < len(buf); i++ {
+		if _, err = xlMetaV1UnmarshalBinary(buf[:i], xlMetaDataSection); err != errCorruptedFormat {
+			t.Fatalf("Expected %v for %d bytes, got %v", errCorruptedFormat, i, err)
+		}
+	}
+}
+
+func TestXLMetaV1UnmarshalLegacy(t *testing.T) {
+	xlMeta := getSampleXLMeta(1)
+	buf, err := json.Marshal(xlMeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := xlMetaV1Unmarshal(context.Background(), buf, xlMetaStatSection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareXLMetaV1(t, xlMeta, m)
+	if !m.legacy {
+		t.Fatal("Expected metadata to be read from legacy format")
+	}
+}
+
+func TestHealMigratesLegacyXLMeta(t *testing.T) {
+	obj, fsDirs, err := prepareXL16()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	ctx := context.Background()
+	bucket := "bucket"
+	object := "object"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello")
+	if _, err = obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Metadata is written as JSON until binary metadata is enabled,
+	// heals do not migrate it either.
+	checkBinary := func(binary bool) {
+		t.Helper()
+		for i, disk := range xl.storageDisks {
+			raw, rerr := disk.ReadAll(bucket, path.Join(object, xlMetaJSONFile))
+			if rerr != nil {
+				t.Fatal(rerr)
+			}
+			if isXLMetaBinary(raw) != binary {
+				t.Fatalf("Expected binary metadata %v on disk %d", binary, i)
+			}
+		}
+	}
+	checkBinary(false)
+	if _, err = obj.HealObject(ctx, bucket, object, false, false, madmin.HealNormalScan); err != nil {
+		t.Fatal(err)
+	}
+	checkBinary(false)
+
+	// Legacy metadata is read transparently.
+	var buf bytes.Buffer
+	if err = obj.GetObject(ctx, bucket, object, 0, int64(len(data)), &buf, "", ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("Unexpected object content")
+	}
+
+	globalXLMetaConfig.Binary = true
+	defer func() { globalXLMetaConfig.Binary = false }()
+	if _, err = obj.HealObject(ctx, bucket, object, false, false, madmin.HealNormalScan); err != nil {
+		t.Fatal(err)
+	}
+	checkBinary(true)
+
+	buf.Reset()
+	if err = obj.GetObject(ctx, bucket, object, 0, int64(len(data)), &buf, "", ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Fatal("Unexpected object content after migration")
+	}
+}
+
+func TestMigrateXLMeta(t *testing.T) {
+	objLayer, fsDirs, err := prepareXLSets32()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+
+	// The migration holds a cluster wide lock.
+	initNSLock(false)
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = objLayer.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("hello")
+	var objects []string
+	for i := 0; i < 10; i++ {
+		object := fmt.Sprintf("object%d", i)
+		if _, err = objLayer.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, object)
+	}
+
+	globalXLMetaConfig.Binary = true
+	defer func() { globalXLMetaConfig.Binary = false }()
+	done, err := migrateXLMeta(ctx, objLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done {
+		t.Fatal("Expected the migration to complete")
+	}
+
+	s := objLayer.(*xlSets)
+	for _, object := range objects {
+		for i, disk := range s.getHashedSet(object).getDisks() {
+			raw, rerr := disk.ReadAll(bucket, path.Join(object, xlMetaJSONFile))
+			if rerr != nil {
+				t.Fatal(rerr)
+			}
+			if !isXLMetaBinary(raw) {
+				t.Fatalf("Expected metadata of %s on disk %d to be migrated", object, i)
+			}
+		}
+		var buf bytes.Buffer
+		if err = objLayer.GetObject(ctx, bucket, object, 0, int64(len(data)), &buf, "", ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatalf("Unexpected content of %s after migration", object)
+		}
+	}
+
+	// A completed migration is not repeated.
+	state, err := readXLMetaMigrationConfig(ctx, objLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Finished.IsZero() {
+		t.Fatalf("Unexpected migration state %v", state)
+	}
+}
+
+func BenchmarkXLMetaV1UnmarshalJSON(b *testing.B) {
+	buf := getXLMetaBytes(10)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := xlMetaV1UnmarshalJSON(context.Background(), buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkXLMetaV1UnmarshalBinary(b *testing.B) {
+	buf := xlMetaV1MarshalBinary(getSampleXLMeta(10))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := xlMetaV1UnmarshalBinary(buf, xlMetaErasureSection); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkXLMetaV1UnmarshalBinaryStat(b *testing.B) {
+	buf := xlMetaV1MarshalBinary(getSampleXLMeta(10))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := xlMetaV1UnmarshalBinary(buf, xlMetaStatSection); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
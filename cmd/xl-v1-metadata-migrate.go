@@ -0,0 +1,245 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+)
+
+const (
+	// Metadata migration progress file.
+	xlMetaMigrationConfigFile = "xlmeta-migration.json"
+
+	// Lock of the meta bucket held by the migrating server.
+	xlMetaMigrationLockPath = "xlmeta-migration.lock"
+
+	// Number of migrated objects after which the progress is saved.
+	xlMetaMigrationSaveInterval = 100
+)
+
+// Only these prefixes of the meta bucket hold objects which are
+// migrated, multipart and temporary data is short lived.
+var xlMetaMigrationMetaPrefixes = []string{bucketConfigPrefix + SlashSeparator, minioConfigPrefix + SlashSeparator}
+
+var xlMetaMigrationTimeout = newDynamicTimeout(60*time.Second, time.Second)
+
+// xlMetaMigrationState - on disk format of the metadata migration
+// progress, the migration resumes from Zone, Bucket and Marker.
+type xlMetaMigrationState struct {
+	Version  int       `json:"version"`
+	Finished time.Time `json:"finished,omitempty"`
+	Zone     int       `json:"zone"`
+	Bucket   string    `json:"bucket,omitempty"`
+	Marker   string    `json:"marker,omitempty"`
+}
+
+// initBackgroundXLMetaMigration creates a go-routine which rewrites
+// the legacy JSON `xl.json` of all objects in the binary format.
+func initBackgroundXLMetaMigration() {
+	go backgroundXLMetaMigration()
+}
+
+func backgroundXLMetaMigration() {
+	var objAPI ObjectLayer
+	ctx := context.Background()
+
+	// Wait until the object layer is ready
+	for {
+		objAPI = newObjectLayerFn()
+		if objAPI == nil {
+			time.Sleep(time.Second)
+			continue
+		}
+		break
+	}
+
+	for {
+		done, err := migrateXLMeta(ctx, objAPI)
+		if err != nil {
+			logger.LogIf(ctx, err)
+		}
+		if done {
+			return
+		}
+		select {
+		case <-GlobalServiceDoneCh:
+			return
+		case <-time.After(time.Minute):
+		}
+	}
+}
+
+// migrateXLMeta - migrates the metadata of all objects not migrated
+// yet, returns true once all objects of the cluster are migrated.
+func migrateXLMeta(ctx context.Context, objAPI ObjectLayer) (bool, error) {
+	// Only one server migrates the cluster.
+	migrationLock := globalNSMutex.NewNSLock(ctx, minioMetaBucket, xlMetaMigrationLockPath)
+	if err := migrationLock.GetLock(xlMetaMigrationTimeout); err != nil {
+		return false, nil
+	}
+	defer migrationLock.Unlock()
+
+	state, err := readXLMetaMigrationConfig(ctx, objAPI)
+	if err != nil {
+		return false, err
+	}
+	if !state.Finished.IsZero() {
+		return true, nil
+	}
+	state.Version = 1
+
+	var migrated int
+	zones := getZoneSets(objAPI)
+	for zoneIdx := state.Zone; zoneIdx < len(zones); zoneIdx++ {
+		s := zones[zoneIdx]
+		if zoneIdx != state.Zone {
+			state.Zone, state.Bucket, state.Marker = zoneIdx, "", ""
+		}
+
+		bucketsInfo, err := s.ListBuckets(ctx)
+		if err != nil {
+			return false, err
+		}
+		// The meta bucket sorts before all buckets.
+		buckets := []string{minioMetaBucket}
+		for _, bucket := range bucketsInfo {
+			buckets = append(buckets, bucket.Name)
+		}
+		sort.Strings(buckets[1:])
+
+		for _, bucket := range buckets {
+			if bucket < state.Bucket {
+				// Already migrated.
+				continue
+			}
+			if bucket != state.Bucket {
+				state.Bucket, state.Marker = bucket, ""
+			}
+			prefixes := []string{""}
+			if bucket == minioMetaBucket {
+				prefixes = xlMetaMigrationMetaPrefixes
+			}
+			for _, prefix := range prefixes {
+				marker := state.Marker
+				if marker != "" && !strings.HasPrefix(marker, prefix) {
+					if marker > prefix {
+						// Prefix is already migrated.
+						continue
+					}
+					marker = ""
+				}
+				for {
+					loi, err := s.ListObjects(ctx, bucket, prefix, marker, "", maxObjectList)
+					if err != nil {
+						if _, ok := err.(BucketNotFound); ok {
+							// Bucket was removed meanwhile.
+							break
+						}
+						return false, err
+					}
+					for _, obj := range loi.Objects {
+						// Save the progress and release the lock
+						// on shutdown.
+						select {
+						case <-GlobalServiceDoneCh:
+							return false, saveXLMetaMigrationConfig(ctx, objAPI, state)
+						default:
+						}
+						err = s.getHashedSet(obj.Name).migrateObjectXLMeta(ctx, bucket, obj.Name)
+						if err != nil && !isErrObjectNotFound(err) {
+							// Migrated by the next heal of the object.
+							logger.LogIf(ctx, err)
+						}
+
+						state.Marker = obj.Name
+						migrated++
+						if migrated%xlMetaMigrationSaveInterval == 0 {
+							if err = saveXLMetaMigrationConfig(ctx, objAPI, state); err != nil {
+								return false, err
+							}
+						}
+					}
+					if !loi.IsTruncated {
+						break
+					}
+					marker = loi.NextMarker
+				}
+			}
+		}
+	}
+
+	state.Finished = UTCNow()
+	state.Zone, state.Bucket, state.Marker = 0, "", ""
+	if err = saveXLMetaMigrationConfig(ctx, objAPI, state); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// migrateObjectXLMeta - rewrites the legacy JSON `xl.json` of the
+// object on the disks holding its latest version.
+func (xl xlObjects) migrateObjectXLMeta(ctx context.Context, bucket, object string) error {
+	// Directories have no metadata.
+	if hasSuffix(object, SlashSeparator) {
+		return nil
+	}
+
+	objectLock := xl.nsMutex.NewNSLock(ctx, bucket, object)
+	if err := objectLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer objectLock.Unlock()
+
+	disks := xl.getDisks()
+	partsMetadata, errs := readAllXLMetadata(ctx, disks, bucket, object)
+	if reducedErr := reduceReadQuorumErrs(ctx, errs, objectOpIgnoredErrs, len(disks)/2); reducedErr != nil {
+		return toObjectErr(reducedErr, bucket, object)
+	}
+	onlineDisks, _ := listOnlineDisks(disks, partsMetadata, errs)
+	migrateLegacyXLMeta(ctx, onlineDisks, partsMetadata, bucket, object)
+	return nil
+}
+
+func saveXLMetaMigrationConfig(ctx context.Context, objAPI ObjectLayer, state xlMetaMigrationState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return saveConfig(ctx, objAPI, path.Join(minioConfigPrefix, xlMetaMigrationConfigFile), data)
+}
+
+// readXLMetaMigrationConfig - returns the migration progress, an empty
+// state if the migration never ran.
+func readXLMetaMigrationConfig(ctx context.Context, objAPI ObjectLayer) (xlMetaMigrationState, error) {
+	var state xlMetaMigrationState
+	configData, err := readConfig(ctx, objAPI, path.Join(minioConfigPrefix, xlMetaMigrationConfigFile))
+	if err != nil {
+		if err == errConfigNotFound {
+			return state, nil
+		}
+		return state, err
+	}
+	err = json.Unmarshal(configData, &state)
+	return state, err
+}
@@ -156,6 +156,13 @@ type xlMetaV1 struct {
 	Meta map[string]string `json:"meta,omitempty"`
 	// Captures all the individual object `xl.json`.
 	Parts []ObjectPartInfo `json:"parts,omitempty"`
+	// Erasure coded shard of small objects stored inline, with
+	// bitrot checksums in the same format as a part file.
+	Data []byte `json:"data,omitempty"`
+
+	// Read from legacy JSON `xl.json`, migrated to the binary
+	// format once binary metadata is enabled.
+	legacy bool
 }
 
 // XL metadata constants.
@@ -194,9 +201,16 @@ func newXLMetaFromXLMeta(meta xlMetaV1) xlMetaV1 {
 	xlMeta := meta
 	xlMeta.Erasure.Checksums = nil
 	xlMeta.Parts = nil
+	xlMeta.Data = nil
 	return xlMeta
 }
 
+// isInline - returns true if the data of the object is stored
+// inline in `xl.json` instead of a part file.
+func (m xlMetaV1) isInline() bool {
+	return len(m.Data) > 0
+}
+
 // IsValid - tells if the format is sane by validating the version
 // string, format and erasure info fields.
 func (m xlMetaV1) IsValid() bool {
@@ -429,15 +443,21 @@ func (xl xlObjects) readXLMetaStat(ctx context.Context, bucket, object string) (
 func writeXLMetadata(ctx context.Context, disk StorageAPI, bucket, prefix string, xlMeta xlMetaV1) error {
 	jsonFile := path.Join(prefix, xlMetaJSONFile)
 
-	// Marshal json.
-	metadataBytes, err := json.Marshal(&xlMeta)
-	if err != nil {
-		logger.LogIf(ctx, err)
-		return err
+	// Metadata is written as JSON until binary metadata is enabled,
+	// servers of previous releases only read JSON.
+	var metadataBytes []byte
+	if globalXLMetaConfig.Binary {
+		metadataBytes = xlMetaV1MarshalBinary(xlMeta)
+	} else {
+		var err error
+		if metadataBytes, err = json.Marshal(&xlMeta); err != nil {
+			logger.LogIf(ctx, err)
+			return err
+		}
 	}
 
 	// Persist marshaled data.
-	err = disk.WriteAll(bucket, jsonFile, bytes.NewReader(metadataBytes))
+	err := disk.WriteAll(bucket, jsonFile, bytes.NewReader(metadataBytes))
 	logger.LogIf(ctx, err)
 	return err
 }
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/minio/pkg/mimedb"
 )
 
@@ -437,6 +438,9 @@ func (xl xlObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID
 	xlMeta.Stat.ModTime = UTCNow()
 
 	md5hex := r.MD5CurrentHexString()
+	if opts.PreserveETag != "" {
+		md5hex = opts.PreserveETag
+	}
 
 	// Add the current part.
 	xlMeta.AddObjectPart(partID, partSuffix, md5hex, n, data.ActualSize())
@@ -631,6 +635,9 @@ func (xl xlObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 
 	// Calculate s3 compatible md5sum for complete multipart.
 	s3MD5 := getCompleteMultipartMD5(parts)
+	if opts.PreserveETag != "" {
+		s3MD5 = opts.PreserveETag
+	}
 
 	// Read metadata associated with the object from all disks.
 	partsMetadata, errs := readAllXLMetadata(ctx, xl.getDisks(), minioMetaMultipartBucket, uploadIDPath)
@@ -723,6 +730,9 @@ func (xl xlObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 	// Save the final object size and modtime.
 	xlMeta.Stat.Size = objectSize
 	xlMeta.Stat.ModTime = UTCNow()
+	if !opts.MTime.IsZero() {
+		xlMeta.Stat.ModTime = opts.MTime
+	}
 
 	// Save successfully calculated md5sum.
 	xlMeta.Meta["etag"] = s3MD5
@@ -789,12 +799,22 @@ func (xl xlObjects) CompleteMultipartUpload(ctx context.Context, bucket string,
 	}
 
 	// Rename the multipart object to final location.
-	if _, err = rename(ctx, onlineDisks, minioMetaMultipartBucket, uploadIDPath, bucket, object, true, writeQuorum, nil); err != nil {
+	if onlineDisks, err = rename(ctx, onlineDisks, minioMetaMultipartBucket, uploadIDPath, bucket, object, true, writeQuorum, nil); err != nil {
 		return oi, toObjectErr(err, bucket, object)
 	}
 
+	// Object was written with quorum but not to all disks.
+	for _, disk := range onlineDisks {
+		if disk == nil {
+			queueMRFHeal(bucket, object, madmin.HealNormalScan)
+			break
+		}
+	}
+
 	// Success, return object info.
-	return xlMeta.ToObjectInfo(bucket, object), nil
+	oi = xlMeta.ToObjectInfo(bucket, object)
+	xl.listIndex.Put(ctx, bucket, oi)
+	return oi, nil
 }
 
 // AbortMultipartUpload - aborts an ongoing multipart operation
@@ -25,6 +25,7 @@ import (
 	"sync"
 
 	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/madmin"
 	"github.com/minio/minio/pkg/mimedb"
 )
 
@@ -111,7 +112,9 @@ func (xl xlObjects) CopyObject(ctx context.Context, srcBucket, srcObject, dstBuc
 			return oi, toObjectErr(err, srcBucket, srcObject)
 		}
 
-		return xlMeta.ToObjectInfo(srcBucket, srcObject), nil
+		oi = xlMeta.ToObjectInfo(srcBucket, srcObject)
+		xl.listIndex.Put(ctx, srcBucket, oi)
+		return oi, nil
 	}
 
 	putOpts := ObjectOptions{ServerSideEncryption: dstOpts.ServerSideEncryption, UserDefined: srcInfo.UserDefined}
@@ -156,8 +159,12 @@ func (xl xlObjects) GetObjectNInfo(ctx context.Context, bucket, object string, r
 		return NewGetObjectReaderFromReader(bytes.NewBuffer(nil), objInfo, opts.CheckCopyPrecondFn, nsUnlocker)
 	}
 
+	// Metadata is read only once, objects stored inline in `xl.json`
+	// are served without reading any other file.
+	metaArr, errs := readAllXLMetadata(ctx, xl.getDisks(), bucket, object)
+
 	var objInfo ObjectInfo
-	objInfo, err = xl.getObjectInfo(ctx, bucket, object)
+	objInfo, err = xl.getObjectInfoWithXLMeta(ctx, bucket, object, metaArr, errs)
 	if err != nil {
 		nsUnlocker()
 		return nil, toObjectErr(err, bucket, object)
@@ -170,7 +177,7 @@ func (xl xlObjects) GetObjectNInfo(ctx context.Context, bucket, object string, r
 
 	pr, pw := io.Pipe()
 	go func() {
-		err := xl.getObject(ctx, bucket, object, off, length, pw, "", opts)
+		err := xl.getObjectWithXLMeta(ctx, bucket, object, off, length, pw, metaArr, errs)
 		pw.CloseWithError(err)
 	}()
 	// Cleanup function to cause the go routine above to exit, in
@@ -225,6 +232,12 @@ func (xl xlObjects) getObject(ctx context.Context, bucket, object string, startO
 	// Read metadata associated with the object from all disks.
 	metaArr, errs := readAllXLMetadata(ctx, xl.getDisks(), bucket, object)
 
+	return xl.getObjectWithXLMeta(ctx, bucket, object, startOffset, length, writer, metaArr, errs)
+}
+
+// getObjectWithXLMeta - reads the object using the metadata already
+// read from all disks.
+func (xl xlObjects) getObjectWithXLMeta(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, metaArr []xlMetaV1, errs []error) error {
 	// get Quorum for this object
 	readQuorum, _, err := objectQuorumFromMeta(ctx, xl, metaArr, errs)
 	if err != nil {
@@ -244,6 +257,16 @@ func (xl xlObjects) getObject(ctx context.Context, bucket, object string, startO
 		return err
 	}
 
+	// Heal the object once it was read if the metadata is missing
+	// or outdated on any of the available disks.
+	healRequired := false
+	for index, disk := range onlineDisks {
+		if disk == OfflineDisk && errs[index] != errDiskNotFound {
+			healRequired = true
+			break
+		}
+	}
+
 	// Reorder online disks based on erasure distribution order.
 	onlineDisks = shuffleDisks(onlineDisks, xlMeta.Erasure.Distribution)
 
@@ -307,6 +330,10 @@ func (xl xlObjects) getObject(ctx context.Context, bucket, object string, startO
 				continue
 			}
 			checksumInfo := metaArr[index].Erasure.GetChecksumInfo(partName)
+			if metaArr[index].isInline() {
+				readers[index] = newInlineBitrotReader(metaArr[index].Data, tillOffset, checksumInfo.Algorithm, erasure.ShardSize())
+				continue
+			}
 			readers[index] = newBitrotReader(disk, bucket, pathJoin(object, partName), tillOffset, checksumInfo.Algorithm, checksumInfo.Hash, erasure.ShardSize())
 		}
 		err := erasure.Decode(ctx, writer, readers, partOffset, partLength, partSize)
@@ -318,6 +345,10 @@ func (xl xlObjects) getObject(ctx context.Context, bucket, object string, startO
 		}
 		for i, r := range readers {
 			if r == nil {
+				if onlineDisks[i] != OfflineDisk {
+					// Shard is missing or failed the bitrot check.
+					healRequired = true
+				}
 				onlineDisks[i] = OfflineDisk
 			}
 		}
@@ -329,6 +360,10 @@ func (xl xlObjects) getObject(ctx context.Context, bucket, object string, startO
 		partOffset = 0
 	} // End of read all parts loop.
 
+	if healRequired {
+		queueMRFHeal(bucket, object, madmin.HealDeepScan)
+	}
+
 	// Return success.
 	return nil
 }
@@ -399,9 +434,16 @@ func (xl xlObjects) GetObjectInfo(ctx context.Context, bucket, object string, op
 func (xl xlObjects) getObjectInfo(ctx context.Context, bucket, object string) (objInfo ObjectInfo, err error) {
 	disks := xl.getDisks()
 
-	// Read metadata associated with the object from all disks.
-	metaArr, errs := readAllXLMetadata(ctx, disks, bucket, object)
+	// Read metadata associated with the object from all disks,
+	// inline data is not needed.
+	metaArr, errs := readAllXLMetadataSection(ctx, disks, bucket, object, xlMetaErasureSection)
+
+	return xl.getObjectInfoWithXLMeta(ctx, bucket, object, metaArr, errs)
+}
 
+// getObjectInfoWithXLMeta - constructs ObjectInfo from the metadata
+// already read from all disks.
+func (xl xlObjects) getObjectInfoWithXLMeta(ctx context.Context, bucket, object string, metaArr []xlMetaV1, errs []error) (objInfo ObjectInfo, err error) {
 	readQuorum, _, err := objectQuorumFromMeta(ctx, xl, metaArr, errs)
 	if err != nil {
 		return objInfo, err
@@ -557,7 +599,9 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 			return ObjectInfo{}, toObjectErr(err, bucket, object)
 		}
 
-		return dirObjectInfo(bucket, object, data.Size(), opts.UserDefined), nil
+		objInfo = dirObjectInfo(bucket, object, data.Size(), opts.UserDefined)
+		xl.listIndex.Put(ctx, bucket, objInfo)
+		return objInfo, nil
 	}
 
 	// Validate input data size and it can never be less than zero.
@@ -611,11 +655,19 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 	partName := "part.1"
 	tempErasureObj := pathJoin(uniqueID, partName)
 
+	// Small objects are stored inline in `xl.json` which saves
+	// writing and reading a part file on every disk.
+	inline := data.Size() > 0 && data.Size() <= smallFileThreshold
+
 	writers := make([]io.Writer, len(onlineDisks))
 	for i, disk := range onlineDisks {
 		if disk == nil {
 			continue
 		}
+		if inline {
+			writers[i] = newInlineBitrotWriter(DefaultBitrotAlgorithm)
+			continue
+		}
 		writers[i] = newBitrotWriter(disk, minioMetaTmpBucket, tempErasureObj, erasure.ShardFileSize(data.Size()), DefaultBitrotAlgorithm, erasure.ShardSize())
 	}
 
@@ -639,12 +691,21 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 		}
 		partsMetadata[i].AddObjectPart(1, partName, "", n, data.ActualSize())
 		partsMetadata[i].Erasure.AddChecksumInfo(ChecksumInfo{partName, DefaultBitrotAlgorithm, bitrotWriterSum(w)})
+		if inline {
+			partsMetadata[i].Data = w.(*inlineBitrotWriter).Bytes()
+		}
 	}
 
 	// Save additional erasureMetadata.
 	modTime := UTCNow()
+	if !opts.MTime.IsZero() {
+		modTime = opts.MTime
+	}
 
 	opts.UserDefined["etag"] = r.MD5CurrentHexString()
+	if opts.PreserveETag != "" {
+		opts.UserDefined["etag"] = opts.PreserveETag
+	}
 
 	// Guess content-type from the extension if possible.
 	if opts.UserDefined["content-type"] == "" {
@@ -686,10 +747,18 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 	}
 
 	// Rename the successfully written temporary object to final location.
-	if _, err = rename(ctx, onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, true, writeQuorum, nil); err != nil {
+	if onlineDisks, err = rename(ctx, onlineDisks, minioMetaTmpBucket, tempObj, bucket, object, true, writeQuorum, nil); err != nil {
 		return ObjectInfo{}, toObjectErr(err, bucket, object)
 	}
 
+	// Object was written with quorum but not to all disks.
+	for _, disk := range onlineDisks {
+		if disk == nil {
+			queueMRFHeal(bucket, object, madmin.HealNormalScan)
+			break
+		}
+	}
+
 	// Object info is the same in all disks, so we can pick the first meta
 	// of the first disk
 	xlMeta = partsMetadata[0]
@@ -706,6 +775,7 @@ func (xl xlObjects) putObject(ctx context.Context, bucket string, object string,
 		UserDefined:     xlMeta.Meta,
 	}
 
+	xl.listIndex.Put(ctx, bucket, xlMeta.ToObjectInfo(bucket, object))
 	return objInfo, nil
 }
 
@@ -768,7 +838,12 @@ func (xl xlObjects) deleteObject(ctx context.Context, bucket, object string, wri
 	wg.Wait()
 
 	// return errors if any during deletion
-	return reduceWriteQuorumErrs(ctx, dErrs, objectOpIgnoredErrs, writeQuorum)
+	if err = reduceWriteQuorumErrs(ctx, dErrs, objectOpIgnoredErrs, writeQuorum); err != nil {
+		return err
+	}
+
+	xl.listIndex.Delete(ctx, bucket, object)
+	return nil
 }
 
 // deleteObject - wrapper for delete object, deletes an object from
@@ -838,6 +913,13 @@ func (xl xlObjects) doDeleteObjects(ctx context.Context, bucket string, objects
 		errs[objIndex] = reduceWriteQuorumErrs(ctx, listErrs, objectOpIgnoredErrs, writeQuorums[objIndex])
 	}
 
+	var deleted []string
+	for objIndex, object := range objects {
+		if errs[objIndex] == nil {
+			deleted = append(deleted, object)
+		}
+	}
+	xl.listIndex.Delete(ctx, bucket, deleted...)
 	return errs, nil
 }
 
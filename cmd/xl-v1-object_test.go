@@ -256,8 +256,10 @@ func TestGetObjectNoQuorum(t *testing.T) {
 	bucket := "bucket"
 	object := "object"
 	opts := ObjectOptions{}
-	// Create "object" under "bucket".
-	_, err = obj.PutObject(context.Background(), bucket, object, mustGetPutObjReader(t, bytes.NewReader([]byte("abcd")), int64(len("abcd")), "", ""), opts)
+	// Create "object" under "bucket", large enough to be stored in
+	// part files which are read after `xl.json`.
+	data := bytes.Repeat([]byte("a"), smallFileThreshold+1)
+	_, err = obj.PutObject(context.Background(), bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), opts)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -280,7 +282,7 @@ func TestGetObjectNoQuorum(t *testing.T) {
 			}
 		}
 		// Fetch object from store.
-		err = xl.GetObject(context.Background(), bucket, object, 0, int64(len("abcd")), ioutil.Discard, "", opts)
+		err = xl.GetObject(context.Background(), bucket, object, 0, int64(len(data)), ioutil.Discard, "", opts)
 		if err != toObjectErr(errXLReadQuorum, bucket, object) {
 			t.Errorf("Expected putObject to fail with %v, but failed with %v", toObjectErr(errXLWriteQuorum, bucket, object), err)
 		}
@@ -444,3 +446,184 @@ func TestHealing(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestGetObjectQueuesMRFHeal(t *testing.T) {
+	obj, fsDirs, err := prepareXL16()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	globalBackgroundHealing = initHealRoutine()
+	defer func() {
+		globalBackgroundHealing = nil
+	}()
+	globalObjLayerMutex.Lock()
+	globalObjectAPI = obj
+	globalObjLayerMutex.Unlock()
+
+	ctx := context.Background()
+	bucket := "bucket"
+	object := "object"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	data := make([]byte, 1*humanize.MiByte)
+	if _, err = rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	getObject := func() {
+		var buf bytes.Buffer
+		if err = obj.GetObject(ctx, bucket, object, 0, int64(len(data)), &buf, "", ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatal("Unexpected object content")
+		}
+	}
+
+	getObject()
+	if queued, _, _, _ := globalBackgroundHealing.mrfStatus(); queued != 0 {
+		t.Fatalf("Expected no queued heal for a healthy object, got %d", queued)
+	}
+
+	// Remove the first data shard, reads reconstruct it from parity.
+	var shardDisk = -1
+	for i, disk := range xl.storageDisks {
+		xlMeta, rerr := readXLMeta(ctx, disk, bucket, object)
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		if xlMeta.Erasure.Index == 1 {
+			shardDisk = i
+		}
+	}
+	if err = os.Remove(path.Join(fsDirs[shardDisk], bucket, object, "part.1")); err != nil {
+		t.Fatal(err)
+	}
+
+	getObject()
+	getObject()
+	if queued, _, _, _ := globalBackgroundHealing.mrfStatus(); queued != 1 {
+		t.Fatalf("Expected one queued heal, got %d", queued)
+	}
+
+	globalBackgroundHealing.healMRF(ctx, <-globalBackgroundHealing.mrfCh)
+	if _, healed, failed, _ := globalBackgroundHealing.mrfStatus(); healed != 1 || failed != 0 {
+		t.Fatalf("Expected one healed object, got %d healed and %d failed", healed, failed)
+	}
+	if _, err = os.Stat(path.Join(fsDirs[shardDisk], bucket, object, "part.1")); err != nil {
+		t.Fatalf("Expected shard to be healed, %s", err)
+	}
+}
+
+func TestPutObjectInline(t *testing.T) {
+	obj, fsDirs, err := prepareXL16()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeRoots(fsDirs)
+	xl := obj.(*xlObjects)
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err = obj.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	small := make([]byte, 4*humanize.KiByte)
+	if _, err = rand.Read(small); err != nil {
+		t.Fatal(err)
+	}
+	large := make([]byte, smallFileThreshold+1)
+	if _, err = rand.Read(large); err != nil {
+		t.Fatal(err)
+	}
+	for object, data := range map[string][]byte{"small": small, "large": large} {
+		if _, err = obj.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only objects up to the threshold are stored inline.
+	for i, disk := range xl.storageDisks {
+		xlMeta, rerr := readXLMeta(ctx, disk, bucket, "small")
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		if !xlMeta.isInline() {
+			t.Fatalf("Expected small object to be inline on disk %d", i)
+		}
+		if _, err = os.Stat(path.Join(fsDirs[i], bucket, "small", "part.1")); !os.IsNotExist(err) {
+			t.Fatalf("Expected no part file for an inline object, got %v", err)
+		}
+		if xlMeta, err = readXLMeta(ctx, disk, bucket, "large"); err != nil {
+			t.Fatal(err)
+		}
+		if xlMeta.isInline() {
+			t.Fatalf("Expected large object not to be inline on disk %d", i)
+		}
+	}
+
+	getObject := func(offset, length int64) {
+		var buf bytes.Buffer
+		if err = obj.GetObject(ctx, bucket, "small", offset, length, &buf, "", ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), small[offset:offset+length]) {
+			t.Fatalf("Unexpected object content at offset %d", offset)
+		}
+	}
+	getObject(0, int64(len(small)))
+	getObject(1000, 2000)
+
+	gr, err := obj.GetObjectNInfo(ctx, bucket, "small", nil, nil, readLock, ObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(gr)
+	gr.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, small) || gr.ObjInfo.Size != int64(len(small)) {
+		t.Fatal("Unexpected object content")
+	}
+
+	// Corrupt the inline shard on one disk, deep heal repairs it.
+	disk := xl.storageDisks[0]
+	xlMeta, err := readXLMeta(ctx, disk, bucket, "small")
+	if err != nil {
+		t.Fatal(err)
+	}
+	xlMeta.Data[len(xlMeta.Data)-1] ^= 0xff
+	if err = disk.DeleteFile(bucket, path.Join("small", xlMetaJSONFile)); err != nil {
+		t.Fatal(err)
+	}
+	if err = writeXLMetadata(ctx, disk, bucket, "small", xlMeta); err != nil {
+		t.Fatal(err)
+	}
+	getObject(0, int64(len(small)))
+
+	partsMetadata, errs := readAllXLMetadata(ctx, xl.storageDisks, bucket, "small")
+	if _, dataErrs := disksWithAllParts(ctx, xl.storageDisks, partsMetadata, errs, bucket, "small", madmin.HealDeepScan); dataErrs[0] != errFileCorrupt {
+		t.Fatalf("Expected %v, got %v", errFileCorrupt, dataErrs[0])
+	}
+
+	if _, err = xl.HealObject(ctx, bucket, "small", false, false, madmin.HealDeepScan); err != nil {
+		t.Fatal(err)
+	}
+	partsMetadata, errs = readAllXLMetadata(ctx, xl.storageDisks, bucket, "small")
+	_, dataErrs := disksWithAllParts(ctx, xl.storageDisks, partsMetadata, errs, bucket, "small", madmin.HealDeepScan)
+	for i, derr := range dataErrs {
+		if derr != nil {
+			t.Fatalf("Expected inline shard on disk %d to be healed, got %v", i, derr)
+		}
+	}
+}
@@ -119,9 +119,19 @@ func hashOrder(key string, cardinality int) []int {
 func xlMetaV1UnmarshalJSON(ctx context.Context, xlMetaBuf []byte) (xlMeta xlMetaV1, err error) {
 	var json = jsoniter.ConfigCompatibleWithStandardLibrary
 	err = json.Unmarshal(xlMetaBuf, &xlMeta)
+	xlMeta.legacy = true
 	return xlMeta, err
 }
 
+// Constructs xlMetaV1 from binary or legacy JSON `xl.json`, binary
+// metadata is only decoded up to the given section.
+func xlMetaV1Unmarshal(ctx context.Context, xlMetaBuf []byte, section xlMetaSection) (xlMeta xlMetaV1, err error) {
+	if isXLMetaBinary(xlMetaBuf) {
+		return xlMetaV1UnmarshalBinary(xlMetaBuf, section)
+	}
+	return xlMetaV1UnmarshalJSON(ctx, xlMetaBuf)
+}
+
 // read xl.json from the given disk, parse and return xlV1MetaV1.Parts.
 func readXLMetaParts(ctx context.Context, disk StorageAPI, bucket string, object string) ([]ObjectPartInfo, map[string]string, error) {
 	// Reads entire `xl.json`.
@@ -132,7 +142,7 @@ func readXLMetaParts(ctx context.Context, disk StorageAPI, bucket string, object
 	}
 
 	var xlMeta xlMetaV1
-	xlMeta, err = xlMetaV1UnmarshalJSON(ctx, xlMetaBuf)
+	xlMeta, err = xlMetaV1Unmarshal(ctx, xlMetaBuf, xlMetaErasureSection)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -151,7 +161,7 @@ func readXLMetaStat(ctx context.Context, disk StorageAPI, bucket string, object
 	}
 
 	var xlMeta xlMetaV1
-	xlMeta, err = xlMetaV1UnmarshalJSON(ctx, xlMetaBuf)
+	xlMeta, err = xlMetaV1Unmarshal(ctx, xlMetaBuf, xlMetaStatSection)
 	if err != nil {
 		return si, mp, err
 	}
@@ -162,6 +172,12 @@ func readXLMetaStat(ctx context.Context, disk StorageAPI, bucket string, object
 
 // readXLMeta reads `xl.json` and returns back XL metadata structure.
 func readXLMeta(ctx context.Context, disk StorageAPI, bucket string, object string) (xlMeta xlMetaV1, err error) {
+	return readXLMetaSection(ctx, disk, bucket, object, xlMetaDataSection)
+}
+
+// readXLMetaSection reads `xl.json` and returns back XL metadata
+// structure decoded up to the given section.
+func readXLMetaSection(ctx context.Context, disk StorageAPI, bucket string, object string, section xlMetaSection) (xlMeta xlMetaV1, err error) {
 	// Reads entire `xl.json`.
 	xlMetaBuf, err := disk.ReadAll(bucket, path.Join(object, xlMetaJSONFile))
 	if err != nil {
@@ -174,12 +190,17 @@ func readXLMeta(ctx context.Context, disk StorageAPI, bucket string, object stri
 	if len(xlMetaBuf) == 0 {
 		return xlMetaV1{}, errFileNotFound
 	}
-	return xlMetaV1UnmarshalJSON(ctx, xlMetaBuf)
+	return xlMetaV1Unmarshal(ctx, xlMetaBuf, section)
 }
 
 // Reads all `xl.json` metadata as a xlMetaV1 slice.
 // Returns error slice indicating the failed metadata reads.
 func readAllXLMetadata(ctx context.Context, disks []StorageAPI, bucket, object string) ([]xlMetaV1, []error) {
+	return readAllXLMetadataSection(ctx, disks, bucket, object, xlMetaDataSection)
+}
+
+// Reads all `xl.json` metadata decoded up to the given section.
+func readAllXLMetadataSection(ctx context.Context, disks []StorageAPI, bucket, object string, section xlMetaSection) ([]xlMetaV1, []error) {
 	errs := make([]error, len(disks))
 	metadataArray := make([]xlMetaV1, len(disks))
 	var wg sync.WaitGroup
@@ -193,7 +214,7 @@ func readAllXLMetadata(ctx context.Context, disks []StorageAPI, bucket, object s
 		// Read `xl.json` in routine.
 		go func(index int, disk StorageAPI) {
 			defer wg.Done()
-			metadataArray[index], errs[index] = readXLMeta(ctx, disk, bucket, object)
+			metadataArray[index], errs[index] = readXLMetaSection(ctx, disk, bucket, object, section)
 		}(index, disk)
 	}
 
@@ -23,6 +23,7 @@ import (
 
 	"github.com/minio/minio/cmd/logger"
 	"github.com/minio/minio/pkg/bpool"
+	"github.com/minio/minio/pkg/tracing"
 )
 
 // XL constants.
@@ -42,9 +43,17 @@ type xlObjects struct {
 	// getDisks returns list of storageAPIs.
 	getDisks func() []StorageAPI
 
+	// getHealDisks returns list of storageAPIs including the slow or
+	// faulty disks excluded by getDisks, heals repair them as well.
+	getHealDisks func() []StorageAPI
+
 	// Byte pools used for temporary i/o buffers.
 	bp *bpool.BytePoolCap
 
+	// Metadata index of the buckets updated while holding
+	// the object lock, nil if disabled.
+	listIndex *xlListIndex
+
 	// TODO: Deprecated only kept here for tests, should be removed in future.
 	storageDisks []StorageAPI
 
@@ -52,6 +61,34 @@ type xlObjects struct {
 	listPool *TreeWalkPool
 }
 
+// withContext - returns the set whose disk calls are traced as part
+// of the request of ctx, the set itself if the request is not traced.
+func (xl *xlObjects) withContext(ctx context.Context) *xlObjects {
+	if tracing.FromContext(ctx) == nil {
+		return xl
+	}
+	getDisks := xl.getDisks
+	traced := *xl
+	traced.getDisks = func() []StorageAPI {
+		return newTracedDisks(ctx, getDisks())
+	}
+	if getHealDisks := xl.getHealDisks; getHealDisks != nil {
+		traced.getHealDisks = func() []StorageAPI {
+			return newTracedDisks(ctx, getHealDisks())
+		}
+	}
+	return &traced
+}
+
+// healDisks - returns the disks used by heals, disks excluded as slow
+// or faulty are healed too.
+func (xl xlObjects) healDisks() []StorageAPI {
+	if xl.getHealDisks == nil {
+		return xl.getDisks()
+	}
+	return xl.getHealDisks()
+}
+
 // Shutdown function for object storage interface.
 func (xl xlObjects) Shutdown(ctx context.Context) error {
 	// Add any object layer shutdown activities here.
@@ -0,0 +1,1149 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/cmd/logger"
+	"github.com/minio/minio/pkg/hash"
+	"github.com/minio/minio/pkg/lifecycle"
+	"github.com/minio/minio/pkg/madmin"
+	"github.com/minio/minio/pkg/policy"
+	"github.com/minio/minio/pkg/sync/errgroup"
+)
+
+// Prefix of the zone locks of objects in the meta bucket.
+const zonesLockPrefix = "zones"
+
+// xlZones - implements ObjectLayer on top of several independent
+// groups of erasure coded sets (zones), each zone has its own
+// `format.json`. Buckets are present on all zones, while every
+// object lives in exactly one zone.
+type xlZones struct {
+	zones []*xlSets
+
+	// Available space of each zone, refreshed in the background.
+	availableMu sync.RWMutex
+	available   []uint64
+}
+
+// Interval of refreshing the available space of the zones.
+const zoneAvailableRefreshInterval = 30 * time.Second
+
+// Initialize all zones of a deployment, all the zones share the
+// deployment ID of the first zone.
+func newXLZones(endpointZones EndpointZones) (ObjectLayer, error) {
+	if len(endpointZones) == 0 {
+		return nil, errInvalidArgument
+	}
+
+	var deploymentID string
+	z := &xlZones{zones: make([]*xlSets, len(endpointZones))}
+	for i, ep := range endpointZones {
+		format, err := waitForFormatXL(context.Background(), ep.Endpoints[0].IsLocal, ep.Endpoints, ep.SetCount, ep.DrivesPerSet, deploymentID)
+		if err != nil {
+			return nil, err
+		}
+		if deploymentID == "" {
+			deploymentID = format.ID
+		}
+		z.zones[i], err = newXLSets(ep.Endpoints, format, len(format.XL.Sets), len(format.XL.Sets[0]))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A single zone is served directly by the erasure coded sets.
+	if len(z.zones) == 1 {
+		return z.zones[0], nil
+	}
+	go z.monitorAvailable(zoneAvailableRefreshInterval)
+	return z, nil
+}
+
+// Returns the lock serializing the placement of an object on the zones,
+// i.e. writes, deletes and moves of the object. It is separate from the
+// object lock taken by the zones themselves as locks are not reentrant.
+func (z *xlZones) newZoneLock(ctx context.Context, bucket, object string) RWLocker {
+	return globalNSMutex.NewNSLock(ctx, minioMetaBucket, pathJoin(zonesLockPrefix, bucket, object))
+}
+
+// Returns true if new objects can not be placed on the zone, either
+// the zone or the erasure set of the object is being decommissioned
+// or was retired by a completed decommission.
+func (z *xlZones) isDraining(zoneIdx int, object string) bool {
+	return globalDecommissionSys.IsExcluded(zoneIdx, z.zones[zoneIdx].getHashedSetIndex(object))
+}
+
+// Returns the zone with the most available space, new objects
+// are placed on this zone. Returns -1 if no zone can be used.
+func (z *xlZones) getAvailableZoneIdx(ctx context.Context, object string) int {
+	available := z.getAvailable(ctx)
+
+	zoneIdx := -1
+	for index := range available {
+		if z.isDraining(index, object) {
+			continue
+		}
+		if zoneIdx < 0 || available[index] > available[zoneIdx] {
+			zoneIdx = index
+		}
+	}
+	return zoneIdx
+}
+
+// Returns the available space of each zone as last refreshed by the
+// background routine, the space is only read in the request path if
+// it was never refreshed.
+func (z *xlZones) getAvailable(ctx context.Context) []uint64 {
+	z.availableMu.RLock()
+	available := z.available
+	z.availableMu.RUnlock()
+	if available != nil {
+		return available
+	}
+	return z.refreshAvailable(ctx)
+}
+
+// Reads the available space of all zones.
+func (z *xlZones) refreshAvailable(ctx context.Context) []uint64 {
+	var wg sync.WaitGroup
+
+	available := make([]uint64, len(z.zones))
+	for index, zone := range z.zones {
+		wg.Add(1)
+		go func(index int, zone *xlSets) {
+			defer wg.Done()
+			for _, set := range zone.sets {
+				available[index] += set.StorageInfo(ctx).Available
+			}
+		}(index, zone)
+	}
+	wg.Wait()
+
+	z.availableMu.Lock()
+	z.available = available
+	z.availableMu.Unlock()
+	return available
+}
+
+// Refreshes the available space of all zones at the given interval
+// until the server shuts down.
+func (z *xlZones) monitorAvailable(interval time.Duration) {
+	ctx := context.Background()
+	for {
+		select {
+		case <-GlobalServiceDoneCh:
+			return
+		case <-time.After(interval):
+			z.refreshAvailable(ctx)
+		}
+	}
+}
+
+// Returns the zone an object should be written to, an object which
+// already exists is always overwritten on its current zone unless
+// that zone is being drained. Only the metadata files of the object
+// are looked up on the drives of its erasure set in each zone.
+func (z *xlZones) getZoneIdx(ctx context.Context, bucket, object string) (int, error) {
+	var wg sync.WaitGroup
+
+	present := make([]bool, len(z.zones))
+	errs := make([]error, len(z.zones))
+	for index, zone := range z.zones {
+		wg.Add(1)
+		go func(index int, zone *xlSets) {
+			defer wg.Done()
+			present[index], errs[index] = zone.getHashedSet(object).isObjectPresent(ctx, bucket, object)
+		}(index, zone)
+	}
+	wg.Wait()
+
+	for index := range z.zones {
+		if errs[index] != nil {
+			return -1, toObjectErr(errs[index], bucket, object)
+		}
+		if present[index] && !z.isDraining(index, object) {
+			return index, nil
+		}
+	}
+	zoneIdx := z.getAvailableZoneIdx(ctx, object)
+	if zoneIdx < 0 {
+		return -1, StorageFull{}
+	}
+	return zoneIdx, nil
+}
+
+// Removes the copies of an object left on draining zones, called
+// after the object was written to zoneIdx.
+func (z *xlZones) deleteDrainingCopies(ctx context.Context, bucket, object string, zoneIdx int) {
+	for index, zone := range z.zones {
+		if index == zoneIdx || !z.isDraining(index, object) {
+			continue
+		}
+		if err := zone.DeleteObject(ctx, bucket, object); err != nil && !isErrObjectNotFound(err) {
+			logger.LogIf(ctx, err)
+		}
+	}
+}
+
+// Returns a reader for a range of the stored data of an object, the
+// data is returned as stored, i.e. still encrypted or compressed.
+func newZoneObjectReader(ctx context.Context, zone *xlSets, bucket, object string, offset, length, actualSize int64) (*hash.Reader, *io.PipeReader, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(zone.GetObject(ctx, bucket, object, offset, length, pw, "", ObjectOptions{}))
+	}()
+	hr, err := hash.NewReader(pr, length, "", "", actualSize, false)
+	if err != nil {
+		pr.Close()
+		return nil, nil, err
+	}
+	return hr, pr, nil
+}
+
+// Moves an object off the zone srcIdx to the zone with the most
+// available space. Stored data, metadata, parts, ETag and modification
+// time are kept as is. Returns the number of bytes moved.
+func (z *xlZones) moveObject(ctx context.Context, srcIdx int, bucket, object string) (int64, error) {
+	// Writes of the object wait for the move, a newer version
+	// is never overwritten by the moved copy.
+	zoneLock := z.newZoneLock(ctx, bucket, object)
+	if err := zoneLock.GetLock(globalObjectTimeout); err != nil {
+		return 0, err
+	}
+	defer zoneLock.Unlock()
+
+	return z.moveLockedObject(ctx, srcIdx, bucket, object)
+}
+
+// Same as moveObject, the caller holds the zone lock of the object.
+func (z *xlZones) moveLockedObject(ctx context.Context, srcIdx int, bucket, object string) (int64, error) {
+	src := z.zones[srcIdx]
+	objInfo, err := src.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+	if err != nil {
+		if isErrObjectNotFound(err) {
+			// Removed in the meantime, nothing to move.
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	dstIdx := z.getAvailableZoneIdx(ctx, object)
+	if dstIdx < 0 {
+		return 0, StorageFull{}
+	}
+	dst := z.zones[dstIdx]
+
+	// The object might have been overwritten while it was draining.
+	if dstInfo, err := dst.GetObjectInfo(ctx, bucket, object, ObjectOptions{}); err == nil && !dstInfo.ModTime.Before(objInfo.ModTime) {
+		return 0, src.DeleteObject(ctx, bucket, object)
+	}
+
+	metadata := make(map[string]string, len(objInfo.UserDefined)+1)
+	for k, v := range objInfo.UserDefined {
+		metadata[k] = v
+	}
+	if !objInfo.Expires.IsZero() {
+		metadata["expires"] = objInfo.Expires.Format(http.TimeFormat)
+	}
+	opts := ObjectOptions{
+		UserDefined:  metadata,
+		PreserveETag: objInfo.ETag,
+		MTime:        objInfo.ModTime,
+	}
+
+	if len(objInfo.Parts) > 1 {
+		uploadID, err := dst.NewMultipartUpload(ctx, bucket, object, ObjectOptions{UserDefined: metadata})
+		if err != nil {
+			return 0, err
+		}
+		var offset int64
+		var parts []CompletePart
+		for _, part := range objInfo.Parts {
+			hr, pr, err := newZoneObjectReader(ctx, src, bucket, object, offset, part.Size, part.ActualSize)
+			if err != nil {
+				dst.AbortMultipartUpload(ctx, bucket, object, uploadID)
+				return 0, err
+			}
+			_, err = dst.PutObjectPart(ctx, bucket, object, uploadID, part.Number, NewPutObjReader(hr, nil, nil), ObjectOptions{PreserveETag: part.ETag})
+			pr.Close()
+			if err != nil {
+				dst.AbortMultipartUpload(ctx, bucket, object, uploadID)
+				return 0, err
+			}
+			parts = append(parts, CompletePart{PartNumber: part.Number, ETag: part.ETag})
+			offset += part.Size
+		}
+		if _, err = dst.CompleteMultipartUpload(ctx, bucket, object, uploadID, parts, opts); err != nil {
+			dst.AbortMultipartUpload(ctx, bucket, object, uploadID)
+			return 0, err
+		}
+	} else {
+		actualSize := objInfo.Size
+		if len(objInfo.Parts) == 1 {
+			actualSize = objInfo.Parts[0].ActualSize
+		}
+		hr, pr, err := newZoneObjectReader(ctx, src, bucket, object, 0, objInfo.Size, actualSize)
+		if err != nil {
+			return 0, err
+		}
+		_, err = dst.PutObject(ctx, bucket, object, NewPutObjReader(hr, nil, nil), opts)
+		pr.Close()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err = src.DeleteObject(ctx, bucket, object); err != nil && !isErrObjectNotFound(err) {
+		return 0, err
+	}
+	return objInfo.Size, nil
+}
+
+// Returns the zone holding an ongoing multipart upload.
+func (z *xlZones) getMultipartZoneIdx(ctx context.Context, bucket, object, uploadID string) (int, error) {
+	for index, zone := range z.zones {
+		_, err := zone.ListObjectParts(ctx, bucket, object, uploadID, 0, 1, ObjectOptions{})
+		if err == nil {
+			return index, nil
+		}
+		if _, ok := err.(InvalidUploadID); !ok {
+			return -1, err
+		}
+	}
+	return -1, InvalidUploadID{UploadID: uploadID}
+}
+
+// StorageInfo - combines output of StorageInfo across all zones.
+func (z *xlZones) StorageInfo(ctx context.Context) StorageInfo {
+	var storageInfo StorageInfo
+	var wg sync.WaitGroup
+
+	storageInfos := make([]StorageInfo, len(z.zones))
+	for index, zone := range z.zones {
+		wg.Add(1)
+		go func(index int, zone *xlSets) {
+			defer wg.Done()
+			storageInfos[index] = zone.StorageInfo(ctx)
+		}(index, zone)
+	}
+	// Wait for the go routines.
+	wg.Wait()
+
+	storageInfo.Backend.Type = BackendErasure
+	for _, lstorageInfo := range storageInfos {
+		storageInfo.Used += lstorageInfo.Used
+		storageInfo.Total += lstorageInfo.Total
+		storageInfo.Available += lstorageInfo.Available
+		storageInfo.Backend.OnlineDisks += lstorageInfo.Backend.OnlineDisks
+		storageInfo.Backend.OfflineDisks += lstorageInfo.Backend.OfflineDisks
+		storageInfo.Backend.Sets = append(storageInfo.Backend.Sets, lstorageInfo.Backend.Sets...)
+	}
+
+	// Redundancy is reported for the first zone.
+	storageInfo.Backend.StandardSCData = storageInfos[0].Backend.StandardSCData
+	storageInfo.Backend.StandardSCParity = storageInfos[0].Backend.StandardSCParity
+	storageInfo.Backend.RRSCData = storageInfos[0].Backend.RRSCData
+	storageInfo.Backend.RRSCParity = storageInfos[0].Backend.RRSCParity
+
+	return storageInfo
+}
+
+// Shutdown shutsdown all zones in parallel
+// returns error upon first error.
+func (z *xlZones) Shutdown(ctx context.Context) error {
+	g := errgroup.WithNErrs(len(z.zones))
+
+	for index := range z.zones {
+		index := index
+		g.Go(func() error {
+			return z.zones[index].Shutdown(ctx)
+		}, index)
+	}
+
+	for _, err := range g.Wait() {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MakeBucketWithLocation - creates a new bucket across all zones
+// simultaneously, if one of the zones fails to create the bucket
+// the successful operations are undone.
+func (z *xlZones) MakeBucketWithLocation(ctx context.Context, bucket, location string) error {
+	g := errgroup.WithNErrs(len(z.zones))
+
+	// Create buckets in parallel across all zones.
+	for index := range z.zones {
+		index := index
+		g.Go(func() error {
+			return z.zones[index].MakeBucketWithLocation(ctx, bucket, location)
+		}, index)
+	}
+
+	errs := g.Wait()
+	for _, err := range errs {
+		if err != nil {
+			undoMakeBucketZones(bucket, z.zones, errs)
+			return err
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// This function is used to undo a successful MakeBucket operation.
+func undoMakeBucketZones(bucket string, zones []*xlSets, errs []error) {
+	g := errgroup.WithNErrs(len(zones))
+
+	// Undo previous make bucket entry on all underlying zones.
+	for index := range zones {
+		index := index
+		if errs[index] == nil {
+			g.Go(func() error {
+				return zones[index].DeleteBucket(context.Background(), bucket)
+			}, index)
+		}
+	}
+
+	// Wait for all delete bucket to finish.
+	g.Wait()
+}
+
+// GetBucketInfo - returns bucket info from the first zone, buckets
+// are present on all zones.
+func (z *xlZones) GetBucketInfo(ctx context.Context, bucket string) (bucketInfo BucketInfo, err error) {
+	return z.zones[0].GetBucketInfo(ctx, bucket)
+}
+
+// ListBuckets - lists buckets from the first zone, buckets are
+// present on all zones.
+func (z *xlZones) ListBuckets(ctx context.Context) (buckets []BucketInfo, err error) {
+	return z.zones[0].ListBuckets(ctx)
+}
+
+// DeleteBucket - deletes a bucket on all zones, any failure undoes
+// the successful deletes by creating the bucket again.
+func (z *xlZones) DeleteBucket(ctx context.Context, bucket string) error {
+	// Make sure the bucket is empty on every zone before deleting.
+	for _, zone := range z.zones {
+		empty, err := isBucketEmpty(ctx, zone, bucket)
+		if err != nil {
+			return err
+		}
+		if !empty {
+			return BucketNotEmpty{Bucket: bucket}
+		}
+	}
+
+	g := errgroup.WithNErrs(len(z.zones))
+
+	// Delete buckets in parallel across all zones.
+	for index := range z.zones {
+		index := index
+		g.Go(func() error {
+			return z.zones[index].DeleteBucket(ctx, bucket)
+		}, index)
+	}
+
+	errs := g.Wait()
+	for _, err := range errs {
+		if err != nil {
+			undoDeleteBucketZones(bucket, z.zones, errs)
+			return err
+		}
+	}
+
+	// Success.
+	return nil
+}
+
+// This function is used to undo a successful DeleteBucket operation.
+func undoDeleteBucketZones(bucket string, zones []*xlSets, errs []error) {
+	g := errgroup.WithNErrs(len(zones))
+
+	// Undo previous delete bucket on all underlying zones.
+	for index := range zones {
+		index := index
+		if errs[index] == nil {
+			g.Go(func() error {
+				return zones[index].MakeBucketWithLocation(context.Background(), bucket, "")
+			}, index)
+		}
+	}
+
+	g.Wait()
+}
+
+// Returns true if the bucket has no objects on the given zone.
+func isBucketEmpty(ctx context.Context, zone *xlSets, bucket string) (bool, error) {
+	loi, err := zone.ListObjects(ctx, bucket, "", "", "", 1)
+	if err != nil {
+		return false, err
+	}
+	return len(loi.Objects) == 0 && len(loi.Prefixes) == 0, nil
+}
+
+// --- Object Operations ---
+
+// GetObjectNInfo - returns object info and locked object ReadCloser
+// from the zone holding the object.
+func (z *xlZones) GetObjectNInfo(ctx context.Context, bucket, object string, rs *HTTPRangeSpec, h http.Header, lockType LockType, opts ObjectOptions) (gr *GetObjectReader, err error) {
+	for _, zone := range z.zones {
+		gr, err = zone.GetObjectNInfo(ctx, bucket, object, rs, h, lockType, opts)
+		if err != nil {
+			if isErrObjectNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		return gr, nil
+	}
+	return nil, ObjectNotFound{Bucket: bucket, Object: object}
+}
+
+// GetObject - reads an object from the zone holding the object.
+func (z *xlZones) GetObject(ctx context.Context, bucket, object string, startOffset int64, length int64, writer io.Writer, etag string, opts ObjectOptions) error {
+	for _, zone := range z.zones {
+		// Stat first, the writer can not be rewound after a failed read.
+		if _, err := zone.GetObjectInfo(ctx, bucket, object, opts); err != nil {
+			if isErrObjectNotFound(err) {
+				continue
+			}
+			return err
+		}
+		return zone.GetObject(ctx, bucket, object, startOffset, length, writer, etag, opts)
+	}
+	return ObjectNotFound{Bucket: bucket, Object: object}
+}
+
+// GetObjectInfo - reads object metadata from the zone holding the object.
+func (z *xlZones) GetObjectInfo(ctx context.Context, bucket, object string, opts ObjectOptions) (objInfo ObjectInfo, err error) {
+	for _, zone := range z.zones {
+		objInfo, err = zone.GetObjectInfo(ctx, bucket, object, opts)
+		if err != nil {
+			if isErrObjectNotFound(err) {
+				continue
+			}
+			return objInfo, err
+		}
+		return objInfo, nil
+	}
+	return objInfo, ObjectNotFound{Bucket: bucket, Object: object}
+}
+
+// PutObject - writes an object to the zone holding the object,
+// or to the zone with the most available space for new objects.
+func (z *xlZones) PutObject(ctx context.Context, bucket string, object string, data *PutObjReader, opts ObjectOptions) (ObjectInfo, error) {
+	zoneLock := z.newZoneLock(ctx, bucket, object)
+	if err := zoneLock.GetLock(globalObjectTimeout); err != nil {
+		return ObjectInfo{}, err
+	}
+	defer zoneLock.Unlock()
+
+	zoneIdx, err := z.getZoneIdx(ctx, bucket, object)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	objInfo, err := z.zones[zoneIdx].PutObject(ctx, bucket, object, data, opts)
+	if err != nil {
+		return objInfo, err
+	}
+	z.deleteDrainingCopies(ctx, bucket, object, zoneIdx)
+	return objInfo, nil
+}
+
+// DeleteObject - deletes an object from all zones, an object might
+// have been left on several zones, e.g. by uploads completed on
+// different zones.
+func (z *xlZones) DeleteObject(ctx context.Context, bucket string, object string) (err error) {
+	zoneLock := z.newZoneLock(ctx, bucket, object)
+	if err = zoneLock.GetLock(globalObjectTimeout); err != nil {
+		return err
+	}
+	defer zoneLock.Unlock()
+
+	var deleted bool
+	for _, zone := range z.zones {
+		err = zone.DeleteObject(ctx, bucket, object)
+		if err != nil {
+			if isErrObjectNotFound(err) {
+				continue
+			}
+			return err
+		}
+		deleted = true
+	}
+	if !deleted {
+		return ObjectNotFound{Bucket: bucket, Object: object}
+	}
+	return nil
+}
+
+// DeleteObjects - bulk delete of objects on all zones, an object
+// is reported as deleted if it was deleted on any of the zones. Like
+// the erasure sets, the objects are split into smaller bulks if some
+// object names are duplicated, to avoid locking an object twice.
+func (z *xlZones) DeleteObjects(ctx context.Context, bucket string, objects []string) ([]error, error) {
+	delErrs := make([]error, 0, len(objects))
+
+	start := 0
+	objectNames := make(map[string]struct{})
+	for i, object := range objects {
+		if _, ok := objectNames[object]; ok {
+			errs, err := z.deleteObjects(ctx, bucket, objects[start:i])
+			if err != nil {
+				return nil, err
+			}
+			delErrs = append(delErrs, errs...)
+			start = i
+			objectNames = make(map[string]struct{})
+		}
+		objectNames[object] = struct{}{}
+	}
+	errs, err := z.deleteObjects(ctx, bucket, objects[start:])
+	if err != nil {
+		return nil, err
+	}
+	return append(delErrs, errs...), nil
+}
+
+// Deletes a bulk of distinct objects on all zones, each zone deletes
+// them in bulks per erasure set.
+func (z *xlZones) deleteObjects(ctx context.Context, bucket string, objects []string) ([]error, error) {
+	delErrs := make([]error, len(objects))
+	deleted := make([]bool, len(objects))
+
+	// Objects are not deleted while they are moved.
+	var lockedObjects []string
+	var lockedIndexes []int
+	for i, object := range objects {
+		zoneLock := z.newZoneLock(ctx, bucket, object)
+		if delErrs[i] = zoneLock.GetLock(globalOperationTimeout); delErrs[i] != nil {
+			continue
+		}
+		defer zoneLock.Unlock()
+		lockedObjects = append(lockedObjects, object)
+		lockedIndexes = append(lockedIndexes, i)
+	}
+	if len(lockedObjects) == 0 {
+		return delErrs, nil
+	}
+
+	for _, zone := range z.zones {
+		errs, err := zone.DeleteObjects(ctx, bucket, lockedObjects)
+		if err != nil {
+			return nil, err
+		}
+		for j, derr := range errs {
+			i := lockedIndexes[j]
+			if deleted[i] {
+				continue
+			}
+			if derr == nil {
+				deleted[i] = true
+				delErrs[i] = nil
+				continue
+			}
+			// Keep the most relevant error, not found is
+			// only reported if no zone had the object.
+			if delErrs[i] == nil || isErrObjectNotFound(delErrs[i]) {
+				delErrs[i] = derr
+			}
+		}
+	}
+	return delErrs, nil
+}
+
+// CopyObject - copies objects across zones, metadata only updates
+// are done in place on the zone holding the object.
+func (z *xlZones) CopyObject(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, srcInfo ObjectInfo, srcOpts, dstOpts ObjectOptions) (objInfo ObjectInfo, err error) {
+	zoneLock := z.newZoneLock(ctx, destBucket, destObject)
+	if err = zoneLock.GetLock(globalObjectTimeout); err != nil {
+		return objInfo, err
+	}
+	defer zoneLock.Unlock()
+
+	// Check if this request is only metadata update.
+	cpSrcDstSame := isStringEqual(pathJoin(srcBucket, srcObject), pathJoin(destBucket, destObject))
+	if cpSrcDstSame && srcInfo.metadataOnly {
+		for index, zone := range z.zones {
+			// Metadata of objects on a draining zone is updated by
+			// copying the object to another zone.
+			if z.isDraining(index, destObject) {
+				continue
+			}
+			objInfo, err = zone.CopyObject(ctx, srcBucket, srcObject, destBucket, destObject, srcInfo, srcOpts, dstOpts)
+			if err != nil {
+				if isErrObjectNotFound(err) {
+					continue
+				}
+				return objInfo, err
+			}
+			return objInfo, nil
+		}
+		srcInfo.metadataOnly = false
+	}
+
+	zoneIdx, err := z.getZoneIdx(ctx, destBucket, destObject)
+	if err != nil {
+		return objInfo, err
+	}
+	objInfo, err = z.zones[zoneIdx].CopyObject(ctx, srcBucket, srcObject, destBucket, destObject, srcInfo, srcOpts, dstOpts)
+	if err != nil {
+		return objInfo, err
+	}
+	z.deleteDrainingCopies(ctx, destBucket, destObject, zoneIdx)
+	return objInfo, nil
+}
+
+// Merges listings of all zones into a single sorted listing of at most
+// maxKeys entries, every zone listing is itself sorted and limited to
+// maxKeys entries.
+func mergeZonesListObjects(lois []ListObjectsInfo, maxKeys int) (loi ListObjectsInfo) {
+	type listEntry struct {
+		name   string
+		prefix bool
+		object ObjectInfo
+	}
+
+	var truncated bool
+	var entries []listEntry
+	seen := make(map[string]struct{})
+	for _, zloi := range lois {
+		truncated = truncated || zloi.IsTruncated
+		for _, objInfo := range zloi.Objects {
+			if _, ok := seen[objInfo.Name]; ok {
+				continue
+			}
+			seen[objInfo.Name] = struct{}{}
+			entries = append(entries, listEntry{name: objInfo.Name, object: objInfo})
+		}
+		for _, prefix := range zloi.Prefixes {
+			if _, ok := seen[prefix]; ok {
+				continue
+			}
+			seen[prefix] = struct{}{}
+			entries = append(entries, listEntry{name: prefix, prefix: true})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+
+	if len(entries) > maxKeys {
+		entries = entries[:maxKeys]
+		truncated = true
+	}
+
+	for _, entry := range entries {
+		if entry.prefix {
+			loi.Prefixes = append(loi.Prefixes, entry.name)
+			continue
+		}
+		loi.Objects = append(loi.Objects, entry.object)
+	}
+
+	loi.IsTruncated = truncated && len(entries) > 0
+	if loi.IsTruncated {
+		loi.NextMarker = entries[len(entries)-1].name
+	}
+	return loi
+}
+
+func (z *xlZones) listObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int, heal bool) (ListObjectsInfo, error) {
+	// With max keys of zero we have reached eof, return right here.
+	if maxKeys == 0 {
+		return ListObjectsInfo{}, nil
+	}
+
+	// Over flowing count - reset to maxObjectList.
+	if maxKeys < 0 || maxKeys > maxObjectList {
+		maxKeys = maxObjectList
+	}
+
+	g := errgroup.WithNErrs(len(z.zones))
+
+	lois := make([]ListObjectsInfo, len(z.zones))
+	for index := range z.zones {
+		index := index
+		g.Go(func() (err error) {
+			if heal {
+				lois[index], err = z.zones[index].ListObjectsHeal(ctx, bucket, prefix, marker, delimiter, maxKeys)
+			} else {
+				lois[index], err = z.zones[index].ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+			}
+			return err
+		}, index)
+	}
+
+	for _, err := range g.Wait() {
+		if err != nil {
+			return ListObjectsInfo{}, err
+		}
+	}
+
+	return mergeZonesListObjects(lois, maxKeys), nil
+}
+
+// ListObjects - lists objects on all zones and merges the result
+// in lexically sorted order.
+func (z *xlZones) ListObjects(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	return z.listObjects(ctx, bucket, prefix, marker, delimiter, maxKeys, false)
+}
+
+// ListObjectsV2 lists all objects in bucket filtered by prefix
+func (z *xlZones) ListObjectsV2(ctx context.Context, bucket, prefix, continuationToken, delimiter string, maxKeys int, fetchOwner bool, startAfter string) (result ListObjectsV2Info, err error) {
+	marker := continuationToken
+	if marker == "" {
+		marker = startAfter
+	}
+
+	loi, err := z.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+	if err != nil {
+		return result, err
+	}
+
+	listObjectsV2Info := ListObjectsV2Info{
+		IsTruncated:           loi.IsTruncated,
+		ContinuationToken:     continuationToken,
+		NextContinuationToken: loi.NextMarker,
+		Objects:               loi.Objects,
+		Prefixes:              loi.Prefixes,
+	}
+	return listObjectsV2Info, err
+}
+
+// Merges the upload listings of all zones into a single listing of at
+// most maxUploads uploads after the markers, sorted by key and upload ID.
+func mergeZonesListMultipartUploads(results []ListMultipartsInfo, keyMarker, uploadIDMarker string, maxUploads int) (result ListMultipartsInfo) {
+	var truncated bool
+	for _, zresult := range results {
+		truncated = truncated || zresult.IsTruncated
+		for _, upload := range zresult.Uploads {
+			// Not all zones skip the uploads up to the markers.
+			if keyMarker != "" {
+				if upload.Object < keyMarker {
+					continue
+				}
+				if upload.Object == keyMarker && (uploadIDMarker == "" || upload.UploadID <= uploadIDMarker) {
+					continue
+				}
+			}
+			result.Uploads = append(result.Uploads, upload)
+		}
+	}
+
+	sort.Slice(result.Uploads, func(i, j int) bool {
+		if result.Uploads[i].Object != result.Uploads[j].Object {
+			return result.Uploads[i].Object < result.Uploads[j].Object
+		}
+		return result.Uploads[i].UploadID < result.Uploads[j].UploadID
+	})
+
+	if len(result.Uploads) > maxUploads {
+		result.Uploads = result.Uploads[:maxUploads]
+		truncated = true
+	}
+
+	result.IsTruncated = truncated && len(result.Uploads) > 0
+	if result.IsTruncated {
+		last := result.Uploads[len(result.Uploads)-1]
+		result.NextKeyMarker = last.Object
+		result.NextUploadIDMarker = last.UploadID
+	}
+	return result
+}
+
+// ListMultipartUploads - lists ongoing uploads of an object on all zones.
+func (z *xlZones) ListMultipartUploads(ctx context.Context, bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (ListMultipartsInfo, error) {
+	results := make([]ListMultipartsInfo, len(z.zones))
+	for index, zone := range z.zones {
+		zresult, err := zone.ListMultipartUploads(ctx, bucket, prefix, keyMarker, uploadIDMarker, delimiter, maxUploads)
+		if err != nil {
+			return ListMultipartsInfo{}, err
+		}
+		results[index] = zresult
+	}
+
+	result := mergeZonesListMultipartUploads(results, keyMarker, uploadIDMarker, maxUploads)
+	result.KeyMarker = keyMarker
+	result.UploadIDMarker = uploadIDMarker
+	result.MaxUploads = maxUploads
+	result.Prefix = prefix
+	result.Delimiter = delimiter
+	return result, nil
+}
+
+// NewMultipartUpload - initiates a new multipart upload on the zone
+// the completed object would be written to.
+func (z *xlZones) NewMultipartUpload(ctx context.Context, bucket, object string, opts ObjectOptions) (string, error) {
+	zoneIdx, err := z.getZoneIdx(ctx, bucket, object)
+	if err != nil {
+		return "", err
+	}
+	return z.zones[zoneIdx].NewMultipartUpload(ctx, bucket, object, opts)
+}
+
+// CopyObjectPart - copies a part of an object into an upload on the zone holding the upload.
+func (z *xlZones) CopyObjectPart(ctx context.Context, srcBucket, srcObject, destBucket, destObject string, uploadID string, partID int,
+	startOffset int64, length int64, srcInfo ObjectInfo, srcOpts, dstOpts ObjectOptions) (PartInfo, error) {
+	zoneIdx, err := z.getMultipartZoneIdx(ctx, destBucket, destObject, uploadID)
+	if err != nil {
+		return PartInfo{}, err
+	}
+	return z.zones[zoneIdx].CopyObjectPart(ctx, srcBucket, srcObject, destBucket, destObject, uploadID, partID,
+		startOffset, length, srcInfo, srcOpts, dstOpts)
+}
+
+// PutObjectPart - writes part of an object to the zone holding the upload.
+func (z *xlZones) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, data *PutObjReader, opts ObjectOptions) (PartInfo, error) {
+	zoneIdx, err := z.getMultipartZoneIdx(ctx, bucket, object, uploadID)
+	if err != nil {
+		return PartInfo{}, err
+	}
+	return z.zones[zoneIdx].PutObjectPart(ctx, bucket, object, uploadID, partID, data, opts)
+}
+
+// ListObjectParts - lists all uploaded parts of an upload.
+func (z *xlZones) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker int, maxParts int, opts ObjectOptions) (result ListPartsInfo, err error) {
+	for _, zone := range z.zones {
+		result, err = zone.ListObjectParts(ctx, bucket, object, uploadID, partNumberMarker, maxParts, opts)
+		if err != nil {
+			if _, ok := err.(InvalidUploadID); ok {
+				continue
+			}
+			return result, err
+		}
+		return result, nil
+	}
+	return result, InvalidUploadID{UploadID: uploadID}
+}
+
+// AbortMultipartUpload - aborts an upload on the zone holding the upload.
+func (z *xlZones) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) error {
+	zoneIdx, err := z.getMultipartZoneIdx(ctx, bucket, object, uploadID)
+	if err != nil {
+		return err
+	}
+	return z.zones[zoneIdx].AbortMultipartUpload(ctx, bucket, object, uploadID)
+}
+
+// CompleteMultipartUpload - completes an upload on the zone holding the upload.
+func (z *xlZones) CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []CompletePart, opts ObjectOptions) (ObjectInfo, error) {
+	zoneLock := z.newZoneLock(ctx, bucket, object)
+	if err := zoneLock.GetLock(globalObjectTimeout); err != nil {
+		return ObjectInfo{}, err
+	}
+	defer zoneLock.Unlock()
+
+	zoneIdx, err := z.getMultipartZoneIdx(ctx, bucket, object, uploadID)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	objInfo, err := z.zones[zoneIdx].CompleteMultipartUpload(ctx, bucket, object, uploadID, uploadedParts, opts)
+	if err != nil {
+		return objInfo, err
+	}
+	if z.isDraining(zoneIdx, object) {
+		// Uploads started before the zone was decommissioned
+		// are moved off the zone once they are complete.
+		if _, err = z.moveLockedObject(ctx, zoneIdx, bucket, object); err != nil {
+			logger.LogIf(ctx, err)
+		}
+		return objInfo, nil
+	}
+
+	// The object might have been written to another zone while the
+	// upload was in progress, the completed upload replaces it.
+	for index, zone := range z.zones {
+		if index == zoneIdx {
+			continue
+		}
+		if err = zone.DeleteObject(ctx, bucket, object); err != nil && !isErrObjectNotFound(err) {
+			logger.LogIf(ctx, err)
+		}
+	}
+	return objInfo, nil
+}
+
+// ReloadFormat - reloads `format.json` on all zones.
+func (z *xlZones) ReloadFormat(ctx context.Context, dryRun bool) error {
+	for _, zone := range z.zones {
+		if err := zone.ReloadFormat(ctx, dryRun); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HealFormat - heals `format.json` on all zones.
+func (z *xlZones) HealFormat(ctx context.Context, dryRun bool) (madmin.HealResultItem, error) {
+	r := madmin.HealResultItem{
+		Type:   madmin.HealItemMetadata,
+		Detail: "disk-format",
+	}
+
+	var healRequired bool
+	for _, zone := range z.zones {
+		result, err := zone.HealFormat(ctx, dryRun)
+		if err != nil && err != errNoHealRequired {
+			return r, err
+		}
+		if err == nil {
+			healRequired = true
+		}
+		r.DiskCount += result.DiskCount
+		r.SetCount += result.SetCount
+		r.Before.Drives = append(r.Before.Drives, result.Before.Drives...)
+		r.After.Drives = append(r.After.Drives, result.After.Drives...)
+	}
+
+	if !healRequired {
+		return r, errNoHealRequired
+	}
+	return r, nil
+}
+
+// HealBucket - heals a bucket on all zones.
+func (z *xlZones) HealBucket(ctx context.Context, bucket string, dryRun, remove bool) (madmin.HealResultItem, error) {
+	r := madmin.HealResultItem{
+		Type:   madmin.HealItemBucket,
+		Bucket: bucket,
+	}
+
+	for _, zone := range z.zones {
+		result, err := zone.HealBucket(ctx, bucket, dryRun, remove)
+		if err != nil {
+			return r, err
+		}
+		r.DiskCount += result.DiskCount
+		r.SetCount += result.SetCount
+		r.Before.Drives = append(r.Before.Drives, result.Before.Drives...)
+		r.After.Drives = append(r.After.Drives, result.After.Drives...)
+	}
+	return r, nil
+}
+
+// HealObject - heals an object on the zone holding the object.
+func (z *xlZones) HealObject(ctx context.Context, bucket, object string, dryRun, remove bool, scanMode madmin.HealScanMode) (madmin.HealResultItem, error) {
+	for _, zone := range z.zones {
+		// Healing a missing object reports it as dangling, look
+		// for the zone which has the object first.
+		if _, err := zone.GetObjectInfo(ctx, bucket, object, ObjectOptions{}); isErrObjectNotFound(err) {
+			continue
+		}
+		return zone.HealObject(ctx, bucket, object, dryRun, remove, scanMode)
+	}
+	return z.zones[0].HealObject(ctx, bucket, object, dryRun, remove, scanMode)
+}
+
+// HealObjects - heals all objects recursively at a specified prefix on all zones.
+func (z *xlZones) HealObjects(ctx context.Context, bucket, prefix string, healObjectFn func(string, string) error) error {
+	for _, zone := range z.zones {
+		if err := zone.HealObjects(ctx, bucket, prefix, healObjectFn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListBucketsHeal - lists all buckets which need healing on all zones.
+func (z *xlZones) ListBucketsHeal(ctx context.Context) ([]BucketInfo, error) {
+	listBuckets := []BucketInfo{}
+	var healBuckets = map[string]BucketInfo{}
+	for _, zone := range z.zones {
+		buckets, err := zone.ListBucketsHeal(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, bucketInfo := range buckets {
+			healBuckets[bucketInfo.Name] = bucketInfo
+		}
+	}
+	for _, bucketInfo := range healBuckets {
+		listBuckets = append(listBuckets, bucketInfo)
+	}
+	return listBuckets, nil
+}
+
+// ListObjectsHeal - lists objects for healing on all zones.
+func (z *xlZones) ListObjectsHeal(ctx context.Context, bucket, prefix, marker, delimiter string, maxKeys int) (ListObjectsInfo, error) {
+	return z.listObjects(ctx, bucket, prefix, marker, delimiter, maxKeys, true)
+}
+
+// SetBucketPolicy persist the new policy on the bucket.
+func (z *xlZones) SetBucketPolicy(ctx context.Context, bucket string, policy *policy.Policy) error {
+	return savePolicyConfig(ctx, z, bucket, policy)
+}
+
+// GetBucketPolicy will return a policy on a bucket
+func (z *xlZones) GetBucketPolicy(ctx context.Context, bucket string) (*policy.Policy, error) {
+	return getPolicyConfig(z, bucket)
+}
+
+// DeleteBucketPolicy deletes all policies on bucket
+func (z *xlZones) DeleteBucketPolicy(ctx context.Context, bucket string) error {
+	return removePolicyConfig(ctx, z, bucket)
+}
+
+// SetBucketLifecycle sets lifecycle on bucket
+func (z *xlZones) SetBucketLifecycle(ctx context.Context, bucket string, lifecycle *lifecycle.Lifecycle) error {
+	return saveLifecycleConfig(ctx, z, bucket, lifecycle)
+}
+
+// GetBucketLifecycle will get lifecycle on bucket
+func (z *xlZones) GetBucketLifecycle(ctx context.Context, bucket string) (*lifecycle.Lifecycle, error) {
+	return getLifecycleConfig(z, bucket)
+}
+
+// DeleteBucketLifecycle deletes all lifecycle on bucket
+func (z *xlZones) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
+	return removeLifecycleConfig(ctx, z, bucket)
+}
+
+// IsNotificationSupported returns whether bucket notification is applicable for this layer.
+func (z *xlZones) IsNotificationSupported() bool {
+	return z.zones[0].IsNotificationSupported()
+}
+
+// IsListenBucketSupported returns whether listen bucket notification is applicable for this layer.
+func (z *xlZones) IsListenBucketSupported() bool {
+	return true
+}
+
+// IsEncryptionSupported returns whether server side encryption is implemented for this layer.
+func (z *xlZones) IsEncryptionSupported() bool {
+	return z.zones[0].IsEncryptionSupported()
+}
+
+// IsCompressionSupported returns whether compression is applicable for this layer.
+func (z *xlZones) IsCompressionSupported() bool {
+	return z.zones[0].IsCompressionSupported()
+}
@@ -0,0 +1,277 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Initializes two zones of four disks each.
+func prepareXLZones(t *testing.T) (*xlZones, []string) {
+	var fsDirs []string
+	var endpointZones EndpointZones
+	for i := 0; i < 2; i++ {
+		disks, err := getRandomDisks(4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsDirs = append(fsDirs, disks...)
+		endpointZones = append(endpointZones, ZoneEndpoints{
+			SetCount:     1,
+			DrivesPerSet: 4,
+			Endpoints:    mustGetNewEndpointList(disks...),
+		})
+	}
+
+	objLayer, err := newXLZones(endpointZones)
+	if err != nil {
+		removeRoots(fsDirs)
+		t.Fatal(err)
+	}
+	z, ok := objLayer.(*xlZones)
+	if !ok {
+		removeRoots(fsDirs)
+		t.Fatalf("Unexpected object layer %T", objLayer)
+	}
+	return z, fsDirs
+}
+
+func TestXLZones(t *testing.T) {
+	z, fsDirs := prepareXLZones(t)
+	defer removeRoots(fsDirs)
+
+	if z.zones[0].format.ID != z.zones[1].format.ID {
+		t.Fatalf("Expected zones to share the deployment ID, got %s and %s", z.zones[0].format.ID, z.zones[1].format.ID)
+	}
+
+	ctx := context.Background()
+	bucket := "bucket"
+	if err := z.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, zone := range z.zones {
+		if _, err := zone.GetBucketInfo(ctx, bucket); err != nil {
+			t.Fatalf("Expected bucket on all zones, %s", err)
+		}
+	}
+
+	data := []byte("hello")
+	// Place an object directly on the second zone, overwrites
+	// have to stay on the zone holding the object.
+	if _, err := z.zones[1].PutObject(ctx, bucket, "zone1", mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.PutObject(ctx, bucket, "zone1", mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.zones[0].GetObjectInfo(ctx, bucket, "zone1", ObjectOptions{}); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected object to stay on its zone, got %v", err)
+	}
+
+	// Writes wait for the zone lock of the object, e.g. held by a move.
+	zoneLock := z.newZoneLock(ctx, bucket, "zone1")
+	if err := zoneLock.GetLock(globalObjectTimeout); err != nil {
+		t.Fatal(err)
+	}
+	putErrCh := make(chan error, 1)
+	reader := mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", "")
+	go func() {
+		_, err := z.PutObject(ctx, bucket, "zone1", reader, ObjectOptions{})
+		putErrCh <- err
+	}()
+	select {
+	case <-putErrCh:
+		t.Fatal("Expected the write to wait for the zone lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+	zoneLock.Unlock()
+	if err := <-putErrCh; err != nil {
+		t.Fatal(err)
+	}
+
+	// Copies left on several zones are all deleted.
+	for _, zone := range z.zones {
+		if _, err := zone.PutObject(ctx, bucket, "copies", mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := z.DeleteObject(ctx, bucket, "copies"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := z.GetObjectInfo(ctx, bucket, "copies", ObjectOptions{}); !isErrObjectNotFound(err) {
+		t.Fatalf("Expected all copies to be deleted, got %v", err)
+	}
+
+	var objects []string
+	for i := 0; i < 10; i++ {
+		object := fmt.Sprintf("object%d", i)
+		if _, err := z.PutObject(ctx, bucket, object, mustGetPutObjReader(t, bytes.NewReader(data), int64(len(data)), "", ""), ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, object)
+	}
+	objects = append(objects, "zone1")
+
+	for _, object := range objects {
+		objInfo, err := z.GetObjectInfo(ctx, bucket, object, ObjectOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if objInfo.Size != int64(len(data)) {
+			t.Fatalf("Expected size %d, got %d", len(data), objInfo.Size)
+		}
+		var buf bytes.Buffer
+		if err = z.GetObject(ctx, bucket, object, 0, objInfo.Size, &buf, "", ObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), data) {
+			t.Fatalf("Expected %s, got %s", data, buf.Bytes())
+		}
+	}
+
+	loi, err := z.ListObjects(ctx, bucket, "", "", "", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loi.IsTruncated || len(loi.Objects) != 4 || loi.NextMarker != "object3" {
+		t.Fatalf("Unexpected listing %v", loi)
+	}
+	loi, err = z.ListObjects(ctx, bucket, "", loi.NextMarker, "", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loi.IsTruncated || len(loi.Objects) != len(objects)-4 {
+		t.Fatalf("Unexpected listing %v", loi)
+	}
+
+	if err = z.DeleteBucket(ctx, bucket); err == nil {
+		t.Fatal("Expected non empty bucket deletion to fail")
+	}
+	for _, zone := range z.zones {
+		if _, err = zone.GetBucketInfo(ctx, bucket); err != nil {
+			t.Fatalf("Expected bucket on all zones, %s", err)
+		}
+	}
+
+	// Duplicated names are deleted once, the later one is not found.
+	errs, err := z.DeleteObjects(ctx, bucket, append(objects, "missing", objects[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != len(objects)+2 {
+		t.Fatalf("Expected %d errors, got %d", len(objects)+2, len(errs))
+	}
+	for i, derr := range errs[:len(objects)] {
+		if derr != nil {
+			t.Fatalf("Unable to delete %s, %s", objects[i], derr)
+		}
+	}
+	for _, derr := range errs[len(objects):] {
+		if !isErrObjectNotFound(derr) {
+			t.Fatalf("Expected object not found, got %v", derr)
+		}
+	}
+	if err = z.DeleteBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXLZonesAvailable(t *testing.T) {
+	z, fsDirs := prepareXLZones(t)
+	defer removeRoots(fsDirs)
+
+	// The available space is read once, afterwards the refreshed
+	// value is used.
+	ctx := context.Background()
+	if available := z.getAvailable(ctx); len(available) != 2 || available[0] == 0 || available[1] == 0 {
+		t.Fatalf("Unexpected available space %v", available)
+	}
+	z.availableMu.Lock()
+	z.available = []uint64{1, 2}
+	z.availableMu.Unlock()
+	if zoneIdx := z.getAvailableZoneIdx(ctx, "object"); zoneIdx != 1 {
+		t.Fatalf("Expected zone 1, got %d", zoneIdx)
+	}
+	z.refreshAvailable(ctx)
+	if available := z.getAvailable(ctx); available[0] <= 2 {
+		t.Fatalf("Expected the available space to be refreshed, got %v", available)
+	}
+}
+
+func TestMergeZonesListObjects(t *testing.T) {
+	lois := []ListObjectsInfo{
+		{
+			IsTruncated: true,
+			Objects:     []ObjectInfo{{Name: "a"}, {Name: "c"}},
+			Prefixes:    []string{"d/"},
+		},
+		{
+			Objects:  []ObjectInfo{{Name: "b"}},
+			Prefixes: []string{"d/"},
+		},
+	}
+
+	loi := mergeZonesListObjects(lois, 3)
+	if !loi.IsTruncated || loi.NextMarker != "c" {
+		t.Fatalf("Expected truncated listing at c, got %v", loi)
+	}
+	var names []string
+	for _, objInfo := range loi.Objects {
+		names = append(names, objInfo.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"a", "b", "c"}) {
+		t.Fatalf("Unexpected objects %v", names)
+	}
+
+	loi = mergeZonesListObjects(lois, 10)
+	if !loi.IsTruncated || loi.NextMarker != "d/" || !reflect.DeepEqual(loi.Prefixes, []string{"d/"}) {
+		t.Fatalf("Unexpected listing %v", loi)
+	}
+}
+
+func TestMergeZonesListMultipartUploads(t *testing.T) {
+	results := []ListMultipartsInfo{
+		{Uploads: []MultipartInfo{{Object: "a", UploadID: "3"}, {Object: "b", UploadID: "1"}}},
+		{Uploads: []MultipartInfo{{Object: "a", UploadID: "1"}, {Object: "a", UploadID: "2"}}},
+	}
+
+	result := mergeZonesListMultipartUploads(results, "", "", 3)
+	if !result.IsTruncated || result.NextKeyMarker != "a" || result.NextUploadIDMarker != "3" {
+		t.Fatalf("Expected truncated listing at a/3, got %v", result)
+	}
+	expected := []MultipartInfo{{Object: "a", UploadID: "1"}, {Object: "a", UploadID: "2"}, {Object: "a", UploadID: "3"}}
+	if !reflect.DeepEqual(result.Uploads, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result.Uploads)
+	}
+
+	// The next page starts after the markers.
+	result = mergeZonesListMultipartUploads(results, result.NextKeyMarker, result.NextUploadIDMarker, 3)
+	expected = []MultipartInfo{{Object: "b", UploadID: "1"}}
+	if result.IsTruncated || !reflect.DeepEqual(result.Uploads, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+
+	result = mergeZonesListMultipartUploads(results, "a", "", 10)
+	if !reflect.DeepEqual(result.Uploads, expected) {
+		t.Fatalf("Expected %v, got %v", expected, result.Uploads)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+)
+
+var (
+	errMissingTargetBucket = errors.New("TargetBucket must be specified when logging is enabled")
+	errTargetGrants        = errors.New("TargetGrants are not supported")
+)
+
+// LoggingEnabled - describes where the server access logs
+// of a bucket are delivered.
+type LoggingEnabled struct {
+	TargetBucket string    `xml:"TargetBucket"`
+	TargetPrefix string    `xml:"TargetPrefix"`
+	TargetGrants *struct{} `xml:"TargetGrants,omitempty"`
+}
+
+// Config - bucket logging status as described in
+// https://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTlogging.html
+type Config struct {
+	XMLName        xml.Name        `xml:"BucketLoggingStatus"`
+	XMLNS          string          `xml:"xmlns,attr,omitempty"`
+	LoggingEnabled *LoggingEnabled `xml:"LoggingEnabled,omitempty"`
+}
+
+// IsEnabled - returns whether server access logging is enabled.
+func (c Config) IsEnabled() bool {
+	return c.LoggingEnabled != nil
+}
+
+// Validate - validates the bucket logging status.
+func (c Config) Validate() error {
+	if c.LoggingEnabled == nil {
+		return nil
+	}
+	if c.LoggingEnabled.TargetBucket == "" {
+		return errMissingTargetBucket
+	}
+	if c.LoggingEnabled.TargetGrants != nil {
+		return errTargetGrants
+	}
+	return nil
+}
+
+// ParseConfig - parses and validates the bucket logging status XML.
+func ParseConfig(reader io.Reader) (*Config, error) {
+	var config Config
+	if err := xml.NewDecoder(reader).Decode(&config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
@@ -0,0 +1,52 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	testCases := []struct {
+		data          string
+		expectEnabled bool
+		expectErr     bool
+	}{
+		{`<BucketLoggingStatus xmlns="http://doc.s3.amazonaws.com/2006-03-01"></BucketLoggingStatus>`, false, false},
+		{`<BucketLoggingStatus><LoggingEnabled><TargetBucket>logs</TargetBucket><TargetPrefix>mybucket/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`, true, false},
+		{`<BucketLoggingStatus><LoggingEnabled><TargetPrefix>mybucket/</TargetPrefix></LoggingEnabled></BucketLoggingStatus>`, false, true},
+		{`<BucketLoggingStatus><LoggingEnabled><TargetBucket>logs</TargetBucket><TargetGrants><Grant></Grant></TargetGrants></LoggingEnabled></BucketLoggingStatus>`, false, true},
+		{`<BucketLoggingStatus><LoggingEnabled>`, false, true},
+	}
+
+	for i, testCase := range testCases {
+		config, err := ParseConfig(strings.NewReader(testCase.data))
+		if testCase.expectErr {
+			if err == nil {
+				t.Fatalf("case %v: expected error, got nil", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if config.IsEnabled() != testCase.expectEnabled {
+			t.Fatalf("case %v: expected enabled %v, got %v", i+1, testCase.expectEnabled, config.IsEnabled())
+		}
+	}
+}
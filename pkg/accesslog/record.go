@@ -0,0 +1,199 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"crypto/tls"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time format used by server access log records.
+const timeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// Record - a single server access log record, see
+// https://docs.aws.amazon.com/AmazonS3/latest/dev/LogFormat.html
+type Record struct {
+	BucketOwner      string
+	Bucket           string
+	Time             time.Time
+	RemoteIP         string
+	Requester        string
+	RequestID        string
+	Operation        string
+	Key              string
+	RequestURI       string
+	HTTPStatus       int
+	ErrorCode        string
+	BytesSent        int64
+	ObjectSize       int64
+	TotalTime        time.Duration
+	TurnAroundTime   time.Duration
+	Referrer         string
+	UserAgent        string
+	VersionID        string
+	HostID           string
+	SignatureVersion string
+	CipherSuite      string
+	AuthType         string
+	HostHeader       string
+	TLSVersion       string
+}
+
+// field - returns the value or "-" if empty.
+func field(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// quoted - returns the quoted value or "-" if empty.
+func quoted(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return `"` + strings.Replace(s, `"`, `\"`, -1) + `"`
+}
+
+// number - returns the number or "-" if not positive.
+func number(n int64) string {
+	if n <= 0 {
+		return "-"
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// escapeKey - URL encodes the object key, keeping the slashes.
+func escapeKey(key string) string {
+	return strings.Replace(url.PathEscape(key), "%2F", "/", -1)
+}
+
+// String - returns the record as a single line in the
+// S3 server access log format.
+func (r Record) String() string {
+	fields := []string{
+		field(r.BucketOwner),
+		field(r.Bucket),
+		"[" + r.Time.UTC().Format(timeFormat) + "]",
+		field(r.RemoteIP),
+		field(r.Requester),
+		field(r.RequestID),
+		field(r.Operation),
+		field(escapeKey(r.Key)),
+		quoted(r.RequestURI),
+		number(int64(r.HTTPStatus)),
+		field(r.ErrorCode),
+		number(r.BytesSent),
+		number(r.ObjectSize),
+		number(int64(r.TotalTime / time.Millisecond)),
+		number(int64(r.TurnAroundTime / time.Millisecond)),
+		quoted(r.Referrer),
+		quoted(r.UserAgent),
+		field(r.VersionID),
+		field(r.HostID),
+		field(r.SignatureVersion),
+		field(r.CipherSuite),
+		field(r.AuthType),
+		field(r.HostHeader),
+		field(r.TLSVersion),
+	}
+	return strings.Join(fields, " ")
+}
+
+// Sub-resources mapped to the resource type of the operation
+// name, checked in order.
+var subResources = []struct {
+	name     string
+	resource string
+}{
+	{"acl", "ACL"},
+	{"policy", "BUCKETPOLICY"},
+	{"lifecycle", "LIFECYCLE"},
+	{"logging", "LOGGING_STATUS"},
+	{"notification", "NOTIFICATION"},
+	{"events", "NOTIFICATION"},
+	{"location", "LOCATION"},
+	{"tagging", "TAGGING"},
+	{"versioning", "VERSIONING"},
+	{"cors", "CORS"},
+	{"website", "WEBSITE"},
+	{"delete", "MULTI_OBJECT_DELETE"},
+	{"select", "SELECT"},
+	{"uploads", "UPLOADS"},
+	{"uploadId", "UPLOAD"},
+}
+
+// Operation - returns the operation name of a request in the
+// REST.HTTP_method.resource_type form.
+func Operation(method string, query url.Values, object string) string {
+	resource := "BUCKET"
+	if object != "" {
+		resource = "OBJECT"
+	}
+	for _, sub := range subResources {
+		if _, ok := query[sub.name]; ok {
+			resource = sub.resource
+			if sub.name == "uploadId" && query.Get("partNumber") != "" {
+				resource = "PART"
+			}
+			break
+		}
+	}
+	return "REST." + strings.ToUpper(method) + "." + resource
+}
+
+// TLSVersion - returns the name of the TLS version.
+func TLSVersion(state *tls.ConnectionState) string {
+	if state == nil {
+		return ""
+	}
+	switch state.Version {
+	case tls.VersionTLS10:
+		return "TLSv1"
+	case tls.VersionTLS11:
+		return "TLSv1.1"
+	case tls.VersionTLS12:
+		return "TLSv1.2"
+	case tls.VersionTLS13:
+		return "TLSv1.3"
+	}
+	return ""
+}
+
+// Names of the cipher suites negotiated by the server.
+var cipherSuites = map[uint16]string{
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256:   "ECDHE-RSA-AES128-GCM-SHA256",
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384:   "ECDHE-RSA-AES256-GCM-SHA384",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256: "ECDHE-ECDSA-AES128-GCM-SHA256",
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384: "ECDHE-ECDSA-AES256-GCM-SHA384",
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305:    "ECDHE-RSA-CHACHA20-POLY1305",
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305:  "ECDHE-ECDSA-CHACHA20-POLY1305",
+	tls.TLS_AES_128_GCM_SHA256:                  "TLS_AES_128_GCM_SHA256",
+	tls.TLS_AES_256_GCM_SHA384:                  "TLS_AES_256_GCM_SHA384",
+	tls.TLS_CHACHA20_POLY1305_SHA256:            "TLS_CHACHA20_POLY1305_SHA256",
+}
+
+// CipherSuite - returns the name of the negotiated cipher suite.
+func CipherSuite(state *tls.ConnectionState) string {
+	if state == nil {
+		return ""
+	}
+	return cipherSuites[state.CipherSuite]
+}
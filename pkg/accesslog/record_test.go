@@ -0,0 +1,75 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package accesslog
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRecordString(t *testing.T) {
+	record := Record{
+		BucketOwner:      "02d6176db174dc93cb1b899f7c6078f08654445fe8cf1b6ce98d8855f66bdbf4",
+		Bucket:           "mybucket",
+		Time:             time.Date(2019, time.February, 6, 0, 0, 38, 0, time.UTC),
+		RemoteIP:         "192.0.2.3",
+		Requester:        "minio",
+		RequestID:        "15F0C3B4D2D8E3B6",
+		Operation:        "REST.GET.OBJECT",
+		Key:              "photos/2019/my photo.jpg",
+		RequestURI:       "GET /mybucket/photos/2019/my%20photo.jpg HTTP/1.1",
+		HTTPStatus:       200,
+		BytesSent:        2662992,
+		ObjectSize:       2662992,
+		TotalTime:        70 * time.Millisecond,
+		TurnAroundTime:   10 * time.Millisecond,
+		UserAgent:        `S3Console/0.4 "quoted"`,
+		SignatureVersion: "SigV4",
+		AuthType:         "AuthHeader",
+		HostHeader:       "localhost:9000",
+	}
+
+	expected := `02d6176db174dc93cb1b899f7c6078f08654445fe8cf1b6ce98d8855f66bdbf4 mybucket [06/Feb/2019:00:00:38 +0000] 192.0.2.3 minio 15F0C3B4D2D8E3B6 REST.GET.OBJECT photos/2019/my%20photo.jpg "GET /mybucket/photos/2019/my%20photo.jpg HTTP/1.1" 200 - 2662992 2662992 70 10 - "S3Console/0.4 \"quoted\"" - - SigV4 - AuthHeader localhost:9000 -`
+	if got := record.String(); got != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestOperation(t *testing.T) {
+	testCases := []struct {
+		method   string
+		query    url.Values
+		object   string
+		expected string
+	}{
+		{"GET", url.Values{}, "object", "REST.GET.OBJECT"},
+		{"PUT", url.Values{}, "", "REST.PUT.BUCKET"},
+		{"GET", url.Values{"acl": []string{""}}, "", "REST.GET.ACL"},
+		{"PUT", url.Values{"logging": []string{""}}, "", "REST.PUT.LOGGING_STATUS"},
+		{"POST", url.Values{"uploads": []string{""}}, "object", "REST.POST.UPLOADS"},
+		{"PUT", url.Values{"uploadId": []string{"id"}, "partNumber": []string{"1"}}, "object", "REST.PUT.PART"},
+		{"POST", url.Values{"uploadId": []string{"id"}}, "object", "REST.POST.UPLOAD"},
+		{"POST", url.Values{"delete": []string{""}}, "", "REST.POST.MULTI_OBJECT_DELETE"},
+	}
+
+	for i, testCase := range testCases {
+		if got := Operation(testCase.method, testCase.query, testCase.object); got != testCase.expected {
+			t.Fatalf("case %v: expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package acl
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio/pkg/policy"
+)
+
+// CannedACL - S3 canned access control list.
+// Refer https://docs.aws.amazon.com/AmazonS3/latest/dev/acl-overview.html#canned-acl
+type CannedACL string
+
+// Supported canned ACLs.
+const (
+	// Private - owner gets FULL_CONTROL, no one else has access rights.
+	Private CannedACL = "private"
+
+	// PublicRead - owner gets FULL_CONTROL, everyone gets READ access.
+	PublicRead CannedACL = "public-read"
+
+	// PublicReadWrite - owner gets FULL_CONTROL, everyone gets READ
+	// and WRITE access.
+	PublicReadWrite CannedACL = "public-read-write"
+
+	// AuthenticatedRead - owner gets FULL_CONTROL, authenticated
+	// users get READ access.
+	AuthenticatedRead CannedACL = "authenticated-read"
+
+	// BucketOwnerFullControl - object and bucket owner get FULL_CONTROL.
+	BucketOwnerFullControl CannedACL = "bucket-owner-full-control"
+)
+
+// Grantee group URIs.
+const (
+	AllUsersURI           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	AuthenticatedUsersURI = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+// Grant permissions.
+const (
+	PermissionFullControl = "FULL_CONTROL"
+	PermissionRead        = "READ"
+	PermissionWrite       = "WRITE"
+)
+
+// ErrUnsupportedGrants - returned when an access control policy
+// cannot be expressed as a canned ACL.
+var ErrUnsupportedGrants = errors.New("only grants equivalent to a canned ACL are supported")
+
+// IsValid - checks if the canned ACL is supported.
+func (acl CannedACL) IsValid() bool {
+	switch acl {
+	case Private, PublicRead, PublicReadWrite, AuthenticatedRead, BucketOwnerFullControl:
+		return true
+	}
+	return false
+}
+
+// Parse - parses the value of the x-amz-acl header, an empty
+// value is treated as private.
+func Parse(s string) (CannedACL, error) {
+	if s == "" {
+		return Private, nil
+	}
+	acl := CannedACL(s)
+	if !acl.IsValid() {
+		return acl, fmt.Errorf("unsupported canned ACL '%s'", s)
+	}
+	return acl, nil
+}
+
+// canRead - returns whether READ is granted to the requester.
+func (acl CannedACL) canRead(isAuthenticated bool) bool {
+	switch acl {
+	case PublicRead, PublicReadWrite:
+		return true
+	case AuthenticatedRead:
+		return isAuthenticated
+	}
+	return false
+}
+
+// IsBucketAllowed - returns whether the bucket ACL grants the action
+// to a requester who is not the owner.
+func (acl CannedACL) IsBucketAllowed(action policy.Action, isAuthenticated bool) bool {
+	switch action {
+	case policy.ListBucketAction, policy.ListBucketMultipartUploadsAction, policy.GetBucketLocationAction:
+		return acl.canRead(isAuthenticated)
+	case policy.PutObjectAction, policy.DeleteObjectAction, policy.AbortMultipartUploadAction,
+		policy.ListMultipartUploadPartsAction:
+		return acl == PublicReadWrite
+	}
+	return false
+}
+
+// IsObjectAllowed - returns whether the object ACL grants the action
+// to a requester who is not the owner.
+func (acl CannedACL) IsObjectAllowed(action policy.Action, isAuthenticated bool) bool {
+	switch action {
+	case policy.GetObjectAction:
+		return acl.canRead(isAuthenticated)
+	}
+	return false
+}
+
+// Owner - owner of the bucket or object.
+type Owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+// Grantee - grantee of a grant, either a canonical user or a group.
+type Grantee struct {
+	XMLNS       string `xml:"xmlns:xsi,attr"`
+	XMLXSI      string `xml:"xsi:type,attr"`
+	ID          string `xml:"ID,omitempty"`
+	DisplayName string `xml:"DisplayName,omitempty"`
+	URI         string `xml:"URI,omitempty"`
+}
+
+// Grant - a permission granted to a grantee.
+type Grant struct {
+	Grantee    Grantee `xml:"Grantee"`
+	Permission string  `xml:"Permission"`
+}
+
+// AccessControlPolicy - S3 access control policy.
+type AccessControlPolicy struct {
+	XMLName           xml.Name `xml:"AccessControlPolicy"`
+	Owner             Owner    `xml:"Owner"`
+	AccessControlList struct {
+		Grants []Grant `xml:"Grant"`
+	} `xml:"AccessControlList"`
+}
+
+func newGroupGrant(uri, permission string) Grant {
+	return Grant{
+		Grantee: Grantee{
+			XMLNS:  "http://www.w3.org/2001/XMLSchema-instance",
+			XMLXSI: "Group",
+			URI:    uri,
+		},
+		Permission: permission,
+	}
+}
+
+// ToPolicy - returns the access control policy granted by the canned ACL.
+func (acl CannedACL) ToPolicy(owner Owner) AccessControlPolicy {
+	acp := AccessControlPolicy{Owner: owner}
+	acp.AccessControlList.Grants = append(acp.AccessControlList.Grants, Grant{
+		Grantee: Grantee{
+			XMLNS:       "http://www.w3.org/2001/XMLSchema-instance",
+			XMLXSI:      "CanonicalUser",
+			ID:          owner.ID,
+			DisplayName: owner.DisplayName,
+		},
+		Permission: PermissionFullControl,
+	})
+
+	switch acl {
+	case PublicRead:
+		acp.AccessControlList.Grants = append(acp.AccessControlList.Grants,
+			newGroupGrant(AllUsersURI, PermissionRead))
+	case PublicReadWrite:
+		acp.AccessControlList.Grants = append(acp.AccessControlList.Grants,
+			newGroupGrant(AllUsersURI, PermissionRead),
+			newGroupGrant(AllUsersURI, PermissionWrite))
+	case AuthenticatedRead:
+		acp.AccessControlList.Grants = append(acp.AccessControlList.Grants,
+			newGroupGrant(AuthenticatedUsersURI, PermissionRead))
+	}
+	return acp
+}
+
+// ParseAccessControlPolicy - parses the access control policy XML.
+func ParseAccessControlPolicy(reader io.Reader) (AccessControlPolicy, error) {
+	var acp AccessControlPolicy
+	if err := xml.NewDecoder(reader).Decode(&acp); err != nil {
+		return acp, err
+	}
+	return acp, nil
+}
+
+// CannedACL - returns the canned ACL equivalent to the access control
+// policy, only the owner FULL_CONTROL grant and READ or WRITE grants
+// to the AllUsers and AuthenticatedUsers groups are supported.
+func (acp AccessControlPolicy) CannedACL() (CannedACL, error) {
+	var allRead, allWrite, authRead bool
+	for _, grant := range acp.AccessControlList.Grants {
+		switch {
+		case grant.Grantee.URI == "" && grant.Permission == PermissionFullControl:
+			// Owner grant.
+		case grant.Grantee.URI == AllUsersURI && grant.Permission == PermissionRead:
+			allRead = true
+		case grant.Grantee.URI == AllUsersURI && grant.Permission == PermissionWrite:
+			allWrite = true
+		case grant.Grantee.URI == AuthenticatedUsersURI && grant.Permission == PermissionRead:
+			authRead = true
+		default:
+			return "", ErrUnsupportedGrants
+		}
+	}
+
+	switch {
+	case allRead && allWrite:
+		return PublicReadWrite, nil
+	case allWrite:
+		return "", ErrUnsupportedGrants
+	case allRead:
+		return PublicRead, nil
+	case authRead:
+		return AuthenticatedRead, nil
+	}
+	return Private, nil
+}
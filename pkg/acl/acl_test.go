@@ -0,0 +1,120 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package acl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio/pkg/policy"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		value       string
+		expectedACL CannedACL
+		expectErr   bool
+	}{
+		{"", Private, false},
+		{"private", Private, false},
+		{"public-read", PublicRead, false},
+		{"public-read-write", PublicReadWrite, false},
+		{"authenticated-read", AuthenticatedRead, false},
+		{"bucket-owner-full-control", BucketOwnerFullControl, false},
+		{"log-delivery-write", "", true},
+		{"PUBLIC-READ", "", true},
+	}
+
+	for i, testCase := range testCases {
+		acl, err := Parse(testCase.value)
+		if testCase.expectErr != (err != nil) {
+			t.Fatalf("Test %d: Expected error %v, got %v", i+1, testCase.expectErr, err)
+		}
+		if err == nil && acl != testCase.expectedACL {
+			t.Fatalf("Test %d: Expected %s, got %s", i+1, testCase.expectedACL, acl)
+		}
+	}
+}
+
+func TestIsAllowed(t *testing.T) {
+	testCases := []struct {
+		acl             CannedACL
+		action          policy.Action
+		isAuthenticated bool
+		isObject        bool
+		expected        bool
+	}{
+		{Private, policy.ListBucketAction, false, false, false},
+		{PublicRead, policy.ListBucketAction, false, false, true},
+		{PublicRead, policy.PutObjectAction, false, false, false},
+		{PublicReadWrite, policy.PutObjectAction, false, false, true},
+		{PublicReadWrite, policy.DeleteBucketAction, false, false, false},
+		{AuthenticatedRead, policy.ListBucketAction, false, false, false},
+		{AuthenticatedRead, policy.ListBucketAction, true, false, true},
+		{PublicRead, policy.GetObjectAction, false, true, true},
+		{AuthenticatedRead, policy.GetObjectAction, true, true, true},
+		{AuthenticatedRead, policy.GetObjectAction, false, true, false},
+		{BucketOwnerFullControl, policy.GetObjectAction, false, true, false},
+		{PublicReadWrite, policy.PutObjectAction, false, true, false},
+	}
+
+	for i, testCase := range testCases {
+		var result bool
+		if testCase.isObject {
+			result = testCase.acl.IsObjectAllowed(testCase.action, testCase.isAuthenticated)
+		} else {
+			result = testCase.acl.IsBucketAllowed(testCase.action, testCase.isAuthenticated)
+		}
+		if result != testCase.expected {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, result)
+		}
+	}
+}
+
+func TestAccessControlPolicy(t *testing.T) {
+	owner := Owner{ID: "02d6176db174dc93cb1b899f7c6078f08654445fe8cf1b6ce98d8855f66bdbf4", DisplayName: "minio"}
+	for _, acl := range []CannedACL{Private, PublicRead, PublicReadWrite, AuthenticatedRead} {
+		data, err := xml.Marshal(acl.ToPolicy(owner))
+		if err != nil {
+			t.Fatal(err)
+		}
+		acp, err := ParseAccessControlPolicy(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := acp.CannedACL()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != acl {
+			t.Errorf("Expected %s, got %s", acl, got)
+		}
+	}
+
+	unsupported := `<AccessControlPolicy><Owner><ID>minio</ID></Owner><AccessControlList><Grant>
+<Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser"><ID>other</ID></Grantee>
+<Permission>WRITE</Permission></Grant></AccessControlList></AccessControlPolicy>`
+	acp, err := ParseAccessControlPolicy(strings.NewReader(unsupported))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = acp.CannedACL(); err != ErrUnsupportedGrants {
+		t.Fatalf("Expected %v, got %v", ErrUnsupportedGrants, err)
+	}
+}
@@ -48,7 +48,7 @@ func (arn *ARN) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		return err
 	}
 
-	parsedARN, err := parseARN(s)
+	parsedARN, err := ParseARN(s)
 	if err != nil {
 		return err
 	}
@@ -57,8 +57,8 @@ func (arn *ARN) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
-// parseARN - parses string to ARN.
-func parseARN(s string) (*ARN, error) {
+// ParseARN - parses string to ARN.
+func ParseARN(s string) (*ARN, error) {
 	// ARN must be in the format of arn:minio:sqs:<REGION>:<ID>:<TYPE>
 	if !strings.HasPrefix(s, "arn:minio:sqs:") {
 		return nil, &ErrInvalidARN{s}
@@ -113,7 +113,7 @@ func TestParseARN(t *testing.T) {
 	}
 
 	for i, testCase := range testCases {
-		arn, err := parseARN(testCase.s)
+		arn, err := ParseARN(testCase.s)
 		expectErr := (err != nil)
 
 		if expectErr != testCase.expectErr {
@@ -119,9 +119,10 @@ func (ruleList FilterRuleList) Pattern() string {
 	return NewPattern(prefix, suffix)
 }
 
-// S3Key - represents elements inside <S3Key>...</S3Key>
+// S3Key - represents elements inside <Filter>...</Filter>
 type S3Key struct {
 	RuleList FilterRuleList `xml:"S3Key,omitempty" json:"S3Key,omitempty"`
+	Object   *ObjectFilter  `xml:"Object,omitempty" json:"Object,omitempty"`
 }
 
 // common - represents common elements inside <QueueConfiguration>, <CloudFunctionConfiguration>
@@ -186,7 +187,7 @@ func (q *Queue) SetRegion(region string) {
 // ToRulesMap - converts Queue to RulesMap
 func (q Queue) ToRulesMap() RulesMap {
 	pattern := q.Filter.RuleList.Pattern()
-	return NewRulesMap(q.Events, pattern, q.ARN.TargetID)
+	return NewFilteredRulesMap(q.Events, pattern, q.Filter.Object, q.ARN.TargetID)
 }
 
 // Unused.  Available for completion.
@@ -805,12 +805,46 @@ func TestConfigToRulesMap(t *testing.T) {
 		panic(err)
 	}
 
+	data = []byte(`
+<NotificationConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+   <QueueConfiguration>
+      <Id>1</Id>
+      <Filter>
+           <S3Key>
+               <FilterRule>
+                   <Name>prefix</Name>
+                   <Value>videos/</Value>
+               </FilterRule>
+           </S3Key>
+           <Object>
+               <MinSize>104857600</MinSize>
+               <Metadata>
+                   <Name>x-amz-meta-pipeline</Name>
+                   <Value>ingest</Value>
+               </Metadata>
+           </Object>
+      </Filter>
+      <Queue>arn:minio:sqs:us-east-1:1:webhook</Queue>
+      <Event>s3:ObjectCreated:*</Event>
+   </QueueConfiguration>
+</NotificationConfiguration>
+`)
+	config4 := &Config{}
+	if err := xml.Unmarshal(data, config4); err != nil {
+		panic(err)
+	}
+
 	rulesMapCase1 := NewRulesMap([]Name{ObjectAccessedAll, ObjectCreatedAll, ObjectRemovedAll}, "*", TargetID{"1", "webhook"})
 
 	rulesMapCase2 := NewRulesMap([]Name{ObjectCreatedPut}, "images/*jpg", TargetID{"1", "webhook"})
 
 	rulesMapCase3 := NewRulesMap([]Name{ObjectAccessedAll, ObjectCreatedAll, ObjectRemovedAll}, "*", TargetID{"1", "webhook"})
-	rulesMapCase3.add([]Name{ObjectCreatedPut}, "images/*jpg", TargetID{"2", "amqp"})
+	rulesMapCase3.add([]Name{ObjectCreatedPut}, NewRule("images/*jpg", nil), TargetID{"2", "amqp"})
+
+	rulesMapCase4 := NewFilteredRulesMap([]Name{ObjectCreatedAll}, "videos/*", &ObjectFilter{
+		MinSize:  104857600,
+		Metadata: []MetadataRule{{"x-amz-meta-pipeline", "ingest"}},
+	}, TargetID{"1", "webhook"})
 
 	testCases := []struct {
 		config         *Config
@@ -819,6 +853,7 @@ func TestConfigToRulesMap(t *testing.T) {
 		{config1, rulesMapCase1},
 		{config2, rulesMapCase2},
 		{config3, rulesMapCase3},
+		{config4, rulesMapCase4},
 	}
 
 	for i, testCase := range testCases {
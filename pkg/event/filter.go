@@ -0,0 +1,191 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"encoding/xml"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio-go/v6/pkg/set"
+	"github.com/minio/minio/pkg/wildcard"
+)
+
+// MetadataRule - represents elements inside <Metadata>...</Metadata>
+type MetadataRule struct {
+	Name  string `xml:"Name" json:"Name"`
+	Value string `xml:"Value" json:"Value"`
+}
+
+// ObjectFilter - represents elements inside <Object>...</Object>, a MinIO
+// extension filtering events by the properties of the object. Content
+// type and metadata values may contain '*' wildcards, an empty metadata
+// value only requires the metadata to be present.
+type ObjectFilter struct {
+	MinSize     int64          `xml:"MinSize,omitempty" json:"MinSize,omitempty"`
+	MaxSize     int64          `xml:"MaxSize,omitempty" json:"MaxSize,omitempty"`
+	ContentType string         `xml:"ContentType,omitempty" json:"ContentType,omitempty"`
+	Metadata    []MetadataRule `xml:"Metadata,omitempty" json:"Metadata,omitempty"`
+}
+
+// UnmarshalXML - decodes XML data.
+func (filter *ObjectFilter) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	// Make subtype to avoid recursive UnmarshalXML().
+	type objectFilter ObjectFilter
+	parsedFilter := struct {
+		objectFilter
+		// Object tags are not stored, rules filtering by tags
+		// are rejected instead of never matching.
+		Tags []struct{} `xml:"Tag"`
+	}{}
+	if err := d.DecodeElement(&parsedFilter, &start); err != nil {
+		return err
+	}
+
+	if len(parsedFilter.Tags) > 0 {
+		return &ErrInvalidObjectFilter{"filtering by object tags is not supported"}
+	}
+
+	if parsedFilter.MinSize < 0 || parsedFilter.MaxSize < 0 {
+		return &ErrInvalidObjectFilter{"object size cannot be negative"}
+	}
+
+	if parsedFilter.MaxSize > 0 && parsedFilter.MinSize > parsedFilter.MaxSize {
+		return &ErrInvalidObjectFilter{"minimum object size exceeds maximum object size"}
+	}
+
+	nameSet := set.NewStringSet()
+	for _, rule := range parsedFilter.Metadata {
+		name := strings.ToLower(rule.Name)
+		if !strings.HasPrefix(name, "x-amz-meta-") || name == "x-amz-meta-" {
+			return &ErrInvalidObjectFilter{"invalid metadata name '" + rule.Name + "'"}
+		}
+
+		if nameSet.Contains(name) {
+			return &ErrInvalidObjectFilter{"more than one metadata rule for '" + rule.Name + "'"}
+		}
+
+		nameSet.Add(name)
+	}
+
+	*filter = ObjectFilter(parsedFilter.objectFilter)
+
+	return nil
+}
+
+// ObjectProperties - properties of the object of an event which are
+// matched against the object filter of a rule.
+type ObjectProperties struct {
+	Size        int64
+	ContentType string
+	// User metadata with lower case 'x-amz-meta-' prefixed names.
+	UserMetadata map[string]string
+}
+
+// Rule - object name pattern and object filter of rules.
+type Rule struct {
+	Pattern     string
+	MinSize     int64
+	MaxSize     int64
+	ContentType string
+	// Metadata rules with lower case names, sorted by name.
+	Metadata []MetadataRule
+}
+
+// ruleKey - comparable form of a rule, rules are indexed by it.
+type ruleKey struct {
+	Pattern     string
+	MinSize     int64
+	MaxSize     int64
+	ContentType string
+	// URL query encoded metadata rules.
+	Metadata string
+}
+
+func (rule Rule) key() ruleKey {
+	key := ruleKey{
+		Pattern:     rule.Pattern,
+		MinSize:     rule.MinSize,
+		MaxSize:     rule.MaxSize,
+		ContentType: rule.ContentType,
+	}
+	if len(rule.Metadata) > 0 {
+		values := make(url.Values)
+		for _, m := range rule.Metadata {
+			values.Set(m.Name, m.Value)
+		}
+		key.Metadata = values.Encode()
+	}
+	return key
+}
+
+// NewRule - creates new rule for pattern and object filter.
+func NewRule(pattern string, filter *ObjectFilter) Rule {
+	rule := Rule{Pattern: pattern}
+	if filter == nil {
+		return rule
+	}
+
+	rule.MinSize = filter.MinSize
+	rule.MaxSize = filter.MaxSize
+	rule.ContentType = filter.ContentType
+
+	if len(filter.Metadata) > 0 {
+		rule.Metadata = make([]MetadataRule, len(filter.Metadata))
+		for i, m := range filter.Metadata {
+			rule.Metadata[i] = MetadataRule{Name: strings.ToLower(m.Name), Value: m.Value}
+		}
+		sort.Slice(rule.Metadata, func(i, j int) bool {
+			return rule.Metadata[i].Name < rule.Metadata[j].Name
+		})
+	}
+
+	return rule
+}
+
+// Match - checks whether object name and object properties match the rule.
+func (rule Rule) Match(objectName string, props ObjectProperties) bool {
+	if !wildcard.MatchSimple(rule.Pattern, objectName) {
+		return false
+	}
+
+	if props.Size < rule.MinSize {
+		return false
+	}
+
+	if rule.MaxSize > 0 && props.Size > rule.MaxSize {
+		return false
+	}
+
+	if rule.ContentType != "" && !wildcard.MatchSimple(rule.ContentType, props.ContentType) {
+		return false
+	}
+
+	for _, m := range rule.Metadata {
+		value, ok := props.UserMetadata[m.Name]
+		if !ok {
+			return false
+		}
+
+		if m.Value != "" && !wildcard.MatchSimple(m.Value, value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,95 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package event
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestObjectFilterUnmarshalXML(t *testing.T) {
+	testCases := []struct {
+		data           []byte
+		expectedResult *ObjectFilter
+		expectErr      bool
+	}{
+		{[]byte(`<Object></Object>`), &ObjectFilter{}, false},
+		{[]byte(`<Object><MinSize>104857600</MinSize></Object>`), &ObjectFilter{MinSize: 104857600}, false},
+		{[]byte(`<Object><MinSize>10</MinSize><MaxSize>20</MaxSize><ContentType>video/*</ContentType></Object>`), &ObjectFilter{MinSize: 10, MaxSize: 20, ContentType: "video/*"}, false},
+		{[]byte(`<Object><Metadata><Name>x-amz-meta-pipeline</Name><Value>ingest</Value></Metadata></Object>`), &ObjectFilter{Metadata: []MetadataRule{{"x-amz-meta-pipeline", "ingest"}}}, false},
+		{[]byte(`<Object><MinSize>-1</MinSize></Object>`), nil, true},
+		{[]byte(`<Object><MinSize>20</MinSize><MaxSize>10</MaxSize></Object>`), nil, true},
+		{[]byte(`<Object><MinSize>ten</MinSize></Object>`), nil, true},
+		{[]byte(`<Object><Metadata><Name>Content-Type</Name><Value>text/plain</Value></Metadata></Object>`), nil, true},
+		{[]byte(`<Object><Metadata><Name>x-amz-meta-a</Name></Metadata><Metadata><Name>X-Amz-Meta-A</Name></Metadata></Object>`), nil, true},
+		{[]byte(`<Object><Tag><Key>project</Key><Value>alpha</Value></Tag></Object>`), nil, true},
+	}
+
+	for i, testCase := range testCases {
+		result := &ObjectFilter{}
+		err := xml.Unmarshal(testCase.data, result)
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("test %v: error: expected: %v, got: %v", i+1, testCase.expectErr, expectErr)
+		}
+
+		if !testCase.expectErr {
+			if !reflect.DeepEqual(result, testCase.expectedResult) {
+				t.Fatalf("test %v: data: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+			}
+		}
+	}
+}
+
+func TestRuleMatch(t *testing.T) {
+	props := ObjectProperties{
+		Size:         200 << 20,
+		ContentType:  "video/mp4",
+		UserMetadata: map[string]string{"x-amz-meta-pipeline": "ingest-v2"},
+	}
+
+	testCases := []struct {
+		rule           Rule
+		objectName     string
+		props          ObjectProperties
+		expectedResult bool
+	}{
+		{NewRule("*", nil), "2010/video.mp4", props, true},
+		{NewRule("2000*", nil), "2010/video.mp4", props, false},
+		{NewRule("*", &ObjectFilter{MinSize: 100 << 20}), "2010/video.mp4", props, true},
+		{NewRule("*", &ObjectFilter{MinSize: 300 << 20}), "2010/video.mp4", props, false},
+		{NewRule("*", &ObjectFilter{MaxSize: 100 << 20}), "2010/video.mp4", props, false},
+		{NewRule("*", &ObjectFilter{MinSize: 100 << 20, MaxSize: 200 << 20}), "2010/video.mp4", props, true},
+		{NewRule("*", &ObjectFilter{ContentType: "video/*"}), "2010/video.mp4", props, true},
+		{NewRule("*", &ObjectFilter{ContentType: "image/*"}), "2010/video.mp4", props, false},
+		{NewRule("*", &ObjectFilter{Metadata: []MetadataRule{{"x-amz-meta-pipeline", ""}}}), "2010/video.mp4", props, true},
+		{NewRule("*", &ObjectFilter{Metadata: []MetadataRule{{"x-amz-meta-pipeline", "ingest-*"}}}), "2010/video.mp4", props, true},
+		{NewRule("*", &ObjectFilter{Metadata: []MetadataRule{{"x-amz-meta-pipeline", "export"}}}), "2010/video.mp4", props, false},
+		{NewRule("*", &ObjectFilter{Metadata: []MetadataRule{{"x-amz-meta-owner", ""}}}), "2010/video.mp4", props, false},
+		{NewRule("*", &ObjectFilter{Metadata: []MetadataRule{{"X-Amz-Meta-Pipeline", "ingest-*"}}}), "2010/video.mp4", props, true},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.rule.Match(testCase.objectName, testCase.props)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("test %v: result: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
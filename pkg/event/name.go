@@ -37,6 +37,15 @@ const (
 	ObjectCreatedPut
 	ObjectRemovedAll
 	ObjectRemovedDelete
+	BucketCreated
+	BucketRemoved
+	LifecycleExpirationAll
+	LifecycleExpirationDelete
+	ObjectHealedAll
+	ObjectHealedRepaired
+	ObjectHealedFailed
+	MultipartUploadAll
+	MultipartUploadInitiate
 )
 
 // Expand - returns expanded values of abbreviated event type.
@@ -48,6 +57,12 @@ func (name Name) Expand() []Name {
 		return []Name{ObjectCreatedCompleteMultipartUpload, ObjectCreatedCopy, ObjectCreatedPost, ObjectCreatedPut}
 	case ObjectRemovedAll:
 		return []Name{ObjectRemovedDelete}
+	case LifecycleExpirationAll:
+		return []Name{LifecycleExpirationDelete}
+	case ObjectHealedAll:
+		return []Name{ObjectHealedRepaired, ObjectHealedFailed}
+	case MultipartUploadAll:
+		return []Name{MultipartUploadInitiate}
 	default:
 		return []Name{name}
 	}
@@ -76,11 +91,50 @@ func (name Name) String() string {
 		return "s3:ObjectRemoved:*"
 	case ObjectRemovedDelete:
 		return "s3:ObjectRemoved:Delete"
+	case BucketCreated:
+		return "s3:BucketCreated:*"
+	case BucketRemoved:
+		return "s3:BucketRemoved:*"
+	case LifecycleExpirationAll:
+		return "s3:LifecycleExpiration:*"
+	case LifecycleExpirationDelete:
+		return "s3:LifecycleExpiration:Delete"
+	case ObjectHealedAll:
+		return "s3:ObjectHealed:*"
+	case ObjectHealedRepaired:
+		return "s3:ObjectHealed:Repaired"
+	case ObjectHealedFailed:
+		return "s3:ObjectHealed:Failed"
+	case MultipartUploadAll:
+		return "s3:MultipartUpload:*"
+	case MultipartUploadInitiate:
+		return "s3:MultipartUpload:Initiate"
 	}
 
 	return ""
 }
 
+// RemovesObject - returns true if the object of an event is removed.
+func (name Name) RemovesObject() bool {
+	switch name {
+	case ObjectRemovedDelete, LifecycleExpirationDelete:
+		return true
+	}
+	return false
+}
+
+// ReflectsObject - returns true if an event carries the current state
+// of its object, targets in namespace format only store such events.
+// Bucket, heal and multipart upload events are ignored by them.
+func (name Name) ReflectsObject() bool {
+	switch name {
+	case ObjectAccessedGet, ObjectAccessedHead,
+		ObjectCreatedCompleteMultipartUpload, ObjectCreatedCopy, ObjectCreatedPost, ObjectCreatedPut:
+		return true
+	}
+	return false
+}
+
 // MarshalXML - encodes to XML data.
 func (name Name) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	return e.EncodeElement(name.String(), start)
@@ -146,6 +200,24 @@ func ParseName(s string) (Name, error) {
 		return ObjectRemovedAll, nil
 	case "s3:ObjectRemoved:Delete":
 		return ObjectRemovedDelete, nil
+	case "s3:BucketCreated:*":
+		return BucketCreated, nil
+	case "s3:BucketRemoved:*":
+		return BucketRemoved, nil
+	case "s3:LifecycleExpiration:*":
+		return LifecycleExpirationAll, nil
+	case "s3:LifecycleExpiration:Delete":
+		return LifecycleExpirationDelete, nil
+	case "s3:ObjectHealed:*":
+		return ObjectHealedAll, nil
+	case "s3:ObjectHealed:Repaired":
+		return ObjectHealedRepaired, nil
+	case "s3:ObjectHealed:Failed":
+		return ObjectHealedFailed, nil
+	case "s3:MultipartUpload:*":
+		return MultipartUploadAll, nil
+	case "s3:MultipartUpload:Initiate":
+		return MultipartUploadInitiate, nil
 	default:
 		return 0, &ErrInvalidEventName{s}
 	}
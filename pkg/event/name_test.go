@@ -32,6 +32,10 @@ func TestNameExpand(t *testing.T) {
 		{ObjectCreatedAll, []Name{ObjectCreatedCompleteMultipartUpload, ObjectCreatedCopy, ObjectCreatedPost, ObjectCreatedPut}},
 		{ObjectRemovedAll, []Name{ObjectRemovedDelete}},
 		{ObjectAccessedHead, []Name{ObjectAccessedHead}},
+		{LifecycleExpirationAll, []Name{LifecycleExpirationDelete}},
+		{ObjectHealedAll, []Name{ObjectHealedRepaired, ObjectHealedFailed}},
+		{MultipartUploadAll, []Name{MultipartUploadInitiate}},
+		{BucketCreated, []Name{BucketCreated}},
 	}
 
 	for i, testCase := range testCases {
@@ -60,6 +64,15 @@ func TestNameString(t *testing.T) {
 		{ObjectCreatedPut, "s3:ObjectCreated:Put"},
 		{ObjectRemovedAll, "s3:ObjectRemoved:*"},
 		{ObjectRemovedDelete, "s3:ObjectRemoved:Delete"},
+		{BucketCreated, "s3:BucketCreated:*"},
+		{BucketRemoved, "s3:BucketRemoved:*"},
+		{LifecycleExpirationAll, "s3:LifecycleExpiration:*"},
+		{LifecycleExpirationDelete, "s3:LifecycleExpiration:Delete"},
+		{ObjectHealedAll, "s3:ObjectHealed:*"},
+		{ObjectHealedRepaired, "s3:ObjectHealed:Repaired"},
+		{ObjectHealedFailed, "s3:ObjectHealed:Failed"},
+		{MultipartUploadAll, "s3:MultipartUpload:*"},
+		{MultipartUploadInitiate, "s3:MultipartUpload:Initiate"},
 		{blankName, ""},
 	}
 
@@ -200,6 +213,11 @@ func TestParseName(t *testing.T) {
 	}{
 		{"s3:ObjectAccessed:*", ObjectAccessedAll, false},
 		{"s3:ObjectRemoved:Delete", ObjectRemovedDelete, false},
+		{"s3:BucketRemoved:*", BucketRemoved, false},
+		{"s3:LifecycleExpiration:Delete", LifecycleExpirationDelete, false},
+		{"s3:ObjectHealed:Failed", ObjectHealedFailed, false},
+		{"s3:MultipartUpload:Initiate", MultipartUploadInitiate, false},
+		{"s3:BucketRemoved:Delete", blankName, true},
 		{"", blankName, true},
 	}
 
@@ -218,3 +236,28 @@ func TestParseName(t *testing.T) {
 		}
 	}
 }
+
+func TestNameObjectState(t *testing.T) {
+	testCases := []struct {
+		name    Name
+		removes bool
+		reflect bool
+	}{
+		{ObjectCreatedPut, false, true},
+		{ObjectAccessedGet, false, true},
+		{ObjectRemovedDelete, true, false},
+		{LifecycleExpirationDelete, true, false},
+		{BucketCreated, false, false},
+		{ObjectHealedRepaired, false, false},
+		{MultipartUploadInitiate, false, false},
+	}
+
+	for i, testCase := range testCases {
+		if removes := testCase.name.RemovesObject(); removes != testCase.removes {
+			t.Fatalf("test %v: removes: expected: %v, got: %v", i+1, testCase.removes, removes)
+		}
+		if reflect := testCase.name.ReflectsObject(); reflect != testCase.reflect {
+			t.Fatalf("test %v: reflects: expected: %v, got: %v", i+1, testCase.reflect, reflect)
+		}
+	}
+}
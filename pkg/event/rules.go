@@ -18,8 +18,6 @@ package event
 
 import (
 	"strings"
-
-	"github.com/minio/minio/pkg/wildcard"
 )
 
 // NewPattern - create new pattern for prefix/suffix.
@@ -45,21 +43,33 @@ func NewPattern(prefix, suffix string) (pattern string) {
 	return pattern
 }
 
+// ruleTargets - a rule along with the targets of its events.
+type ruleTargets struct {
+	Rule      Rule
+	TargetIDs TargetIDSet
+}
+
 // Rules - event rules
-type Rules map[string]TargetIDSet
+type Rules map[ruleKey]ruleTargets
 
 // Add - adds pattern and target ID.
 func (rules Rules) Add(pattern string, targetID TargetID) {
-	rules[pattern] = NewTargetIDSet(targetID).Union(rules[pattern])
+	rules.AddRule(NewRule(pattern, nil), targetID)
+}
+
+// AddRule - adds rule and target ID.
+func (rules Rules) AddRule(rule Rule, targetID TargetID) {
+	key := rule.key()
+	rules[key] = ruleTargets{Rule: rule, TargetIDs: NewTargetIDSet(targetID).Union(rules[key].TargetIDs)}
 }
 
-// Match - returns TargetIDSet matching object name in rules.
-func (rules Rules) Match(objectName string) TargetIDSet {
+// Match - returns TargetIDSet matching object name and object properties in rules.
+func (rules Rules) Match(objectName string, props ObjectProperties) TargetIDSet {
 	targetIDs := NewTargetIDSet()
 
-	for pattern, targetIDSet := range rules {
-		if wildcard.MatchSimple(pattern, objectName) {
-			targetIDs = targetIDs.Union(targetIDSet)
+	for _, rt := range rules {
+		if rt.Rule.Match(objectName, props) {
+			targetIDs = targetIDs.Union(rt.TargetIDs)
 		}
 	}
 
@@ -70,8 +80,8 @@ func (rules Rules) Match(objectName string) TargetIDSet {
 func (rules Rules) Clone() Rules {
 	rulesCopy := make(Rules)
 
-	for pattern, targetIDSet := range rules {
-		rulesCopy[pattern] = targetIDSet.Clone()
+	for key, rt := range rules {
+		rulesCopy[key] = ruleTargets{Rule: rt.Rule, TargetIDs: rt.TargetIDs.Clone()}
 	}
 
 	return rulesCopy
@@ -81,8 +91,8 @@ func (rules Rules) Clone() Rules {
 func (rules Rules) Union(rules2 Rules) Rules {
 	nrules := rules.Clone()
 
-	for pattern, targetIDSet := range rules2 {
-		nrules[pattern] = nrules[pattern].Union(targetIDSet)
+	for key, rt := range rules2 {
+		nrules[key] = ruleTargets{Rule: rt.Rule, TargetIDs: nrules[key].TargetIDs.Union(rt.TargetIDs)}
 	}
 
 	return nrules
@@ -92,9 +102,9 @@ func (rules Rules) Union(rules2 Rules) Rules {
 func (rules Rules) Difference(rules2 Rules) Rules {
 	nrules := make(Rules)
 
-	for pattern, targetIDSet := range rules {
-		if nv := targetIDSet.Difference(rules2[pattern]); len(nv) > 0 {
-			nrules[pattern] = nv
+	for key, rt := range rules {
+		if nv := rt.TargetIDs.Difference(rules2[key].TargetIDs); len(nv) > 0 {
+			nrules[key] = ruleTargets{Rule: rt.Rule, TargetIDs: nv}
 		}
 	}
 
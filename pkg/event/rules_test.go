@@ -124,7 +124,7 @@ func TestRulesMatch(t *testing.T) {
 	}
 
 	for i, testCase := range testCases {
-		result := testCase.rules.Match(testCase.objectName)
+		result := testCase.rules.Match(testCase.objectName, ObjectProperties{})
 
 		if !reflect.DeepEqual(testCase.expectedResult, result) {
 			t.Fatalf("test %v: result: expected: %v, got: %v", i+1, testCase.expectedResult, result)
@@ -17,6 +17,8 @@
 package event
 
 import (
+	"bytes"
+	"encoding/gob"
 	"reflect"
 	"testing"
 )
@@ -66,7 +68,7 @@ func TestRulesMapAdd(t *testing.T) {
 	rulesMapCase3 := NewRulesMap([]Name{ObjectCreatedAll}, "*", TargetID{"1", "webhook"})
 	rulesMapToAddCase3 := NewRulesMap([]Name{ObjectCreatedAll}, "2010*.jpg", TargetID{"1", "webhook"})
 	expectedResultCase3 := NewRulesMap([]Name{ObjectCreatedAll}, "2010*.jpg", TargetID{"1", "webhook"})
-	expectedResultCase3.add([]Name{ObjectCreatedAll}, "*", TargetID{"1", "webhook"})
+	expectedResultCase3.add([]Name{ObjectCreatedAll}, NewRule("*", nil), TargetID{"1", "webhook"})
 
 	testCases := []struct {
 		rulesMap       RulesMap
@@ -97,7 +99,7 @@ func TestRulesMapRemove(t *testing.T) {
 	expectedResultCase2 := make(RulesMap)
 
 	rulesMapCase3 := NewRulesMap([]Name{ObjectCreatedAll}, "2010*.jpg", TargetID{"1", "webhook"})
-	rulesMapCase3.add([]Name{ObjectCreatedAll}, "*", TargetID{"1", "webhook"})
+	rulesMapCase3.add([]Name{ObjectCreatedAll}, NewRule("*", nil), TargetID{"1", "webhook"})
 	rulesMapToAddCase3 := NewRulesMap([]Name{ObjectCreatedAll}, "2010*.jpg", TargetID{"1", "webhook"})
 	expectedResultCase3 := NewRulesMap([]Name{ObjectCreatedAll}, "*", TargetID{"1", "webhook"})
 
@@ -128,22 +130,34 @@ func TestRulesMapMatch(t *testing.T) {
 	rulesMapCase3 := NewRulesMap([]Name{ObjectCreatedAll}, "2010*.jpg", TargetID{"1", "webhook"})
 
 	rulesMapCase4 := NewRulesMap([]Name{ObjectCreatedAll}, "2010*.jpg", TargetID{"1", "webhook"})
-	rulesMapCase4.add([]Name{ObjectCreatedAll}, "*", TargetID{"2", "amqp"})
+	rulesMapCase4.add([]Name{ObjectCreatedAll}, NewRule("*", nil), TargetID{"2", "amqp"})
+
+	rulesMapCase5 := NewRulesMap([]Name{ObjectCreatedAll}, "*", TargetID{"1", "webhook"})
+	rulesMapCase5.Add(NewFilteredRulesMap([]Name{ObjectCreatedAll}, "", &ObjectFilter{
+		MinSize:  100 << 20,
+		Metadata: []MetadataRule{{"X-Amz-Meta-Pipeline", ""}},
+	}, TargetID{"2", "amqp"}))
+
+	largeObject := ObjectProperties{Size: 200 << 20, UserMetadata: map[string]string{"x-amz-meta-pipeline": "ingest"}}
+	smallObject := ObjectProperties{Size: 1 << 20, UserMetadata: map[string]string{"x-amz-meta-pipeline": "ingest"}}
 
 	testCases := []struct {
 		rulesMap       RulesMap
 		eventName      Name
 		objectName     string
+		props          ObjectProperties
 		expectedResult TargetIDSet
 	}{
-		{rulesMapCase1, ObjectCreatedPut, "2010/photo.jpg", NewTargetIDSet()},
-		{rulesMapCase2, ObjectCreatedPut, "2010/photo.jpg", NewTargetIDSet(TargetID{"1", "webhook"})},
-		{rulesMapCase3, ObjectCreatedPut, "2000/photo.png", NewTargetIDSet()},
-		{rulesMapCase4, ObjectCreatedPut, "2000/photo.png", NewTargetIDSet(TargetID{"2", "amqp"})},
+		{rulesMapCase1, ObjectCreatedPut, "2010/photo.jpg", ObjectProperties{}, NewTargetIDSet()},
+		{rulesMapCase2, ObjectCreatedPut, "2010/photo.jpg", ObjectProperties{}, NewTargetIDSet(TargetID{"1", "webhook"})},
+		{rulesMapCase3, ObjectCreatedPut, "2000/photo.png", ObjectProperties{}, NewTargetIDSet()},
+		{rulesMapCase4, ObjectCreatedPut, "2000/photo.png", ObjectProperties{}, NewTargetIDSet(TargetID{"2", "amqp"})},
+		{rulesMapCase5, ObjectCreatedPut, "2000/video.mp4", largeObject, NewTargetIDSet(TargetID{"1", "webhook"}, TargetID{"2", "amqp"})},
+		{rulesMapCase5, ObjectCreatedPut, "2000/video.mp4", smallObject, NewTargetIDSet(TargetID{"1", "webhook"})},
 	}
 
 	for i, testCase := range testCases {
-		result := testCase.rulesMap.Match(testCase.eventName, testCase.objectName)
+		result := testCase.rulesMap.Match(testCase.eventName, testCase.objectName, testCase.props)
 
 		if !reflect.DeepEqual(result, testCase.expectedResult) {
 			t.Fatalf("test %v: result: expected: %v, got: %v", i+1, testCase.expectedResult, result)
@@ -151,15 +165,45 @@ func TestRulesMapMatch(t *testing.T) {
 	}
 }
 
+func TestRulesMapGob(t *testing.T) {
+	rulesMap := NewFilteredRulesMap([]Name{ObjectCreatedAll}, "*", &ObjectFilter{
+		Metadata: []MetadataRule{{"X-Amz-Meta-Stage", "raw"}, {"X-Amz-Meta-Pipeline", "ingest-*"}},
+	}, TargetID{"1", "webhook"})
+
+	// Rules maps are sent to peers with gob.
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(rulesMap); err != nil {
+		t.Fatal(err)
+	}
+	var decoded RulesMap
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	props := ObjectProperties{UserMetadata: map[string]string{"x-amz-meta-pipeline": "ingest-v2", "x-amz-meta-stage": "raw"}}
+	expected := NewTargetIDSet(TargetID{"1", "webhook"})
+	if result := decoded.Match(ObjectCreatedPut, "photo.jpg", props); !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expected: %v, got: %v", expected, result)
+	}
+
+	// The same filter in a different order removes the decoded rules.
+	decoded.Remove(NewFilteredRulesMap([]Name{ObjectCreatedAll}, "*", &ObjectFilter{
+		Metadata: []MetadataRule{{"x-amz-meta-pipeline", "ingest-*"}, {"x-amz-meta-stage", "raw"}},
+	}, TargetID{"1", "webhook"}))
+	if len(decoded) != 0 {
+		t.Fatalf("expected empty rules map, got: %v", decoded)
+	}
+}
+
 func TestNewRulesMap(t *testing.T) {
 	rulesMapCase1 := make(RulesMap)
-	rulesMapCase1.add([]Name{ObjectAccessedGet, ObjectAccessedHead}, "*", TargetID{"1", "webhook"})
+	rulesMapCase1.add([]Name{ObjectAccessedGet, ObjectAccessedHead}, NewRule("*", nil), TargetID{"1", "webhook"})
 
 	rulesMapCase2 := make(RulesMap)
-	rulesMapCase2.add([]Name{ObjectAccessedGet, ObjectAccessedHead, ObjectCreatedPut}, "*", TargetID{"1", "webhook"})
+	rulesMapCase2.add([]Name{ObjectAccessedGet, ObjectAccessedHead, ObjectCreatedPut}, NewRule("*", nil), TargetID{"1", "webhook"})
 
 	rulesMapCase3 := make(RulesMap)
-	rulesMapCase3.add([]Name{ObjectRemovedDelete}, "2010*.jpg", TargetID{"1", "webhook"})
+	rulesMapCase3.add([]Name{ObjectRemovedDelete}, NewRule("2010*.jpg", nil), TargetID{"1", "webhook"})
 
 	testCases := []struct {
 		eventNames     []Name
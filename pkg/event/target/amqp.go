@@ -77,6 +77,7 @@ type AMQPTarget struct {
 	conn      *amqp.Connection
 	connMutex sync.Mutex
 	store     Store
+	queue     *EventQueue
 }
 
 // ID - returns TargetID.
@@ -84,6 +85,12 @@ func (target *AMQPTarget) ID() event.TargetID {
 	return target.id
 }
 
+// Queue - returns the queue of undelivered events, nil if no queue
+// directory is configured.
+func (target *AMQPTarget) Queue() *EventQueue {
+	return target.queue
+}
+
 func (target *AMQPTarget) channel() (*amqp.Channel, error) {
 	var err error
 	var conn *amqp.Connection
@@ -245,9 +252,7 @@ func NewAMQPTarget(id string, args AMQPArgs, doneCh <-chan struct{}) (*AMQPTarge
 
 	if target.store != nil {
 		// Replays the events from the store.
-		eventKeyCh := replayEvents(target.store, doneCh)
-		// Start replaying events from the store.
-		go sendEvents(target, eventKeyCh, doneCh)
+		target.queue = newEventQueue(target, target.store, doneCh)
 	}
 
 	return target, nil
@@ -70,6 +70,7 @@ type ElasticsearchTarget struct {
 	args   ElasticsearchArgs
 	client *elastic.Client
 	store  Store
+	queue  *EventQueue
 }
 
 // ID - returns target ID.
@@ -77,6 +78,12 @@ func (target *ElasticsearchTarget) ID() event.TargetID {
 	return target.id
 }
 
+// Queue - returns the queue of undelivered events, nil if no queue
+// directory is configured.
+func (target *ElasticsearchTarget) Queue() *EventQueue {
+	return target.queue
+}
+
 // Save - saves the events to the store if queuestore is configured, which will be replayed when the elasticsearch connection is active.
 func (target *ElasticsearchTarget) Save(eventData event.Event) error {
 	if target.store != nil {
@@ -125,9 +132,9 @@ func (target *ElasticsearchTarget) send(eventData event.Event) error {
 		}
 
 		key = eventData.S3.Bucket.Name + "/" + objectName
-		if eventData.EventName == event.ObjectRemovedDelete {
+		if eventData.EventName.RemovesObject() {
 			err = remove()
-		} else {
+		} else if eventData.EventName.ReflectsObject() {
 			err = update()
 		}
 
@@ -256,9 +263,7 @@ func NewElasticsearchTarget(id string, args ElasticsearchArgs, doneCh <-chan str
 
 	if target.store != nil {
 		// Replays the events from the store.
-		eventKeyCh := replayEvents(target.store, doneCh)
-		// Start replaying events from the store.
-		go sendEvents(target, eventKeyCh, doneCh)
+		target.queue = newEventQueue(target, target.store, doneCh)
 	}
 
 	return target, nil
@@ -83,6 +83,7 @@ type KafkaTarget struct {
 	producer sarama.SyncProducer
 	config   *sarama.Config
 	store    Store
+	queue    *EventQueue
 }
 
 // ID - returns target ID.
@@ -90,6 +91,12 @@ func (target *KafkaTarget) ID() event.TargetID {
 	return target.id
 }
 
+// Queue - returns the queue of undelivered events, nil if no queue
+// directory is configured.
+func (target *KafkaTarget) Queue() *EventQueue {
+	return target.queue
+}
+
 // Save - saves the events to the store which will be replayed when the Kafka connection is active.
 func (target *KafkaTarget) Save(eventData event.Event) error {
 	if target.store != nil {
@@ -242,9 +249,7 @@ func NewKafkaTarget(id string, args KafkaArgs, doneCh <-chan struct{}) (*KafkaTa
 
 	if target.store != nil {
 		// Replays the events from the store.
-		eventKeyCh := replayEvents(target.store, doneCh)
-		// Start replaying events from the store.
-		go sendEvents(target, eventKeyCh, doneCh)
+		target.queue = newEventQueue(target, target.store, doneCh)
 	}
 
 	return target, nil
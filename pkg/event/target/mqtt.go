@@ -86,6 +86,7 @@ type MQTTTarget struct {
 	args   MQTTArgs
 	client mqtt.Client
 	store  Store
+	queue  *EventQueue
 }
 
 // ID - returns target ID.
@@ -93,6 +94,12 @@ func (target *MQTTTarget) ID() event.TargetID {
 	return target.id
 }
 
+// Queue - returns the queue of undelivered events, nil if no queue
+// directory is configured.
+func (target *MQTTTarget) Queue() *EventQueue {
+	return target.queue
+}
+
 // send - sends an event to the mqtt.
 func (target *MQTTTarget) send(eventData event.Event) error {
 	objectName, err := url.QueryUnescape(eventData.S3.Object.Key)
@@ -217,9 +224,7 @@ func NewMQTTTarget(id string, args MQTTArgs, doneCh <-chan struct{}) (*MQTTTarge
 
 	if target.store != nil {
 		// Replays the events from the store.
-		eventKeyCh := replayEvents(target.store, doneCh)
-		// Start replaying events from the store.
-		go sendEvents(target, eventKeyCh, doneCh)
+		target.queue = newEventQueue(target, target.store, doneCh)
 	}
 
 	return target, nil
@@ -150,6 +150,7 @@ type MySQLTarget struct {
 	insertStmt *sql.Stmt
 	db         *sql.DB
 	store      Store
+	queue      *EventQueue
 	firstPing  bool
 }
 
@@ -158,6 +159,12 @@ func (target *MySQLTarget) ID() event.TargetID {
 	return target.id
 }
 
+// Queue - returns the queue of undelivered events, nil if no queue
+// directory is configured.
+func (target *MySQLTarget) Queue() *EventQueue {
+	return target.queue
+}
+
 // Save - saves the events to the store which will be replayed when the SQL connection is active.
 func (target *MySQLTarget) Save(eventData event.Event) error {
 	if target.store != nil {
@@ -181,9 +188,9 @@ func (target *MySQLTarget) send(eventData event.Event) error {
 		}
 		key := eventData.S3.Bucket.Name + "/" + objectName
 
-		if eventData.EventName == event.ObjectRemovedDelete {
+		if eventData.EventName.RemovesObject() {
 			_, err = target.deleteStmt.Exec(key)
-		} else {
+		} else if eventData.EventName.ReflectsObject() {
 			var data []byte
 			if data, err = json.Marshal(struct{ Records []event.Event }{[]event.Event{eventData}}); err != nil {
 				return err
@@ -363,9 +370,7 @@ func NewMySQLTarget(id string, args MySQLArgs, doneCh <-chan struct{}) (*MySQLTa
 
 	if target.store != nil {
 		// Replays the events from the store.
-		eventKeyCh := replayEvents(target.store, doneCh)
-		// Start replaying events from the store.
-		go sendEvents(target, eventKeyCh, doneCh)
+		target.queue = newEventQueue(target, target.store, doneCh)
 	}
 
 	return target, nil
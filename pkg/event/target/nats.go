@@ -120,6 +120,7 @@ type NATSTarget struct {
 	natsConn *nats.Conn
 	stanConn stan.Conn
 	store    Store
+	queue    *EventQueue
 }
 
 // ID - returns target ID.
@@ -127,6 +128,12 @@ func (target *NATSTarget) ID() event.TargetID {
 	return target.id
 }
 
+// Queue - returns the queue of undelivered events, nil if no queue
+// directory is configured.
+func (target *NATSTarget) Queue() *EventQueue {
+	return target.queue
+}
+
 // Save - saves the events to the store which will be replayed when the Nats connection is active.
 func (target *NATSTarget) Save(eventData event.Event) error {
 	if target.store != nil {
@@ -271,9 +278,7 @@ func NewNATSTarget(id string, args NATSArgs, doneCh <-chan struct{}) (*NATSTarge
 
 	if target.store != nil {
 		// Replays the events from the store.
-		eventKeyCh := replayEvents(target.store, doneCh)
-		// Start replaying events from the store.
-		go sendEvents(target, eventKeyCh, doneCh)
+		target.queue = newEventQueue(target, target.store, doneCh)
 	}
 
 	return target, nil
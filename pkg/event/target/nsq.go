@@ -74,6 +74,7 @@ type NSQTarget struct {
 	args     NSQArgs
 	producer *nsq.Producer
 	store    Store
+	queue    *EventQueue
 }
 
 // ID - returns target ID.
@@ -81,6 +82,12 @@ func (target *NSQTarget) ID() event.TargetID {
 	return target.id
 }
 
+// Queue - returns the queue of undelivered events, nil if no queue
+// directory is configured.
+func (target *NSQTarget) Queue() *EventQueue {
+	return target.queue
+}
+
 // Save - saves the events to the store which will be replayed when the nsq connection is active.
 func (target *NSQTarget) Save(eventData event.Event) error {
 	if target.store != nil {
@@ -189,9 +196,7 @@ func NewNSQTarget(id string, args NSQArgs, doneCh <-chan struct{}) (*NSQTarget,
 
 	if target.store != nil {
 		// Replays the events from the store.
-		eventKeyCh := replayEvents(target.store, doneCh)
-		// Start replaying events from the store.
-		go sendEvents(target, eventKeyCh, doneCh)
+		target.queue = newEventQueue(target, target.store, doneCh)
 	}
 
 	return target, nil
@@ -148,6 +148,7 @@ type PostgreSQLTarget struct {
 	insertStmt *sql.Stmt
 	db         *sql.DB
 	store      Store
+	queue      *EventQueue
 	firstPing  bool
 }
 
@@ -156,6 +157,12 @@ func (target *PostgreSQLTarget) ID() event.TargetID {
 	return target.id
 }
 
+// Queue - returns the queue of undelivered events, nil if no queue
+// directory is configured.
+func (target *PostgreSQLTarget) Queue() *EventQueue {
+	return target.queue
+}
+
 // Save - saves the events to the store if questore is configured, which will be replayed when the PostgreSQL connection is active.
 func (target *PostgreSQLTarget) Save(eventData event.Event) error {
 	if target.store != nil {
@@ -184,9 +191,9 @@ func (target *PostgreSQLTarget) send(eventData event.Event) error {
 		}
 		key := eventData.S3.Bucket.Name + "/" + objectName
 
-		if eventData.EventName == event.ObjectRemovedDelete {
+		if eventData.EventName.RemovesObject() {
 			_, err = target.deleteStmt.Exec(key)
-		} else {
+		} else if eventData.EventName.ReflectsObject() {
 			var data []byte
 			if data, err = json.Marshal(struct{ Records []event.Event }{[]event.Event{eventData}}); err != nil {
 				return err
@@ -370,9 +377,7 @@ func NewPostgreSQLTarget(id string, args PostgreSQLArgs, doneCh <-chan struct{})
 
 	if target.store != nil {
 		// Replays the events from the store.
-		eventKeyCh := replayEvents(target.store, doneCh)
-		// Start replaying events from the store.
-		go sendEvents(target, eventKeyCh, doneCh)
+		target.queue = newEventQueue(target, target.store, doneCh)
 	}
 
 	return target, nil
@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/minio/minio/pkg/event"
 	"github.com/minio/minio/pkg/sys"
@@ -170,6 +171,24 @@ func (store *QueueStore) List() []string {
 	return store.list()
 }
 
+// Oldest - returns the modification time of the oldest event file.
+func (store *QueueStore) Oldest() time.Time {
+	store.RLock()
+	defer store.RUnlock()
+
+	var oldest time.Time
+	for _, name := range store.list() {
+		fi, err := os.Stat(filepath.Join(store.directory, name))
+		if err != nil {
+			continue
+		}
+		if oldest.IsZero() || fi.ModTime().Before(oldest) {
+			oldest = fi.ModTime()
+		}
+	}
+	return oldest
+}
+
 // lockless call.
 func (store *QueueStore) list() []string {
 	var names []string
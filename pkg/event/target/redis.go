@@ -99,6 +99,7 @@ type RedisTarget struct {
 	args      RedisArgs
 	pool      *redis.Pool
 	store     Store
+	queue     *EventQueue
 	firstPing bool
 }
 
@@ -107,6 +108,12 @@ func (target *RedisTarget) ID() event.TargetID {
 	return target.id
 }
 
+// Queue - returns the queue of undelivered events, nil if no queue
+// directory is configured.
+func (target *RedisTarget) Queue() *EventQueue {
+	return target.queue
+}
+
 // Save - saves the events to the store if questore is configured, which will be replayed when the redis connection is active.
 func (target *RedisTarget) Save(eventData event.Event) error {
 	if target.store != nil {
@@ -142,9 +149,9 @@ func (target *RedisTarget) send(eventData event.Event) error {
 		}
 		key := eventData.S3.Bucket.Name + "/" + objectName
 
-		if eventData.EventName == event.ObjectRemovedDelete {
+		if eventData.EventName.RemovesObject() {
 			_, err = conn.Do("HDEL", target.args.Key, key)
-		} else {
+		} else if eventData.EventName.ReflectsObject() {
 			var data []byte
 			if data, err = json.Marshal(struct{ Records []event.Event }{[]event.Event{eventData}}); err != nil {
 				return err
@@ -288,9 +295,7 @@ func NewRedisTarget(id string, args RedisArgs, doneCh <-chan struct{}) (*RedisTa
 
 	if target.store != nil {
 		// Replays the events from the store.
-		eventKeyCh := replayEvents(target.store, doneCh)
-		// Start replaying events from the store.
-		go sendEvents(target, eventKeyCh, doneCh)
+		target.queue = newEventQueue(target, target.store, doneCh)
 	}
 
 	return target, nil
@@ -22,6 +22,7 @@ import (
 	"net"
 	"os"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -43,10 +44,103 @@ type Store interface {
 	List() []string
 	Del(key string) error
 	Open() error
+	// Oldest - returns the time the oldest event was stored at,
+	// the zero time if the store is empty.
+	Oldest() time.Time
+}
+
+// QueuedTarget - target able to persist its undelivered events in a
+// queue directory.
+type QueuedTarget interface {
+	event.Target
+	Queue() *EventQueue
+}
+
+// EventQueue - the events of a target persisted in its store until
+// they are delivered, and the state of their delivery.
+type EventQueue struct {
+	store Store
+	// One if the last delivery failed as the target is not connected.
+	offline int32
+	// Wake up the replay of the events before the retry interval.
+	listCh  chan struct{}
+	retryCh chan struct{}
+}
+
+// newEventQueue - starts replaying the events of the store to target.
+func newEventQueue(target event.Target, store Store, doneCh <-chan struct{}) *EventQueue {
+	q := &EventQueue{
+		store:   store,
+		listCh:  make(chan struct{}, 1),
+		retryCh: make(chan struct{}, 1),
+	}
+
+	// Replays the events from the store.
+	eventKeyCh := replayEvents(store, doneCh, q.listCh)
+	// Start replaying events from the store.
+	go sendEvents(target, eventKeyCh, doneCh, q)
+
+	return q
+}
+
+// Online - returns whether the last delivery to the target succeeded.
+func (q *EventQueue) Online() bool {
+	return atomic.LoadInt32(&q.offline) == 0
+}
+
+// Len - returns the number of queued events.
+func (q *EventQueue) Len() int {
+	return len(q.store.List())
+}
+
+// Oldest - returns the time the oldest queued event was stored at,
+// the zero time if the queue is empty.
+func (q *EventQueue) Oldest() time.Time {
+	return q.store.Oldest()
+}
+
+// Peek - returns up to n queued events, oldest first.
+func (q *EventQueue) Peek(n int) []event.Event {
+	var events []event.Event
+	for _, name := range q.store.List() {
+		if len(events) >= n {
+			break
+		}
+		eventData, err := q.store.Get(strings.TrimSuffix(name, eventExt))
+		if err != nil {
+			// Delivered in the meantime.
+			continue
+		}
+		events = append(events, eventData)
+	}
+	return events
+}
+
+// Purge - removes all queued events without delivering them, returns
+// the number of removed events.
+func (q *EventQueue) Purge() int {
+	var count int
+	for _, name := range q.store.List() {
+		if q.store.Del(strings.TrimSuffix(name, eventExt)) == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// Replay - retries the delivery of the queued events right away instead
+// of waiting for the retry interval.
+func (q *EventQueue) Replay() {
+	for _, ch := range []chan struct{}{q.listCh, q.retryCh} {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
 }
 
 // replayEvents - Reads the events from the store and replays.
-func replayEvents(store Store, doneCh <-chan struct{}) <-chan string {
+func replayEvents(store Store, doneCh <-chan struct{}, listCh <-chan struct{}) <-chan string {
 	var names []string
 	eventKeyCh := make(chan string)
 
@@ -69,6 +163,7 @@ func replayEvents(store Store, doneCh <-chan struct{}) <-chan string {
 				retryTimer.Reset(retryInterval)
 				select {
 				case <-retryTimer.C:
+				case <-listCh:
 				case <-doneCh:
 					return
 				}
@@ -110,7 +205,7 @@ func IsConnResetErr(err error) bool {
 }
 
 // sendEvents - Reads events from the store and re-plays.
-func sendEvents(target event.Target, eventKeyCh <-chan string, doneCh <-chan struct{}) {
+func sendEvents(target event.Target, eventKeyCh <-chan string, doneCh <-chan struct{}, q *EventQueue) {
 	retryTimer := time.NewTimer(retryInterval)
 	defer retryTimer.Stop()
 
@@ -118,16 +213,19 @@ func sendEvents(target event.Target, eventKeyCh <-chan string, doneCh <-chan str
 		for {
 			err := target.Send(eventKey)
 			if err == nil {
+				atomic.StoreInt32(&q.offline, 0)
 				break
 			}
 
 			if err != errNotConnected && !IsConnResetErr(err) {
 				panic(fmt.Errorf("target.Send() failed with '%v'", err))
 			}
+			atomic.StoreInt32(&q.offline, 1)
 
 			retryTimer.Reset(retryInterval)
 			select {
 			case <-retryTimer.C:
+			case <-q.retryCh:
 			case <-doneCh:
 				return false
 			}
@@ -0,0 +1,129 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package target
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/event"
+)
+
+// testQueuedTarget - delivers events of its store once it is connected.
+type testQueuedTarget struct {
+	store     Store
+	connected int32
+}
+
+func (target *testQueuedTarget) ID() event.TargetID {
+	return event.TargetID{ID: "1", Name: "test"}
+}
+
+func (target *testQueuedTarget) Save(eventData event.Event) error {
+	return target.store.Put(eventData)
+}
+
+func (target *testQueuedTarget) Send(eventKey string) error {
+	if atomic.LoadInt32(&target.connected) == 0 {
+		return errNotConnected
+	}
+	if _, err := target.store.Get(eventKey); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return target.store.Del(eventKey)
+}
+
+func (target *testQueuedTarget) Close() error {
+	return nil
+}
+
+func TestEventQueue(t *testing.T) {
+	defer func() {
+		if err := tearDownStore(); err != nil {
+			t.Fatal("Failed to tear down store ", err)
+		}
+	}()
+	store, err := setUpStore(queueDir, 100)
+	if err != nil {
+		t.Fatal("Failed to create a queue store ", err)
+	}
+
+	target := &testQueuedTarget{store: store}
+	for i := 0; i < 5; i++ {
+		if err = target.Save(testEvent); err != nil {
+			t.Fatal("Failed to save event ", err)
+		}
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	q := newEventQueue(target, store, doneCh)
+
+	// Wait for the first failed delivery.
+	deadline := time.Now().Add(5 * time.Second)
+	for q.Online() {
+		if time.Now().After(deadline) {
+			t.Fatal("Online() Expected: false, got true")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if q.Len() != 5 {
+		t.Fatalf("Len() Expected: 5, got %d", q.Len())
+	}
+	if q.Oldest().IsZero() {
+		t.Fatal("Oldest() Expected: time of the oldest event, got zero time")
+	}
+	if events := q.Peek(2); len(events) != 2 {
+		t.Fatalf("Peek() Expected: 2 events, got %d", len(events))
+	}
+
+	// A replay does not wait for the retry interval.
+	atomic.StoreInt32(&target.connected, 1)
+	q.Replay()
+	deadline = time.Now().Add(retryInterval / 2)
+	for q.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Len() Expected: 0 after replay, got %d", q.Len())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !q.Online() {
+		t.Fatal("Online() Expected: true, got false")
+	}
+	if !q.Oldest().IsZero() {
+		t.Fatalf("Oldest() Expected: zero time, got %v", q.Oldest())
+	}
+
+	atomic.StoreInt32(&target.connected, 0)
+	for i := 0; i < 3; i++ {
+		if err = target.Save(testEvent); err != nil {
+			t.Fatal("Failed to save event ", err)
+		}
+	}
+	if n := q.Purge(); n != 3 {
+		t.Fatalf("Purge() Expected: 3, got %d", n)
+	}
+	if q.Len() != 0 {
+		t.Fatalf("Len() Expected: 0 after purge, got %d", q.Len())
+	}
+}
@@ -18,8 +18,11 @@ package target
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -36,13 +39,26 @@ import (
 	xnet "github.com/minio/minio/pkg/net"
 )
 
+// WebhookSignatureHeader - header carrying the hex encoded HMAC-SHA256 of
+// the request body, prefixed by "sha256=", if a HMAC secret is configured.
+const WebhookSignatureHeader = "X-Minio-Signature"
+
+// Response header timeout used if no request timeout is configured.
+const webhookResponseHeaderTimeout = 3 * time.Second
+
 // WebhookArgs - Webhook target arguments.
 type WebhookArgs struct {
-	Enable     bool           `json:"enable"`
-	Endpoint   xnet.URL       `json:"endpoint"`
-	RootCAs    *x509.CertPool `json:"-"`
-	QueueDir   string         `json:"queueDir"`
-	QueueLimit uint64         `json:"queueLimit"`
+	Enable      bool           `json:"enable"`
+	Endpoint    xnet.URL       `json:"endpoint"`
+	AuthToken   string         `json:"authToken"`
+	ClientCert  string         `json:"clientCert"`
+	ClientKey   string         `json:"clientKey"`
+	HMACSecret  string         `json:"hmacSecret"`
+	Timeout     string         `json:"timeout"`
+	MaxRequests int            `json:"maxRequests"`
+	RootCAs     *x509.CertPool `json:"-"`
+	QueueDir    string         `json:"queueDir"`
+	QueueLimit  uint64         `json:"queueLimit"`
 }
 
 // Validate WebhookArgs fields
@@ -61,6 +77,26 @@ func (w WebhookArgs) Validate() error {
 	if w.QueueLimit > maxLimit {
 		return errors.New("queueLimit should not exceed 10000")
 	}
+	if (w.ClientCert == "") != (w.ClientKey == "") {
+		return errors.New("clientCert and clientKey must be specified together")
+	}
+	if w.ClientCert != "" {
+		if _, err := tls.LoadX509KeyPair(w.ClientCert, w.ClientKey); err != nil {
+			return fmt.Errorf("unable to load client certificate: %v", err)
+		}
+	}
+	if w.Timeout != "" {
+		timeout, err := time.ParseDuration(w.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %v", err)
+		}
+		if timeout <= 0 {
+			return errors.New("timeout should be positive")
+		}
+	}
+	if w.MaxRequests < 0 {
+		return errors.New("maxRequests should not be negative")
+	}
 	return nil
 }
 
@@ -70,6 +106,9 @@ type WebhookTarget struct {
 	args       WebhookArgs
 	httpClient *http.Client
 	store      Store
+	queue      *EventQueue
+	// Limits the number of concurrent requests if not nil.
+	requestCh chan struct{}
 }
 
 // ID - returns target ID.
@@ -77,6 +116,12 @@ func (target WebhookTarget) ID() event.TargetID {
 	return target.id
 }
 
+// Queue - returns the queue of undelivered events, nil if no queue
+// directory is configured.
+func (target *WebhookTarget) Queue() *EventQueue {
+	return target.queue
+}
+
 // Save - saves the events to the store if queuestore is configured, which will be replayed when the wenhook connection is active.
 func (target *WebhookTarget) Save(eventData event.Event) error {
 	if target.store != nil {
@@ -114,6 +159,19 @@ func (target *WebhookTarget) send(eventData event.Event) error {
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if target.args.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+target.args.AuthToken)
+	}
+	if target.args.HMACSecret != "" {
+		mac := hmac.New(sha256.New, []byte(target.args.HMACSecret))
+		mac.Write(data)
+		req.Header.Set(WebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	if target.requestCh != nil {
+		target.requestCh <- struct{}{}
+		defer func() { <-target.requestCh }()
+	}
 
 	resp, err := target.httpClient.Do(req)
 	if err != nil {
@@ -171,16 +229,34 @@ func (target *WebhookTarget) Close() error {
 }
 
 // NewWebhookTarget - creates new Webhook target.
-func NewWebhookTarget(id string, args WebhookArgs, doneCh <-chan struct{}) *WebhookTarget {
-
+func NewWebhookTarget(id string, args WebhookArgs, doneCh <-chan struct{}) (*WebhookTarget, error) {
 	var store Store
 
 	if args.QueueDir != "" {
 		queueDir := filepath.Join(args.QueueDir, storePrefix+"-webhook-"+id)
 		store = NewQueueStore(queueDir, args.QueueLimit)
 		if oErr := store.Open(); oErr != nil {
-			return nil
+			return nil, oErr
+		}
+	}
+
+	tlsConfig := &tls.Config{RootCAs: args.RootCAs}
+	if args.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(args.ClientCert, args.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	var timeout time.Duration
+	responseHeaderTimeout := webhookResponseHeaderTimeout
+	if args.Timeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(args.Timeout); err != nil {
+			return nil, err
 		}
+		responseHeaderTimeout = timeout
 	}
 
 	target := &WebhookTarget{
@@ -188,25 +264,28 @@ func NewWebhookTarget(id string, args WebhookArgs, doneCh <-chan struct{}) *Webh
 		args: args,
 		httpClient: &http.Client{
 			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{RootCAs: args.RootCAs},
+				TLSClientConfig: tlsConfig,
 				DialContext: (&net.Dialer{
 					Timeout:   5 * time.Second,
 					KeepAlive: 5 * time.Second,
 				}).DialContext,
 				TLSHandshakeTimeout:   3 * time.Second,
-				ResponseHeaderTimeout: 3 * time.Second,
+				ResponseHeaderTimeout: responseHeaderTimeout,
 				ExpectContinueTimeout: 2 * time.Second,
 			},
+			Timeout: timeout,
 		},
 		store: store,
 	}
 
+	if args.MaxRequests > 0 {
+		target.requestCh = make(chan struct{}, args.MaxRequests)
+	}
+
 	if target.store != nil {
 		// Replays the events from the store.
-		eventKeyCh := replayEvents(target.store, doneCh)
-		// Start replaying events from the store.
-		go sendEvents(target, eventKeyCh, doneCh)
+		target.queue = newEventQueue(target, target.store, doneCh)
 	}
 
-	return target
+	return target, nil
 }
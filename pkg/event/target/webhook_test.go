@@ -0,0 +1,107 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package target
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/minio/minio/pkg/event"
+	xnet "github.com/minio/minio/pkg/net"
+)
+
+func TestWebhookArgsValidate(t *testing.T) {
+	endpoint, err := xnet.ParseURL("http://localhost:3000/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		args      WebhookArgs
+		expectErr bool
+	}{
+		{WebhookArgs{Enable: true, Endpoint: *endpoint}, false},
+		{WebhookArgs{Enable: true, Endpoint: *endpoint, Timeout: "10s", MaxRequests: 4}, false},
+		{WebhookArgs{Enable: true, Endpoint: *endpoint, Timeout: "ten"}, true},
+		{WebhookArgs{Enable: true, Endpoint: *endpoint, Timeout: "-1s"}, true},
+		{WebhookArgs{Enable: true, Endpoint: *endpoint, MaxRequests: -1}, true},
+		{WebhookArgs{Enable: true, Endpoint: *endpoint, ClientCert: "/tmp/public.crt"}, true},
+		{WebhookArgs{Enable: true, Endpoint: *endpoint, ClientCert: "/nonexistent/public.crt", ClientKey: "/nonexistent/private.key"}, true},
+	}
+
+	for i, testCase := range testCases {
+		err := testCase.args.Validate()
+		expectErr := (err != nil)
+
+		if expectErr != testCase.expectErr {
+			t.Fatalf("test %v: error: expected: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+	}
+}
+
+func TestWebhookTargetSend(t *testing.T) {
+	const secret = "webhook-secret"
+
+	var authorization, signature string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		signature = r.Header.Get(WebhookSignatureHeader)
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	endpoint, err := xnet.ParseURL(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := WebhookArgs{
+		Enable:      true,
+		Endpoint:    *endpoint,
+		AuthToken:   "token",
+		HMACSecret:  secret,
+		Timeout:     "5s",
+		MaxRequests: 1,
+	}
+	target, err := NewWebhookTarget("1", args, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eventData := event.Event{EventName: event.ObjectCreatedPut}
+	eventData.S3.Bucket.Name = "bucket"
+	eventData.S3.Object.Key = "object"
+	if err = target.Save(eventData); err != nil {
+		t.Fatal(err)
+	}
+
+	if authorization != "Bearer token" {
+		t.Fatalf("authorization: expected: %v, got: %v", "Bearer token", authorization)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if expected := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != expected {
+		t.Fatalf("signature: expected: %v, got: %v", expected, signature)
+	}
+}
@@ -29,10 +29,31 @@ type Target interface {
 	Close() error
 }
 
+// targetState - a target along with the error of its last
+// attempt to save an event.
+type targetState struct {
+	Target
+
+	mu  sync.Mutex
+	err error
+}
+
+func (t *targetState) setErr(err error) {
+	t.mu.Lock()
+	t.err = err
+	t.mu.Unlock()
+}
+
+func (t *targetState) getErr() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
 // TargetList - holds list of targets indexed by target ID.
 type TargetList struct {
 	sync.RWMutex
-	targets map[TargetID]Target
+	targets map[TargetID]*targetState
 }
 
 // Add - adds unique target to target list.
@@ -44,7 +65,7 @@ func (list *TargetList) Add(target Target) error {
 		return fmt.Errorf("target %v already exists", target.ID())
 	}
 
-	list.targets[target.ID()] = target
+	list.targets[target.ID()] = &targetState{Target: target}
 	return nil
 }
 
@@ -79,7 +100,7 @@ func (list *TargetList) Remove(targetids ...TargetID) <-chan TargetIDErr {
 			list.RUnlock()
 			if ok {
 				wg.Add(1)
-				go func(id TargetID, target Target) {
+				go func(id TargetID, target *targetState) {
 					defer wg.Done()
 					if err := target.Close(); err != nil {
 						errCh <- TargetIDErr{
@@ -102,6 +123,30 @@ func (list *TargetList) Remove(targetids ...TargetID) <-chan TargetIDErr {
 	return errCh
 }
 
+// Target - returns the target with target ID, nil if it does not exist.
+func (list *TargetList) Target(id TargetID) Target {
+	list.RLock()
+	defer list.RUnlock()
+
+	if target, ok := list.targets[id]; ok {
+		return target.Target
+	}
+	return nil
+}
+
+// Err - returns the error of the last failed attempt to save an event
+// to the target, nil if the last event was saved.
+func (list *TargetList) Err(id TargetID) error {
+	list.RLock()
+	target, ok := list.targets[id]
+	list.RUnlock()
+
+	if !ok {
+		return nil
+	}
+	return target.getErr()
+}
+
 // List - returns available target IDs.
 func (list *TargetList) List() []TargetID {
 	list.RLock()
@@ -129,9 +174,11 @@ func (list *TargetList) Send(event Event, targetIDs ...TargetID) <-chan TargetID
 			list.RUnlock()
 			if ok {
 				wg.Add(1)
-				go func(id TargetID, target Target) {
+				go func(id TargetID, target *targetState) {
 					defer wg.Done()
-					if err := target.Save(event); err != nil {
+					err := target.Save(event)
+					target.setErr(err)
+					if err != nil {
 						errCh <- TargetIDErr{
 							ID:  id,
 							Err: err,
@@ -148,5 +195,7 @@ func (list *TargetList) Send(event Event, targetIDs ...TargetID) <-chan TargetID
 
 // NewTargetList - creates TargetList.
 func NewTargetList() *TargetList {
-	return &TargetList{targets: make(map[TargetID]Target)}
+	return &TargetList{
+		targets: make(map[TargetID]*targetState),
+	}
 }
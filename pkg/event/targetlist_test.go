@@ -275,6 +275,32 @@ func TestTargetListSend(t *testing.T) {
 	}
 }
 
+func TestTargetListErr(t *testing.T) {
+	targetList := NewTargetList()
+	if err := targetList.Add(&ExampleTarget{TargetID{"1", "testcase"}, false, false}); err != nil {
+		panic(err)
+	}
+	if err := targetList.Add(&ExampleTarget{TargetID{"2", "testcase"}, true, false}); err != nil {
+		panic(err)
+	}
+
+	for range targetList.Send(Event{}, TargetID{"1", "testcase"}, TargetID{"2", "testcase"}) {
+	}
+
+	if err := targetList.Err(TargetID{"1", "testcase"}); err != nil {
+		t.Fatalf("test 1: error: expected: <nil>, got: %v", err)
+	}
+	if err := targetList.Err(TargetID{"2", "testcase"}); err == nil {
+		t.Fatalf("test 2: error: expected: <non-nil>, got: <nil>")
+	}
+
+	for range targetList.Remove(TargetID{"2", "testcase"}) {
+	}
+	if err := targetList.Err(TargetID{"2", "testcase"}); err != nil {
+		t.Fatalf("test 3: error: expected: <nil>, got: %v", err)
+	}
+}
+
 func TestNewTargetList(t *testing.T) {
 	if result := NewTargetList(); result == nil {
 		t.Fatalf("test: result: expected: <non-nil>, got: <nil>")
@@ -25,25 +25,62 @@ import (
 	"net"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	jwtgo "github.com/dgrijalva/jwt-go"
+	iampolicy "github.com/minio/minio/pkg/iam/policy"
 	xnet "github.com/minio/minio/pkg/net"
 )
 
 // JWKSArgs - RSA authentication target arguments
 type JWKSArgs struct {
-	URL        *xnet.URL `json:"url"`
-	publicKeys map[string]crypto.PublicKey
+	URL *xnet.URL `json:"url"`
+
+	// OpenID discovery document URL, the JWKS URL and the issuer
+	// are looked up from it when not configured explicitly, e.g.
+	// https://accounts.google.com/.well-known/openid-configuration
+	ConfigURL *xnet.URL `json:"configURL,omitempty"`
+
+	// Expected audience of the token, usually the client ID
+	// registered with the identity provider.
+	ClientID string `json:"clientID,omitempty"`
+
+	// Expected issuer of the token.
+	Issuer string `json:"issuer,omitempty"`
+
+	// Claim carrying the policy names, defaults to "policy".
+	ClaimName string `json:"claimName,omitempty"`
+
+	// Only claim values with this prefix are mapped to policies,
+	// the prefix is removed to get the policy name.
+	ClaimPrefix string `json:"claimPrefix,omitempty"`
+
+	discoveryDoc DiscoveryDoc
+	publicKeys   map[string]crypto.PublicKey
 }
 
-// PopulatePublicKey - populates a new publickey from the JWKS URL.
-func (r *JWKSArgs) PopulatePublicKey() error {
+// DiscoveryDoc - parsed OpenID discovery document, only the fields
+// used by MinIO are decoded.
+// https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata
+type DiscoveryDoc struct {
+	Issuer                 string   `json:"issuer,omitempty"`
+	AuthEndpoint           string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint          string   `json:"token_endpoint,omitempty"`
+	UserInfoEndpoint       string   `json:"userinfo_endpoint,omitempty"`
+	JwksURI                string   `json:"jwks_uri,omitempty"`
+	ClaimsSupported        []string `json:"claims_supported,omitempty"`
+	IDTokenSigningAlgs     []string `json:"id_token_signing_alg_values_supported,omitempty"`
+	ResponseTypesSupported []string `json:"response_types_supported,omitempty"`
+}
+
+// getJSON - fetches and decodes the JSON document at the given URL.
+func getJSON(u *xnet.URL, v interface{}) error {
 	insecureClient := &http.Client{Transport: newCustomHTTPTransport(true)}
 	client := &http.Client{Transport: newCustomHTTPTransport(false)}
-	resp, err := client.Get(r.URL.String())
+	resp, err := client.Get(u.String())
 	if err != nil {
-		resp, err = insecureClient.Get(r.URL.String())
+		resp, err = insecureClient.Get(u.String())
 		if err != nil {
 			return err
 		}
@@ -53,8 +90,84 @@ func (r *JWKSArgs) PopulatePublicKey() error {
 		return errors.New(resp.Status)
 	}
 
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// parseDiscoveryDoc - fetches the OpenID discovery document.
+func parseDiscoveryDoc(u *xnet.URL) (DiscoveryDoc, error) {
+	d := DiscoveryDoc{}
+	if err := getJSON(u, &d); err != nil {
+		return d, err
+	}
+	if d.JwksURI == "" {
+		return d, errors.New("jwks_uri missing in OpenID discovery document")
+	}
+	return d, nil
+}
+
+// loadDiscoveryDoc - fetches the discovery document if one is
+// configured and was not loaded yet.
+func (r *JWKSArgs) loadDiscoveryDoc() error {
+	if r.ConfigURL == nil || r.ConfigURL.String() == "" || r.discoveryDoc.JwksURI != "" {
+		return nil
+	}
+	d, err := parseDiscoveryDoc(r.ConfigURL)
+	if err != nil {
+		return err
+	}
+	r.discoveryDoc = d
+	return nil
+}
+
+// jwksURL - returns the configured JWKS URL or the one advertised
+// by the discovery document. The discovery document is loaded in
+// either case, so that the issuer it advertises is verified.
+func (r *JWKSArgs) jwksURL() (*xnet.URL, error) {
+	if err := r.loadDiscoveryDoc(); err != nil {
+		return nil, err
+	}
+	if r.URL != nil && r.URL.String() != "" {
+		return r.URL, nil
+	}
+	if r.ConfigURL == nil || r.ConfigURL.String() == "" {
+		return nil, errors.New("no JWKS URL or OpenID discovery URL configured")
+	}
+	return xnet.ParseURL(r.discoveryDoc.JwksURI)
+}
+
+// claimName - returns the claim carrying the policy names.
+func (r *JWKSArgs) claimName() string {
+	if r.ClaimName != "" {
+		return r.ClaimName
+	}
+	return iampolicy.PolicyName
+}
+
+// issuer - returns the configured issuer or the one advertised by
+// the discovery document.
+func (r *JWKSArgs) issuer() string {
+	if r.Issuer != "" {
+		return r.Issuer
+	}
+	return r.discoveryDoc.Issuer
+}
+
+// IsEnabled - returns true if either a JWKS URL or an OpenID
+// discovery URL is configured.
+func (r *JWKSArgs) IsEnabled() bool {
+	return (r.URL != nil && r.URL.String() != "") ||
+		(r.ConfigURL != nil && r.ConfigURL.String() != "")
+}
+
+// PopulatePublicKey - populates a new publickey from the JWKS URL.
+func (r *JWKSArgs) PopulatePublicKey() error {
+	u, err := r.jwksURL()
+	if err != nil {
+		return err
+	}
+
 	var jwk JWKS
-	if err = json.NewDecoder(resp.Body).Decode(&jwk); err != nil {
+	if err = getJSON(u, &jwk); err != nil {
 		return err
 	}
 
@@ -93,6 +206,7 @@ func (r *JWKSArgs) UnmarshalJSON(data []byte) error {
 
 // JWT - rs client grants provider details.
 type JWT struct {
+	id   ID
 	args JWKSArgs
 }
 
@@ -183,6 +297,22 @@ func (p *JWT) Validate(token, dsecs string) (map[string]interface{}, error) {
 		return nil, ErrTokenExpired
 	}
 
+	// The issuer is advertised by the discovery document.
+	if err = p.args.loadDiscoveryDoc(); err != nil {
+		return nil, err
+	}
+	if iss := p.args.issuer(); iss != "" && !claims.VerifyIssuer(iss, true) {
+		return nil, ErrInvalidIssuer
+	}
+
+	if p.args.ClientID != "" && !audienceContains(claims["aud"], p.args.ClientID) {
+		return nil, ErrInvalidAudience
+	}
+
+	if p.args.ClaimPrefix != "" || p.args.claimName() != iampolicy.PolicyName {
+		claims[iampolicy.PolicyName] = strings.Join(p.policiesFromClaim(claims), ",")
+	}
+
 	expAt, err := expToInt64(claims["exp"])
 	if err != nil {
 		return nil, err
@@ -206,14 +336,70 @@ func (p *JWT) Validate(token, dsecs string) (map[string]interface{}, error) {
 
 }
 
+// audienceContains - returns true if the "aud" claim, which may be
+// a string or a list of strings, contains the given audience.
+func audienceContains(aud interface{}, audience string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == audience
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// policiesFromClaim - returns the policy names mapped from the
+// configured claim, which may be a string or a list of strings.
+func (p *JWT) policiesFromClaim(claims jwtgo.MapClaims) []string {
+	var values []string
+	switch v := claims[p.args.claimName()].(type) {
+	case string:
+		values = strings.Split(v, ",")
+	case []interface{}:
+		for _, val := range v {
+			if s, ok := val.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	policies := []string{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if !strings.HasPrefix(v, p.args.ClaimPrefix) {
+			continue
+		}
+		if v = strings.TrimPrefix(v, p.args.ClaimPrefix); v != "" {
+			policies = append(policies, v)
+		}
+	}
+	return policies
+}
+
 // ID returns the provider name and authentication type.
 func (p *JWT) ID() ID {
-	return "jwt"
+	return p.id
+}
+
+// Issuer returns the expected issuer of tokens of this provider.
+func (p *JWT) Issuer() string {
+	return p.args.issuer()
 }
 
 // NewJWT - initialize new jwt authenticator.
 func NewJWT(args JWKSArgs) *JWT {
+	return NewJWTWithID("jwt", args)
+}
+
+// NewJWTWithID - initialize new jwt authenticator with the given
+// provider id, used when multiple identity providers are configured.
+func NewJWTWithID(id ID, args JWKSArgs) *JWT {
 	return &JWT{
+		id:   id,
 		args: args,
 	}
 }
@@ -19,10 +19,14 @@ package openid
 import (
 	"crypto"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"testing"
 	"time"
 
+	jwtgo "github.com/dgrijalva/jwt-go"
 	xnet "github.com/minio/minio/pkg/net"
 )
 
@@ -118,3 +122,121 @@ func TestDefaultExpiryDuration(t *testing.T) {
 		}
 	}
 }
+
+func TestDiscoveryDoc(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			w.Write([]byte(`{"issuer":"` + ts.URL + `","jwks_uri":"` + ts.URL + `/certs"}`))
+		case "/certs":
+			w.Write([]byte(`{"keys":[{"kty":"RSA","kid":"2011-04-29","alg":"RS256","e":"AQAB",
+"n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	u, err := xnet.ParseURL(ts.URL + "/.well-known/openid-configuration")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := JWKSArgs{ConfigURL: u}
+	if !args.IsEnabled() {
+		t.Fatal("Expected OpenID provider to be enabled")
+	}
+	if err = args.PopulatePublicKey(); err != nil {
+		t.Fatal(err)
+	}
+	if len(args.publicKeys) != 1 {
+		t.Fatalf("Expected 1 public key, got %d", len(args.publicKeys))
+	}
+
+	jwt := NewJWTWithID("keycloak", args)
+	if jwt.ID() != "keycloak" {
+		t.Fatalf("Unexpected id %s for the validator", jwt.ID())
+	}
+	if jwt.Issuer() != ts.URL {
+		t.Fatalf("Expected issuer %s, got %s", ts.URL, jwt.Issuer())
+	}
+
+	// The issuer is looked up even if the JWKS URL is configured.
+	jwksURL, err := xnet.ParseURL(ts.URL + "/certs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args = JWKSArgs{URL: jwksURL, ConfigURL: u}
+	if err = args.PopulatePublicKey(); err != nil {
+		t.Fatal(err)
+	}
+	if jwt = NewJWT(args); jwt.Issuer() != ts.URL {
+		t.Fatalf("Expected issuer %s, got %s", ts.URL, jwt.Issuer())
+	}
+}
+
+func TestPoliciesFromClaim(t *testing.T) {
+	testCases := []struct {
+		claimName   string
+		claimPrefix string
+		claims      jwtgo.MapClaims
+		policies    []string
+	}{
+		{
+			claimName: "groups",
+			claims:    jwtgo.MapClaims{"groups": []interface{}{"readwrite", "diagnostics"}},
+			policies:  []string{"readwrite", "diagnostics"},
+		},
+		{
+			claimName:   "groups",
+			claimPrefix: "minio-",
+			claims:      jwtgo.MapClaims{"groups": []interface{}{"minio-readonly", "developers"}},
+			policies:    []string{"readonly"},
+		},
+		{
+			claimName: "roles",
+			claims:    jwtgo.MapClaims{"roles": "readonly, writeonly"},
+			policies:  []string{"readonly", "writeonly"},
+		},
+		{
+			claimName: "groups",
+			claims:    jwtgo.MapClaims{"policy": "readonly"},
+			policies:  []string{},
+		},
+		// The prefix applies to the default claim too.
+		{
+			claimPrefix: "minio-",
+			claims:      jwtgo.MapClaims{"policy": "minio-readonly,consoleAdmin"},
+			policies:    []string{"readonly"},
+		},
+	}
+
+	for i, testCase := range testCases {
+		jwt := NewJWT(JWKSArgs{ClaimName: testCase.claimName, ClaimPrefix: testCase.claimPrefix})
+		policies := jwt.policiesFromClaim(testCase.claims)
+		if !reflect.DeepEqual(policies, testCase.policies) {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.policies, policies)
+		}
+	}
+}
+
+func TestAudienceContains(t *testing.T) {
+	testCases := []struct {
+		aud      interface{}
+		expected bool
+	}{
+		{"minio", true},
+		{"other", false},
+		{[]interface{}{"other", "minio"}, true},
+		{[]interface{}{"other"}, false},
+		{nil, false},
+	}
+
+	for i, testCase := range testCases {
+		if got := audienceContains(testCase.aud, "minio"); got != testCase.expected {
+			t.Errorf("Test %d: Expected %v, got %v", i+1, testCase.expected, got)
+		}
+	}
+}
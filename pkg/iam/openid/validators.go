@@ -20,6 +20,8 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+
+	jwtgo "github.com/dgrijalva/jwt-go"
 )
 
 // ID - holds identification name authentication validator target.
@@ -40,6 +42,8 @@ type Validator interface {
 var (
 	ErrTokenExpired    = errors.New("token expired")
 	ErrInvalidDuration = errors.New("duration higher than token expiry")
+	ErrInvalidIssuer   = errors.New("token issuer mismatch")
+	ErrInvalidAudience = errors.New("token audience mismatch")
 )
 
 // Validators - holds list of providers indexed by provider id.
@@ -86,6 +90,26 @@ func (list *Validators) Get(id ID) (p Validator, err error) {
 	return p, nil
 }
 
+// GetForToken - returns the provider whose issuer matches the "iss"
+// claim of the given token, if no provider matches the default
+// "jwt" provider is returned.
+func (list *Validators) GetForToken(token string) (Validator, error) {
+	claims := jwtgo.MapClaims{}
+	if _, _, err := new(jwtgo.Parser).ParseUnverified(token, &claims); err == nil {
+		if iss, ok := claims["iss"].(string); ok && iss != "" {
+			list.RLock()
+			for _, p := range list.providers {
+				if ip, ok := p.(interface{ Issuer() string }); ok && ip.Issuer() == iss {
+					list.RUnlock()
+					return p, nil
+				}
+			}
+			list.RUnlock()
+		}
+	}
+	return list.Get("jwt")
+}
+
 // NewValidators - creates Validators.
 func NewValidators() *Validators {
 	return &Validators{providers: make(map[ID]Validator)}
@@ -82,6 +82,24 @@ const (
 	// GetBucketLifecycleAction - GetBucketLifecycle Rest API action.
 	GetBucketLifecycleAction = "s3:GetBucketLifecycle"
 
+	// GetBucketACLAction - GetBucketAcl Rest API action.
+	GetBucketACLAction = "s3:GetBucketAcl"
+
+	// PutBucketACLAction - PutBucketAcl Rest API action.
+	PutBucketACLAction = "s3:PutBucketAcl"
+
+	// GetObjectACLAction - GetObjectAcl Rest API action.
+	GetObjectACLAction = "s3:GetObjectAcl"
+
+	// PutObjectACLAction - PutObjectAcl Rest API action.
+	PutObjectACLAction = "s3:PutObjectAcl"
+
+	// GetBucketLoggingAction - GetBucketLogging Rest API action.
+	GetBucketLoggingAction = "s3:GetBucketLogging"
+
+	// PutBucketLoggingAction - PutBucketLogging Rest API action.
+	PutBucketLoggingAction = "s3:PutBucketLogging"
+
 	// PutBucketNotificationAction - PutObjectNotification Rest API action.
 	PutBucketNotificationAction = "s3:PutBucketNotification"
 
@@ -118,6 +136,12 @@ var supportedActions = map[Action]struct{}{
 	PutObjectAction:                  {},
 	GetBucketLifecycleAction:         {},
 	PutBucketLifecycleAction:         {},
+	GetBucketACLAction:               {},
+	PutBucketACLAction:               {},
+	GetObjectACLAction:               {},
+	PutObjectACLAction:               {},
+	GetBucketLoggingAction:           {},
+	PutBucketLoggingAction:           {},
 }
 
 // isObjectAction - returns whether action is object type or not.
@@ -126,6 +150,8 @@ func (action Action) isObjectAction() bool {
 	case AbortMultipartUploadAction, DeleteObjectAction, GetObjectAction:
 		fallthrough
 	case ListMultipartUploadPartsAction, PutObjectAction, AllActions:
+		fallthrough
+	case GetObjectACLAction, PutObjectACLAction:
 		return true
 	}
 
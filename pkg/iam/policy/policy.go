@@ -73,6 +73,18 @@ func (iamp Policy) IsAllowed(args Args) bool {
 	return false
 }
 
+// IsDenied - checks whether any deny statement of the policy
+// matches the given args, unlike IsAllowed this tells an explicit
+// deny apart from a missing allow.
+func (iamp Policy) IsDenied(args Args) bool {
+	for _, statement := range iamp.Statements {
+		if statement.Effect == policy.Deny && !statement.IsAllowed(args) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEmpty - returns whether policy is empty or not.
 func (iamp Policy) IsEmpty() bool {
 	return len(iamp.Statements) == 0
@@ -0,0 +1,126 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DecommissionStatus represents the state of a decommission.
+type DecommissionStatus string
+
+// Different decommission states.
+const (
+	DecommissionRunning  DecommissionStatus = "running"
+	DecommissionComplete DecommissionStatus = "complete"
+	DecommissionCanceled DecommissionStatus = "canceled"
+	DecommissionFailed   DecommissionStatus = "failed"
+)
+
+// DecommissionRetired - a zone or an erasure set of a zone which was
+// completely drained, it never receives new objects again. Set is -1
+// when the whole zone is retired.
+type DecommissionRetired struct {
+	Zone int `json:"zone"`
+	Set  int `json:"set"`
+}
+
+// DecommissionInfo - represents the progress of draining a zone
+// or an erasure set of a zone, Set is -1 when the whole zone is
+// decommissioned.
+type DecommissionInfo struct {
+	Zone          int                `json:"zone"`
+	Set           int                `json:"set"`
+	Status        DecommissionStatus `json:"status,omitempty"`
+	StartTime     time.Time          `json:"startTime"`
+	EndTime       time.Time          `json:"endTime,omitempty"`
+	Bucket        string             `json:"bucket,omitempty"`
+	Marker        string             `json:"marker,omitempty"`
+	ObjectsMoved  int64              `json:"objectsMoved"`
+	BytesMoved    int64              `json:"bytesMoved"`
+	ObjectsFailed int64              `json:"objectsFailed"`
+	// Multipart uploads left on the drained zone or set, the
+	// decommission waits until they are completed or aborted.
+	PendingUploads int64  `json:"pendingUploads,omitempty"`
+	Error          string `json:"error,omitempty"`
+	// Zones and sets retired by all completed decommissions.
+	Retired []DecommissionRetired `json:"retired,omitempty"`
+}
+
+// StartDecommission - starts draining all objects of a zone, or of a
+// single erasure set in that zone when set is not negative, to the
+// remaining zones. The drained zone or set no longer receives new objects.
+func (adm *AdminClient) StartDecommission(zone, set int) error {
+	v := url.Values{}
+	v.Set("zone", strconv.Itoa(zone))
+	v.Set("set", strconv.Itoa(set))
+	resp, err := adm.executeMethod("POST", requestData{
+		relPath:     "/v1/decommission/start",
+		queryValues: v,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// DecommissionStatus - returns the progress of the current or the last
+// decommission.
+func (adm *AdminClient) DecommissionStatus() (DecommissionInfo, error) {
+	var info DecommissionInfo
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath: "/v1/decommission/status",
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return info, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return info, httpRespToErrorResponse(resp)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	return info, err
+}
+
+// CancelDecommission - cancels a running decommission, the zone or
+// set receives new objects again. A completed decommission can not
+// be canceled, the zone or set stays retired.
+func (adm *AdminClient) CancelDecommission() error {
+	resp, err := adm.executeMethod("POST", requestData{
+		relPath: "/v1/decommission/cancel",
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
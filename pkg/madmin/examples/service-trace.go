@@ -40,10 +40,11 @@ func main() {
 	defer close(doneCh)
 
 	// Start listening on all http trace activity from all servers
-	// in the minio cluster.
-	allTrace := false
-	errTrace := false
-	traceCh := madmClnt.ServiceTrace(allTrace, errTrace, doneCh)
+	// in the minio cluster, including the calls to the disks.
+	opts := madmin.ServiceTraceOpts{
+		Storage: true,
+	}
+	traceCh := madmClnt.ServiceTrace(opts, doneCh)
 	for traceInfo := range traceCh {
 		if traceInfo.Err != nil {
 			fmt.Println(traceInfo.Err)
@@ -81,10 +81,11 @@ const (
 
 // Drive state constants
 const (
-	DriveStateOk      string = "ok"
-	DriveStateOffline        = "offline"
-	DriveStateCorrupt        = "corrupt"
-	DriveStateMissing        = "missing"
+	DriveStateOk       string = "ok"
+	DriveStateOffline         = "offline"
+	DriveStateCorrupt         = "corrupt"
+	DriveStateMissing         = "missing"
+	DriveStateExcluded        = "excluded"
 )
 
 // HealDriveInfo - struct for an individual drive info item.
@@ -92,6 +93,11 @@ type HealDriveInfo struct {
 	UUID     string `json:"uuid"`
 	Endpoint string `json:"endpoint"`
 	State    string `json:"state"`
+
+	// Average latency and error rate of recent requests
+	// to the drive, only reported by StorageInfo.
+	Latency   time.Duration `json:"latency,omitempty"`
+	ErrorRate float64       `json:"errorRate,omitempty"`
 }
 
 // HealResultItem - struct for an individual heal result item
@@ -274,6 +280,16 @@ func (adm *AdminClient) Heal(bucket, prefix string, healOpts HealOpts,
 type BgHealState struct {
 	ScannedItemsCount int64
 	LastHealActivity  time.Time
+
+	// Objects found degraded on read or partially written,
+	// waiting in the most recently failed (MRF) queue.
+	MRFQueueDepth int64
+	// Objects healed from the MRF queue.
+	MRFHealedCount int64
+	// Objects from the MRF queue which could not be healed.
+	MRFFailedCount int64
+	// Objects not queued because the MRF queue was full.
+	MRFDroppedCount int64
 }
 
 // BackgroundHealStatus returns the background heal status of the
@@ -303,3 +319,38 @@ func (adm *AdminClient) BackgroundHealStatus() (BgHealState, error) {
 	}
 	return healState, nil
 }
+
+// DriveHealInfo represents the healing progress of a replaced drive,
+// Finished is zero while the drive is being healed.
+type DriveHealInfo struct {
+	Endpoint      string    `json:"endpoint"`
+	Zone          int       `json:"zone"`
+	Set           int       `json:"set"`
+	Started       time.Time `json:"started"`
+	Finished      time.Time `json:"finished,omitempty"`
+	ObjectsHealed int64     `json:"objectsHealed"`
+	ObjectsFailed int64     `json:"objectsFailed"`
+	BytesHealed   int64     `json:"bytesHealed"`
+	// Estimated completion time, zero until it can be estimated.
+	ETA time.Time `json:"eta,omitempty"`
+}
+
+// DrivesHealStatus returns the healing progress of all replaced
+// drives of the cluster.
+func (adm *AdminClient) DrivesHealStatus() ([]DriveHealInfo, error) {
+	resp, err := adm.executeMethod("POST", requestData{relPath: "/v1/background-heal/drives"})
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponse(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	var drives []DriveHealInfo
+	if err = json.NewDecoder(resp.Body).Decode(&drives); err != nil {
+		return nil, err
+	}
+	return drives, nil
+}
@@ -0,0 +1,115 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/minio/minio/pkg/event"
+)
+
+// NotificationTargetInfo - represents the delivery state of a
+// notification target on a server.
+type NotificationTargetInfo struct {
+	Node string `json:"node"`
+	ARN  string `json:"arn"`
+	// Whether the last delivery to the target succeeded.
+	Online bool `json:"online"`
+	// Whether undelivered events are kept in a queue directory.
+	Queued      bool      `json:"queued"`
+	QueueLength int       `json:"queueLength"`
+	OldestEvent time.Time `json:"oldestEvent,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// NotificationQueueInfo - represents the queued events of a notification
+// target on a server.
+type NotificationQueueInfo struct {
+	Node   string        `json:"node"`
+	Events []event.Event `json:"events,omitempty"`
+	// Number of events removed by a purge.
+	Purged int    `json:"purged,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// NotificationTargets - returns the delivery state of the notification
+// targets of all servers.
+func (adm *AdminClient) NotificationTargets() ([]NotificationTargetInfo, error) {
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath: "/v1/notification/targets",
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	var targets []NotificationTargetInfo
+	err = json.NewDecoder(resp.Body).Decode(&targets)
+	return targets, err
+}
+
+// PeekNotificationQueue - returns up to count of the oldest queued events
+// of the target with arn of all servers.
+func (adm *AdminClient) PeekNotificationQueue(arn string, count int) ([]NotificationQueueInfo, error) {
+	v := url.Values{}
+	v.Set("arn", arn)
+	v.Set("count", strconv.Itoa(count))
+	return adm.notificationQueue("GET", "/v1/notification/queue", v)
+}
+
+// PurgeNotificationQueue - removes all queued events of the target with
+// arn of all servers without delivering them.
+func (adm *AdminClient) PurgeNotificationQueue(arn string) ([]NotificationQueueInfo, error) {
+	v := url.Values{}
+	v.Set("arn", arn)
+	return adm.notificationQueue("POST", "/v1/notification/queue/purge", v)
+}
+
+// ReplayNotificationQueue - retries the delivery of the queued events of
+// the target with arn of all servers right away.
+func (adm *AdminClient) ReplayNotificationQueue(arn string) ([]NotificationQueueInfo, error) {
+	v := url.Values{}
+	v.Set("arn", arn)
+	return adm.notificationQueue("POST", "/v1/notification/queue/replay", v)
+}
+
+func (adm *AdminClient) notificationQueue(method, relPath string, queryValues url.Values) ([]NotificationQueueInfo, error) {
+	resp, err := adm.executeMethod(method, requestData{
+		relPath:     relPath,
+		queryValues: queryValues,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp)
+	}
+
+	var queues []NotificationQueueInfo
+	err = json.NewDecoder(resp.Body).Decode(&queues)
+	return queues, err
+}
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	trace "github.com/minio/minio/pkg/trace"
 )
@@ -77,16 +78,60 @@ type ServiceTraceInfo struct {
 	Err   error `json:"-"`
 }
 
-// ServiceTrace - listen on http trace notifications.
-func (adm AdminClient) ServiceTrace(allTrace, errTrace bool, doneCh <-chan struct{}) <-chan ServiceTraceInfo {
+// ServiceTraceOpts - selects the trace records of ServiceTrace.
+type ServiceTraceOpts struct {
+	// Trace internal API requests as well.
+	All bool
+	// Trace failed requests and calls only.
+	OnlyErrors bool
+	// Trace the calls to the disks and namespace locks as well.
+	Storage bool
+	Lock    bool
+
+	// Trace the given node only, e.g. "server1".
+	Node string
+	// Trace the given API only, e.g. "s3.PutObject" or "ReadFile".
+	API string
+	// Trace requests and calls on the given bucket only.
+	Bucket string
+	// Trace requests with the given status code only.
+	StatusCode int
+	// Trace requests and calls taking at least Threshold only.
+	Threshold time.Duration
+}
+
+func (opts ServiceTraceOpts) queryValues() url.Values {
+	urlValues := make(url.Values)
+	urlValues.Set("all", strconv.FormatBool(opts.All))
+	urlValues.Set("err", strconv.FormatBool(opts.OnlyErrors))
+	urlValues.Set("storage", strconv.FormatBool(opts.Storage))
+	urlValues.Set("lock", strconv.FormatBool(opts.Lock))
+	if opts.Node != "" {
+		urlValues.Set("node", opts.Node)
+	}
+	if opts.API != "" {
+		urlValues.Set("api", opts.API)
+	}
+	if opts.Bucket != "" {
+		urlValues.Set("bucket", opts.Bucket)
+	}
+	if opts.StatusCode != 0 {
+		urlValues.Set("statuscode", strconv.Itoa(opts.StatusCode))
+	}
+	if opts.Threshold != 0 {
+		urlValues.Set("threshold", opts.Threshold.String())
+	}
+	return urlValues
+}
+
+// ServiceTrace - listen on http, storage and lock trace notifications.
+func (adm AdminClient) ServiceTrace(opts ServiceTraceOpts, doneCh <-chan struct{}) <-chan ServiceTraceInfo {
 	traceInfoCh := make(chan ServiceTraceInfo)
 	// Only success, start a routine to start reading line by line.
 	go func(traceInfoCh chan<- ServiceTraceInfo) {
 		defer close(traceInfoCh)
 		for {
-			urlValues := make(url.Values)
-			urlValues.Set("all", strconv.FormatBool(allTrace))
-			urlValues.Set("err", strconv.FormatBool(errTrace))
+			urlValues := opts.queryValues()
 			reqData := requestData{
 				relPath:     "/v1/trace",
 				queryValues: urlValues,
@@ -0,0 +1,87 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package madmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// BucketStorageClass - storage classes applied to objects uploaded
+// without the x-amz-storage-class header. Prefixes maps object name
+// prefixes to storage classes, the longest matching prefix takes
+// precedence over Default.
+type BucketStorageClass struct {
+	Default  string            `json:"default,omitempty"`
+	Prefixes map[string]string `json:"prefixes,omitempty"`
+}
+
+// IsEmpty - returns true if no storage class is set.
+func (sc BucketStorageClass) IsEmpty() bool {
+	return sc.Default == "" && len(sc.Prefixes) == 0
+}
+
+// SetBucketStorageClass - sets the default storage classes of a
+// bucket, an empty BucketStorageClass removes them.
+func (adm *AdminClient) SetBucketStorageClass(bucket string, sc BucketStorageClass) error {
+	data, err := json.Marshal(sc)
+	if err != nil {
+		return err
+	}
+
+	v := url.Values{}
+	v.Set("bucket", bucket)
+	resp, err := adm.executeMethod("PUT", requestData{
+		relPath:     "/v1/bucket-storage-class",
+		queryValues: v,
+		content:     data,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return httpRespToErrorResponse(resp)
+	}
+	return nil
+}
+
+// GetBucketStorageClass - returns the default storage classes of a
+// bucket.
+func (adm *AdminClient) GetBucketStorageClass(bucket string) (BucketStorageClass, error) {
+	var sc BucketStorageClass
+
+	v := url.Values{}
+	v.Set("bucket", bucket)
+	resp, err := adm.executeMethod("GET", requestData{
+		relPath:     "/v1/bucket-storage-class",
+		queryValues: v,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return sc, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return sc, httpRespToErrorResponse(resp)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&sc)
+	return sc, err
+}
@@ -89,6 +89,24 @@ const (
 
 	// GetBucketLifecycleAction - GetBucketLifecycle Rest API action.
 	GetBucketLifecycleAction = "s3:GetBucketLifecycle"
+
+	// GetBucketACLAction - GetBucketAcl Rest API action.
+	GetBucketACLAction = "s3:GetBucketAcl"
+
+	// PutBucketACLAction - PutBucketAcl Rest API action.
+	PutBucketACLAction = "s3:PutBucketAcl"
+
+	// GetObjectACLAction - GetObjectAcl Rest API action.
+	GetObjectACLAction = "s3:GetObjectAcl"
+
+	// PutObjectACLAction - PutObjectAcl Rest API action.
+	PutObjectACLAction = "s3:PutObjectAcl"
+
+	// GetBucketLoggingAction - GetBucketLogging Rest API action.
+	GetBucketLoggingAction = "s3:GetBucketLogging"
+
+	// PutBucketLoggingAction - PutBucketLogging Rest API action.
+	PutBucketLoggingAction = "s3:PutBucketLogging"
 )
 
 // isObjectAction - returns whether action is object type or not.
@@ -97,6 +115,8 @@ func (action Action) isObjectAction() bool {
 	case AbortMultipartUploadAction, DeleteObjectAction, GetObjectAction:
 		fallthrough
 	case ListMultipartUploadPartsAction, PutObjectAction:
+		fallthrough
+	case GetObjectACLAction, PutObjectACLAction:
 		return true
 	}
 
@@ -121,6 +141,10 @@ func (action Action) IsValid() bool {
 	case PutBucketPolicyAction, PutObjectAction:
 		fallthrough
 	case PutBucketLifecycleAction, GetBucketLifecycleAction:
+		fallthrough
+	case GetBucketACLAction, PutBucketACLAction, GetObjectACLAction, PutObjectACLAction:
+		fallthrough
+	case GetBucketLoggingAction, PutBucketLoggingAction:
 		return true
 	}
 
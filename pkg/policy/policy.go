@@ -70,6 +70,18 @@ func (policy Policy) IsAllowed(args Args) bool {
 	return false
 }
 
+// IsDenied - checks whether any deny statement of the policy
+// matches the given args, unlike IsAllowed this tells an explicit
+// deny apart from a missing allow.
+func (policy Policy) IsDenied(args Args) bool {
+	for _, statement := range policy.Statements {
+		if statement.Effect == Deny && !statement.IsAllowed(args) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEmpty - returns whether policy is empty or not.
 func (policy Policy) IsEmpty() bool {
 	return len(policy.Statements) == 0
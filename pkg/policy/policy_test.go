@@ -184,6 +184,50 @@ func TestPolicyIsAllowed(t *testing.T) {
 	}
 }
 
+func TestPolicyIsDenied(t *testing.T) {
+	denyPolicy := Policy{
+		Version: DefaultVersion,
+		Statements: []Statement{
+			NewStatement(
+				Allow,
+				NewPrincipal("*"),
+				NewActionSet(GetObjectAction, PutObjectAction),
+				NewResourceSet(NewResource("mybucket", "*")),
+				condition.NewFunctions(),
+			),
+			NewStatement(
+				Deny,
+				NewPrincipal("*"),
+				NewActionSet(GetObjectAction),
+				NewResourceSet(NewResource("mybucket", "/private*")),
+				condition.NewFunctions(),
+			)},
+	}
+
+	testCases := []struct {
+		policy         Policy
+		args           Args
+		expectedResult bool
+	}{
+		{denyPolicy, Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "private/object"}, true},
+		// The owner is still subject to deny statements.
+		{denyPolicy, Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "private/object", IsOwner: true}, true},
+		// Allowed objects and missing allows are not denied.
+		{denyPolicy, Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "public/object"}, false},
+		{denyPolicy, Args{Action: PutObjectAction, BucketName: "mybucket", ObjectName: "private/object"}, false},
+		{denyPolicy, Args{Action: GetObjectAction, BucketName: "otherbucket", ObjectName: "private/object"}, false},
+		{Policy{Version: DefaultVersion}, Args{Action: GetObjectAction, BucketName: "mybucket", ObjectName: "private/object"}, false},
+	}
+
+	for i, testCase := range testCases {
+		result := testCase.policy.IsDenied(testCase.args)
+
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: expected: %v, got: %v\n", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
 func TestPolicyIsEmpty(t *testing.T) {
 	case1Policy := Policy{
 		Version: DefaultVersion,
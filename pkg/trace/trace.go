@@ -21,14 +21,39 @@ import (
 	"time"
 )
 
+// Type - kind of the call a trace record describes.
+type Type int
+
+const (
+	// HTTP - an S3, admin or internal HTTP request.
+	HTTP Type = iota
+	// Storage - a call to a local disk.
+	Storage
+	// Lock - acquiring or releasing a namespace lock.
+	Lock
+)
+
 // Info - represents a trace record, additionally
 // also reports errors if any while listening on trace.
 type Info struct {
+	TraceType Type         `json:"type"`
 	NodeName  string       `json:"nodename"`
 	FuncName  string       `json:"funcname"`
 	ReqInfo   RequestInfo  `json:"request"`
 	RespInfo  ResponseInfo `json:"response"`
 	CallStats CallStats    `json:"stats"`
+
+	// Set for storage and lock calls only.
+	CallInfo CallInfo `json:"call"`
+}
+
+// CallInfo represents trace of a storage or lock call
+type CallInfo struct {
+	Time     time.Time     `json:"time"`
+	Disk     string        `json:"disk,omitempty"`
+	Path     string        `json:"path,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
 }
 
 // CallStats records request stats
@@ -0,0 +1,265 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Spans waiting for export, further spans are dropped.
+	exportQueueSize = 10000
+
+	// Maximum number of spans sent in one request.
+	exportBatchSize = 512
+
+	// Interval at which queued spans are sent.
+	exportInterval = 5 * time.Second
+)
+
+// HTTPExporter - sends spans in batches to an OTLP/HTTP collector, e.g.
+// the OpenTelemetry collector or Jaeger, using the JSON encoding.
+type HTTPExporter struct {
+	endpoint   string
+	resource   []otlpKeyValue
+	httpClient *http.Client
+
+	queue   chan *Span
+	dropped uint64
+
+	// Closed to stop the exporter, wg waits for the last batch.
+	doneCh    chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+
+	// Called with the error of a failed export.
+	onError func(error)
+}
+
+// NewHTTPExporter - returns an exporter sending spans to endpoint, e.g.
+// http://localhost:4318/v1/traces. The resource attributes describe
+// the process, e.g. service.name.
+func NewHTTPExporter(endpoint string, resource map[string]string, transport http.RoundTripper, onError func(error)) *HTTPExporter {
+	e := &HTTPExporter{
+		endpoint: endpoint,
+		resource: toOTLPAttributes(resource),
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   exportInterval,
+		},
+		queue:   make(chan *Span, exportQueueSize),
+		doneCh:  make(chan struct{}),
+		onError: onError,
+	}
+	e.wg.Add(1)
+	go e.run()
+	return e
+}
+
+// Export - queues a finished span, it is dropped if the queue is full.
+func (e *HTTPExporter) Export(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+		atomic.AddUint64(&e.dropped, 1)
+	}
+}
+
+// Dropped - returns the number of spans dropped as the collector
+// could not keep up.
+func (e *HTTPExporter) Dropped() uint64 {
+	return atomic.LoadUint64(&e.dropped)
+}
+
+// Close - sends the queued spans and stops the exporter.
+func (e *HTTPExporter) Close() error {
+	e.closeOnce.Do(func() { close(e.doneCh) })
+	e.wg.Wait()
+	return nil
+}
+
+func (e *HTTPExporter) run() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	var batch []*Span
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil && e.onError != nil {
+			e.onError(err)
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= exportBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.doneCh:
+			for {
+				select {
+				case span := <-e.queue:
+					batch = append(batch, span)
+					if len(batch) >= exportBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *HTTPExporter) send(batch []*Span) error {
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, span := range batch {
+		spans = append(spans, toOTLPSpan(span))
+	}
+	data, err := json.Marshal(otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: e.resource},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "minio"},
+				Spans: spans,
+			}},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("Unable to export %d spans to %s: %s", len(batch), e.endpoint, resp.Status)
+	}
+	return nil
+}
+
+// OTLP/HTTP JSON encoding of spans, as described in
+// https://github.com/open-telemetry/opentelemetry-proto
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// Status codes of OTLP.
+const (
+	otlpStatusOK    = 1
+	otlpStatusError = 2
+)
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func toOTLPAttributes(attrs map[string]string) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		kvs = append(kvs, otlpKeyValue{Key: k, Value: otlpValue{StringValue: v}})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+	return kvs
+}
+
+func toOTLPSpan(span *Span) otlpSpan {
+	s := otlpSpan{
+		TraceID:           span.Context.TraceID.String(),
+		SpanID:            span.Context.SpanID.String(),
+		Name:              span.Name,
+		Kind:              span.Kind,
+		StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+		Attributes:        toOTLPAttributes(span.Attributes),
+		Status:            otlpStatus{Code: otlpStatusOK},
+	}
+	if span.Parent.IsValid() {
+		s.ParentSpanID = span.Parent.String()
+	}
+	if span.Err != "" {
+		s.Status = otlpStatus{Code: otlpStatusError, Message: span.Err}
+	}
+	return s
+}
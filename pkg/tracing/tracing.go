@@ -0,0 +1,264 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package tracing implements span based distributed tracing. Spans are
+// propagated between servers with the W3C traceparent header and
+// exported in the OpenTelemetry (OTLP) format.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceParentHeader - W3C trace context header carrying the span
+// context of the caller.
+const TraceParentHeader = "traceparent"
+
+// TraceID - identifies all spans of a trace.
+type TraceID [16]byte
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID - identifies a span within a trace.
+type SpanID [8]byte
+
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// IsValid - returns false for the all zero span ID.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// SpanContext - identifies a span across servers.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// IsValid - returns true if both IDs are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID.IsValid()
+}
+
+// SpanKind - role of a span in a trace, the values match OTLP.
+type SpanKind int
+
+// Span kinds.
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+// Span - a timed operation of a trace. All methods may be called on a
+// nil span, which is returned for requests which are not traced.
+type Span struct {
+	tracer *Tracer
+
+	Name    string
+	Kind    SpanKind
+	Context SpanContext
+	Parent  SpanID
+	Start   time.Time
+	End     time.Time
+
+	mu         sync.Mutex
+	Attributes map[string]string
+	Err        string
+}
+
+// SetAttribute - sets an attribute of the span.
+func (s *Span) SetAttribute(key, value string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]string)
+	}
+	s.Attributes[key] = value
+}
+
+// SetError - marks the span as failed, a nil error is ignored.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Err = err.Error()
+}
+
+// Finish - ends the span and hands it to the exporter, the span must
+// not be modified afterwards.
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.End = time.Now()
+	s.mu.Unlock()
+	s.tracer.exporter.Export(s)
+}
+
+// Exporter - delivers finished spans to a collector.
+type Exporter interface {
+	Export(span *Span)
+	Close() error
+}
+
+// Tracer - starts spans and exports them once finished.
+type Tracer struct {
+	exporter Exporter
+	// Fraction of new traces which are recorded.
+	sampleRatio float64
+}
+
+// New - returns a tracer recording the given fraction of new traces,
+// traces continued from other servers are always recorded.
+func New(exporter Exporter, sampleRatio float64) *Tracer {
+	return &Tracer{exporter: exporter, sampleRatio: sampleRatio}
+}
+
+// Close - flushes the spans not exported yet.
+func (t *Tracer) Close() error {
+	if t == nil {
+		return nil
+	}
+	return t.exporter.Close()
+}
+
+type spanKey struct{}
+
+type remoteSpanKey struct{}
+
+// FromContext - returns the span of the context, nil if the context
+// is not traced.
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithSpan - returns a context carrying span, e.g. to trace
+// calls without the deadline of the context the span was started in.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// ContextWithRemoteParent - returns a context whose spans are children
+// of a span of another server.
+func ContextWithRemoteParent(ctx context.Context, parent SpanContext) context.Context {
+	return context.WithValue(ctx, remoteSpanKey{}, parent)
+}
+
+// Start - starts a child of the span of the context, or of the remote
+// parent of the context. Without a parent a new trace is started if
+// it is sampled. A nil span is returned if the request is not traced.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	if t == nil {
+		return ctx, nil
+	}
+	span := &Span{tracer: t, Name: name, Kind: kind, Start: time.Now()}
+	if parent := FromContext(ctx); parent != nil {
+		span.Context.TraceID = parent.Context.TraceID
+		span.Parent = parent.Context.SpanID
+	} else if remote, ok := ctx.Value(remoteSpanKey{}).(SpanContext); ok && remote.IsValid() {
+		span.Context.TraceID = remote.TraceID
+		span.Parent = remote.SpanID
+	} else {
+		if !t.sample() {
+			return ctx, nil
+		}
+		span.Context.TraceID = newTraceID()
+	}
+	span.Context.SpanID = newSpanID()
+	return ContextWithSpan(ctx, span), span
+}
+
+// Start - starts a child of the span of the context, a nil span is
+// returned if the context is not traced.
+func Start(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent := FromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	return parent.tracer.Start(ctx, name, kind)
+}
+
+func (t *Tracer) sample() bool {
+	if t.sampleRatio >= 1 {
+		return true
+	}
+	if t.sampleRatio <= 0 {
+		return false
+	}
+	var b [8]byte
+	rand.Read(b[:])
+	return float64(binary.BigEndian.Uint64(b[:])>>11)/(1<<53) < t.sampleRatio
+}
+
+func newTraceID() (id TraceID) {
+	rand.Read(id[:])
+	return id
+}
+
+func newSpanID() (id SpanID) {
+	rand.Read(id[:])
+	return id
+}
+
+// Inject - sets the traceparent header of an outgoing request to the
+// span of the context, if any.
+func Inject(ctx context.Context, h http.Header) {
+	span := FromContext(ctx)
+	if span == nil {
+		return
+	}
+	// Only sampled spans are propagated.
+	h.Set(TraceParentHeader, fmt.Sprintf("00-%s-%s-01", span.Context.TraceID, span.Context.SpanID))
+}
+
+// Extract - returns the span context of the traceparent header of an
+// incoming request, false if the request is not traced.
+func Extract(h http.Header) (sc SpanContext, ok bool) {
+	parts := strings.Split(h.Get(TraceParentHeader), "-")
+	if len(parts) != 4 || parts[0] != "00" || parts[3] != "01" {
+		return sc, false
+	}
+	if len(parts[1]) != hex.EncodedLen(len(sc.TraceID)) || len(parts[2]) != hex.EncodedLen(len(sc.SpanID)) {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return sc, false
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return sc, false
+	}
+	return sc, sc.IsValid()
+}
@@ -0,0 +1,167 @@
+/*
+ * MinIO Cloud Storage, (C) 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type memExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+}
+
+func (e *memExporter) Export(span *Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+func (e *memExporter) Close() error { return nil }
+
+func TestPropagation(t *testing.T) {
+	client, server := &memExporter{}, &memExporter{}
+	clientTracer, serverTracer := New(client, 1), New(server, 0)
+
+	ctx, root := clientTracer.Start(context.Background(), "s3.PutObject", KindServer)
+	ctx, call := Start(ctx, "storage.CreateFile", KindClient)
+	h := make(http.Header)
+	Inject(ctx, h)
+
+	// The server continues the trace even though it samples no new traces.
+	sc, ok := Extract(h)
+	if !ok {
+		t.Fatalf("Expected a span context in %v", h)
+	}
+	_, handler := serverTracer.Start(ContextWithRemoteParent(context.Background(), sc), "internal.CreateFile", KindServer)
+	handler.SetError(errors.New("disk full"))
+	handler.Finish()
+	call.Finish()
+	root.Finish()
+
+	if len(client.spans) != 2 || len(server.spans) != 1 {
+		t.Fatalf("Expected 2 client and 1 server spans, got %d and %d", len(client.spans), len(server.spans))
+	}
+	if call.Context.TraceID != root.Context.TraceID || call.Parent != root.Context.SpanID {
+		t.Fatal("Expected the storage call to be a child of the request")
+	}
+	if handler.Context.TraceID != root.Context.TraceID || handler.Parent != call.Context.SpanID {
+		t.Fatal("Expected the remote handler to be a child of the storage call")
+	}
+	if handler.Err != "disk full" {
+		t.Fatalf("Expected the handler to fail, got %q", handler.Err)
+	}
+
+	// Untraced contexts start no spans.
+	if _, span := serverTracer.Start(context.Background(), "s3.GetObject", KindServer); span != nil {
+		t.Fatal("Expected no span without sampling")
+	}
+	if _, span := Start(context.Background(), "storage.ReadFile", KindClient); span != nil {
+		t.Fatal("Expected no child span without a parent")
+	}
+	var tracer *Tracer
+	if _, span := tracer.Start(context.Background(), "s3.GetObject", KindServer); span != nil {
+		t.Fatal("Expected no span if tracing is disabled")
+	}
+}
+
+func TestExtract(t *testing.T) {
+	testCases := []struct {
+		header string
+		ok     bool
+	}{
+		{"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", true},
+		{"", false},
+		// Not sampled.
+		{"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00", false},
+		{"01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", false},
+		{"00-0af7651916cd43dd8448eb211c80319c00-b7ad6b7169203331-01", false},
+		{"00-0af7651916cd43dd8448eb211c80319c-b7ad6b71692033-01", false},
+		{"00-0af7651916cd43dd8448eb211c80319x-b7ad6b7169203331-01", false},
+		{"00-00000000000000000000000000000000-b7ad6b7169203331-01", false},
+	}
+	for i, testCase := range testCases {
+		h := make(http.Header)
+		h.Set(TraceParentHeader, testCase.header)
+		if _, ok := Extract(h); ok != testCase.ok {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.ok, ok)
+		}
+	}
+}
+
+func TestHTTPExporter(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []otlpSpan
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req otlpRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, rs := range req.ResourceSpans {
+			if len(rs.Resource.Attributes) != 1 || rs.Resource.Attributes[0].Value.StringValue != "minio" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			for _, ss := range rs.ScopeSpans {
+				received = append(received, ss.Spans...)
+			}
+		}
+	}))
+	defer collector.Close()
+
+	exporter := NewHTTPExporter(collector.URL+"/v1/traces", map[string]string{"service.name": "minio"}, http.DefaultTransport, func(err error) {
+		t.Error(err)
+	})
+	tracer := New(exporter, 1)
+	ctx, root := tracer.Start(context.Background(), "s3.GetObject", KindServer)
+	root.SetAttribute("bucket", "photos")
+	_, child := Start(ctx, "storage.ReadFile", KindClient)
+	child.SetError(errors.New("file not found"))
+	child.Finish()
+	root.Finish()
+
+	// Close sends the queued spans.
+	if err := tracer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(received))
+	}
+	c, r := received[0], received[1]
+	if r.ParentSpanID != "" || c.ParentSpanID != r.SpanID || c.TraceID != r.TraceID {
+		t.Fatalf("Expected ReadFile to be a child of GetObject, got %v", received)
+	}
+	if r.Status.Code != otlpStatusOK || c.Status.Code != otlpStatusError || c.Status.Message != "file not found" {
+		t.Fatalf("Unexpected status %v and %v", r.Status, c.Status)
+	}
+	if len(r.Attributes) != 1 || r.Attributes[0].Key != "bucket" || r.Attributes[0].Value.StringValue != "photos" {
+		t.Fatalf("Unexpected attributes %v", r.Attributes)
+	}
+}